@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vv-sam/otus-project/agent/internal/control"
+	"github.com/vv-sam/otus-project/agent/internal/logs"
+	"github.com/vv-sam/otus-project/agent/internal/probe"
+	"github.com/vv-sam/otus-project/agent/internal/rcon"
+	"github.com/vv-sam/otus-project/agent/internal/runtime"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Сколько последних строк вывода возвращает команда fetch logs
+	fetchLogsTail = 200
+
+	// Сколько ждать остановки контейнера перед принудительным завершением
+	stopTimeout = 30 * time.Second
+)
+
+// Регистрирует обработчики команд, приходящих по каналу управления
+func registerCommands(c *control.Client) {
+	c.Handle(api.CommandType_COMMAND_TYPE_START, startCommand)
+	c.Handle(api.CommandType_COMMAND_TYPE_STOP, stopCommand)
+	c.Handle(api.CommandType_COMMAND_TYPE_FETCH_LOGS, fetchLogsCommand)
+	c.Handle(api.CommandType_COMMAND_TYPE_CONSOLE, consoleCommand)
+	c.Handle(api.CommandType_COMMAND_TYPE_PROBE, probeCommand)
+	c.Handle(api.CommandType_COMMAND_TYPE_UPDATE_RESOURCES, updateResourcesCommand)
+	c.HandleLogs(streamLogs)
+}
+
+// RCON-клиенты игровых серверов по id конфигурации. Соединение
+// переиспользуется между командами
+var (
+	consolesM sync.Mutex
+	consoles  = make(map[string]*console)
+)
+
+type console struct {
+	addr     string
+	password string
+	client   *rcon.Client
+
+	// Число выполняющихся команд. Заменённый клиент закрывается,
+	// когда завершится последняя из них
+	users    int
+	replaced bool
+}
+
+// Имя контейнера игрового сервера для конфигурации
+func containerName(configurationId string) string {
+	return "otus-" + configurationId
+}
+
+func startCommand(ctx context.Context, cmd *api.Command) ([]byte, error) {
+	if cmd.ConfigurationId == "" {
+		return nil, errors.New("configuration id is required")
+	}
+	if err := rt.Start(ctx, containerName(cmd.ConfigurationId)); err != nil {
+		return nil, err
+	}
+
+	// Вывод собирается с момента запуска, а не с первого запроса логов
+	if err := lm.Watch(containerName(cmd.ConfigurationId)); err != nil {
+		log.Printf("Failed to watch logs of %s: %v\n", cmd.ConfigurationId, err)
+	}
+	return nil, nil
+}
+
+func stopCommand(ctx context.Context, cmd *api.Command) ([]byte, error) {
+	if cmd.ConfigurationId == "" {
+		return nil, errors.New("configuration id is required")
+	}
+	return nil, rt.Stop(ctx, containerName(cmd.ConfigurationId), stopTimeout)
+}
+
+func fetchLogsCommand(ctx context.Context, cmd *api.Command) ([]byte, error) {
+	if cmd.ConfigurationId == "" {
+		return nil, errors.New("configuration id is required")
+	}
+
+	var b strings.Builder
+	err := lm.Read(ctx, containerName(cmd.ConfigurationId), logs.Query{Tail: fetchLogsTail}, func(lines []logs.Line) error {
+		for _, l := range lines {
+			b.WriteString(l.Text)
+			b.WriteByte('\n')
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(b.String()), nil
+}
+
+// Применяет новые ограничения CPU и памяти к контейнеру без перезапуска
+func updateResourcesCommand(ctx context.Context, cmd *api.Command) ([]byte, error) {
+	if cmd.ConfigurationId == "" {
+		return nil, errors.New("configuration id is required")
+	}
+
+	var res api.Resources
+	if err := proto.Unmarshal(cmd.Payload, &res); err != nil {
+		return nil, fmt.Errorf("failed to parse resources: %w", err)
+	}
+
+	return nil, rt.Update(ctx, containerName(cmd.ConfigurationId), runtime.Resources{
+		CpuCores:    res.CpuCores,
+		CpuShares:   res.CpuShares,
+		MemoryBytes: res.MemoryBytes,
+		DiskBytes:   res.DiskBytes,
+	})
+}
+
+func consoleCommand(ctx context.Context, cmd *api.Command) ([]byte, error) {
+	if cmd.ConfigurationId == "" {
+		return nil, errors.New("configuration id is required")
+	}
+
+	var req api.RconCommand
+	if err := proto.Unmarshal(cmd.Payload, &req); err != nil {
+		return nil, fmt.Errorf("failed to parse console command: %w", err)
+	}
+	if !validPort(req.Port) {
+		return nil, fmt.Errorf("invalid rcon port %d", req.Port)
+	}
+
+	output, err := rconExec(cmd.ConfigurationId, req.Port, req.Password)(ctx, req.Command)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(output), nil
+}
+
+// Проверяет, отвечает ли игровой сервер игрокам. Вывод - api.ProbeResult
+func probeCommand(ctx context.Context, cmd *api.Command) ([]byte, error) {
+	if cmd.ConfigurationId == "" {
+		return nil, errors.New("configuration id is required")
+	}
+
+	var req api.ProbeRequest
+	if err := proto.Unmarshal(cmd.Payload, &req); err != nil {
+		return nil, fmt.Errorf("failed to parse probe request: %w", err)
+	}
+	if !validPort(req.Port) {
+		return nil, fmt.Errorf("invalid port %d", req.Port)
+	}
+
+	addr := localAddr(req.Port)
+
+	var status *probe.Status
+	var err error
+	switch req.Game {
+	case "minecraft":
+		status, err = probe.Minecraft(ctx, addr)
+	case "factorio":
+		if !validPort(req.RconPort) || req.RconPassword == "" {
+			return nil, errors.New("rcon is not configured")
+		}
+		status, err = probe.Factorio(ctx, addr, rconExec(cmd.ConfigurationId, req.RconPort, req.RconPassword))
+	default:
+		return nil, fmt.Errorf("game %q can't be probed", req.Game)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(&api.ProbeResult{
+		LatencyMs:     uint32(status.Latency.Milliseconds()),
+		Version:       status.Version,
+		Motd:          status.Motd,
+		PlayersOnline: status.PlayersOnline,
+		PlayersMax:    status.PlayersMax,
+		Players:       status.Players,
+	})
+}
+
+// Выполняет команды через RCON-клиент игрового сервера. Клиент пересоздаётся,
+// если сменились порт или пароль
+func rconExec(configurationId string, port uint32, password string) func(ctx context.Context, command string) (string, error) {
+	return func(ctx context.Context, command string) (string, error) {
+		c := acquireConsole(configurationId, localAddr(port), password)
+		defer releaseConsole(c)
+
+		return c.client.Exec(ctx, command)
+	}
+}
+
+func acquireConsole(configurationId, addr, password string) *console {
+	consolesM.Lock()
+	defer consolesM.Unlock()
+
+	c := consoles[configurationId]
+	if c == nil || c.addr != addr || c.password != password {
+		if c != nil {
+			c.replaced = true
+			if c.users == 0 {
+				c.client.Close()
+			}
+		}
+		c = &console{addr: addr, password: password, client: rcon.New(addr, password, rcon.Options{})}
+		consoles[configurationId] = c
+	}
+	c.users++
+	return c
+}
+
+func releaseConsole(c *console) {
+	consolesM.Lock()
+	defer consolesM.Unlock()
+
+	c.users--
+	if c.replaced && c.users == 0 {
+		c.client.Close()
+	}
+}
+
+// Порты игровых серверов опубликованы контейнерами на хосте агента
+func localAddr(port uint32) string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port)))
+}
+
+func validPort(port uint32) bool {
+	return port != 0 && port <= math.MaxUint16
+}
+
+func streamLogs(ctx context.Context, req *api.LogsRequest, send func(*api.LogChunk) error) error {
+	if req.ConfigurationId == "" {
+		return errors.New("configuration id is required")
+	}
+
+	q := logs.Query{Tail: int(req.Tail), Follow: req.Follow}
+	if req.Since != nil {
+		q.Since = req.Since.AsTime()
+	}
+
+	return lm.Read(ctx, containerName(req.ConfigurationId), q, func(lines []logs.Line) error {
+		chunk := &api.LogChunk{Lines: make([]*api.LogLine, len(lines))}
+		for i, l := range lines {
+			chunk.Lines[i] = &api.LogLine{Time: timestamppb.New(l.Time), Text: l.Text}
+		}
+		return send(chunk)
+	})
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAcquireConsoleKeepsReplacedClientInUse(t *testing.T) {
+	old := acquireConsole("conf", "127.0.0.1:27015", "old")
+	defer releaseConsole(old)
+
+	if c := acquireConsole("conf", "127.0.0.1:27015", "old"); c != old {
+		t.Fatal("console was recreated without changes")
+	} else {
+		releaseConsole(c)
+	}
+
+	c := acquireConsole("conf", "127.0.0.1:27015", "new")
+	releaseConsole(c)
+	if c == old {
+		t.Fatal("console was not recreated after the password changed")
+	}
+
+	consolesM.Lock()
+	defer consolesM.Unlock()
+	if !old.replaced || old.users != 1 {
+		t.Errorf("replaced console: replaced %v, %d users, want it kept for the running command", old.replaced, old.users)
+	}
+	if consoles["conf"] != c || c.users != 0 {
+		t.Errorf("current console has %d users", c.users)
+	}
+}
+
+// Запускать с -race: замена клиента не должна гоняться с выполнением команд
+func TestAcquireConsoleConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			password := "even"
+			if i%2 == 1 {
+				password = "odd"
+			}
+			c := acquireConsole("concurrent", "127.0.0.1:25575", password)
+			if c.password != password {
+				t.Errorf("got console for %q, want %q", c.password, password)
+			}
+			releaseConsole(c)
+		}()
+	}
+	wg.Wait()
+
+	consolesM.Lock()
+	defer consolesM.Unlock()
+	if c := consoles["concurrent"]; c.users != 0 || c.replaced {
+		t.Errorf("current console: %d users, replaced %v", c.users, c.replaced)
+	}
+}
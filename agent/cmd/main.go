@@ -2,38 +2,87 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"log"
-	"math/rand"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/agent/internal/collector"
+	"github.com/vv-sam/otus-project/agent/internal/config"
+	"github.com/vv-sam/otus-project/agent/internal/connection"
+	"github.com/vv-sam/otus-project/agent/internal/control"
+	"github.com/vv-sam/otus-project/agent/internal/deploy"
+	"github.com/vv-sam/otus-project/agent/internal/enroll"
+	"github.com/vv-sam/otus-project/agent/internal/executor"
+	"github.com/vv-sam/otus-project/agent/internal/logs"
+	"github.com/vv-sam/otus-project/agent/internal/runtime"
+	"github.com/vv-sam/otus-project/agent/internal/transport"
+	"github.com/vv-sam/otus-project/agent/internal/usage"
 	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/metadata"
 )
 
 var (
-	login    = flag.String("login", "admin", "login")
-	password = flag.String("password", "1234", "password")
-
-	token string
-	au    api.AuthServiceClient
-	ac    api.AgentServiceClient
-	cc    api.ConfigurationServiceClient
-	tc    api.TaskServiceClient
+	configPath = flag.String("config", "agent.yaml", "path to agent config file")
+
+	cfg *config.Config
+	cm  *connection.Manager
+	ob  *connection.Outbox
+	au  api.AuthServiceClient
+	ac  api.AgentServiceClient
+	ctl api.AgentControlClient
+	cc  api.ConfigurationServiceClient
+	tc  api.TaskServiceClient
+	mc  *collector.Collector
+	rt  runtime.Runtime
+	lm  *logs.Manager
+	ut  *usage.Tracker
+	dp  *deploy.Deployer
 )
 
 func main() {
 	flag.Parse()
 
-	if *password == "" {
-		log.Fatal("password is required")
+	// Файл по умолчанию может отсутствовать, тогда используются значения по умолчанию и переменные окружения
+	explicit := false
+	flag.Visit(func(f *flag.Flag) { explicit = explicit || f.Name == "config" })
+
+	var err error
+	cfg, err = config.Load(*configPath, explicit)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		log.Fatalf("Failed to configure tls: %v", err)
+	}
+
+	switch cfg.Runtime.Name {
+	case "docker":
+		rt = runtime.NewDocker(cfg.Runtime.DockerSocket)
+	case "fake":
+		rt = runtime.NewFake()
 	}
 
+	if err := os.MkdirAll(cfg.LogsDir(), 0755); err != nil {
+		log.Fatalf("Failed to create logs dir: %v", err)
+	}
+	lm = logs.New(rt, logs.Options{
+		Dir:         cfg.LogsDir(),
+		BufferLines: int(cfg.Logs.BufferLines),
+		MaxFileSize: int64(cfg.Logs.MaxFileMb) << 20,
+		MaxFiles:    int(cfg.Logs.MaxFiles),
+	})
+	defer lm.Close()
+
 	log.Println("Agent is running...")
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
@@ -45,126 +94,220 @@ func main() {
 		stop()
 	}()
 
-	s, err := grpc.NewClient("localhost:8081", grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.Fatalf("Failed to connect: %v", err)
+	var s grpc.ClientConnInterface
+	switch cfg.Server.Transport {
+	case "grpc":
+		creds := insecure.NewCredentials()
+		if tlsConfig != nil {
+			creds = credentials.NewTLS(tlsConfig)
+		}
+
+		if cfg.Enrollment.JoinToken != "" && !cfg.HasClientCertificate() {
+			enrollAgent(ctx, creds)
+		}
+
+		conn, err := grpc.NewClient(cfg.Server.GrpcAddr, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			log.Fatalf("Failed to connect: %v", err)
+		}
+		s = conn
+	case "ws":
+		// Websocket-эндпоинт требует токен при подключении, поэтому логинимся через REST
+		hc := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}, Timeout: 30 * time.Second}
+		s = transport.NewWebsocket(transport.WebsocketUrl(cfg.Server.HttpAddr), tlsConfig, func(ctx context.Context) (string, error) {
+			return transport.Login(ctx, hc, cfg.Server.HttpAddr, cfg.Server.Login, cfg.Server.Password)
+		})
 	}
 
+	// Вызов Login не требует токена, поэтому идёт напрямую через транспорт.
+	// Агент с клиентским сертификатом входит по mTLS и токен не использует
 	au = api.NewAuthServiceClient(s)
-	ac = api.NewAgentServiceClient(s)
-	cc = api.NewConfigurationServiceClient(s)
-	tc = api.NewTaskServiceClient(s)
+	var login connection.LoginFunc
+	if !cfg.HasClientCertificate() {
+		login = func(ctx context.Context) (string, error) {
+			resp, err := au.Login(ctx, &api.LoginRequest{Username: cfg.Server.Login, Password: cfg.Server.Password})
+			if err != nil {
+				return "", err
+			}
+			return resp.Token, nil
+		}
+	}
+	cm = connection.New(s, login, connection.DefaultBackoff)
 
-	loginResponse, err := au.Login(ctx, &api.LoginRequest{
-		Username: *login,
-		Password: *password,
+	ac = api.NewAgentServiceClient(cm)
+	cc = api.NewConfigurationServiceClient(cm)
+	tc = api.NewTaskServiceClient(cm)
+	ctl = api.NewAgentControlClient(cm)
+
+	// id агента с сертификатом задан сертификатом
+	var agentId string
+	if cfg.HasClientCertificate() {
+		agentId, err = enroll.AgentId(cfg.TLS.Cert)
+	} else {
+		agentId, err = loadAgentId(cfg.IdFile)
+	}
+	if err != nil {
+		log.Fatalf("Failed to load agent id: %v", err)
+	}
+
+	mc = collector.New(collector.Options{DataDir: cfg.DataDir})
+	ut = usage.New(rt, usage.Options{Prefix: containerName(""), RevisionLabel: deploy.RevisionLabel})
+	dp = deploy.New(rt, deploy.Options{Prefix: containerName(""), DataDir: cfg.ServersDir(), StopTimeout: stopTimeout})
+
+	// Сервер может быть недоступен при старте агента: ждём его, а не завершаемся
+	var registerResponse *api.RegisterAgentResponse
+	err = cm.Retry(ctx, "register agent", func(ctx context.Context) error {
+		var err error
+		registerResponse, err = ac.Register(ctx, &api.RegisterAgentRequest{
+			AgentId: agentId,
+			Metrics: collectMetrics(),
+			Config:  agentConfig(),
+		})
+		return err
 	})
 	if err != nil {
-		log.Fatalf("Failed to login: %v", err)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Fatalf("Failed to register agent: %v", err)
 	}
+	log.Printf("registered agent with id: %v", registerResponse.AgentId)
+
+	ob = connection.NewOutbox(0, connection.DefaultBackoff, cm.IsTransient)
+	go ob.Run(ctx)
 
-	log.Printf("token: %v", loginResponse.Token)
+	go heartbeat(ctx, registerResponse.AgentId, time.Duration(registerResponse.HeartbeatIntervalSeconds)*time.Second)
 
-	token = loginResponse.Token
+	cl := control.New(control.GrpcDialer(ctl), registerResponse.AgentId, control.Options{})
+	registerCommands(cl)
+	go cl.Run(ctx)
 
-	agents, err := ac.GetAll(ctx, &api.GetAllAgentsRequest{})
+	e := executor.New(tc, registerResponse.AgentId, executor.Options{Outbox: ob})
+	e.Handle("factorio", serverTask)
+	e.Handle("minecraft", serverTask)
+	e.Run(ctx)
+}
+
+// Обменивает join-токен на клиентский сертификат. Отдельное подключение нужно,
+// потому что TLS-сессия, открытая без сертификата, не станет mTLS после его получения
+func enrollAgent(ctx context.Context, creds credentials.TransportCredentials) {
+	conn, err := grpc.NewClient(cfg.Server.GrpcAddr, grpc.WithTransportCredentials(creds))
 	if err != nil {
-		log.Fatalf("Failed to get agents: %v", err)
+		log.Fatalf("Failed to connect: %v", err)
 	}
-	log.Printf("current agents: %v", agents)
+	defer conn.Close()
 
-	configs, err := cc.GetAll(ctx, &api.GetAllConfigurationsRequest{})
+	var agentId string
+	err = connection.New(conn, nil, connection.DefaultBackoff).Retry(ctx, "enroll agent", func(ctx context.Context) error {
+		var err error
+		agentId, err = enroll.Enroll(ctx, api.NewEnrollmentServiceClient(conn), cfg.Enrollment.JoinToken, cfg.TLS.Cert, cfg.TLS.Key)
+		return err
+	})
 	if err != nil {
-		log.Fatalf("Failed to get configs: %v", err)
+		log.Fatalf("Failed to enroll agent: %v", err)
 	}
-	log.Printf("current configs: %v", configs)
 
-	tasks, err := tc.GetAll(ctx, &api.GetAllTasksRequest{})
-	if err != nil {
-		log.Fatalf("Failed to get tasks: %v", err)
+	if err := os.WriteFile(cfg.IdFile, []byte(agentId), 0600); err != nil {
+		log.Fatalf("Failed to save agent id: %v", err)
 	}
-	log.Printf("current tasks: %v", tasks)
+	log.Printf("enrolled as agent %s, join token is no longer needed", agentId)
+}
 
-	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
-	generateStructs(ctx)
+// Конфигурация, о которой агент сообщает серверу при регистрации
+func agentConfig() *api.AgentConfig {
+	return &api.AgentConfig{
+		Location: cfg.Location,
+		Labels:   cfg.Labels,
+		PortRange: &api.PortRange{
+			Min: uint32(cfg.Ports.Min),
+			Max: uint32(cfg.Ports.Max),
+		},
+		Capacity: &api.AgentCapacity{
+			CpuCores:    cfg.Capacity.CpuCores,
+			MemoryBytes: cfg.Capacity.MemoryMb << 20,
+			DiskBytes:   cfg.Capacity.DiskGb << 30,
+			MaxServers:  cfg.Capacity.MaxServers,
+		},
+		Transport: cfg.Server.Transport,
+		DataDir:   cfg.DataDir,
+		Runtime:   cfg.Runtime.Name,
+	}
+}
+
+// Читает id агента из файла, а при его отсутствии генерирует новый и сохраняет,
+// чтобы после перезапуска агент регистрировался под тем же id
+func loadAgentId(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		id, err := uuid.Parse(strings.TrimSpace(string(data)))
+		if err != nil {
+			return "", err
+		}
+		return id.String(), nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	id := uuid.New().String()
+	if err := os.WriteFile(path, []byte(id), 0600); err != nil {
+		return "", err
+	}
+	return id, nil
 }
 
-func generateStructs(ctx context.Context) {
+func heartbeat(ctx context.Context, agentId string, interval time.Duration) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("generateStructs is stopping...")
+			log.Println("heartbeat is stopping...")
 			return
-		case <-time.After(time.Second * 5):
-			r := rand.Int31n(4)
-			switch r {
-			case 0:
-				s := api.AgentInfo{
-					AgentId: uuid.New().String(),
-					Status:  0,
-				}
-				_, err := ac.Post(ctx, &api.PostAgentRequest{
-					Agent: &s,
-				})
-				if err != nil {
-					log.Printf("Failed to post agent: %v\n", err)
-					continue
-				}
-				log.Printf("posted agent with id: %v\n", s.AgentId)
-				r, err := ac.GetById(ctx, &api.GetAgentByIdRequest{
-					Id: s.AgentId,
-				})
-				if err != nil {
-					log.Printf("Failed to get agent: %v\n", err)
-					continue
-				}
-				log.Printf("got agent with id: %v\n", r.Agent.AgentId)
-			case 1:
-				s := api.FactorioConfig{
-					Base: &api.BaseConfig{
-						Id:      uuid.New().String(),
-						AgentId: uuid.New().String(),
-					},
-				}
-				_, err := cc.Post(ctx, &api.PostConfigurationRequest{
-					Configuration: &s,
-				})
-				if err != nil {
-					log.Printf("Failed to post configuration: %v\n", err)
-					continue
-				}
-				log.Printf("posted configuration with id: %v\n", s.Base.Id)
-				r, err := cc.GetById(ctx, &api.GetConfigByIdRequest{
-					Id: s.Base.Id,
-				})
-				if err != nil {
-					log.Printf("Failed to get configuration: %v\n", err)
-					continue
-				}
-				log.Printf("got configuration with id: %v\n", r.Configuration.Base.Id)
-			case 2:
-				s := api.Task{
-					Id:     uuid.New().String(),
-					Status: api.TaskStatus_TASK_STATUS_QUEUED,
-					Type:   "factorio",
-				}
-				_, err := tc.Post(ctx, &api.PostTaskRequest{
-					Task: &s,
-				})
-				if err != nil {
-					log.Printf("Failed to post task: %v\n", err)
-					continue
-				}
-				log.Printf("posted task with id: %v\n", s.Id)
-				r, err := tc.GetById(ctx, &api.GetTaskByIdRequest{
-					Id: s.Id,
-				})
-				if err != nil {
-					log.Printf("Failed to get task: %v\n", err)
-					continue
-				}
-				log.Printf("got task with id: %v\n", r.Task.Id)
+		case <-t.C:
+			// Состояние снимается сейчас, а доставляется, когда сервер будет доступен
+			req := &api.HeartbeatRequest{
+				AgentId: agentId,
+				Status:  agentStatus(ctx),
+				Metrics: collectMetrics(),
+				Servers: collectUsage(ctx),
 			}
+			ob.PushLatest("heartbeat", func(ctx context.Context) error {
+				_, err := ac.Heartbeat(ctx, req)
+				return err
+			})
 		}
 	}
 }
+
+// Без доступа к среде запуска агент не может управлять серверами и считается degraded
+func agentStatus(ctx context.Context) api.AgentStatus {
+	if err := rt.Ping(ctx); err != nil {
+		log.Printf("Container runtime is unavailable: %v\n", err)
+		return api.AgentStatus_AGENT_STATUS_DEGRADED
+	}
+	return api.AgentStatus_AGENT_STATUS_ONLINE
+}
+
+func collectMetrics() *api.HostMetrics {
+	m, err := mc.Collect()
+	if err != nil {
+		log.Printf("Failed to collect metrics: %v\n", err)
+		return nil
+	}
+	return m
+}
+
+func collectUsage(ctx context.Context) []*api.ServerUsage {
+	u, err := ut.Collect(ctx)
+	if err != nil {
+		log.Printf("Failed to collect server usage: %v\n", err)
+	}
+	return u
+}
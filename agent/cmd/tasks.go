@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Выполняет задачу над игровым сервером. Каждое действие приводит сервер
+// в конечное состояние, а не меняет его относительно текущего, поэтому
+// повторное выполнение задачи безопасно
+func serverTask(ctx context.Context, task *api.Task) (json.RawMessage, error) {
+	if task.ConfigurationId == "" {
+		return nil, errors.New("configuration id is required")
+	}
+	id := task.ConfigurationId
+	name := containerName(id)
+
+	var err error
+	started := false
+	switch task.Action {
+	case api.TaskAction_TASK_ACTION_DEPLOY:
+		var dep api.Deployment
+		if err := protojson.Unmarshal(task.Payload, &dep); err != nil {
+			return nil, fmt.Errorf("failed to parse deployment: %w", err)
+		}
+		err = dp.Deploy(ctx, id, &dep)
+		started = dep.Start
+	case api.TaskAction_TASK_ACTION_START:
+		err = rt.Start(ctx, name)
+		started = true
+	case api.TaskAction_TASK_ACTION_STOP:
+		err = rt.Stop(ctx, name, stopTimeout)
+	case api.TaskAction_TASK_ACTION_RESTART:
+		if err = rt.Stop(ctx, name, stopTimeout); err == nil {
+			err = rt.Start(ctx, name)
+		}
+		started = true
+	case api.TaskAction_TASK_ACTION_DELETE:
+		err = dp.Remove(ctx, id)
+	default:
+		return nil, fmt.Errorf("unsupported action %v", task.Action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if started {
+		if err := lm.Watch(name); err != nil {
+			log.Printf("Failed to watch logs of %s: %v\n", id, err)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+)
+
+// Невыполнимая задача должна завершиться ошибкой, а не отчитаться об успехе
+func TestServerTaskUnsupportedAction(t *testing.T) {
+	for _, action := range []api.TaskAction{api.TaskAction_TASK_ACTION_BACKUP, api.TaskAction(100)} {
+		task := &api.Task{Id: "task", Type: "minecraft", Action: action, ConfigurationId: "conf"}
+		if _, err := serverTask(context.Background(), task); err == nil {
+			t.Errorf("%v succeeded", action)
+		}
+	}
+}
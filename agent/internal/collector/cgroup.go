@@ -0,0 +1,38 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Читает лимит и текущее потребление памяти из cgroup v2.
+// ok == false, если cgroup недоступна или лимит не установлен.
+func readCgroupMemory(root string) (limit uint64, usage uint64, ok bool) {
+	limit, ok = readCgroupValue(filepath.Join(root, "memory.max"))
+	if !ok {
+		return 0, 0, false
+	}
+
+	usage, _ = readCgroupValue(filepath.Join(root, "memory.current"))
+	return limit, usage, true
+}
+
+func readCgroupValue(path string) (uint64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, false
+	}
+
+	v, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+)
+
+type Options struct {
+	ProcRoot   string // Корень procfs, по умолчанию /proc
+	CgroupRoot string // Корень cgroup v2, по умолчанию /sys/fs/cgroup
+	DataDir    string // Каталог с данными игровых серверов, для него считается место на диске
+}
+
+// Собирает метрики хоста из procfs и cgroup.
+// Загрузка CPU считается как разница между двумя последовательными замерами,
+// поэтому первый вызов Collect возвращает среднюю загрузку с момента старта хоста.
+type Collector struct {
+	opts Options
+
+	m       sync.Mutex
+	prevCpu cpuTimes
+}
+
+func New(opts Options) *Collector {
+	if opts.ProcRoot == "" {
+		opts.ProcRoot = "/proc"
+	}
+
+	if opts.CgroupRoot == "" {
+		opts.CgroupRoot = "/sys/fs/cgroup"
+	}
+
+	if opts.DataDir == "" {
+		opts.DataDir = "."
+	}
+
+	return &Collector{opts: opts}
+}
+
+func (c *Collector) Collect() (*api.HostMetrics, error) {
+	cpu, err := readCpuTimes(filepath.Join(c.opts.ProcRoot, "stat"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cpu times: %w", err)
+	}
+
+	mem, err := readMemInfo(filepath.Join(c.opts.ProcRoot, "meminfo"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read meminfo: %w", err)
+	}
+
+	// Внутри контейнера лимит памяти может быть меньше, чем видно в /proc/meminfo
+	if limit, usage, ok := readCgroupMemory(c.opts.CgroupRoot); ok && limit < mem.total {
+		mem.total = limit
+		mem.available = min(mem.available, limit-min(usage, limit))
+	}
+
+	load, err := readLoadAvg(filepath.Join(c.opts.ProcRoot, "loadavg"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loadavg: %w", err)
+	}
+
+	disk, err := readDiskUsage(c.opts.DataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read disk usage of %s: %w", c.opts.DataDir, err)
+	}
+
+	net, err := readNetDev(filepath.Join(c.opts.ProcRoot, "net", "dev"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read network counters: %w", err)
+	}
+
+	c.m.Lock()
+	usage := cpu.usageSince(c.prevCpu)
+	c.prevCpu = cpu
+	c.m.Unlock()
+
+	return &api.HostMetrics{
+		CpuUsage:      usage,
+		RamAvailable:  mem.available,
+		RamTotal:      mem.total,
+		LoadAvg_1:     load[0],
+		LoadAvg_5:     load[1],
+		LoadAvg_15:    load[2],
+		DiskTotal:     disk.total,
+		DiskAvailable: disk.available,
+		NetRxBytes:    net.rx,
+		NetTxBytes:    net.tx,
+	}, nil
+}
@@ -0,0 +1,178 @@
+package collector
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const KB = 1024
+
+// Копирует testdata/proc во временный каталог, чтобы тест мог подменять файлы
+func procRoot(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	err := os.CopyFS(root, os.DirFS("testdata/proc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func replace(t *testing.T, root, name, fixture string) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, name), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func near(got float32, want float32) bool {
+	return math.Abs(float64(got-want)) < 0.001
+}
+
+func newTestCollector(t *testing.T, root string) *Collector {
+	// Пустой каталог cgroup - лимита памяти нет
+	return New(Options{ProcRoot: root, CgroupRoot: t.TempDir(), DataDir: t.TempDir()})
+}
+
+func TestCollect(t *testing.T) {
+	m, err := newTestCollector(t, "testdata/proc").Collect()
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	// Первый замер - средняя загрузка с момента старта: 1500 из 10000 jiffies
+	if !near(m.CpuUsage, 15) {
+		t.Errorf("cpu usage = %v, want 15", m.CpuUsage)
+	}
+	if m.RamTotal != 16384000*KB || m.RamAvailable != 8192000*KB {
+		t.Errorf("ram = %d/%d, want %d/%d", m.RamAvailable, m.RamTotal, 8192000*KB, 16384000*KB)
+	}
+	if m.LoadAvg_1 != 0.52 || m.LoadAvg_5 != 0.58 || m.LoadAvg_15 != 0.59 {
+		t.Errorf("load average = %v %v %v", m.LoadAvg_1, m.LoadAvg_5, m.LoadAvg_15)
+	}
+	// lo не учитывается
+	if m.NetRxBytes != 1300 || m.NetTxBytes != 2400 {
+		t.Errorf("net = %d/%d, want 1300/2400", m.NetRxBytes, m.NetTxBytes)
+	}
+	if m.DiskTotal == 0 || m.DiskAvailable > m.DiskTotal {
+		t.Errorf("disk = %d/%d", m.DiskAvailable, m.DiskTotal)
+	}
+}
+
+func TestCollectCpuDelta(t *testing.T) {
+	root := procRoot(t)
+	c := newTestCollector(t, root)
+
+	if _, err := c.Collect(); err != nil {
+		t.Fatal(err)
+	}
+
+	// За интервал прошло 1500 jiffies, из них 600 простоя
+	replace(t, root, "stat", "stat.later")
+	m, err := c.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(m.CpuUsage, 60) {
+		t.Errorf("cpu usage = %v, want 60", m.CpuUsage)
+	}
+
+	// Счётчики не изменились
+	m, err = c.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.CpuUsage != 0 {
+		t.Errorf("cpu usage without new ticks = %v, want 0", m.CpuUsage)
+	}
+}
+
+func TestCollectMemInfoWithoutAvailable(t *testing.T) {
+	root := procRoot(t)
+	replace(t, root, "meminfo", "meminfo.old")
+
+	m, err := newTestCollector(t, root).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// MemFree + Buffers + Cached
+	if want := uint64(4200000 * KB); m.RamAvailable != want {
+		t.Errorf("ram available = %d, want %d", m.RamAvailable, want)
+	}
+}
+
+func TestCollectCgroupLimit(t *testing.T) {
+	c := New(Options{ProcRoot: "testdata/proc", CgroupRoot: "testdata/cgroup", DataDir: t.TempDir()})
+
+	m, err := c.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Лимит 8 GiB, из них занято 2 GiB
+	if m.RamTotal != 8<<30 || m.RamAvailable != 6<<30 {
+		t.Errorf("ram = %d/%d, want %d/%d", m.RamAvailable, m.RamTotal, uint64(6<<30), uint64(8<<30))
+	}
+}
+
+func TestCollectCgroupNoLimit(t *testing.T) {
+	cgroup := t.TempDir()
+	if err := os.WriteFile(filepath.Join(cgroup, "memory.max"), []byte("max\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(Options{ProcRoot: "testdata/proc", CgroupRoot: cgroup, DataDir: t.TempDir()})
+	m, err := c.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.RamTotal != 16384000*KB {
+		t.Errorf("ram total = %d, want %d", m.RamTotal, 16384000*KB)
+	}
+}
+
+func TestCollectParseErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		file    string
+		fixture string
+		err     string
+	}{
+		{"invalid cpu counter", "stat", "invalid/stat", `invalid cpu counter "x"`},
+		{"no cpu line", "stat", "invalid/stat.nocpu", "cpu line not found"},
+		{"invalid meminfo value", "meminfo", "invalid/meminfo", `invalid MemTotal value "lots"`},
+		{"no MemTotal", "meminfo", "invalid/meminfo.nototal", "MemTotal not found"},
+		{"short loadavg", "loadavg", "invalid/loadavg", "unexpected format"},
+		{"invalid loadavg", "loadavg", "invalid/loadavg.value", `invalid load average "abc"`},
+		{"short net/dev line", "net/dev", "invalid/net_dev", "unexpected format"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := procRoot(t)
+			replace(t, root, tc.file, tc.fixture)
+
+			_, err := newTestCollector(t, root).Collect()
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestCollectMissingProc(t *testing.T) {
+	_, err := newTestCollector(t, t.TempDir()).Collect()
+	if err == nil || !strings.Contains(err.Error(), "failed to read cpu times") {
+		t.Errorf("expected cpu times error, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+
+package collector
+
+import "errors"
+
+type diskUsage struct {
+	total     uint64
+	available uint64
+}
+
+func readDiskUsage(dir string) (diskUsage, error) {
+	return diskUsage{}, errors.New("disk usage is not supported on this platform")
+}
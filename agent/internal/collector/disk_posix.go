@@ -0,0 +1,22 @@
+//go:build linux || darwin
+
+package collector
+
+import "syscall"
+
+type diskUsage struct {
+	total     uint64
+	available uint64
+}
+
+func readDiskUsage(dir string) (diskUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return diskUsage{}, err
+	}
+
+	return diskUsage{
+		total:     st.Blocks * uint64(st.Bsize),
+		available: st.Bavail * uint64(st.Bsize),
+	}, nil
+}
@@ -0,0 +1,207 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Счётчики из строки "cpu" файла /proc/stat (в jiffies)
+type cpuTimes struct {
+	idle  uint64
+	total uint64
+}
+
+// Загрузка CPU в процентах между prev и t
+func (t cpuTimes) usageSince(prev cpuTimes) float32 {
+	if t.total <= prev.total {
+		return 0
+	}
+
+	total := t.total - prev.total
+	idle := t.idle - min(prev.idle, t.idle)
+	if idle > total {
+		return 0
+	}
+	return float32(total-idle) / float32(total) * 100
+}
+
+func readCpuTimes(path string) (cpuTimes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return cpuTimes{}, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+
+		// user nice system idle iowait irq softirq steal guest guest_nice,
+		// guest и guest_nice уже учтены в user и nice
+		var t cpuTimes
+		for i, f := range fields[1:min(len(fields), 9)] {
+			v, err := strconv.ParseUint(f, 10, 64)
+			if err != nil {
+				return cpuTimes{}, fmt.Errorf("invalid cpu counter %q: %w", f, err)
+			}
+			t.total += v
+			if i == 3 || i == 4 {
+				t.idle += v
+			}
+		}
+		return t, nil
+	}
+
+	if err := s.Err(); err != nil {
+		return cpuTimes{}, err
+	}
+	return cpuTimes{}, fmt.Errorf("cpu line not found in %s", path)
+}
+
+type memInfo struct {
+	total     uint64
+	available uint64
+}
+
+func readMemInfo(path string) (memInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return memInfo{}, err
+	}
+	defer f.Close()
+
+	var (
+		m                      memInfo
+		hasTotal, hasAvailable bool
+		free, buffers, cached  uint64
+	)
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		key, value, ok := strings.Cut(s.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+
+		v, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return memInfo{}, fmt.Errorf("invalid %s value %q: %w", key, fields[0], err)
+		}
+
+		// Значения в /proc/meminfo указаны в килобайтах
+		if len(fields) > 1 && fields[1] == "kB" {
+			v *= 1024
+		}
+
+		switch key {
+		case "MemTotal":
+			m.total, hasTotal = v, true
+		case "MemAvailable":
+			m.available, hasAvailable = v, true
+		case "MemFree":
+			free = v
+		case "Buffers":
+			buffers = v
+		case "Cached":
+			cached = v
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return memInfo{}, err
+	}
+
+	if !hasTotal {
+		return memInfo{}, fmt.Errorf("MemTotal not found in %s", path)
+	}
+
+	// Старые ядра (до 3.14) не отдают MemAvailable
+	if !hasAvailable {
+		m.available = min(free+buffers+cached, m.total)
+	}
+
+	return m, nil
+}
+
+func readLoadAvg(path string) ([3]float32, error) {
+	var load [3]float32
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return load, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return load, fmt.Errorf("unexpected format of %s", path)
+	}
+
+	for i := range load {
+		v, err := strconv.ParseFloat(fields[i], 32)
+		if err != nil {
+			return load, fmt.Errorf("invalid load average %q: %w", fields[i], err)
+		}
+		load[i] = float32(v)
+	}
+
+	return load, nil
+}
+
+type netCounters struct {
+	rx uint64
+	tx uint64
+}
+
+// Суммирует принятые и отправленные байты по всем интерфейсам, кроме loopback
+func readNetDev(path string) (netCounters, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return netCounters{}, err
+	}
+	defer f.Close()
+
+	var c netCounters
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		iface, value, ok := strings.Cut(s.Text(), ":")
+		if !ok {
+			// Первые две строки - заголовок таблицы
+			continue
+		}
+
+		if strings.TrimSpace(iface) == "lo" {
+			continue
+		}
+
+		fields := strings.Fields(value)
+		if len(fields) < 9 {
+			return netCounters{}, fmt.Errorf("unexpected format of %s", path)
+		}
+
+		rx, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return netCounters{}, fmt.Errorf("invalid rx bytes %q: %w", fields[0], err)
+		}
+
+		tx, err := strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			return netCounters{}, fmt.Errorf("invalid tx bytes %q: %w", fields[8], err)
+		}
+
+		c.rx += rx
+		c.tx += tx
+	}
+
+	return c, s.Err()
+}
@@ -0,0 +1,382 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vv-sam/otus-project/agent/internal/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+// Префикс переменных окружения, переопределяющих значения из файла
+const EnvPrefix = "OTUS_AGENT_"
+
+// Конфигурация агента. Значения читаются из YAML-файла, затем переопределяются
+// переменными окружения OTUS_AGENT_*, например OTUS_AGENT_SERVER_PASSWORD
+type Config struct {
+	Server     Server     `yaml:"server"`
+	TLS        TLS        `yaml:"tls"`
+	Enrollment Enrollment `yaml:"enrollment"`
+	Runtime    Runtime    `yaml:"runtime"`
+	Logs       Logs       `yaml:"logs"`
+
+	// Файл, в котором хранится id агента между перезапусками
+	IdFile string `yaml:"id_file"`
+
+	// Каталог с томами игровых серверов
+	DataDir string `yaml:"data_dir"`
+
+	Location string            `yaml:"location"`
+	Labels   map[string]string `yaml:"labels"`
+
+	// Порты, которые агент может отдавать игровым серверам
+	Ports    PortRange `yaml:"ports"`
+	Capacity Capacity  `yaml:"capacity"`
+}
+
+type Server struct {
+	Transport string `yaml:"transport"` // grpc или ws
+	GrpcAddr  string `yaml:"grpc_addr"`
+	HttpAddr  string `yaml:"http_addr"` // Используется транспортом ws
+	Login     string `yaml:"login"`
+	Password  string `yaml:"password"`
+}
+
+type TLS struct {
+	Enabled    bool   `yaml:"enabled"` // Включается автоматически, если задан ca или cert
+	CA         string `yaml:"ca"`
+	Cert       string `yaml:"cert"`
+	Key        string `yaml:"key"`
+	ServerName string `yaml:"server_name"`
+}
+
+// Получение клиентского сертификата по одноразовому join-токену. Если сертификата
+// tls.cert ещё нет, агент обменивает токен на сертификат и сохраняет его в tls.cert и tls.key
+type Enrollment struct {
+	JoinToken string `yaml:"join_token"`
+}
+
+type Runtime struct {
+	Name         string `yaml:"name"` // docker или fake
+	DockerSocket string `yaml:"docker_socket"`
+}
+
+// История вывода игровых серверов
+type Logs struct {
+	Dir         string `yaml:"dir"`          // По умолчанию data_dir/logs
+	BufferLines uint32 `yaml:"buffer_lines"` // Сколько последних строк держать в памяти
+	MaxFileMb   uint64 `yaml:"max_file_mb"`  // Размер файла, после которого он ротируется
+	MaxFiles    uint32 `yaml:"max_files"`    // Сколько ротированных файлов хранить
+}
+
+type PortRange struct {
+	Min uint16 `yaml:"min"`
+	Max uint16 `yaml:"max"`
+}
+
+// Ресурсы, которые агент готов отдать игровым серверам. Ноль - без ограничения
+type Capacity struct {
+	CpuCores   float64 `yaml:"cpu_cores"`
+	MemoryMb   uint64  `yaml:"memory_mb"`
+	DiskGb     uint64  `yaml:"disk_gb"`
+	MaxServers uint32  `yaml:"max_servers"`
+}
+
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Transport: "grpc",
+			GrpcAddr:  "localhost:8081",
+			HttpAddr:  "http://localhost:8080",
+			Login:     "admin",
+			Password:  "1234",
+		},
+		Runtime: Runtime{
+			Name:         "docker",
+			DockerSocket: runtime.DefaultDockerSocket,
+		},
+		Logs: Logs{
+			BufferLines: 1000,
+			MaxFileMb:   10,
+			MaxFiles:    5,
+		},
+		IdFile:  "agent.id",
+		DataDir: ".",
+		Ports:   PortRange{Min: 27000, Max: 27999},
+	}
+}
+
+// Читает конфигурацию из path поверх значений по умолчанию и применяет переменные окружения.
+// Если required == false, отсутствующий файл не считается ошибкой
+func Load(path string, required bool) (*Config, error) {
+	c := Default()
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && !required:
+	default:
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := c.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return c, nil
+}
+
+func (c *Config) Validate() error {
+	switch c.Server.Transport {
+	case "grpc":
+		if c.Server.GrpcAddr == "" {
+			return errors.New("server.grpc_addr is required for grpc transport")
+		}
+	case "ws":
+		u, err := url.Parse(c.Server.HttpAddr)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("server.http_addr %q must be an http(s) url", c.Server.HttpAddr)
+		}
+	default:
+		return fmt.Errorf("unknown server.transport %q", c.Server.Transport)
+	}
+
+	// Агент с клиентским сертификатом входит без пароля
+	if c.TLS.Cert == "" && (c.Server.Login == "" || c.Server.Password == "") {
+		return errors.New("server.login and server.password are required")
+	}
+
+	if (c.TLS.Cert == "") != (c.TLS.Key == "") {
+		return errors.New("tls.cert and tls.key must be set together")
+	}
+
+	// Websocket-эндпоинт авторизует вызовы только токеном, сертификат агента туда не доходит
+	if c.TLS.Cert != "" && c.Server.Transport != "grpc" {
+		return errors.New("tls.cert requires grpc transport: the websocket transport authenticates by server.login and server.password")
+	}
+
+	// До регистрации по join-токену сертификата и ключа ещё нет
+	files := []string{c.TLS.CA, c.TLS.Cert, c.TLS.Key}
+	if c.Enrollment.JoinToken != "" {
+		if c.TLS.Cert == "" || c.TLS.CA == "" {
+			return errors.New("enrollment requires tls.ca, tls.cert and tls.key")
+		}
+		if c.Server.Transport != "grpc" {
+			return errors.New("enrollment requires grpc transport")
+		}
+		files = files[:1]
+	}
+	for _, f := range files {
+		if f == "" {
+			continue
+		}
+		if _, err := os.Stat(f); err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+	}
+
+	switch c.Runtime.Name {
+	case "docker", "fake":
+	default:
+		return fmt.Errorf("unknown runtime.name %q", c.Runtime.Name)
+	}
+
+	if c.IdFile == "" {
+		return errors.New("id_file is required")
+	}
+
+	info, err := os.Stat(c.DataDir)
+	if err != nil {
+		return fmt.Errorf("data_dir: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("data_dir %s is not a directory", c.DataDir)
+	}
+
+	if c.Logs.MaxFileMb == 0 {
+		return errors.New("logs.max_file_mb must be positive")
+	}
+
+	if c.Ports.Min == 0 || c.Ports.Min > c.Ports.Max {
+		return fmt.Errorf("ports: invalid range %d-%d", c.Ports.Min, c.Ports.Max)
+	}
+
+	if c.Capacity.CpuCores < 0 {
+		return errors.New("capacity.cpu_cores must not be negative")
+	}
+
+	return nil
+}
+
+// Настройки TLS для подключения к серверу. nil, если TLS не используется
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	if !c.TLS.Enabled && c.TLS.CA == "" && c.TLS.Cert == "" {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{ServerName: c.TLS.ServerName, MinVersion: tls.VersionTLS12}
+
+	if c.TLS.CA != "" {
+		pem, err := os.ReadFile(c.TLS.CA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.TLS.CA)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if c.TLS.Cert != "" {
+		// Сертификат читается при каждом подключении: он может появиться после регистрации по join-токену
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if !c.HasClientCertificate() {
+				return &tls.Certificate{}, nil
+			}
+			cert, err := tls.LoadX509KeyPair(c.TLS.Cert, c.TLS.Key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			return &cert, nil
+		}
+	}
+
+	return cfg, nil
+}
+
+// Каталог истории вывода игровых серверов
+func (c *Config) LogsDir() string {
+	if c.Logs.Dir != "" {
+		return c.Logs.Dir
+	}
+	return filepath.Join(c.DataDir, "logs")
+}
+
+// Каталоги данных игровых серверов
+func (c *Config) ServersDir() string {
+	return filepath.Join(c.DataDir, "servers")
+}
+
+// Есть ли у агента клиентский сертификат
+func (c *Config) HasClientCertificate() bool {
+	if c.TLS.Cert == "" {
+		return false
+	}
+	_, err := os.Stat(c.TLS.Cert)
+	return err == nil
+}
+
+// Переменные окружения и поля, которые они переопределяют
+func (c *Config) envFields() map[string]any {
+	return map[string]any{
+		"SERVER_TRANSPORT":      &c.Server.Transport,
+		"SERVER_GRPC_ADDR":      &c.Server.GrpcAddr,
+		"SERVER_HTTP_ADDR":      &c.Server.HttpAddr,
+		"SERVER_LOGIN":          &c.Server.Login,
+		"SERVER_PASSWORD":       &c.Server.Password,
+		"TLS_ENABLED":           &c.TLS.Enabled,
+		"TLS_CA":                &c.TLS.CA,
+		"TLS_CERT":              &c.TLS.Cert,
+		"TLS_KEY":               &c.TLS.Key,
+		"TLS_SERVER_NAME":       &c.TLS.ServerName,
+		"ENROLLMENT_JOIN_TOKEN": &c.Enrollment.JoinToken,
+		"RUNTIME_NAME":          &c.Runtime.Name,
+		"RUNTIME_DOCKER_SOCKET": &c.Runtime.DockerSocket,
+		"LOGS_DIR":              &c.Logs.Dir,
+		"LOGS_BUFFER_LINES":     &c.Logs.BufferLines,
+		"LOGS_MAX_FILE_MB":      &c.Logs.MaxFileMb,
+		"LOGS_MAX_FILES":        &c.Logs.MaxFiles,
+		"ID_FILE":               &c.IdFile,
+		"DATA_DIR":              &c.DataDir,
+		"LOCATION":              &c.Location,
+		"LABELS":                &c.Labels,
+		"PORTS_MIN":             &c.Ports.Min,
+		"PORTS_MAX":             &c.Ports.Max,
+		"CAPACITY_CPU_CORES":    &c.Capacity.CpuCores,
+		"CAPACITY_MEMORY_MB":    &c.Capacity.MemoryMb,
+		"CAPACITY_DISK_GB":      &c.Capacity.DiskGb,
+		"CAPACITY_MAX_SERVERS":  &c.Capacity.MaxServers,
+	}
+}
+
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	for name, field := range c.envFields() {
+		value, ok := lookup(EnvPrefix + name)
+		if !ok {
+			continue
+		}
+
+		if err := setField(field, value); err != nil {
+			return fmt.Errorf("invalid %s%s: %w", EnvPrefix, name, err)
+		}
+	}
+	return nil
+}
+
+func setField(field any, value string) error {
+	switch f := field.(type) {
+	case *string:
+		*f = value
+	case *bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*f = v
+	case *uint16:
+		v, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return err
+		}
+		*f = uint16(v)
+	case *uint32:
+		v, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return err
+		}
+		*f = uint32(v)
+	case *uint64:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		*f = v
+	case *float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*f = v
+	case *map[string]string:
+		// Метки задаются списком key=value через запятую
+		labels := make(map[string]string)
+		for _, pair := range strings.Split(value, ",") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("label %q must be key=value", pair)
+			}
+			labels[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+		*f = labels
+	default:
+		return fmt.Errorf("unsupported field type %T", field)
+	}
+	return nil
+}
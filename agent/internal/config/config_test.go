@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestValidateClientCertificateTransport(t *testing.T) {
+	tests := []struct {
+		transport string
+		ok        bool
+	}{
+		{"grpc", true},
+		{"ws", false},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.transport, func(t *testing.T) {
+			c := Default()
+			c.DataDir = dir
+			c.Server.Transport = tt.transport
+			c.Server.Login, c.Server.Password = "", ""
+			c.TLS.Cert, c.TLS.Key = dir, dir
+
+			if err := c.Validate(); (err == nil) != tt.ok {
+				t.Errorf("got %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package connection
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Экспоненциальная пауза между попытками со случайным разбросом, чтобы агенты,
+// потерявшие связь одновременно, не переподключались к серверу все разом
+type Backoff struct {
+	Min    time.Duration // Пауза перед первой повторной попыткой
+	Max    time.Duration // Максимальная пауза
+	Jitter float64       // Доля паузы, на которую она случайно уменьшается, от 0 до 1
+}
+
+var DefaultBackoff = Backoff{Min: time.Second, Max: 30 * time.Second, Jitter: 0.5}
+
+// Пауза перед попыткой с номером attempt, начиная с нуля
+func (b Backoff) Delay(attempt int) time.Duration {
+	if b.Min <= 0 {
+		b.Min = DefaultBackoff.Min
+	}
+	if b.Max < b.Min {
+		b.Max = b.Min
+	}
+
+	d := b.Min
+	for i := 0; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	d = min(d, b.Max)
+
+	if b.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * min(b.Jitter, 1) * float64(d))
+	}
+	return d
+}
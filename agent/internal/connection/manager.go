@@ -0,0 +1,166 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Получает новый токен сервера
+type LoginFunc func(ctx context.Context) (string, error)
+
+// Подключение агента к серверу. Реализует grpc.ClientConnInterface поверх
+// транспорта: подставляет токен в каждый вызов, а когда сервер отвечает
+// Unauthenticated - логинится заново и повторяет вызов. Без LoginFunc вызовы
+// идут без токена, например когда агент входит по клиентскому сертификату
+type Manager struct {
+	conn    grpc.ClientConnInterface
+	login   LoginFunc
+	backoff Backoff
+
+	m     sync.Mutex
+	token string
+}
+
+func New(conn grpc.ClientConnInterface, login LoginFunc, backoff Backoff) *Manager {
+	return &Manager{conn: conn, login: login, backoff: backoff}
+}
+
+func (m *Manager) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	for retried := false; ; retried = true {
+		token, err := m.Token(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = m.conn.Invoke(withToken(ctx, token), method, args, reply, opts...)
+		if status.Code(err) != codes.Unauthenticated || retried || m.login == nil {
+			return err
+		}
+
+		// Токен истёк или сервер перезапустился с другим ключом
+		m.invalidate(token)
+	}
+}
+
+func (m *Manager) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	token, err := m.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := m.conn.NewStream(withToken(ctx, token), desc, method, opts...)
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			m.invalidate(token)
+		}
+		return nil, err
+	}
+
+	return &stream{ClientStream: s, m: m, token: token}, nil
+}
+
+// Текущий токен. Если его нет, выполняет вход
+func (m *Manager) Token(ctx context.Context) (string, error) {
+	if m.login == nil {
+		return "", nil
+	}
+
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	if m.token != "" {
+		return m.token, nil
+	}
+
+	token, err := m.login(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	log.Println("Logged in to the server")
+	m.token = token
+	return token, nil
+}
+
+// Выполняет fn, пока она не завершится успешно или с постоянной ошибкой.
+// Между попытками выдерживается пауза из Backoff
+func (m *Manager) Retry(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || !m.IsTransient(err) {
+			return err
+		}
+
+		delay := m.backoff.Delay(attempt)
+		log.Printf("Failed to %s: %v, retrying in %v\n", op, err, delay.Round(time.Millisecond))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Сбрасывает токен, если его ещё не обновил другой вызов
+func (m *Manager) invalidate(token string) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	if m.token == token {
+		m.token = ""
+	}
+}
+
+// Ошибки, после которых вызов имеет смысл повторить: сервер недоступен или перегружен
+func IsTransient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// Как IsTransient, но Unauthenticated тоже повторяется, если агент может войти
+// заново. Без LoginFunc агента отверг сертификат, и повтор ничего не изменит
+func (m *Manager) IsTransient(err error) bool {
+	if m.login != nil && status.Code(err) == codes.Unauthenticated {
+		return true
+	}
+	return IsTransient(err)
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// Поток, сбрасывающий токен, если сервер отверг его посреди потока
+type stream struct {
+	grpc.ClientStream
+	m     *Manager
+	token string
+}
+
+func (s *stream) RecvMsg(msg any) error {
+	err := s.ClientStream.RecvMsg(msg)
+	if status.Code(err) == codes.Unauthenticated {
+		s.m.invalidate(s.token)
+	}
+	return err
+}
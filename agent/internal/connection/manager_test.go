@@ -0,0 +1,54 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryUnauthenticated(t *testing.T) {
+	unauthenticated := status.Error(codes.Unauthenticated, "invalid token")
+
+	// Без входа по паролю повтор ничего не изменит
+	calls := 0
+	err := New(nil, nil, testBackoff).Retry(context.Background(), "test", func(ctx context.Context) error {
+		calls++
+		return unauthenticated
+	})
+	if status.Code(err) != codes.Unauthenticated || calls != 1 {
+		t.Errorf("got %v after %d calls, want Unauthenticated after 1", err, calls)
+	}
+
+	login := func(ctx context.Context) (string, error) { return "token", nil }
+	calls = 0
+	err = New(nil, login, testBackoff).Retry(context.Background(), "test", func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return unauthenticated
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("got %v after %d calls, want success after 2", err, calls)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	cases := map[error]bool{
+		status.Error(codes.Unavailable, ""):       true,
+		status.Error(codes.ResourceExhausted, ""): true,
+		context.DeadlineExceeded:                  true,
+		status.Error(codes.Unauthenticated, ""):   false,
+		status.Error(codes.InvalidArgument, ""):   false,
+		errors.New("failure"):                     false,
+	}
+
+	for err, want := range cases {
+		if got := IsTransient(err); got != want {
+			t.Errorf("IsTransient(%v) = %v, want %v", err, got, want)
+		}
+	}
+}
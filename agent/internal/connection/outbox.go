@@ -0,0 +1,169 @@
+package connection
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Сколько сообщений копится, пока сервер недоступен, если размер не задан
+const DefaultOutboxSize = 256
+
+// Очередь сообщений серверу, которые нельзя потерять при обрыве связи:
+// heartbeat'ы и результаты задач. Сообщения доставляются по порядку, при
+// временной ошибке доставка повторяется. Из сообщений, добавленных через
+// PushLatest, в очереди остаётся только последнее с таким именем. При
+// переполнении сначала отбрасываются они, затем самые старые из остальных
+type Outbox struct {
+	size      int
+	backoff   Backoff
+	transient func(err error) bool
+
+	m      sync.Mutex
+	queue  []outboxMessage
+	seq    uint64
+	notify chan struct{}
+}
+
+type outboxMessage struct {
+	seq     uint64
+	name    string
+	latest  bool
+	deliver func(ctx context.Context) error
+}
+
+// transient определяет, какие ошибки доставки повторять, по умолчанию IsTransient
+func NewOutbox(size int, backoff Backoff, transient func(err error) bool) *Outbox {
+	if size <= 0 {
+		size = DefaultOutboxSize
+	}
+
+	if transient == nil {
+		transient = IsTransient
+	}
+
+	return &Outbox{size: size, backoff: backoff, transient: transient, notify: make(chan struct{}, 1)}
+}
+
+// Ставит сообщение в очередь на доставку. name используется только в логах
+func (o *Outbox) Push(name string, deliver func(ctx context.Context) error) {
+	o.push(outboxMessage{name: name, deliver: deliver})
+}
+
+// Ставит в очередь сообщение, которое заменяет недоставленные сообщения с тем
+// же именем, например heartbeat: серверу нужно только последнее состояние
+func (o *Outbox) PushLatest(name string, deliver func(ctx context.Context) error) {
+	o.push(outboxMessage{name: name, latest: true, deliver: deliver})
+}
+
+func (o *Outbox) push(msg outboxMessage) {
+	o.m.Lock()
+	if msg.latest {
+		o.queue = slices.DeleteFunc(o.queue, func(m outboxMessage) bool {
+			return m.latest && m.name == msg.name
+		})
+	}
+
+	if len(o.queue) >= o.size {
+		// Заменяемые сообщения вытесняются первыми и никогда не вытесняют остальные
+		i := slices.IndexFunc(o.queue, func(m outboxMessage) bool { return m.latest })
+		switch {
+		case i >= 0:
+			log.Printf("Outbox is full, dropping %s\n", o.queue[i].name)
+			o.queue = slices.Delete(o.queue, i, i+1)
+		case msg.latest:
+			log.Printf("Outbox is full, dropping %s\n", msg.name)
+			o.m.Unlock()
+			return
+		default:
+			log.Printf("Outbox is full, dropping %s\n", o.queue[0].name)
+			o.queue = o.queue[1:]
+		}
+	}
+
+	o.seq++
+	msg.seq = o.seq
+	o.queue = append(o.queue, msg)
+	o.m.Unlock()
+
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Количество недоставленных сообщений
+func (o *Outbox) Len() int {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	return len(o.queue)
+}
+
+// Доставляет сообщения, пока не отменён ctx
+func (o *Outbox) Run(ctx context.Context) {
+	attempt := 0
+	for {
+		msg, ok := o.peek()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-o.notify:
+				continue
+			}
+		}
+
+		err := msg.deliver(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil && o.transient(err) {
+			delay := o.backoff.Delay(attempt)
+			attempt++
+			if attempt == 1 {
+				log.Printf("Failed to deliver %s: %v, buffering until the server is reachable\n", msg.name, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			continue
+		}
+
+		if err != nil {
+			log.Printf("Failed to deliver %s: %v, dropping\n", msg.name, err)
+		} else if attempt > 0 {
+			log.Printf("Connection to the server restored, %d messages left in outbox\n", o.Len()-1)
+		}
+
+		attempt = 0
+		o.pop(msg)
+	}
+}
+
+func (o *Outbox) peek() (outboxMessage, bool) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if len(o.queue) == 0 {
+		return outboxMessage{}, false
+	}
+	return o.queue[0], true
+}
+
+// Убирает доставленное сообщение, если его ещё не вытеснило переполнение
+// или более новое сообщение с тем же именем
+func (o *Outbox) pop(msg outboxMessage) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if len(o.queue) > 0 && o.queue[0].seq == msg.seq {
+		o.queue = o.queue[1:]
+	}
+}
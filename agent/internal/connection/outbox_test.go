@@ -0,0 +1,153 @@
+package connection
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testBackoff = Backoff{Min: time.Millisecond, Max: time.Millisecond}
+
+func nop(ctx context.Context) error { return nil }
+
+// Имена недоставленных сообщений по порядку
+func queued(o *Outbox) []string {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	var names []string
+	for _, msg := range o.queue {
+		names = append(names, msg.name)
+	}
+	return names
+}
+
+func TestOutboxKeepsLatestHeartbeat(t *testing.T) {
+	o := NewOutbox(10, testBackoff, nil)
+
+	delivered := ""
+	for _, n := range []string{"1", "2", "3"} {
+		o.PushLatest("heartbeat", func(ctx context.Context) error {
+			delivered = n
+			return nil
+		})
+		o.Push("result "+n, nop)
+	}
+
+	want := []string{"result 1", "result 2", "heartbeat", "result 3"}
+	if got := queued(o); !slices.Equal(got, want) {
+		t.Fatalf("queue = %v, want %v", got, want)
+	}
+
+	msg, _ := o.peek()
+	for msg.name != "heartbeat" {
+		o.pop(msg)
+		msg, _ = o.peek()
+	}
+	msg.deliver(context.Background())
+	if delivered != "3" {
+		t.Errorf("delivered heartbeat %s, want the latest", delivered)
+	}
+}
+
+func TestOutboxOverflowDropsHeartbeatFirst(t *testing.T) {
+	o := NewOutbox(3, testBackoff, nil)
+
+	o.Push("result 1", nop)
+	o.PushLatest("heartbeat", nop)
+	o.Push("result 2", nop)
+	o.Push("result 3", nop)
+
+	want := []string{"result 1", "result 2", "result 3"}
+	if got := queued(o); !slices.Equal(got, want) {
+		t.Fatalf("queue = %v, want %v", got, want)
+	}
+
+	// Heartbeat не вытесняет результаты
+	o.PushLatest("heartbeat", nop)
+	if got := queued(o); !slices.Equal(got, want) {
+		t.Fatalf("queue = %v, want %v", got, want)
+	}
+
+	// Без heartbeat'ов вытесняется самый старый результат
+	o.Push("result 4", nop)
+	want = []string{"result 2", "result 3", "result 4"}
+	if got := queued(o); !slices.Equal(got, want) {
+		t.Fatalf("queue = %v, want %v", got, want)
+	}
+}
+
+func TestOutboxRun(t *testing.T) {
+	var (
+		m         sync.Mutex
+		delivered []string
+		failures  = 2
+	)
+	deliver := func(name string, err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			m.Lock()
+			defer m.Unlock()
+
+			if err == nil && name == "result 1" && failures > 0 {
+				failures--
+				return status.Error(codes.Unavailable, "server is down")
+			}
+			delivered = append(delivered, name)
+			return err
+		}
+	}
+
+	o := NewOutbox(10, testBackoff, nil)
+	o.Push("result 1", deliver("result 1", nil))
+	o.Push("rejected", deliver("rejected", status.Error(codes.InvalidArgument, "bad request")))
+	o.Push("result 2", deliver("result 2", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go o.Run(ctx)
+
+	deadline := time.Now().Add(time.Second)
+	for o.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	// Временная ошибка повторяется, постоянная - отбрасывает сообщение
+	want := []string{"result 1", "rejected", "result 2"}
+	if !slices.Equal(delivered, want) || failures != 0 {
+		t.Errorf("delivered %v with %d failures left, want %v", delivered, failures, want)
+	}
+}
+
+func TestOutboxRunUsesTransient(t *testing.T) {
+	calls := 0
+	o := NewOutbox(10, testBackoff, func(err error) bool { return false })
+	o.Push("heartbeat", func(ctx context.Context) error {
+		calls++
+		return status.Error(codes.Unavailable, "server is down")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go o.Run(ctx)
+
+	deadline := time.Now().Add(time.Second)
+	for o.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if o.Len() != 0 {
+		t.Fatal("message was not dropped after a permanent error")
+	}
+	cancel()
+
+	if calls != 1 {
+		t.Errorf("delivered %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,259 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vv-sam/otus-project/agent/internal/connection"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/grpc"
+)
+
+// Обработчик команды сервера. Возвращённые данные отправляются серверу как вывод команды
+type Handler func(ctx context.Context, cmd *api.Command) ([]byte, error)
+
+// Обработчик запроса вывода игрового сервера. Порции вывода отправляются серверу через send
+type LogsHandler func(ctx context.Context, req *api.LogsRequest, send func(*api.LogChunk) error) error
+
+// Поток канала управления со стороны агента
+type Stream interface {
+	Send(msg *api.AgentMessage) error
+	Recv() (*api.ServerMessage, error)
+}
+
+// Открывает новый поток канала управления
+type Dialer func(ctx context.Context) (Stream, error)
+
+type Options struct {
+	MinReconnectDelay time.Duration // Пауза перед первой попыткой переподключения
+	MaxReconnectDelay time.Duration // Максимальная пауза между попытками
+}
+
+// Держит открытым канал управления с сервером и выполняет присланные команды
+type Client struct {
+	dial    Dialer
+	agentId string
+	opts    Options
+
+	m        sync.RWMutex
+	handlers map[api.CommandType]Handler
+	logs     LogsHandler
+}
+
+// Dialer поверх gRPC-клиента AgentControl
+func GrpcDialer(client api.AgentControlClient, opts ...grpc.CallOption) Dialer {
+	return func(ctx context.Context) (Stream, error) {
+		return client.Connect(ctx, opts...)
+	}
+}
+
+func New(dial Dialer, agentId string, opts Options) *Client {
+	if opts.MinReconnectDelay <= 0 {
+		opts.MinReconnectDelay = time.Second
+	}
+
+	if opts.MaxReconnectDelay <= 0 {
+		opts.MaxReconnectDelay = 30 * time.Second
+	}
+
+	return &Client{
+		dial:     dial,
+		agentId:  agentId,
+		opts:     opts,
+		handlers: make(map[api.CommandType]Handler),
+	}
+}
+
+// Регистрирует обработчик для команд типа t
+func (c *Client) Handle(t api.CommandType, h Handler) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.handlers[t] = h
+}
+
+// Регистрирует обработчик запросов вывода
+func (c *Client) HandleLogs(h LogsHandler) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.logs = h
+}
+
+// Подключается к серверу и переподключается при обрыве потока, пока не отменён ctx
+func (c *Client) Run(ctx context.Context) {
+	backoff := connection.Backoff{
+		Min:    c.opts.MinReconnectDelay,
+		Max:    c.opts.MaxReconnectDelay,
+		Jitter: connection.DefaultBackoff.Jitter,
+	}
+
+	attempt := 0
+	for {
+		started := time.Now()
+		err := c.serve(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Поток, проживший дольше максимальной паузы, считаем успешным и начинаем отсчёт заново
+		if time.Since(started) > c.opts.MaxReconnectDelay {
+			attempt = 0
+		}
+
+		delay := backoff.Delay(attempt)
+		log.Printf("Control channel is down: %v, reconnecting in %v\n", err, delay.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		attempt++
+	}
+}
+
+func (c *Client) serve(ctx context.Context) error {
+	// Обрыв потока не прерывает выполняющиеся команды, чтобы сбой связи не оставил
+	// сервер наполовину запущенным или остановленным. Их результат просто теряется
+	cmdCtx := ctx
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	var sendM sync.Mutex
+	send := func(msg *api.AgentMessage) error {
+		sendM.Lock()
+		defer sendM.Unlock()
+		return stream.Send(msg)
+	}
+
+	err = send(&api.AgentMessage{
+		Body: &api.AgentMessage_Hello{Hello: &api.AgentHello{AgentId: c.agentId}},
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("Control channel is connected")
+
+	// Потоки вывода, в отличие от команд, привязаны к потоку канала управления
+	// и могут быть отменены сервером
+	var streamsM sync.Mutex
+	streams := make(map[string]context.CancelFunc)
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		switch body := msg.Body.(type) {
+		case *api.ServerMessage_Command:
+			err = send(&api.AgentMessage{
+				RequestId: msg.RequestId,
+				Body:      &api.AgentMessage_Ack{Ack: &api.CommandAck{}},
+			})
+			if err != nil {
+				return err
+			}
+
+			go func() {
+				res := c.execute(cmdCtx, body.Command)
+				err := send(&api.AgentMessage{
+					RequestId: msg.RequestId,
+					Body:      &api.AgentMessage_Result{Result: res},
+				})
+				if err != nil {
+					log.Printf("Failed to send result of command %s: %v\n", msg.RequestId, err)
+				}
+			}()
+		case *api.ServerMessage_Logs:
+			logsCtx, cancel := context.WithCancel(ctx)
+			streamsM.Lock()
+			streams[msg.RequestId] = cancel
+			streamsM.Unlock()
+
+			go func() {
+				defer func() {
+					streamsM.Lock()
+					delete(streams, msg.RequestId)
+					streamsM.Unlock()
+					cancel()
+				}()
+
+				res := c.streamLogs(logsCtx, msg.RequestId, body.Logs, send)
+				if ctx.Err() != nil {
+					return
+				}
+				err := send(&api.AgentMessage{
+					RequestId: msg.RequestId,
+					Body:      &api.AgentMessage_Result{Result: res},
+				})
+				if err != nil {
+					log.Printf("Failed to finish log stream %s: %v\n", msg.RequestId, err)
+				}
+			}()
+		case *api.ServerMessage_Cancel:
+			streamsM.Lock()
+			cancel, ok := streams[msg.RequestId]
+			streamsM.Unlock()
+			if ok {
+				cancel()
+			}
+		}
+	}
+}
+
+func (c *Client) streamLogs(ctx context.Context, requestId string, req *api.LogsRequest, send func(*api.AgentMessage) error) *api.CommandResult {
+	c.m.RLock()
+	h := c.logs
+	c.m.RUnlock()
+
+	if h == nil {
+		return &api.CommandResult{Error: "logs are not supported"}
+	}
+
+	err := h(ctx, req, func(chunk *api.LogChunk) error {
+		return send(&api.AgentMessage{
+			RequestId: requestId,
+			Body:      &api.AgentMessage_Logs{Logs: chunk},
+		})
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return &api.CommandResult{Error: err.Error()}
+	}
+
+	return &api.CommandResult{Ok: true}
+}
+
+func (c *Client) execute(ctx context.Context, cmd *api.Command) *api.CommandResult {
+	c.m.RLock()
+	h, ok := c.handlers[cmd.Type]
+	c.m.RUnlock()
+
+	if !ok {
+		return &api.CommandResult{Error: fmt.Sprintf("command %v is not supported", cmd.Type)}
+	}
+
+	if cmd.Deadline != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, cmd.Deadline.AsTime())
+		defer cancel()
+	}
+
+	output, err := h(ctx, cmd)
+	if err != nil {
+		return &api.CommandResult{Output: output, Error: err.Error()}
+	}
+
+	return &api.CommandResult{Ok: true, Output: output}
+}
@@ -0,0 +1,156 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vv-sam/otus-project/agent/internal/runtime"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+)
+
+// Метка контейнера с ревизией развёрнутой конфигурации
+const RevisionLabel = "otus.revision"
+
+type Options struct {
+	Prefix      string        // Префикс имён контейнеров игровых серверов
+	DataDir     string        // Каталоги данных серверов: <DataDir>/<id конфигурации>
+	StopTimeout time.Duration // Сколько ждать остановки сервера перед удалением
+}
+
+// Разворачивает игровые серверы по описанию из задачи deploy. Повторное
+// развёртывание той же ревизии не пересоздаёт контейнер, поэтому задачу
+// можно безопасно выполнить ещё раз, например после перезапуска агента
+type Deployer struct {
+	rt   runtime.Runtime
+	opts Options
+}
+
+func New(rt runtime.Runtime, opts Options) *Deployer {
+	if opts.StopTimeout <= 0 {
+		opts.StopTimeout = 30 * time.Second
+	}
+
+	return &Deployer{rt: rt, opts: opts}
+}
+
+// Создаёт контейнер сервера. Контейнер другой ревизии удаляется,
+// каталог данных с миром сохраняется, файлы настроек перезаписываются
+func (d *Deployer) Deploy(ctx context.Context, id string, dep *api.Deployment) error {
+	// Пути приходят с сервера, но не должны выходить за каталог сервера.
+	// Проверяем до удаления старого контейнера
+	for rel := range dep.Files {
+		if !filepath.IsLocal(rel) {
+			return fmt.Errorf("invalid file path %q", rel)
+		}
+	}
+
+	name := d.opts.Prefix + id
+	res := resources(dep.Resources)
+
+	state, err := d.rt.Inspect(ctx, name)
+	switch {
+	case errors.Is(err, runtime.ErrNotFound):
+	case err != nil:
+		return err
+	case state.Labels[RevisionLabel] == dep.Revision:
+		if err := d.rt.Update(ctx, name, res); err != nil {
+			return fmt.Errorf("failed to update resources: %w", err)
+		}
+		if dep.Start {
+			return d.rt.Start(ctx, name)
+		}
+		return nil
+	default:
+		if err := d.Remove(ctx, id); err != nil {
+			return fmt.Errorf("failed to remove previous revision %s: %w", state.Labels[RevisionLabel], err)
+		}
+	}
+
+	dir, err := filepath.Abs(filepath.Join(d.opts.DataDir, id))
+	if err != nil {
+		return err
+	}
+	if err := writeFiles(dir, dep.Files); err != nil {
+		return err
+	}
+
+	spec := runtime.ContainerSpec{
+		Name:      name,
+		Image:     dep.Image,
+		Env:       dep.Env,
+		Labels:    map[string]string{RevisionLabel: dep.Revision},
+		Mounts:    []runtime.Mount{{Source: dir, Target: dep.DataPath}},
+		Resources: res,
+	}
+	for _, p := range dep.Ports {
+		if p.Port == 0 || p.Port > 65535 {
+			return fmt.Errorf("invalid port %d", p.Port)
+		}
+		spec.Ports = append(spec.Ports, runtime.PortBinding{
+			HostPort:      uint16(p.Port),
+			ContainerPort: uint16(p.Port),
+			Protocol:      p.Protocol,
+		})
+	}
+
+	if _, err := d.rt.Create(ctx, spec); err != nil {
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+
+	if dep.Start {
+		return d.rt.Start(ctx, name)
+	}
+	return nil
+}
+
+// Останавливает и удаляет контейнер сервера. Каталог данных остаётся на хосте,
+// чтобы мир не терялся при случайном удалении. Отсутствие контейнера не ошибка
+func (d *Deployer) Remove(ctx context.Context, id string) error {
+	name := d.opts.Prefix + id
+
+	err := d.rt.Stop(ctx, name, d.opts.StopTimeout)
+	if errors.Is(err, runtime.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = d.rt.Remove(ctx, name)
+	if errors.Is(err, runtime.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func writeFiles(dir string, files map[string][]byte) error {
+	for rel, data := range files {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+	}
+
+	// Каталог мог быть не создан, если файлов нет
+	return os.MkdirAll(dir, 0755)
+}
+
+func resources(r *api.Resources) runtime.Resources {
+	if r == nil {
+		return runtime.Resources{}
+	}
+
+	return runtime.Resources{
+		CpuCores:    r.CpuCores,
+		CpuShares:   r.CpuShares,
+		MemoryBytes: r.MemoryBytes,
+		DiskBytes:   r.DiskBytes,
+	}
+}
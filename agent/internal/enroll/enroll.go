@@ -0,0 +1,80 @@
+package enroll
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+)
+
+// Генерирует ключ, обменивает join-токен на подписанный сервером сертификат
+// и сохраняет сертификат и ключ. Возвращает id агента, выданный сервером
+func Enroll(ctx context.Context, client api.EnrollmentServiceClient, joinToken, certPath, keyPath string) (string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "otus-project agent"},
+	}, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to create csr: %w", err)
+	}
+
+	resp, err := client.Enroll(ctx, &api.EnrollRequest{
+		JoinToken: joinToken,
+		Csr:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	// Ключ пишется первым: сертификат без ключа агент посчитает готовым к работе
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		return "", fmt.Errorf("failed to write key: %w", err)
+	}
+	if err := os.WriteFile(certPath, resp.Certificate, 0644); err != nil {
+		return "", fmt.Errorf("failed to write certificate: %w", err)
+	}
+
+	return resp.AgentId, nil
+}
+
+// id агента, которому выдан сертификат
+func AgentId(certPath string) (string, error) {
+	data, err := os.ReadFile(certPath)
+	if err != nil {
+		return "", err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", errors.New("certificate is not in PEM format")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := uuid.Parse(cert.Subject.CommonName)
+	if err != nil {
+		return "", fmt.Errorf("certificate is not issued for an agent: %w", err)
+	}
+	return id.String(), nil
+}
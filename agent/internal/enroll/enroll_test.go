@@ -0,0 +1,145 @@
+package enroll
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/grpc"
+)
+
+// Сервер регистрации: подписывает CSR своим CA для агента с CN commonName
+type fakeEnrollmentClient struct {
+	api.EnrollmentServiceClient
+
+	commonName string
+	err        error
+	token      string
+}
+
+func (c *fakeEnrollmentClient) Enroll(ctx context.Context, in *api.EnrollRequest, opts ...grpc.CallOption) (*api.EnrollResponse, error) {
+	c.token = in.JoinToken
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	block, _ := pem.Decode(in.Csr)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("csr is not in PEM format")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: c.commonName},
+		NotBefore:    ca.NotBefore,
+		NotAfter:     ca.NotAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, csr.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.EnrollResponse{
+		AgentId:     c.commonName,
+		Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}, nil
+}
+
+func testPaths(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	return filepath.Join(dir, "agent.crt"), filepath.Join(dir, "agent.key")
+}
+
+func TestEnroll(t *testing.T) {
+	certPath, keyPath := testPaths(t)
+	agentId := uuid.New().String()
+	client := &fakeEnrollmentClient{commonName: agentId}
+
+	id, err := Enroll(context.Background(), client, "join-token", certPath, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != agentId || client.token != "join-token" {
+		t.Errorf("enrolled as %q with token %q", id, client.token)
+	}
+
+	// Сертификат выдан для сохранённого ключа
+	if _, err := tls.LoadX509KeyPair(certPath, keyPath); err != nil {
+		t.Errorf("certificate does not match the key: %v", err)
+	}
+	if info, err := os.Stat(keyPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("key file: %v, %v", info, err)
+	}
+
+	if id, err := AgentId(certPath); err != nil || id != agentId {
+		t.Errorf("AgentId: %q, %v", id, err)
+	}
+}
+
+// Отказ сервера не оставляет файлов, иначе агент посчитал бы себя зарегистрированным
+func TestEnrollRejected(t *testing.T) {
+	certPath, keyPath := testPaths(t)
+	client := &fakeEnrollmentClient{err: errors.New("join token is invalid, expired or already used")}
+
+	if _, err := Enroll(context.Background(), client, "used", certPath, keyPath); err == nil {
+		t.Fatal("enrollment with a rejected token succeeded")
+	}
+	for _, path := range []string{certPath, keyPath} {
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestAgentIdRejectsForeignCertificate(t *testing.T) {
+	certPath, keyPath := testPaths(t)
+	client := &fakeEnrollmentClient{commonName: "not an agent"}
+
+	if _, err := Enroll(context.Background(), client, "token", certPath, keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AgentId(certPath); err == nil {
+		t.Error("certificate without an agent id was accepted")
+	}
+
+	if err := os.WriteFile(certPath, []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AgentId(certPath); err == nil {
+		t.Error("certificate not in PEM format was accepted")
+	}
+}
@@ -0,0 +1,218 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/grpc"
+)
+
+// Обработчик задачи определённого типа. Результат в JSON сохраняется в задаче,
+// возвращённая ошибка переводит задачу в статус failed
+type Handler func(ctx context.Context, task *api.Task) (json.RawMessage, error)
+
+type taskClient interface {
+	Lease(ctx context.Context, in *api.LeaseTasksRequest, opts ...grpc.CallOption) (*api.LeaseTasksResponse, error)
+	Report(ctx context.Context, in *api.ReportTaskRequest, opts ...grpc.CallOption) (*api.ReportTaskResponse, error)
+}
+
+// Буфер отчётов, которые должны дойти до сервера и после обрыва связи
+type Outbox interface {
+	Push(name string, deliver func(ctx context.Context) error)
+}
+
+type Options struct {
+	PollInterval      time.Duration // Как часто запрашивать новые задачи
+	VisibilityTimeout time.Duration // Время аренды задачи, продлевается пока задача выполняется
+	MaxTasks          int           // Сколько задач агент выполняет одновременно
+	Outbox            Outbox        // Через него отправляются итоговые отчёты; если не задан, отчёт отправляется сразу
+}
+
+// Забирает из очереди задачи, назначенные агенту, и выполняет их
+// зарегистрированными обработчиками
+type Executor struct {
+	client  taskClient
+	agentId string
+	opts    Options
+
+	m        sync.RWMutex
+	handlers map[string]Handler
+	running  chan struct{}
+}
+
+func New(client taskClient, agentId string, opts Options) *Executor {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = time.Minute
+	}
+
+	if opts.MaxTasks <= 0 {
+		opts.MaxTasks = 1
+	}
+
+	return &Executor{
+		client:   client,
+		agentId:  agentId,
+		opts:     opts,
+		handlers: make(map[string]Handler),
+		running:  make(chan struct{}, opts.MaxTasks),
+	}
+}
+
+// Регистрирует обработчик для задач с типом taskType
+func (e *Executor) Handle(taskType string, h Handler) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.handlers[taskType] = h
+}
+
+func (e *Executor) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	t := time.NewTicker(e.opts.PollInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("executor is stopping...")
+			return
+		case <-t.C:
+		}
+
+		free := e.opts.MaxTasks - len(e.running)
+		types := e.types()
+		if free <= 0 || len(types) == 0 {
+			continue
+		}
+
+		resp, err := e.client.Lease(ctx, &api.LeaseTasksRequest{
+			AgentId:                  e.agentId,
+			Types:                    types,
+			MaxTasks:                 uint32(free),
+			VisibilityTimeoutSeconds: e.visibilityTimeoutSeconds(),
+		})
+		if err != nil {
+			log.Printf("Failed to lease tasks: %v\n", err)
+			continue
+		}
+
+		for _, task := range resp.Tasks {
+			e.running <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-e.running }()
+				e.execute(ctx, task)
+			}()
+		}
+	}
+}
+
+func (e *Executor) execute(ctx context.Context, task *api.Task) {
+	e.m.RLock()
+	h, ok := e.handlers[task.Type]
+	e.m.RUnlock()
+
+	if !ok {
+		// Тип задачи мог быть снят с регистрации после аренды, тогда задача вернётся в очередь по таймауту
+		log.Printf("no handler for task %s of type %q\n", task.Id, task.Type)
+		return
+	}
+
+	if err := e.report(ctx, task, api.TaskStatus_TASK_STATUS_IN_PROGRESS, nil, nil); err != nil {
+		log.Printf("Failed to take task %s: %v\n", task.Id, err)
+		return
+	}
+
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go e.keepLease(taskCtx, task)
+
+	result, err := h(taskCtx, task)
+	if ctx.Err() != nil {
+		// Агент останавливается: не отчитываемся, задача вернётся в очередь после истечения аренды
+		return
+	}
+
+	st := api.TaskStatus_TASK_STATUS_OK
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Printf("task %s timed out: %v\n", task.Id, err)
+		st = api.TaskStatus_TASK_STATUS_TIMED_OUT
+	case err != nil:
+		log.Printf("task %s failed: %v\n", task.Id, err)
+		st = api.TaskStatus_TASK_STATUS_FAILED
+	}
+
+	if e.opts.Outbox == nil {
+		if err := e.report(ctx, task, st, result, err); err != nil {
+			log.Printf("Failed to report task %s: %v\n", task.Id, err)
+		}
+		return
+	}
+
+	// Результат не должен потеряться, если сервер сейчас недоступен
+	e.opts.Outbox.Push("result of task "+task.Id, func(ctx context.Context) error {
+		return e.report(ctx, task, st, result, err)
+	})
+}
+
+// Продлевает аренду, пока выполняется обработчик
+func (e *Executor) keepLease(ctx context.Context, task *api.Task) {
+	t := time.NewTicker(e.opts.VisibilityTimeout / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := e.report(ctx, task, api.TaskStatus_TASK_STATUS_IN_PROGRESS, nil, nil); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to extend lease of task %s: %v\n", task.Id, err)
+			}
+		}
+	}
+}
+
+func (e *Executor) report(ctx context.Context, task *api.Task, st api.TaskStatus, result json.RawMessage, taskErr error) error {
+	req := &api.ReportTaskRequest{
+		Id:                       task.Id,
+		AgentId:                  e.agentId,
+		Status:                   st,
+		VisibilityTimeoutSeconds: e.visibilityTimeoutSeconds(),
+		Result:                   result,
+	}
+	if taskErr != nil {
+		req.Error = taskErr.Error()
+	}
+
+	_, err := e.client.Report(ctx, req)
+	return err
+}
+
+func (e *Executor) types() []string {
+	e.m.RLock()
+	defer e.m.RUnlock()
+
+	types := make([]string, 0, len(e.handlers))
+	for t := range e.handlers {
+		types = append(types, t)
+	}
+	return types
+}
+
+func (e *Executor) visibilityTimeoutSeconds() uint32 {
+	return uint32(e.opts.VisibilityTimeout / time.Second)
+}
@@ -0,0 +1,244 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vv-sam/otus-project/agent/internal/runtime"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/grpc"
+)
+
+// Сервер задач: отдаёт задачи при первой аренде и запоминает отчёты
+type fakeTaskClient struct {
+	m       sync.Mutex
+	tasks   []*api.Task
+	reports []*api.ReportTaskRequest
+
+	// Итоговые отчёты, без продления аренды
+	final chan *api.ReportTaskRequest
+}
+
+func newFakeTaskClient(tasks ...*api.Task) *fakeTaskClient {
+	return &fakeTaskClient{tasks: tasks, final: make(chan *api.ReportTaskRequest, len(tasks))}
+}
+
+func (c *fakeTaskClient) Lease(ctx context.Context, in *api.LeaseTasksRequest, opts ...grpc.CallOption) (*api.LeaseTasksResponse, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	n := min(int(in.MaxTasks), len(c.tasks))
+	tasks := c.tasks[:n]
+	c.tasks = c.tasks[n:]
+	return &api.LeaseTasksResponse{Tasks: tasks}, nil
+}
+
+func (c *fakeTaskClient) Report(ctx context.Context, in *api.ReportTaskRequest, opts ...grpc.CallOption) (*api.ReportTaskResponse, error) {
+	c.m.Lock()
+	c.reports = append(c.reports, in)
+	c.m.Unlock()
+
+	if in.Status != api.TaskStatus_TASK_STATUS_IN_PROGRESS {
+		c.final <- in
+	}
+	return &api.ReportTaskResponse{}, nil
+}
+
+func (c *fakeTaskClient) statuses(id string) []api.TaskStatus {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	var res []api.TaskStatus
+	for _, r := range c.reports {
+		if r.Id == id {
+			res = append(res, r.Status)
+		}
+	}
+	return res
+}
+
+// Среда запуска, в которой запуск контейнера завершается ошибкой err
+type failingRuntime struct {
+	*runtime.Fake
+	err error
+}
+
+func (r failingRuntime) Start(ctx context.Context, id string) error {
+	return r.err
+}
+
+// Среда запуска, в которой остановка контейнера ждёт отмены
+type blockingRuntime struct {
+	*runtime.Fake
+	stopping chan struct{}
+}
+
+func (r blockingRuntime) Stop(ctx context.Context, id string, timeout time.Duration) error {
+	close(r.stopping)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Обработчик задач над контейнером, id контейнера - id конфигурации
+func containerHandler(rt runtime.Runtime) Handler {
+	return func(ctx context.Context, task *api.Task) (json.RawMessage, error) {
+		switch task.Action {
+		case api.TaskAction_TASK_ACTION_START:
+			return nil, rt.Start(ctx, task.ConfigurationId)
+		case api.TaskAction_TASK_ACTION_STOP:
+			return nil, rt.Stop(ctx, task.ConfigurationId, time.Second)
+		}
+		return nil, fmt.Errorf("unexpected action %v", task.Action)
+	}
+}
+
+func newTestExecutor(client taskClient, rt runtime.Runtime) *Executor {
+	e := New(client, "agent", Options{PollInterval: time.Millisecond})
+	e.Handle("minecraft", containerHandler(rt))
+	return e
+}
+
+func createContainer(t *testing.T, rt *runtime.Fake) string {
+	t.Helper()
+
+	id, err := rt.Create(context.Background(), runtime.ContainerSpec{Name: "server"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// Запускает исполнителя до получения n итоговых отчётов
+func runUntilReported(t *testing.T, e *Executor, client *fakeTaskClient, n int) []*api.ReportTaskRequest {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	var reports []*api.ReportTaskRequest
+	for range n {
+		select {
+		case r := <-client.final:
+			reports = append(reports, r)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d of %d reports", len(reports), n)
+		}
+	}
+	return reports
+}
+
+func TestExecutorStartStop(t *testing.T) {
+	rt := runtime.NewFake()
+	id := createContainer(t, rt)
+
+	client := newFakeTaskClient(&api.Task{Id: "start", Type: "minecraft", Action: api.TaskAction_TASK_ACTION_START, ConfigurationId: id})
+	e := newTestExecutor(client, rt)
+
+	r := runUntilReported(t, e, client, 1)[0]
+	if r.Status != api.TaskStatus_TASK_STATUS_OK || r.AgentId != "agent" {
+		t.Fatalf("start reported %v by %q: %s", r.Status, r.AgentId, r.Error)
+	}
+	if s, _ := rt.Inspect(context.Background(), id); !s.Running {
+		t.Fatalf("container is %s after start", s.Status)
+	}
+
+	// Перед выполнением задача берётся в работу
+	want := []api.TaskStatus{api.TaskStatus_TASK_STATUS_IN_PROGRESS, api.TaskStatus_TASK_STATUS_OK}
+	if got := client.statuses("start"); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("reported %v, want %v", got, want)
+	}
+
+	client = newFakeTaskClient(&api.Task{Id: "stop", Type: "minecraft", Action: api.TaskAction_TASK_ACTION_STOP, ConfigurationId: id})
+	e = newTestExecutor(client, rt)
+
+	if r := runUntilReported(t, e, client, 1)[0]; r.Status != api.TaskStatus_TASK_STATUS_OK {
+		t.Fatalf("stop reported %v: %s", r.Status, r.Error)
+	}
+	if s, _ := rt.Inspect(context.Background(), id); s.Running {
+		t.Fatalf("container is %s after stop", s.Status)
+	}
+}
+
+func TestExecutorRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status api.TaskStatus
+	}{
+		{"failed", errors.New("image not found"), api.TaskStatus_TASK_STATUS_FAILED},
+		{"timed out", fmt.Errorf("docker: %w", context.DeadlineExceeded), api.TaskStatus_TASK_STATUS_TIMED_OUT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := runtime.NewFake()
+			id := createContainer(t, fake)
+
+			client := newFakeTaskClient(&api.Task{Id: "start", Type: "minecraft", Action: api.TaskAction_TASK_ACTION_START, ConfigurationId: id})
+			e := newTestExecutor(client, failingRuntime{Fake: fake, err: tt.err})
+
+			r := runUntilReported(t, e, client, 1)[0]
+			if r.Status != tt.status || r.Error != tt.err.Error() {
+				t.Errorf("reported %v with %q, want %v with %q", r.Status, r.Error, tt.status, tt.err)
+			}
+		})
+	}
+}
+
+func TestExecutorUnknownContainer(t *testing.T) {
+	client := newFakeTaskClient(&api.Task{Id: "start", Type: "minecraft", Action: api.TaskAction_TASK_ACTION_START, ConfigurationId: "missing"})
+	e := newTestExecutor(client, runtime.NewFake())
+
+	if r := runUntilReported(t, e, client, 1)[0]; r.Status != api.TaskStatus_TASK_STATUS_FAILED {
+		t.Errorf("reported %v, want failed", r.Status)
+	}
+}
+
+// При остановке агента обработчик отменяется, а итоговый отчёт не отправляется:
+// задача вернётся в очередь после истечения аренды
+func TestExecutorCancel(t *testing.T) {
+	fake := runtime.NewFake()
+	id := createContainer(t, fake)
+	rt := blockingRuntime{Fake: fake, stopping: make(chan struct{})}
+
+	client := newFakeTaskClient(&api.Task{Id: "stop", Type: "minecraft", Action: api.TaskAction_TASK_ACTION_STOP, ConfigurationId: id})
+	e := newTestExecutor(client, rt)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-rt.stopping:
+	case <-time.After(5 * time.Second):
+		t.Fatal("task was not started")
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("executor did not stop")
+	}
+
+	want := []api.TaskStatus{api.TaskStatus_TASK_STATUS_IN_PROGRESS}
+	if got := client.statuses("stop"); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("reported %v, want only %v", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// История вывода одного контейнера на диске: JSON-строки в name.log,
+// при превышении размера файл сдвигается в name.log.1, name.log.1 в name.log.2 и т.д.
+type rotatingFile struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	f    *os.File
+	size int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &rotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles, f: f, size: info.Size()}, nil
+}
+
+func (r *rotatingFile) write(l Line) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if r.size > 0 && r.size+int64(len(data)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate %s: %w", r.path, err)
+		}
+	}
+
+	n, err := r.f.Write(data)
+	r.size += int64(n)
+	return err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+
+	// Самый старый файл вытесняется переименованием поверх него
+	for i := r.maxFiles - 1; i >= 1; i-- {
+		err := os.Rename(r.name(i), r.name(i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if r.maxFiles > 0 {
+		if err := os.Rename(r.path, r.name(1)); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	r.f, r.size = f, 0
+	return nil
+}
+
+// Открывает все файлы истории от старых к новым. Открытые файлы можно
+// дочитать, даже если запись тем временем их ротирует
+func (r *rotatingFile) open() ([]*os.File, error) {
+	var files []*os.File
+	for i := r.maxFiles; i >= 0; i-- {
+		f, err := os.Open(r.name(i))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			closeAll(files)
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// Последняя записанная строка, чтобы после перезапуска агента продолжить нумерацию
+func (r *rotatingFile) last() (Line, error) {
+	for i := 0; i <= r.maxFiles; i++ {
+		f, err := os.Open(r.name(i))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Line{}, err
+		}
+
+		var last Line
+		found := false
+		err = scan(f, func(l Line) error {
+			last, found = l, true
+			return nil
+		})
+		f.Close()
+		if err != nil {
+			return Line{}, err
+		}
+		if found {
+			return last, nil
+		}
+	}
+	return Line{}, nil
+}
+
+func (r *rotatingFile) close() error {
+	return r.f.Close()
+}
+
+func (r *rotatingFile) name(i int) string {
+	if i == 0 {
+		return r.path
+	}
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
+
+// Читает строки истории. Повреждённые строки, например недописанные при сбое, пропускаются
+func scan(r io.Reader, fn func(Line) error) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), maxLineSize*2)
+
+	for s.Scan() {
+		var l Line
+		if err := json.Unmarshal(s.Bytes(), &l); err != nil {
+			continue
+		}
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+func closeAll(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
+
+func logPath(dir, name string) string {
+	return filepath.Join(dir, name+".log")
+}
@@ -0,0 +1,509 @@
+package logs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vv-sam/otus-project/agent/internal/runtime"
+)
+
+const (
+	DefaultBufferLines = 1000
+	DefaultMaxFileSize = 10 << 20
+	DefaultMaxFiles    = 5
+
+	// Более длинные строки обрезаются
+	maxLineSize = 64 * 1024
+
+	// Сколько строк отдаётся читателю за раз
+	chunkLines = 100
+
+	// Сколько новых строк может ждать медленного читателя, прежде чем он будет отключён
+	subscriberBuffer = 1024
+)
+
+var (
+	ErrSlowReader = errors.New("log reader is too slow")
+	ErrClosed     = errors.New("log manager is closed")
+)
+
+// Строка вывода контейнера. Seq растёт на единицу с каждой строкой и
+// сохраняется между перезапусками агента
+type Line struct {
+	Seq  uint64    `json:"seq"`
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// Выборка вывода. Since и Tail ограничивают историю, Follow продолжает
+// выдачу новыми строками
+type Query struct {
+	Since  time.Time
+	Tail   int // Сколько последних строк вернуть, 0 - все
+	Follow bool
+}
+
+type Options struct {
+	Dir         string // Каталог с историей вывода
+	BufferLines int    // Сколько последних строк каждого контейнера держать в памяти
+	MaxFileSize int64  // Размер файла истории, после которого он ротируется
+	MaxFiles    int    // Сколько ротированных файлов хранить
+}
+
+// Собирает вывод контейнеров: читает его из среды запуска, держит последние
+// строки в памяти и пишет историю на диск с ротацией по размеру
+type Manager struct {
+	rt     runtime.Runtime
+	opts   Options
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	m          sync.Mutex
+	containers map[string]*container
+}
+
+func New(rt runtime.Runtime, opts Options) *Manager {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+
+	if opts.BufferLines <= 0 {
+		opts.BufferLines = DefaultBufferLines
+	}
+
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = DefaultMaxFileSize
+	}
+
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = DefaultMaxFiles
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		rt:         rt,
+		opts:       opts,
+		ctx:        ctx,
+		cancel:     cancel,
+		containers: make(map[string]*container),
+	}
+}
+
+// Начинает собирать вывод контейнера, если ещё не собирает. Сбор
+// прекращается, когда контейнер останавливается
+func (m *Manager) Watch(name string) error {
+	_, err := m.watch(name)
+	return err
+}
+
+// Отдаёт вывод контейнера порциями в fn: сначала историю по q.Since и q.Tail,
+// затем, если задан q.Follow, новые строки, пока не отменён ctx или не остановился контейнер
+func (m *Manager) Read(ctx context.Context, name string, q Query, fn func([]Line) error) error {
+	c, err := m.watch(name)
+	if err != nil {
+		return err
+	}
+
+	// Подписка и снимок истории берутся под одной блокировкой, чтобы
+	// между историей и новыми строками не было ни пропусков, ни повторов
+	c.m.Lock()
+	var sub *subscriber
+	if q.Follow && c.following {
+		sub = c.subscribe()
+	}
+	lastSeq := c.last.Seq
+	recent := c.recent.snapshot()
+
+	var files []*os.File
+	if !covers(recent, q) {
+		files, err = c.file.open()
+	}
+	c.m.Unlock()
+
+	if sub != nil {
+		defer c.unsubscribe(sub)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open log history: %w", err)
+	}
+
+	if files != nil {
+		err = sendHistory(func(yield func(Line) error) error {
+			for _, f := range files {
+				if err := scan(f, yield); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, lastSeq, q, fn)
+		closeAll(files)
+	} else {
+		err = sendHistory(func(yield func(Line) error) error {
+			for _, l := range recent {
+				if err := yield(l); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, lastSeq, q, fn)
+	}
+	if err != nil || sub == nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case l, ok := <-sub.lines:
+			if !ok {
+				return sub.err
+			}
+
+			// Всё, что уже накопилось, уходит одной порцией
+			chunk := []Line{l}
+		drain:
+			for len(chunk) < chunkLines {
+				select {
+				case l, ok := <-sub.lines:
+					if !ok {
+						break drain
+					}
+					chunk = append(chunk, l)
+				default:
+					break drain
+				}
+			}
+
+			if err := fn(chunk); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Прекращает сбор вывода всех контейнеров
+func (m *Manager) Close() error {
+	m.cancel()
+
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	for _, c := range m.containers {
+		c.m.Lock()
+		c.file.close()
+		c.m.Unlock()
+	}
+	return nil
+}
+
+func (m *Manager) watch(name string) (*container, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid container name %q", name)
+	}
+
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	if m.ctx.Err() != nil {
+		return nil, ErrClosed
+	}
+
+	c, ok := m.containers[name]
+	if !ok {
+		path := logPath(m.opts.Dir, name)
+
+		// История удалённого контейнера остаётся доступной, но для
+		// неизвестного контейнера файл истории не заводится
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			if _, err := m.rt.Inspect(m.ctx, name); err != nil {
+				return nil, err
+			}
+		}
+
+		file, err := openRotatingFile(path, m.opts.MaxFileSize, m.opts.MaxFiles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log history: %w", err)
+		}
+
+		last, err := file.last()
+		if err != nil {
+			file.close()
+			return nil, fmt.Errorf("failed to read log history: %w", err)
+		}
+
+		c = &container{
+			name:   name,
+			file:   file,
+			last:   last,
+			recent: newRing(m.opts.BufferLines),
+			subs:   make(map[*subscriber]struct{}),
+		}
+		m.containers[name] = c
+	}
+
+	c.m.Lock()
+	start := !c.following
+	c.following = true
+	c.m.Unlock()
+
+	if start {
+		go m.follow(c)
+	}
+	return c, nil
+}
+
+// Читает вывод контейнера, пока тот не остановится
+func (m *Manager) follow(c *container) {
+	defer c.stop()
+
+	c.m.Lock()
+	since := c.last.Time
+	c.m.Unlock()
+
+	r, err := m.rt.Logs(m.ctx, c.name, runtime.LogsOptions{Follow: true, Timestamps: true, Since: since})
+	if err != nil {
+		if m.ctx.Err() == nil && !errors.Is(err, runtime.ErrNotFound) {
+			log.Printf("Failed to read logs of %s: %v\n", c.name, err)
+		}
+		return
+	}
+	defer r.Close()
+
+	br := bufio.NewReaderSize(r, 64*1024)
+	for {
+		text, err := readLine(br)
+		if err == nil || text != "" {
+			c.append(parseLine(text))
+		}
+
+		if err != nil {
+			if err != io.EOF && m.ctx.Err() == nil {
+				log.Printf("Failed to read logs of %s: %v\n", c.name, err)
+			}
+			return
+		}
+	}
+}
+
+type container struct {
+	name string
+
+	m           sync.Mutex
+	file        *rotatingFile
+	last        Line
+	recent      *ring
+	subs        map[*subscriber]struct{}
+	following   bool
+	writeFailed bool
+}
+
+// Читатель новых строк. Канал закрывается, когда контейнер останавливается
+// или читатель не успевает за выводом, во втором случае err = ErrSlowReader
+type subscriber struct {
+	lines chan Line
+	err   error
+}
+
+// Добавляет строку. Строки не новее последней сохранённой уже записаны:
+// среда запуска отдаёт вывод с точностью до секунды и повторяет их после переподключения
+func (c *container) append(t time.Time, stamped bool, text string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if stamped && !t.After(c.last.Time) {
+		return
+	}
+
+	l := Line{Seq: c.last.Seq + 1, Time: t, Text: text}
+	if err := c.file.write(l); err != nil {
+		if !c.writeFailed {
+			log.Printf("Failed to write logs of %s: %v\n", c.name, err)
+		}
+		c.writeFailed = true
+	} else {
+		c.writeFailed = false
+	}
+
+	c.last = l
+	c.recent.push(l)
+
+	for sub := range c.subs {
+		select {
+		case sub.lines <- l:
+		default:
+			sub.err = ErrSlowReader
+			delete(c.subs, sub)
+			close(sub.lines)
+		}
+	}
+}
+
+func (c *container) subscribe() *subscriber {
+	sub := &subscriber{lines: make(chan Line, subscriberBuffer)}
+	c.subs[sub] = struct{}{}
+	return sub
+}
+
+func (c *container) unsubscribe(sub *subscriber) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if _, ok := c.subs[sub]; ok {
+		delete(c.subs, sub)
+		close(sub.lines)
+	}
+}
+
+func (c *container) stop() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.following = false
+	for sub := range c.subs {
+		delete(c.subs, sub)
+		close(sub.lines)
+	}
+}
+
+// Хватает ли строк в памяти, чтобы ответить без чтения файлов истории
+func covers(recent []Line, q Query) bool {
+	if len(recent) == 0 {
+		return false
+	}
+
+	// В памяти вся история
+	if recent[0].Seq == 1 {
+		return true
+	}
+
+	if !q.Since.IsZero() && recent[0].Time.Before(q.Since) {
+		return true
+	}
+
+	if q.Tail > 0 {
+		n := 0
+		for _, l := range recent {
+			if !l.Time.Before(q.Since) {
+				n++
+			}
+		}
+		return n >= q.Tail
+	}
+
+	return false
+}
+
+// Отбирает строки истории по запросу и отдаёт их порциями. Строки новее
+// lastSeq придут читателю через подписку
+func sendHistory(lines func(yield func(Line) error) error, lastSeq uint64, q Query, fn func([]Line) error) error {
+	match := func(l Line) bool {
+		return l.Seq <= lastSeq && !l.Time.Before(q.Since)
+	}
+
+	if q.Tail > 0 {
+		tail := newRing(q.Tail)
+		err := lines(func(l Line) error {
+			if match(l) {
+				tail.push(l)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		selected := tail.snapshot()
+		for len(selected) > 0 {
+			n := min(len(selected), chunkLines)
+			if err := fn(selected[:n]); err != nil {
+				return err
+			}
+			selected = selected[n:]
+		}
+		return nil
+	}
+
+	var chunk []Line
+	err := lines(func(l Line) error {
+		if !match(l) {
+			return nil
+		}
+
+		chunk = append(chunk, l)
+		if len(chunk) < chunkLines {
+			return nil
+		}
+
+		err := fn(chunk)
+		chunk = nil
+		return err
+	})
+	if err != nil || len(chunk) == 0 {
+		return err
+	}
+	return fn(chunk)
+}
+
+// Читает строку без перевода строки, обрезая слишком длинные
+func readLine(r *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line) < maxLineSize {
+			line = append(line, chunk[:min(len(chunk), maxLineSize-len(line))]...)
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return strings.TrimRight(string(line), "\r\n"), err
+	}
+}
+
+// Отделяет время, которое среда запуска добавляет в начало строки
+func parseLine(text string) (time.Time, bool, string) {
+	ts, rest, ok := strings.Cut(text, " ")
+	if ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			return t, true, rest
+		}
+	}
+	return time.Now(), false, text
+}
+
+// Кольцевой буфер последних строк. Память выделяется по мере заполнения
+type ring struct {
+	size  int
+	buf   []Line
+	start int
+}
+
+func newRing(size int) *ring {
+	return &ring{size: size}
+}
+
+func (r *ring) push(l Line) {
+	if len(r.buf) < r.size {
+		r.buf = append(r.buf, l)
+		return
+	}
+
+	r.buf[r.start] = l
+	r.start = (r.start + 1) % r.size
+}
+
+// Строки от старых к новым
+func (r *ring) snapshot() []Line {
+	lines := make([]Line, 0, len(r.buf))
+	lines = append(lines, r.buf[r.start:]...)
+	return append(lines, r.buf[:r.start]...)
+}
@@ -0,0 +1,114 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// Сколько ждать ответа на UDP-датаграмму. Отсутствие ответа не считается ошибкой
+	udpWait = time.Second
+
+	playersOnlineCommand = "/players online"
+)
+
+var (
+	ErrPortClosed = errors.New("port is closed")
+
+	playersOnlineHeader = regexp.MustCompile(`\((\d+)\)`)
+)
+
+// Выполняет команду RCON
+type RconFunc func(ctx context.Context, command string) (string, error)
+
+// Проверяет сервер Factorio: игровой UDP-порт должен быть открыт, а сервер -
+// отвечать по RCON. Список игроков берётся из /players online
+func Factorio(ctx context.Context, addr string, rcon RconFunc) (*Status, error) {
+	if err := checkUDP(ctx, addr); err != nil {
+		return nil, fmt.Errorf("game port: %w", err)
+	}
+
+	// Ожидание ответа по UDP ничего не говорит о задержке, её показывает RCON
+	started := time.Now()
+	out, err := rcon(ctx, playersOnlineCommand)
+	if err != nil {
+		return nil, fmt.Errorf("rcon: %w", err)
+	}
+
+	status, err := parsePlayersOnline(out)
+	if err != nil {
+		return nil, err
+	}
+	status.Latency = time.Since(started)
+	return status, nil
+}
+
+// UDP не подтверждает доставку, поэтому закрытый порт виден только по ICMP
+// port unreachable, который приходит ошибкой при чтении. Сервер Factorio
+// молча отбрасывает незнакомую датаграмму, так что таймаут означает, что порт открыт
+func checkUDP(ctx context.Context, addr string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, udpWait)
+	defer cancel()
+	stop := withDeadline(ctx, conn)
+	defer stop()
+
+	if _, err := conn.Write([]byte{0}); err != nil {
+		return udpError(err)
+	}
+
+	buf := make([]byte, 1)
+	_, err = conn.Read(buf)
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return nil
+	}
+	return udpError(err)
+}
+
+func udpError(err error) error {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return ErrPortClosed
+	}
+	return err
+}
+
+// Разбирает ответ вида
+//
+//	Online players (2):
+//	  alice (online)
+//	  bob (online)
+func parsePlayersOnline(out string) (*Status, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+
+	m := playersOnlineHeader.FindStringSubmatch(lines[0])
+	if m == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidResponse, lines[0])
+	}
+	online, err := strconv.ParseUint(m[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	status := &Status{PlayersOnline: uint32(online)}
+	for _, l := range lines[1:] {
+		name := strings.TrimSuffix(strings.TrimSpace(l), " (online)")
+		if name != "" {
+			status.Players = append(status.Players, name)
+		}
+	}
+	return status, nil
+}
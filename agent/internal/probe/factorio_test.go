@@ -0,0 +1,81 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestFactorio(t *testing.T) {
+	f, err := newFakeFactorio([]string{"alice", "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	got, err := Factorio(context.Background(), f.Addr(), f.Rcon)
+	if err != nil {
+		t.Fatalf("Factorio: %v", err)
+	}
+	if got.PlayersOnline != 2 || !reflect.DeepEqual(got.Players, []string{"alice", "bob"}) {
+		t.Errorf("unexpected status: %+v", got)
+	}
+
+	f.SetPlayers(nil)
+	got, err = Factorio(context.Background(), f.Addr(), f.Rcon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PlayersOnline != 0 || len(got.Players) != 0 {
+		t.Errorf("stale players: %+v", got)
+	}
+}
+
+func TestFactorioClosedPort(t *testing.T) {
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := udp.LocalAddr().String()
+	udp.Close()
+
+	rcon := func(context.Context, string) (string, error) {
+		t.Error("rcon must not be called when the game port is closed")
+		return "", nil
+	}
+	if _, err := Factorio(context.Background(), addr, rcon); !errors.Is(err, ErrPortClosed) {
+		t.Errorf("expected ErrPortClosed, got %v", err)
+	}
+}
+
+func TestFactorioRconError(t *testing.T) {
+	f, err := newFakeFactorio(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	failed := errors.New("connection refused")
+	rcon := func(context.Context, string) (string, error) {
+		return "", failed
+	}
+	if _, err := Factorio(context.Background(), f.Addr(), rcon); !errors.Is(err, failed) {
+		t.Errorf("expected the rcon error, got %v", err)
+	}
+}
+
+func TestParsePlayersOnline(t *testing.T) {
+	status, err := parsePlayersOnline("Online players (2):\n  alice (online)\n  bob (online)\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.PlayersOnline != 2 || !reflect.DeepEqual(status.Players, []string{"alice", "bob"}) {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	if _, err := parsePlayersOnline("Unknown command \"players\"."); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse, got %v", err)
+	}
+}
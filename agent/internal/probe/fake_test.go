@@ -0,0 +1,171 @@
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Локальный сервер Minecraft, отвечающий только на Server List Ping
+type fakeMinecraft struct {
+	l net.Listener
+
+	m      sync.Mutex
+	status Status
+}
+
+func newFakeMinecraft(status Status) (*fakeMinecraft, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	f := &fakeMinecraft{l: l, status: status}
+	go f.serve()
+	return f, nil
+}
+
+func (f *fakeMinecraft) Addr() string {
+	return f.l.Addr().String()
+}
+
+// Меняет статус, который сервер сообщает
+func (f *fakeMinecraft) SetStatus(status Status) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.status = status
+}
+
+func (f *fakeMinecraft) Close() error {
+	return f.l.Close()
+}
+
+func (f *fakeMinecraft) serve() {
+	for {
+		conn, err := f.l.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeMinecraft) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	// Handshake, запрос статуса, затем ping, на который сервер отвечает тем же пакетом
+	if _, err := readPacket(r); err != nil {
+		return
+	}
+	if _, err := readPacket(r); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(f.response())
+	if err != nil {
+		return
+	}
+
+	var resp bytes.Buffer
+	writeVarInt(&resp, 0x00)
+	writeVarInt(&resp, int32(len(data)))
+	resp.Write(data)
+	if err := writePacket(conn, resp.Bytes()); err != nil {
+		return
+	}
+
+	ping, err := readPacket(r)
+	if err != nil {
+		return
+	}
+	writePacket(conn, ping)
+}
+
+func (f *fakeMinecraft) response() map[string]any {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	sample := make([]map[string]string, len(f.status.Players))
+	for i, name := range f.status.Players {
+		sample[i] = map[string]string{"name": name, "id": fmt.Sprintf("00000000-0000-0000-0000-%012d", i)}
+	}
+
+	return map[string]any{
+		"version": map[string]any{"name": f.status.Version, "protocol": 763},
+		"players": map[string]any{
+			"max":    f.status.PlayersMax,
+			"online": f.status.PlayersOnline,
+			"sample": sample,
+		},
+		"description": map[string]any{"text": f.status.Motd},
+	}
+}
+
+// Локальный сервер Factorio: UDP-порт, молча принимающий датаграммы, и
+// RCON-команды, на которые отвечает как сервер
+type fakeFactorio struct {
+	udp net.PacketConn
+
+	m       sync.Mutex
+	players []string
+}
+
+func newFakeFactorio(players []string) (*fakeFactorio, error) {
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	f := &fakeFactorio{udp: udp, players: players}
+	go f.serve()
+	return f, nil
+}
+
+// Адрес игрового UDP-порта
+func (f *fakeFactorio) Addr() string {
+	return f.udp.LocalAddr().String()
+}
+
+func (f *fakeFactorio) SetPlayers(players []string) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.players = players
+}
+
+func (f *fakeFactorio) Close() error {
+	return f.udp.Close()
+}
+
+func (f *fakeFactorio) serve() {
+	buf := make([]byte, 1500)
+	for {
+		if _, _, err := f.udp.ReadFrom(buf); err != nil {
+			return
+		}
+	}
+}
+
+// RCON сервера: ответ на команду
+func (f *fakeFactorio) Rcon(ctx context.Context, command string) (string, error) {
+	if command != playersOnlineCommand {
+		return fmt.Sprintf("Unknown command %q.", strings.TrimPrefix(command, "/")), nil
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Online players (%d):\n", len(f.players))
+	for _, p := range f.players {
+		fmt.Fprintf(&b, "  %s (online)\n", p)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,221 @@
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Статус не зависит от версии протокола, -1 принято передавать, когда она неизвестна
+	slpProtocolVersion = -1
+
+	// Ответ со статусом заведомо меньше. Ограничение защищает от мусора вместо длины пакета
+	maxSlpPacketSize = 1 << 20
+)
+
+var ErrInvalidResponse = errors.New("invalid server response")
+
+// Опрашивает сервер Minecraft по Server List Ping, как это делает список серверов в клиенте игры
+func Minecraft(ctx context.Context, addr string) (*Status, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	stop := withDeadline(ctx, conn)
+	defer stop()
+
+	r := bufio.NewReader(conn)
+
+	// Handshake с переходом в состояние status, затем запрос статуса
+	var handshake bytes.Buffer
+	writeVarInt(&handshake, 0x00)
+	writeVarInt(&handshake, slpProtocolVersion)
+	writeString(&handshake, host)
+	binary.Write(&handshake, binary.BigEndian, uint16(port))
+	writeVarInt(&handshake, 1)
+	if err := writePacket(conn, handshake.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := writePacket(conn, []byte{0x00}); err != nil {
+		return nil, err
+	}
+
+	packet, err := readPacket(r)
+	if err != nil {
+		return nil, err
+	}
+	pr := bytes.NewReader(packet)
+	if id, err := readVarInt(pr); err != nil || id != 0x00 {
+		return nil, fmt.Errorf("%w: unexpected status packet", ErrInvalidResponse)
+	}
+	data, err := readString(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := parseMinecraftStatus(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Задержка измеряется отдельным ping, как в клиенте игры
+	var ping bytes.Buffer
+	writeVarInt(&ping, 0x01)
+	binary.Write(&ping, binary.BigEndian, time.Now().UnixMilli())
+	started := time.Now()
+	if err := writePacket(conn, ping.Bytes()); err != nil {
+		return nil, err
+	}
+	if _, err := readPacket(r); err != nil {
+		return nil, err
+	}
+	status.Latency = time.Since(started)
+
+	return status, nil
+}
+
+type minecraftStatus struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int    `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Max    uint32 `json:"max"`
+		Online uint32 `json:"online"`
+		Sample []struct {
+			Name string `json:"name"`
+		} `json:"sample"`
+	} `json:"players"`
+	Description json.RawMessage `json:"description"`
+}
+
+func parseMinecraftStatus(data []byte) (*Status, error) {
+	var s minecraftStatus
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	status := &Status{
+		Version:       s.Version.Name,
+		Motd:          chatText(s.Description),
+		PlayersOnline: s.Players.Online,
+		PlayersMax:    s.Players.Max,
+	}
+	for _, p := range s.Players.Sample {
+		status.Players = append(status.Players, p.Name)
+	}
+	return status, nil
+}
+
+// MOTD приходит строкой или chat-компонентом с вложенными extra
+func chatText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var component struct {
+		Text  string            `json:"text"`
+		Extra []json.RawMessage `json:"extra"`
+	}
+	if err := json.Unmarshal(raw, &component); err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(component.Text)
+	for _, e := range component.Extra {
+		b.WriteString(chatText(e))
+	}
+	return b.String()
+}
+
+func writePacket(w io.Writer, payload []byte) error {
+	var buf bytes.Buffer
+	writeVarInt(&buf, int32(len(payload)))
+	buf.Write(payload)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func readPacket(r *bufio.Reader) ([]byte, error) {
+	size, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if size <= 0 || size > maxSlpPacketSize {
+		return nil, fmt.Errorf("%w: packet size %d", ErrInvalidResponse, size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeVarInt(buf, int32(len(s)))
+	buf.WriteString(s)
+}
+
+func readString(r *bytes.Reader) ([]byte, error) {
+	n, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || int(n) > r.Len() {
+		return nil, fmt.Errorf("%w: string length %d", ErrInvalidResponse, n)
+	}
+
+	data := make([]byte, n)
+	_, err = io.ReadFull(r, data)
+	return data, err
+}
+
+func writeVarInt(buf *bytes.Buffer, v int32) {
+	u := uint32(v)
+	for u >= 0x80 {
+		buf.WriteByte(byte(u) | 0x80)
+		u >>= 7
+	}
+	buf.WriteByte(byte(u))
+}
+
+func readVarInt(r io.ByteReader) (int32, error) {
+	var v uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		v |= uint32(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(v), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: varint is too long", ErrInvalidResponse)
+}
@@ -0,0 +1,118 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMinecraft(t *testing.T) {
+	want := Status{
+		Version:       "1.20.1",
+		Motd:          "A Minecraft Server",
+		PlayersOnline: 2,
+		PlayersMax:    20,
+		Players:       []string{"alice", "bob"},
+	}
+
+	f, err := newFakeMinecraft(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	got, err := Minecraft(context.Background(), f.Addr())
+	if err != nil {
+		t.Fatalf("Minecraft: %v", err)
+	}
+	if got.Latency <= 0 {
+		t.Errorf("latency is not measured: %v", got.Latency)
+	}
+
+	got.Latency = 0
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+
+	// Статус читается заново при каждой проверке
+	f.SetStatus(Status{Version: "1.20.1", PlayersMax: 20})
+	got, err = Minecraft(context.Background(), f.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PlayersOnline != 0 || len(got.Players) != 0 {
+		t.Errorf("stale players: %+v", got)
+	}
+}
+
+func TestMinecraftClosedPort(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	if _, err := Minecraft(context.Background(), addr); err == nil {
+		t.Fatal("expected an error for a closed port")
+	}
+}
+
+func TestMinecraftTimeout(t *testing.T) {
+	// Сервер принимает соединение, но ничего не отвечает
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = Minecraft(ctx, l.Addr().String())
+	var ne net.Error
+	if !errors.As(err, &ne) || !ne.Timeout() {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestParseMinecraftStatus(t *testing.T) {
+	cases := map[string]struct {
+		data string
+		motd string
+		err  bool
+	}{
+		"string motd":    {data: `{"description":"hello"}`, motd: "hello"},
+		"chat component": {data: `{"description":{"text":"a","extra":[{"text":"b"},"c"]}}`, motd: "abc"},
+		"invalid json":   {data: `{"description":`, err: true},
+	}
+
+	for name, c := range cases {
+		status, err := parseMinecraftStatus([]byte(c.data))
+		if c.err {
+			if !errors.Is(err, ErrInvalidResponse) {
+				t.Errorf("%s: expected ErrInvalidResponse, got %v", name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if status.Motd != c.motd {
+			t.Errorf("%s: motd = %q, want %q", name, status.Motd, c.motd)
+		}
+	}
+}
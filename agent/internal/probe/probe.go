@@ -0,0 +1,35 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// Сколько ждать ответа сервера, если ctx не задаёт своего срока
+const DefaultTimeout = 5 * time.Second
+
+// Состояние игрового сервера глазами игрока
+type Status struct {
+	Latency       time.Duration
+	Version       string
+	Motd          string
+	PlayersOnline uint32
+	PlayersMax    uint32   // 0, если игра его не сообщает
+	Players       []string // Minecraft присылает только часть списка
+}
+
+// Срок ctx или DefaultTimeout ограничивает все операции с соединением,
+// отмена ctx прерывает их сразу
+func withDeadline(ctx context.Context, conn net.Conn) func() {
+	deadline := time.Now().Add(DefaultTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	return func() { stop() }
+}
@@ -0,0 +1,118 @@
+package rcon
+
+import (
+	"bufio"
+	"net"
+	"sync"
+)
+
+const fakeChunkSize = 4096
+
+// Локальный сервер RCON для проверок без игрового сервера. Принимает только
+// пароль password и отвечает на команды функцией handler
+type fakeServer struct {
+	l        net.Listener
+	password string
+	handler  func(command string) string
+
+	m        sync.Mutex
+	conns    map[net.Conn]struct{}
+	accepted int
+}
+
+func newFakeServer(password string, handler func(command string) string) (*fakeServer, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &fakeServer{l: l, password: password, handler: handler, conns: make(map[net.Conn]struct{})}
+	go s.serve()
+	return s, nil
+}
+
+func (s *fakeServer) Addr() string {
+	return s.l.Addr().String()
+}
+
+// Закрывает сервер и все подключения к нему
+func (s *fakeServer) Close() error {
+	err := s.l.Close()
+	s.Drop()
+	return err
+}
+
+// Обрывает подключения, как перезапуск игрового сервера
+func (s *fakeServer) Drop() {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// Сколько раз к серверу подключались
+func (s *fakeServer) Accepted() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.accepted
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+
+		s.m.Lock()
+		s.conns[conn] = struct{}{}
+		s.accepted++
+		s.m.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+// Ведёт себя как сервер Minecraft: перед ответом на авторизацию присылает
+// пустой RESPONSE_VALUE, а на пакеты неизвестного типа отвечает с тем же id
+func (s *fakeServer) handle(conn net.Conn) {
+	defer func() {
+		s.m.Lock()
+		delete(s.conns, conn)
+		s.m.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	authorized := false
+	for {
+		p, err := readPacket(r)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case p.typ == typeAuth:
+			writePacket(conn, p.id, typeResponseValue, "")
+			authorized = p.body == s.password
+			if !authorized {
+				writePacket(conn, -1, typeAuthResponse, "")
+				return
+			}
+			writePacket(conn, p.id, typeAuthResponse, "")
+		case !authorized:
+			return
+		case p.typ == typeExecCommand:
+			// Длинный ответ, как и у Minecraft, делится на пакеты по 4096 байт
+			out := s.handler(p.body)
+			for len(out) > fakeChunkSize {
+				writePacket(conn, p.id, typeResponseValue, out[:fakeChunkSize])
+				out = out[fakeChunkSize:]
+			}
+			writePacket(conn, p.id, typeResponseValue, out)
+		default:
+			writePacket(conn, p.id, typeResponseValue, "Unknown request 0")
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package rcon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Типы пакетов протокола Source RCON
+const (
+	typeResponseValue = 0
+	typeExecCommand   = 2
+	typeAuthResponse  = 2
+	typeAuth          = 3
+)
+
+const (
+	// Minecraft не принимает команды длиннее
+	MaxCommandLength = 1446
+
+	DefaultTimeout = 10 * time.Second
+
+	// Заголовок пакета без поля размера и два завершающих нуля
+	packetOverhead = 4 + 4 + 2
+
+	// Ответ сервера не бывает больше, кроме разбиения на несколько пакетов
+	maxPacketSize = 64 * 1024
+)
+
+var (
+	ErrAuthFailed      = errors.New("rcon authentication failed")
+	ErrCommandTooLong  = fmt.Errorf("rcon command is longer than %d bytes", MaxCommandLength)
+	ErrInvalidResponse = errors.New("invalid rcon response")
+)
+
+type Options struct {
+	Timeout time.Duration // Ожидание подключения и ответа на одну команду
+}
+
+// Клиент Source RCON (Minecraft, Factorio). Подключается и авторизуется при
+// первой команде, после обрыва соединения переподключается при следующей
+type Client struct {
+	addr     string
+	password string
+	opts     Options
+
+	m      sync.Mutex
+	conn   net.Conn
+	r      *bufio.Reader
+	lastId int32
+}
+
+func New(addr, password string, opts Options) *Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	return &Client{addr: addr, password: password, opts: opts}
+}
+
+// Выполняет команду и возвращает её вывод. Ответ, разбитый сервером на
+// несколько пакетов, собирается целиком
+func (c *Client) Exec(ctx context.Context, command string) (string, error) {
+	if len(command) > MaxCommandLength {
+		return "", ErrCommandTooLong
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	// Соединение могло оборваться, пока простаивало, например при перезапуске
+	// игрового сервера. Его проверяет пустой запрос: повтор самой команды мог бы
+	// выполнить её дважды
+	if c.conn != nil {
+		if err := c.ping(ctx); err != nil {
+			c.reset()
+		}
+	}
+
+	if err := c.connect(ctx); err != nil {
+		return "", err
+	}
+
+	out, err := c.exec(ctx, command)
+	if err != nil {
+		c.reset()
+		return "", err
+	}
+	return out, nil
+}
+
+func (c *Client) Close() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.reset()
+	return nil
+}
+
+func (c *Client) connect(ctx context.Context) error {
+	if c.conn != nil {
+		return nil
+	}
+
+	d := net.Dialer{Timeout: c.opts.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	c.conn, c.r = conn, bufio.NewReader(conn)
+
+	if err := c.auth(ctx); err != nil {
+		c.reset()
+		return err
+	}
+	return nil
+}
+
+func (c *Client) auth(ctx context.Context) error {
+	stop := c.deadline(ctx)
+	defer stop()
+
+	id := c.nextId()
+	if err := c.write(id, typeAuth, c.password); err != nil {
+		return err
+	}
+
+	// Source-серверы перед ответом на авторизацию присылают пустой RESPONSE_VALUE
+	for {
+		p, err := c.read()
+		if err != nil {
+			return err
+		}
+		if p.typ != typeAuthResponse {
+			continue
+		}
+
+		if p.id == -1 {
+			return ErrAuthFailed
+		}
+		if p.id != id {
+			return ErrInvalidResponse
+		}
+		return nil
+	}
+}
+
+// Конец ответа отмечается пакетом-маркером: сервер отвечает на него уже после
+// всех пакетов ответа на команду
+func (c *Client) exec(ctx context.Context, command string) (string, error) {
+	stop := c.deadline(ctx)
+	defer stop()
+
+	id := c.nextId()
+	if err := c.write(id, typeExecCommand, command); err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	err := c.waitMarker(func(p packet) {
+		if p.id == id {
+			out.WriteString(p.body)
+		}
+	})
+	return out.String(), err
+}
+
+func (c *Client) ping(ctx context.Context) error {
+	stop := c.deadline(ctx)
+	defer stop()
+
+	return c.waitMarker(func(packet) {})
+}
+
+// Отправляет маркер и читает пакеты, пока сервер не ответит на него
+func (c *Client) waitMarker(fn func(packet)) error {
+	marker := c.nextId()
+	if err := c.write(marker, typeResponseValue, ""); err != nil {
+		return err
+	}
+
+	for {
+		p, err := c.read()
+		if err != nil {
+			return err
+		}
+		if p.id == marker {
+			return nil
+		}
+		fn(p)
+	}
+}
+
+// Дедлайн соединения по таймауту и отмена ctx прерывают ожидание ответа
+func (c *Client) deadline(ctx context.Context) func() {
+	conn := c.conn
+	deadline := time.Now().Add(c.opts.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	return func() { stop() }
+}
+
+func (c *Client) nextId() int32 {
+	// -1 сервер использует для отказа в авторизации
+	c.lastId++
+	if c.lastId <= 0 {
+		c.lastId = 1
+	}
+	return c.lastId
+}
+
+func (c *Client) reset() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn, c.r = nil, nil
+}
+
+type packet struct {
+	id   int32
+	typ  int32
+	body string
+}
+
+func (c *Client) write(id, typ int32, body string) error {
+	return writePacket(c.conn, id, typ, body)
+}
+
+func (c *Client) read() (packet, error) {
+	return readPacket(c.r)
+}
+
+func writePacket(w io.Writer, id, typ int32, body string) error {
+	buf := make([]byte, 4, 4+packetOverhead+len(body))
+	binary.LittleEndian.PutUint32(buf, uint32(packetOverhead+len(body)))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(id))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(typ))
+	buf = append(buf, body...)
+	buf = append(buf, 0, 0)
+
+	_, err := w.Write(buf)
+	return err
+}
+
+func readPacket(r *bufio.Reader) (packet, error) {
+	var size int32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return packet{}, err
+	}
+	if size < packetOverhead || size > maxPacketSize {
+		return packet{}, fmt.Errorf("%w: packet size %d", ErrInvalidResponse, size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return packet{}, err
+	}
+
+	return packet{
+		id:   int32(binary.LittleEndian.Uint32(data[0:4])),
+		typ:  int32(binary.LittleEndian.Uint32(data[4:8])),
+		body: string(bytes.TrimRight(data[8:], "\x00")),
+	}, nil
+}
@@ -0,0 +1,132 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, handler func(command string) string) *fakeServer {
+	t.Helper()
+
+	s, err := newFakeServer("secret", handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func echo(command string) string {
+	return "you said " + command
+}
+
+func TestExec(t *testing.T) {
+	s := newTestServer(t, echo)
+	c := New(s.Addr(), "secret", Options{Timeout: time.Second})
+	defer c.Close()
+
+	for _, cmd := range []string{"list", "say hi"} {
+		out, err := c.Exec(context.Background(), cmd)
+		if err != nil {
+			t.Fatalf("Exec(%q): %v", cmd, err)
+		}
+		if out != "you said "+cmd {
+			t.Errorf("Exec(%q) = %q", cmd, out)
+		}
+	}
+
+	if n := s.Accepted(); n != 1 {
+		t.Errorf("commands used %d connections, want 1", n)
+	}
+}
+
+func TestExecResponseSplitAcrossPackets(t *testing.T) {
+	long := strings.Repeat("0123456789", 1000)
+	s := newTestServer(t, func(command string) string {
+		if command == "help" {
+			return long
+		}
+		return echo(command)
+	})
+	c := New(s.Addr(), "secret", Options{Timeout: time.Second})
+	defer c.Close()
+
+	out, err := c.Exec(context.Background(), "help")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != long {
+		t.Errorf("got %d bytes, want %d", len(out), len(long))
+	}
+
+	// Следующая команда не получает хвост предыдущего ответа
+	if out, err := c.Exec(context.Background(), "list"); err != nil || out != "you said list" {
+		t.Errorf("Exec = %q, %v", out, err)
+	}
+}
+
+func TestExecReconnectsAfterDrop(t *testing.T) {
+	s := newTestServer(t, echo)
+	c := New(s.Addr(), "secret", Options{Timeout: time.Second})
+	defer c.Close()
+
+	if _, err := c.Exec(context.Background(), "first"); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Drop()
+
+	out, err := c.Exec(context.Background(), "second")
+	if err != nil {
+		t.Fatalf("Exec after drop: %v", err)
+	}
+	if out != "you said second" {
+		t.Errorf("Exec after drop = %q", out)
+	}
+	if n := s.Accepted(); n != 2 {
+		t.Errorf("accepted %d connections, want 2", n)
+	}
+}
+
+func TestExecAuthFailed(t *testing.T) {
+	s := newTestServer(t, echo)
+	c := New(s.Addr(), "wrong", Options{Timeout: time.Second})
+	defer c.Close()
+
+	if _, err := c.Exec(context.Background(), "list"); !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("expected ErrAuthFailed, got %v", err)
+	}
+}
+
+func TestExecCommandTooLong(t *testing.T) {
+	c := New("127.0.0.1:1", "secret", Options{})
+	if _, err := c.Exec(context.Background(), strings.Repeat("a", MaxCommandLength+1)); !errors.Is(err, ErrCommandTooLong) {
+		t.Errorf("expected ErrCommandTooLong, got %v", err)
+	}
+}
+
+func TestExecCancelled(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	s := newTestServer(t, func(string) string {
+		<-block
+		return ""
+	})
+	c := New(s.Addr(), "secret", Options{Timeout: 5 * time.Second})
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	if _, err := c.Exec(ctx, "hang"); err == nil {
+		t.Fatal("expected an error for a cancelled command")
+	}
+	if d := time.Since(started); d > 2*time.Second {
+		t.Errorf("cancellation took %v", d)
+	}
+}
@@ -0,0 +1,500 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultDockerSocket = "/var/run/docker.sock"
+
+	dockerApiVersion = "v1.43"
+)
+
+// Драйвер Docker Engine API поверх unix-сокета
+type Docker struct {
+	c *http.Client
+}
+
+func NewDocker(socket string) *Docker {
+	if socket == "" {
+		socket = DefaultDockerSocket
+	}
+
+	return &Docker{
+		c: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
+
+func (d *Docker) Ping(ctx context.Context) error {
+	resp, err := d.do(ctx, http.MethodGet, "/_ping", nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+type dockerCreateRequest struct {
+	Image        string              `json:"Image"`
+	Cmd          []string            `json:"Cmd,omitempty"`
+	Env          []string            `json:"Env,omitempty"`
+	Labels       map[string]string   `json:"Labels,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	HostConfig   dockerHostConfig    `json:"HostConfig"`
+}
+
+type dockerHostConfig struct {
+	PortBindings map[string][]dockerPortBinding `json:"PortBindings,omitempty"`
+	Mounts       []dockerMount                  `json:"Mounts,omitempty"`
+	StorageOpt   map[string]string              `json:"StorageOpt,omitempty"`
+	dockerResources
+}
+
+// Ограничения в формате docker. Те же поля принимает /containers/{id}/update
+type dockerResources struct {
+	NanoCpus   int64 `json:"NanoCpus,omitempty"`
+	CpuShares  int64 `json:"CpuShares,omitempty"`
+	Memory     int64 `json:"Memory,omitempty"`
+	MemorySwap int64 `json:"MemorySwap,omitempty"`
+}
+
+func newDockerResources(res Resources) dockerResources {
+	r := dockerResources{
+		NanoCpus:  int64(res.CpuCores * 1e9),
+		CpuShares: int64(res.CpuShares),
+		Memory:    int64(res.MemoryBytes),
+	}
+	// Без swap лимит памяти действительно ограничивает сервер
+	if res.MemoryBytes > 0 {
+		r.MemorySwap = r.Memory
+	}
+	return r
+}
+
+type dockerPortBinding struct {
+	HostPort string `json:"HostPort"`
+}
+
+type dockerMount struct {
+	Type     string `json:"Type"`
+	Source   string `json:"Source"`
+	Target   string `json:"Target"`
+	ReadOnly bool   `json:"ReadOnly"`
+}
+
+func (d *Docker) Create(ctx context.Context, spec ContainerSpec) (string, error) {
+	body := dockerCreateRequest{
+		Image:        spec.Image,
+		Cmd:          spec.Cmd,
+		Labels:       spec.Labels,
+		ExposedPorts: make(map[string]struct{}),
+		HostConfig: dockerHostConfig{
+			PortBindings:    make(map[string][]dockerPortBinding),
+			dockerResources: newDockerResources(spec.Resources),
+		},
+	}
+
+	// Квоту диска поддерживают не все драйверы хранилища, например overlay2 только на xfs с pquota
+	if spec.Resources.DiskBytes > 0 {
+		body.HostConfig.StorageOpt = map[string]string{"size": strconv.FormatUint(spec.Resources.DiskBytes, 10)}
+	}
+
+	for k, v := range spec.Env {
+		body.Env = append(body.Env, k+"="+v)
+	}
+
+	for _, p := range spec.Ports {
+		proto := p.Protocol
+		if proto == "" {
+			proto = "tcp"
+		}
+
+		key := fmt.Sprintf("%d/%s", p.ContainerPort, proto)
+		body.ExposedPorts[key] = struct{}{}
+		body.HostConfig.PortBindings[key] = append(body.HostConfig.PortBindings[key], dockerPortBinding{
+			HostPort: strconv.Itoa(int(p.HostPort)),
+		})
+	}
+
+	for _, m := range spec.Mounts {
+		body.HostConfig.Mounts = append(body.HostConfig.Mounts, dockerMount{
+			Type:     "bind",
+			Source:   m.Source,
+			Target:   m.Target,
+			ReadOnly: m.ReadOnly,
+		})
+	}
+
+	query := url.Values{}
+	if spec.Name != "" {
+		query.Set("name", spec.Name)
+	}
+
+	id, err := d.create(ctx, query, body)
+	if errors.Is(err, ErrNotFound) {
+		// Образа ещё нет на хосте
+		if err := d.pull(ctx, spec.Image); err != nil {
+			return "", fmt.Errorf("failed to pull image %s: %w", spec.Image, err)
+		}
+		id, err = d.create(ctx, query, body)
+	}
+
+	return id, err
+}
+
+func (d *Docker) create(ctx context.Context, query url.Values, body dockerCreateRequest) (string, error) {
+	resp, err := d.do(ctx, http.MethodPost, "/containers/create", query, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var created struct {
+		Id string `json:"Id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("failed to decode create response: %w", err)
+	}
+	return created.Id, nil
+}
+
+func (d *Docker) pull(ctx context.Context, image string) error {
+	name, tag := image, "latest"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, tag = image[:i], image[i+1:]
+	}
+
+	resp, err := d.do(ctx, http.MethodPost, "/images/create", url.Values{"fromImage": {name}, "tag": {tag}}, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Docker присылает прогресс загрузки потоком JSON-сообщений, ошибка приходит последним сообщением
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var msg struct {
+			Error string `json:"error"`
+		}
+		if err := dec.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if msg.Error != "" {
+			return errors.New(msg.Error)
+		}
+	}
+}
+
+func (d *Docker) Start(ctx context.Context, id string) error {
+	resp, err := d.do(ctx, http.MethodPost, "/containers/"+id+"/start", nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (d *Docker) Stop(ctx context.Context, id string, timeout time.Duration) error {
+	query := url.Values{"t": {strconv.Itoa(int(timeout / time.Second))}}
+	resp, err := d.do(ctx, http.MethodPost, "/containers/"+id+"/stop", query, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (d *Docker) Remove(ctx context.Context, id string) error {
+	resp, err := d.do(ctx, http.MethodDelete, "/containers/"+id, url.Values{"force": {"true"}}, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (d *Docker) Inspect(ctx context.Context, id string) (*ContainerState, error) {
+	resp, err := d.do(ctx, http.MethodGet, "/containers/"+id+"/json", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var info struct {
+		Id     string `json:"Id"`
+		Name   string `json:"Name"`
+		Config struct {
+			Image  string            `json:"Image"`
+			Labels map[string]string `json:"Labels"`
+		} `json:"Config"`
+		State struct {
+			Status     string    `json:"Status"`
+			Running    bool      `json:"Running"`
+			ExitCode   int       `json:"ExitCode"`
+			OOMKilled  bool      `json:"OOMKilled"`
+			StartedAt  time.Time `json:"StartedAt"`
+			FinishedAt time.Time `json:"FinishedAt"`
+		} `json:"State"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to decode inspect response: %w", err)
+	}
+
+	return &ContainerState{
+		Id:         info.Id,
+		Name:       strings.TrimPrefix(info.Name, "/"),
+		Image:      info.Config.Image,
+		Status:     info.State.Status,
+		Running:    info.State.Running,
+		Labels:     info.Config.Labels,
+		ExitCode:   info.State.ExitCode,
+		OOMKilled:  info.State.OOMKilled,
+		StartedAt:  info.State.StartedAt,
+		FinishedAt: info.State.FinishedAt,
+	}, nil
+}
+
+func (d *Docker) List(ctx context.Context) ([]ContainerState, error) {
+	resp, err := d.do(ctx, http.MethodGet, "/containers/json", url.Values{"all": {"true"}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var list []struct {
+		Id     string            `json:"Id"`
+		Names  []string          `json:"Names"`
+		Image  string            `json:"Image"`
+		State  string            `json:"State"`
+		Labels map[string]string `json:"Labels"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode list response: %w", err)
+	}
+
+	res := make([]ContainerState, len(list))
+	for i, c := range list {
+		res[i] = ContainerState{
+			Id:      c.Id,
+			Image:   c.Image,
+			Status:  c.State,
+			Running: c.State == "running",
+			Labels:  c.Labels,
+		}
+		if len(c.Names) > 0 {
+			res[i].Name = strings.TrimPrefix(c.Names[0], "/")
+		}
+	}
+	return res, nil
+}
+
+func (d *Docker) Update(ctx context.Context, id string, res Resources) error {
+	resp, err := d.do(ctx, http.MethodPost, "/containers/"+id+"/update", nil, newDockerResources(res))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (d *Docker) Logs(ctx context.Context, id string, opts LogsOptions) (io.ReadCloser, error) {
+	query := url.Values{
+		"stdout":     {"true"},
+		"stderr":     {"true"},
+		"follow":     {strconv.FormatBool(opts.Follow)},
+		"timestamps": {strconv.FormatBool(opts.Timestamps)},
+	}
+	if opts.Tail > 0 {
+		query.Set("tail", strconv.Itoa(opts.Tail))
+	}
+	if !opts.Since.IsZero() {
+		query.Set("since", strconv.FormatInt(opts.Since.Unix(), 10))
+	}
+
+	resp, err := d.do(ctx, http.MethodGet, "/containers/"+id+"/logs", query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Контейнеры без TTY отдают мультиплексированный поток stdout/stderr
+	if resp.Header.Get("Content-Type") == "application/vnd.docker.raw-stream" {
+		return resp.Body, nil
+	}
+	return &demuxReader{r: resp.Body}, nil
+}
+
+func (d *Docker) Stats(ctx context.Context, id string) (*Stats, error) {
+	resp, err := d.do(ctx, http.MethodGet, "/containers/"+id+"/stats", url.Values{"stream": {"false"}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	type cpuStats struct {
+		CpuUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+		SystemCpuUsage uint64 `json:"system_cpu_usage"`
+		OnlineCpus     uint32 `json:"online_cpus"`
+	}
+
+	var stats struct {
+		CpuStats    cpuStats `json:"cpu_stats"`
+		PreCpuStats cpuStats `json:"precpu_stats"`
+		MemoryStats struct {
+			Usage uint64 `json:"usage"`
+			Limit uint64 `json:"limit"`
+		} `json:"memory_stats"`
+		Networks map[string]struct {
+			RxBytes uint64 `json:"rx_bytes"`
+			TxBytes uint64 `json:"tx_bytes"`
+		} `json:"networks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("failed to decode stats response: %w", err)
+	}
+
+	res := &Stats{
+		MemoryUsage: stats.MemoryStats.Usage,
+		MemoryLimit: stats.MemoryStats.Limit,
+	}
+
+	cpuDelta := float64(stats.CpuStats.CpuUsage.TotalUsage) - float64(stats.PreCpuStats.CpuUsage.TotalUsage)
+	systemDelta := float64(stats.CpuStats.SystemCpuUsage) - float64(stats.PreCpuStats.SystemCpuUsage)
+	if cpuDelta > 0 && systemDelta > 0 {
+		res.CpuUsage = cpuDelta / systemDelta * float64(max(stats.CpuStats.OnlineCpus, 1)) * 100
+	}
+
+	for _, n := range stats.Networks {
+		res.NetRxBytes += n.RxBytes
+		res.NetTxBytes += n.TxBytes
+	}
+
+	return res, nil
+}
+
+func (d *Docker) DiskUsage(ctx context.Context, id string) (uint64, error) {
+	resp, err := d.do(ctx, http.MethodGet, "/containers/"+id+"/json", url.Values{"size": {"true"}}, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var info struct {
+		SizeRw int64 `json:"SizeRw"`
+		Mounts []struct {
+			Type   string `json:"Type"`
+			Source string `json:"Source"`
+			RW     bool   `json:"RW"`
+		} `json:"Mounts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return 0, fmt.Errorf("failed to decode inspect response: %w", err)
+	}
+
+	usage := uint64(max(info.SizeRw, 0))
+	for _, m := range info.Mounts {
+		if m.Type != "bind" || !m.RW {
+			continue
+		}
+		size, err := dirSize(ctx, m.Source)
+		if err != nil {
+			return 0, fmt.Errorf("failed to measure %s: %w", m.Source, err)
+		}
+		usage += size
+	}
+	return usage, nil
+}
+
+func (d *Docker) do(ctx context.Context, method, path string, query url.Values, body any) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+
+	u := "http://docker/" + dockerApiVersion + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := d.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 304 - контейнер уже запущен или уже остановлен
+	if resp.StatusCode < 300 || resp.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	var msg struct {
+		Message string `json:"message"`
+	}
+	json.NewDecoder(resp.Body).Decode(&msg)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, msg.Message)
+	}
+	return nil, fmt.Errorf("docker %s %s: %d %s", method, path, resp.StatusCode, msg.Message)
+}
+
+// Убирает 8-байтовые заголовки кадров мультиплексированного потока docker
+type demuxReader struct {
+	r    io.ReadCloser
+	left uint32
+}
+
+func (d *demuxReader) Read(p []byte) (int, error) {
+	for d.left == 0 {
+		var header [8]byte
+		if _, err := io.ReadFull(d.r, header[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF
+			}
+			return 0, err
+		}
+		d.left = binary.BigEndian.Uint32(header[4:])
+	}
+
+	if uint32(len(p)) > d.left {
+		p = p[:d.left]
+	}
+
+	n, err := d.r.Read(p)
+	d.left -= uint32(n)
+	return n, err
+}
+
+func (d *demuxReader) Close() error {
+	return d.r.Close()
+}
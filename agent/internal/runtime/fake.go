@@ -0,0 +1,279 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Среда запуска в памяти: ничего не запускает, только хранит состояние контейнеров.
+// Нужна для тестов и запуска агента на хостах без docker
+type Fake struct {
+	m          sync.Mutex
+	containers map[string]*fakeContainer
+}
+
+type fakeContainer struct {
+	spec  ContainerSpec
+	state ContainerState
+	logs  bytes.Buffer
+	stats Stats
+	disk  uint64
+}
+
+func NewFake() *Fake {
+	return &Fake{containers: make(map[string]*fakeContainer)}
+}
+
+func (f *Fake) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (f *Fake) Create(ctx context.Context, spec ContainerSpec) (string, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	for _, c := range f.containers {
+		if spec.Name != "" && c.spec.Name == spec.Name {
+			return "", fmt.Errorf("container name %q is already in use", spec.Name)
+		}
+	}
+
+	id := strings.ReplaceAll(uuid.New().String(), "-", "")
+	f.containers[id] = &fakeContainer{
+		spec: spec,
+		state: ContainerState{
+			Id:     id,
+			Name:   spec.Name,
+			Image:  spec.Image,
+			Status: "created",
+			Labels: spec.Labels,
+		},
+	}
+	return id, nil
+}
+
+func (f *Fake) Start(ctx context.Context, id string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return err
+	}
+
+	c.state.Status = "running"
+	c.state.Running = true
+	c.state.ExitCode = 0
+	c.state.OOMKilled = false
+	c.state.StartedAt = time.Now()
+	return nil
+}
+
+func (f *Fake) Stop(ctx context.Context, id string, timeout time.Duration) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return err
+	}
+
+	if c.state.Running {
+		c.state.Status = "exited"
+		c.state.Running = false
+		c.state.FinishedAt = time.Now()
+	}
+	return nil
+}
+
+func (f *Fake) Remove(ctx context.Context, id string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return err
+	}
+
+	delete(f.containers, c.state.Id)
+	return nil
+}
+
+func (f *Fake) Inspect(ctx context.Context, id string) (*ContainerState, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	state := c.state
+	return &state, nil
+}
+
+func (f *Fake) List(ctx context.Context) ([]ContainerState, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	res := make([]ContainerState, 0, len(f.containers))
+	for _, c := range f.containers {
+		res = append(res, c.state)
+	}
+	return res, nil
+}
+
+func (f *Fake) Update(ctx context.Context, id string, res Resources) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return err
+	}
+
+	// Как и docker, квоту диска после создания не меняем
+	res.DiskBytes = c.spec.Resources.DiskBytes
+	c.spec.Resources = res
+	return nil
+}
+
+// Ограничения, с которыми сейчас работает контейнер
+func (f *Fake) Resources(id string) (Resources, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return Resources{}, err
+	}
+	return c.spec.Resources, nil
+}
+
+// Возвращает уже записанный через WriteLogs вывод, опция Follow не поддерживается
+func (f *Fake) Logs(ctx context.Context, id string, opts LogsOptions) (io.ReadCloser, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := c.logs.String()
+	if opts.Tail > 0 {
+		lines := strings.SplitAfter(logs, "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		logs = strings.Join(lines[max(len(lines)-opts.Tail, 0):], "")
+	}
+
+	return io.NopCloser(strings.NewReader(logs)), nil
+}
+
+func (f *Fake) Stats(ctx context.Context, id string) (*Stats, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := c.stats
+	return &stats, nil
+}
+
+func (f *Fake) DiskUsage(ctx context.Context, id string) (uint64, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return 0, err
+	}
+	return c.disk, nil
+}
+
+// Дописывает строки в вывод контейнера
+func (f *Fake) WriteLogs(id string, data string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return err
+	}
+
+	c.logs.WriteString(data)
+	return nil
+}
+
+// Задаёт значения, которые будет возвращать Stats
+func (f *Fake) SetStats(id string, stats Stats) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return err
+	}
+
+	c.stats = stats
+	return nil
+}
+
+// Задаёт значение, которое будет возвращать DiskUsage
+func (f *Fake) SetDiskUsage(id string, bytes uint64) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return err
+	}
+
+	c.disk = bytes
+	return nil
+}
+
+// Имитирует завершение процесса в контейнере
+func (f *Fake) Exit(id string, code int, oomKilled bool) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c, err := f.get(id)
+	if err != nil {
+		return err
+	}
+
+	c.state.Status = "exited"
+	c.state.Running = false
+	c.state.ExitCode = code
+	c.state.OOMKilled = oomKilled
+	c.state.FinishedAt = time.Now()
+	return nil
+}
+
+// Контейнер ищется по id или по имени, как в docker
+func (f *Fake) get(id string) (*fakeContainer, error) {
+	if c, ok := f.containers[id]; ok {
+		return c, nil
+	}
+
+	for _, c := range f.containers {
+		if c.spec.Name == id {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("container not found")
+)
+
+// Среда запуска контейнеров с игровыми серверами
+type Runtime interface {
+	// Проверяет доступность среды запуска
+	Ping(ctx context.Context) error
+
+	Create(ctx context.Context, spec ContainerSpec) (string, error)
+	Start(ctx context.Context, id string) error
+	Stop(ctx context.Context, id string, timeout time.Duration) error
+	Remove(ctx context.Context, id string) error
+	Inspect(ctx context.Context, id string) (*ContainerState, error)
+
+	// Все контейнеры, включая остановленные
+	List(ctx context.Context) ([]ContainerState, error)
+
+	// Меняет ограничения CPU и памяти запущенного контейнера. Квота диска
+	// задаётся только при создании
+	Update(ctx context.Context, id string, res Resources) error
+
+	// Вывод контейнера (stdout и stderr вместе)
+	Logs(ctx context.Context, id string, opts LogsOptions) (io.ReadCloser, error)
+
+	// Текущее потребление ресурсов контейнером
+	Stats(ctx context.Context, id string) (*Stats, error)
+
+	// Место на диске: записываемый слой контейнера и подключённые на запись
+	// каталоги хоста. Требует обхода файлов, поэтому не входит в Stats
+	DiskUsage(ctx context.Context, id string) (uint64, error)
+}
+
+type ContainerSpec struct {
+	Name   string
+	Image  string
+	Cmd    []string
+	Env    map[string]string
+	Labels map[string]string
+	Ports  []PortBinding
+	Mounts []Mount
+
+	Resources Resources
+}
+
+// Ограничения ресурсов контейнера. Нулевое значение - без ограничения
+type Resources struct {
+	CpuCores    float64 // Жёсткая квота в ядрах
+	CpuShares   uint32  // Относительный вес при конкуренции за CPU
+	MemoryBytes uint64  // При превышении процесс убивается OOM killer
+	DiskBytes   uint64  // Размер записываемого слоя контейнера
+}
+
+type PortBinding struct {
+	HostPort      uint16
+	ContainerPort uint16
+	Protocol      string // tcp или udp, по умолчанию tcp
+}
+
+type Mount struct {
+	Source   string // Каталог на хосте
+	Target   string // Путь внутри контейнера
+	ReadOnly bool
+}
+
+type ContainerState struct {
+	Id         string
+	Name       string
+	Image      string
+	Status     string // created, running, paused, restarting, exited, dead
+	Running    bool
+	Labels     map[string]string
+	ExitCode   int
+	OOMKilled  bool
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+type LogsOptions struct {
+	Follow     bool      // Продолжать читать новый вывод
+	Tail       int       // Сколько последних строк вернуть, 0 - все
+	Since      time.Time // Вывод начиная с момента времени
+	Timestamps bool      // Добавлять время к каждой строке
+}
+
+type Stats struct {
+	CpuUsage    float64 // Загрузка CPU в процентах от одного ядра
+	MemoryUsage uint64
+	MemoryLimit uint64
+	NetRxBytes  uint64
+	NetTxBytes  uint64
+}
@@ -0,0 +1,65 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	Token string `json:"token"`
+}
+
+// Получает токен через REST API сервера. Нужен websocket-транспорту:
+// подключение к /api/agents/ws требует авторизации ещё до первого вызова
+func Login(ctx context.Context, client *http.Client, baseUrl, username, password string) (string, error) {
+	body, err := json.Marshal(loginRequest{Username: username, Password: password})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(baseUrl, "/")+"/api/auth/login", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("login failed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var lr loginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&lr); err != nil {
+		return "", fmt.Errorf("failed to decode login response: %w", err)
+	}
+	return lr.Token, nil
+}
+
+// Адрес websocket-эндпоинта агентов для базового HTTP-адреса сервера
+func WebsocketUrl(baseUrl string) string {
+	u := strings.TrimSuffix(baseUrl, "/") + "/api/agents/ws"
+	switch {
+	case strings.HasPrefix(u, "https://"):
+		return "wss://" + strings.TrimPrefix(u, "https://")
+	case strings.HasPrefix(u, "http://"):
+		return "ws://" + strings.TrimPrefix(u, "http://")
+	default:
+		return u
+	}
+}
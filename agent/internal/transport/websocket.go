@@ -0,0 +1,387 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Сколько кадров вызова буферизуется, пока вызывающий их не прочитал
+const callBuffer = 16
+
+var errConnClosed = errors.New("websocket connection closed")
+
+// Возвращает токен для авторизации websocket-подключения
+type TokenFunc func(ctx context.Context) (string, error)
+
+// Клиент gRPC-сервисов сервера поверх websocket. Реализует grpc.ClientConnInterface,
+// поэтому сгенерированные клиенты api работают с ним так же, как с grpc.ClientConn.
+// Подключение устанавливается при первом вызове и заново после обрыва
+type Websocket struct {
+	url    string
+	dialer *websocket.Dialer
+	token  TokenFunc
+
+	m    sync.Mutex
+	conn *wsClientConn
+}
+
+// tlsConfig может быть nil, тогда для wss используются настройки по умолчанию
+func NewWebsocket(url string, tlsConfig *tls.Config, token TokenFunc) *Websocket {
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = tlsConfig
+
+	return &Websocket{url: url, dialer: &dialer, token: token}
+}
+
+func (w *Websocket) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	call, err := w.open(ctx, method)
+	if err != nil {
+		return err
+	}
+	defer call.finish()
+
+	if err := call.send(args.(proto.Message)); err != nil {
+		return err
+	}
+	if err := call.closeSend(); err != nil {
+		return err
+	}
+
+	if err := call.recv(reply.(proto.Message)); err != nil {
+		if err == io.EOF {
+			return status.Error(codes.Internal, "server closed call without response")
+		}
+		return err
+	}
+
+	// Дожидаемся статуса вызова
+	if err := call.recv(nil); err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func (w *Websocket) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	call, err := w.open(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			call.finish()
+		case <-call.done:
+		}
+	}()
+
+	return &wsClientStream{ctx: ctx, call: call}, nil
+}
+
+// Закрывает текущее подключение
+func (w *Websocket) Close() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.ws.Close()
+	w.conn = nil
+	return err
+}
+
+func (w *Websocket) open(ctx context.Context, method string) (*wsClientCall, error) {
+	conn, err := w.connect(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to connect: %v", err)
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	headers := make(map[string]string, len(md))
+	for k, v := range md {
+		if len(v) > 0 {
+			headers[k] = v[0]
+		}
+	}
+
+	return conn.open(method, headers)
+}
+
+func (w *Websocket) connect(ctx context.Context) (*wsClientConn, error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.conn != nil && !w.conn.isClosed() {
+		return w.conn, nil
+	}
+
+	header := http.Header{}
+	if w.token != nil {
+		token, err := w.token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		header.Set("Authorization", token)
+	}
+
+	ws, _, err := w.dialer.DialContext(ctx, w.url, header)
+	if err != nil {
+		return nil, err
+	}
+
+	w.conn = newWsClientConn(ws)
+	return w.conn, nil
+}
+
+type wsClientConn struct {
+	ws *websocket.Conn
+
+	writeM sync.Mutex
+
+	m      sync.Mutex
+	nextId uint64
+	calls  map[uint64]*wsClientCall
+	closed chan struct{}
+	err    error
+}
+
+func newWsClientConn(ws *websocket.Conn) *wsClientConn {
+	c := &wsClientConn{
+		ws:     ws,
+		calls:  make(map[uint64]*wsClientCall),
+		closed: make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+func (c *wsClientConn) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *wsClientConn) open(method string, headers map[string]string) (*wsClientCall, error) {
+	c.m.Lock()
+	if c.err != nil {
+		c.m.Unlock()
+		return nil, status.Error(codes.Unavailable, c.err.Error())
+	}
+	c.nextId++
+	call := &wsClientCall{
+		id:   c.nextId,
+		conn: c,
+		in:   make(chan *api.Frame, callBuffer),
+		done: make(chan struct{}),
+	}
+	c.calls[call.id] = call
+	c.m.Unlock()
+
+	err := c.write(&api.Frame{CallId: call.id, Type: api.FrameType_FRAME_TYPE_OPEN, Method: method, Metadata: headers})
+	if err != nil {
+		call.finish()
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return call, nil
+}
+
+func (c *wsClientConn) readLoop() {
+	var err error
+	defer func() {
+		c.m.Lock()
+		c.err = err
+		calls := c.calls
+		c.calls = make(map[uint64]*wsClientCall)
+		c.m.Unlock()
+
+		close(c.closed)
+		for _, call := range calls {
+			call.fail(status.Errorf(codes.Unavailable, "connection lost: %v", err))
+		}
+		c.ws.Close()
+	}()
+
+	for {
+		var data []byte
+		_, data, err = c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		f := &api.Frame{}
+		if err = proto.Unmarshal(data, f); err != nil {
+			return
+		}
+
+		c.m.Lock()
+		call := c.calls[f.CallId]
+		c.m.Unlock()
+		if call == nil {
+			continue
+		}
+
+		select {
+		case call.in <- f:
+		case <-call.done:
+		}
+	}
+}
+
+func (c *wsClientConn) write(f *api.Frame) error {
+	data, err := proto.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	c.writeM.Lock()
+	defer c.writeM.Unlock()
+
+	if c.isClosed() {
+		return errConnClosed
+	}
+	return c.ws.WriteMessage(websocket.BinaryMessage, data)
+}
+
+// Один вызов в рамках websocket-подключения
+type wsClientCall struct {
+	id   uint64
+	conn *wsClientConn
+	in   chan *api.Frame
+
+	once sync.Once
+	done chan struct{}
+
+	m      sync.Mutex
+	failed error // подключение оборвалось
+	ended  error // сервер завершил вызов; io.EOF при успехе
+}
+
+func (c *wsClientCall) send(m proto.Message) error {
+	payload, err := proto.Marshal(m)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal message: %v", err)
+	}
+
+	if err := c.conn.write(&api.Frame{CallId: c.id, Type: api.FrameType_FRAME_TYPE_MESSAGE, Payload: payload}); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return nil
+}
+
+func (c *wsClientCall) closeSend() error {
+	if err := c.conn.write(&api.Frame{CallId: c.id, Type: api.FrameType_FRAME_TYPE_HALF_CLOSE}); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return nil
+}
+
+// Читает следующее сообщение в m. После завершения вызова сервером возвращает io.EOF
+// или ошибку со статусом вызова
+func (c *wsClientCall) recv(m proto.Message) error {
+	c.m.Lock()
+	ended := c.ended
+	c.m.Unlock()
+	if ended != nil {
+		return ended
+	}
+
+	select {
+	case f := <-c.in:
+		switch f.Type {
+		case api.FrameType_FRAME_TYPE_MESSAGE:
+			if m == nil {
+				return status.Error(codes.Internal, "unexpected message")
+			}
+			return proto.Unmarshal(f.Payload, m)
+		case api.FrameType_FRAME_TYPE_END:
+			err := io.EOF
+			if codes.Code(f.StatusCode) != codes.OK {
+				err = status.Error(codes.Code(f.StatusCode), f.StatusMessage)
+			}
+			c.m.Lock()
+			c.ended = err
+			c.m.Unlock()
+			c.finish()
+			return err
+		default:
+			return status.Errorf(codes.Internal, "unexpected frame %v", f.Type)
+		}
+	case <-c.done:
+		c.m.Lock()
+		defer c.m.Unlock()
+		if c.ended != nil {
+			return c.ended
+		}
+		if c.failed != nil {
+			return c.failed
+		}
+		return status.Error(codes.Canceled, "call cancelled")
+	}
+}
+
+func (c *wsClientCall) fail(err error) {
+	c.m.Lock()
+	c.failed = err
+	c.m.Unlock()
+	c.once.Do(func() { close(c.done) })
+}
+
+// Освобождает вызов. Если сервер ещё не завершил его, вызов отменяется
+func (c *wsClientCall) finish() {
+	c.once.Do(func() {
+		close(c.done)
+
+		c.m.Lock()
+		ended := c.ended != nil
+		c.m.Unlock()
+		if !ended {
+			c.conn.write(&api.Frame{CallId: c.id, Type: api.FrameType_FRAME_TYPE_CANCEL})
+		}
+
+		c.conn.m.Lock()
+		delete(c.conn.calls, c.id)
+		c.conn.m.Unlock()
+	})
+}
+
+// Потоковый вызов поверх websocket со стороны клиента
+type wsClientStream struct {
+	ctx  context.Context
+	call *wsClientCall
+}
+
+func (s *wsClientStream) Header() (metadata.MD, error) { return nil, nil }
+func (s *wsClientStream) Trailer() metadata.MD         { return nil }
+
+func (s *wsClientStream) CloseSend() error {
+	return s.call.closeSend()
+}
+
+func (s *wsClientStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *wsClientStream) SendMsg(m any) error {
+	return s.call.send(m.(proto.Message))
+}
+
+func (s *wsClientStream) RecvMsg(m any) error {
+	return s.call.recv(m.(proto.Message))
+}
@@ -0,0 +1,135 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vv-sam/otus-project/agent/internal/runtime"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Как часто пересчитывать место на диске, если не задано
+const DefaultDiskInterval = time.Minute
+
+type Options struct {
+	Prefix        string        // Префикс имён контейнеров игровых серверов
+	RevisionLabel string        // Метка контейнера с ревизией развёрнутой конфигурации
+	DiskInterval  time.Duration // Обход файлов дорогой, поэтому размер кешируется
+}
+
+type diskSample struct {
+	at    time.Time
+	bytes uint64
+}
+
+// Собирает потребление ресурсов игровыми серверами из статистики среды запуска.
+// Сервер определяется по имени контейнера: префикс и id конфигурации
+type Tracker struct {
+	rt   runtime.Runtime
+	opts Options
+
+	m    sync.Mutex
+	disk map[string]diskSample
+}
+
+func New(rt runtime.Runtime, opts Options) *Tracker {
+	if opts.DiskInterval <= 0 {
+		opts.DiskInterval = DefaultDiskInterval
+	}
+
+	return &Tracker{rt: rt, opts: opts, disk: make(map[string]diskSample)}
+}
+
+// Потребление всех игровых серверов агента, включая остановленные.
+// Ошибка одного сервера не мешает собрать остальные, она возвращается вместе с ними
+func (t *Tracker) Collect(ctx context.Context) ([]*api.ServerUsage, error) {
+	containers, err := t.rt.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	now := time.Now()
+	seen := make(map[string]struct{})
+	var res []*api.ServerUsage
+	var errs []error
+	for _, c := range containers {
+		id, ok := strings.CutPrefix(c.Name, t.opts.Prefix)
+		if !ok || id == "" {
+			continue
+		}
+		seen[c.Name] = struct{}{}
+
+		u, err := t.collect(ctx, c.Name, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("server %s: %w", id, err))
+			continue
+		}
+		u.ConfigurationId = id
+		res = append(res, u)
+	}
+
+	t.m.Lock()
+	for name := range t.disk {
+		if _, ok := seen[name]; !ok {
+			delete(t.disk, name)
+		}
+	}
+	t.m.Unlock()
+
+	return res, errors.Join(errs...)
+}
+
+func (t *Tracker) collect(ctx context.Context, name string, now time.Time) (*api.ServerUsage, error) {
+	// Список контейнеров не сообщает о причине остановки
+	state, err := t.rt.Inspect(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &api.ServerUsage{Running: state.Running, Revision: state.Labels[t.opts.RevisionLabel]}
+	if state.OOMKilled && !state.FinishedAt.IsZero() {
+		u.OomKilledAt = timestamppb.New(state.FinishedAt)
+	}
+
+	if state.Running {
+		stats, err := t.rt.Stats(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		u.CpuUsage = float32(stats.CpuUsage)
+		u.MemoryUsage = stats.MemoryUsage
+		u.MemoryLimit = stats.MemoryLimit
+		u.NetRxBytes = stats.NetRxBytes
+		u.NetTxBytes = stats.NetTxBytes
+	}
+
+	u.DiskUsage, err = t.diskUsage(ctx, name, now)
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (t *Tracker) diskUsage(ctx context.Context, name string, now time.Time) (uint64, error) {
+	t.m.Lock()
+	s, ok := t.disk[name]
+	t.m.Unlock()
+	if ok && now.Sub(s.at) < t.opts.DiskInterval {
+		return s.bytes, nil
+	}
+
+	bytes, err := t.rt.DiskUsage(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	t.m.Lock()
+	t.disk[name] = diskSample{at: now, bytes: bytes}
+	t.m.Unlock()
+	return bytes, nil
+}
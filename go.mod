@@ -3,8 +3,10 @@ module github.com/vv-sam/otus-project
 go 1.24.3
 
 require (
+	github.com/alicebob/miniredis/v2 v2.37.0
 	github.com/golang-jwt/jwt/v5 v5.2.2
 	github.com/google/uuid v1.6.0
+	github.com/gorilla/websocket v1.5.3
 	github.com/redis/go-redis/v9 v9.10.0
 	go.mongodb.org/mongo-driver/v2 v2.2.2
 )
@@ -26,6 +28,7 @@ require (
 )
 
 require (
+	github.com/yuin/gopher-lua v1.1.1 // indirect
 	golang.org/x/net v0.40.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
@@ -43,5 +46,5 @@ require (
 	github.com/swaggo/swag v1.16.4
 	golang.org/x/tools v0.33.0 // indirect
 	google.golang.org/grpc v1.72.2
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
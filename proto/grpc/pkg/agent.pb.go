@@ -9,6 +9,7 @@ package api
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,12 +22,67 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type AgentStatus int32
+
+const (
+	AgentStatus_AGENT_STATUS_UNSPECIFIED AgentStatus = 0
+	AgentStatus_AGENT_STATUS_ONLINE      AgentStatus = 1
+	AgentStatus_AGENT_STATUS_DEGRADED    AgentStatus = 2
+	AgentStatus_AGENT_STATUS_OFFLINE     AgentStatus = 3
+)
+
+// Enum value maps for AgentStatus.
+var (
+	AgentStatus_name = map[int32]string{
+		0: "AGENT_STATUS_UNSPECIFIED",
+		1: "AGENT_STATUS_ONLINE",
+		2: "AGENT_STATUS_DEGRADED",
+		3: "AGENT_STATUS_OFFLINE",
+	}
+	AgentStatus_value = map[string]int32{
+		"AGENT_STATUS_UNSPECIFIED": 0,
+		"AGENT_STATUS_ONLINE":      1,
+		"AGENT_STATUS_DEGRADED":    2,
+		"AGENT_STATUS_OFFLINE":     3,
+	}
+)
+
+func (x AgentStatus) Enum() *AgentStatus {
+	p := new(AgentStatus)
+	*p = x
+	return p
+}
+
+func (x AgentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AgentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[0].Descriptor()
+}
+
+func (AgentStatus) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[0]
+}
+
+func (x AgentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AgentStatus.Descriptor instead.
+func (AgentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{0}
+}
+
 type AgentInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Status        int32                  `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Status        AgentStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=api.AgentStatus" json:"status,omitempty"`
 	CurrentTasks  []*Task                `protobuf:"bytes,3,rep,name=current_tasks,json=currentTasks,proto3" json:"current_tasks,omitempty"`
 	Metrics       *HostMetrics           `protobuf:"bytes,4,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	LastSeen      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Config        *AgentConfig           `protobuf:"bytes,6,opt,name=config,proto3" json:"config,omitempty"`
+	Servers       []*ServerUsage         `protobuf:"bytes,7,rep,name=servers,proto3" json:"servers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -68,11 +124,11 @@ func (x *AgentInfo) GetAgentId() string {
 	return ""
 }
 
-func (x *AgentInfo) GetStatus() int32 {
+func (x *AgentInfo) GetStatus() AgentStatus {
 	if x != nil {
 		return x.Status
 	}
-	return 0
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
 }
 
 func (x *AgentInfo) GetCurrentTasks() []*Task {
@@ -89,6 +145,239 @@ func (x *AgentInfo) GetMetrics() *HostMetrics {
 	return nil
 }
 
+func (x *AgentInfo) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *AgentInfo) GetConfig() *AgentConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *AgentInfo) GetServers() []*ServerUsage {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+type PortRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           uint32                 `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           uint32                 `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PortRange) Reset() {
+	*x = PortRange{}
+	mi := &file_agent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortRange) ProtoMessage() {}
+
+func (x *PortRange) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortRange.ProtoReflect.Descriptor instead.
+func (*PortRange) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PortRange) GetMin() uint32 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *PortRange) GetMax() uint32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+type AgentCapacity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CpuCores      float64                `protobuf:"fixed64,1,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	MemoryBytes   uint64                 `protobuf:"varint,2,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`
+	DiskBytes     uint64                 `protobuf:"varint,3,opt,name=disk_bytes,json=diskBytes,proto3" json:"disk_bytes,omitempty"`
+	MaxServers    uint32                 `protobuf:"varint,4,opt,name=max_servers,json=maxServers,proto3" json:"max_servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentCapacity) Reset() {
+	*x = AgentCapacity{}
+	mi := &file_agent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentCapacity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentCapacity) ProtoMessage() {}
+
+func (x *AgentCapacity) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentCapacity.ProtoReflect.Descriptor instead.
+func (*AgentCapacity) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AgentCapacity) GetCpuCores() float64 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *AgentCapacity) GetMemoryBytes() uint64 {
+	if x != nil {
+		return x.MemoryBytes
+	}
+	return 0
+}
+
+func (x *AgentCapacity) GetDiskBytes() uint64 {
+	if x != nil {
+		return x.DiskBytes
+	}
+	return 0
+}
+
+func (x *AgentCapacity) GetMaxServers() uint32 {
+	if x != nil {
+		return x.MaxServers
+	}
+	return 0
+}
+
+type AgentConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PortRange     *PortRange             `protobuf:"bytes,3,opt,name=port_range,json=portRange,proto3" json:"port_range,omitempty"`
+	Capacity      *AgentCapacity         `protobuf:"bytes,4,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Transport     string                 `protobuf:"bytes,5,opt,name=transport,proto3" json:"transport,omitempty"`
+	DataDir       string                 `protobuf:"bytes,6,opt,name=data_dir,json=dataDir,proto3" json:"data_dir,omitempty"`
+	Runtime       string                 `protobuf:"bytes,7,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentConfig) Reset() {
+	*x = AgentConfig{}
+	mi := &file_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentConfig) ProtoMessage() {}
+
+func (x *AgentConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentConfig.ProtoReflect.Descriptor instead.
+func (*AgentConfig) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AgentConfig) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *AgentConfig) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *AgentConfig) GetPortRange() *PortRange {
+	if x != nil {
+		return x.PortRange
+	}
+	return nil
+}
+
+func (x *AgentConfig) GetCapacity() *AgentCapacity {
+	if x != nil {
+		return x.Capacity
+	}
+	return nil
+}
+
+func (x *AgentConfig) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *AgentConfig) GetDataDir() string {
+	if x != nil {
+		return x.DataDir
+	}
+	return ""
+}
+
+func (x *AgentConfig) GetRuntime() string {
+	if x != nil {
+		return x.Runtime
+	}
+	return ""
+}
+
 type GetAgentByIdRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -98,7 +387,7 @@ type GetAgentByIdRequest struct {
 
 func (x *GetAgentByIdRequest) Reset() {
 	*x = GetAgentByIdRequest{}
-	mi := &file_agent_proto_msgTypes[1]
+	mi := &file_agent_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -110,7 +399,7 @@ func (x *GetAgentByIdRequest) String() string {
 func (*GetAgentByIdRequest) ProtoMessage() {}
 
 func (x *GetAgentByIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[1]
+	mi := &file_agent_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -123,7 +412,7 @@ func (x *GetAgentByIdRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAgentByIdRequest.ProtoReflect.Descriptor instead.
 func (*GetAgentByIdRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{1}
+	return file_agent_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetAgentByIdRequest) GetId() string {
@@ -142,7 +431,7 @@ type GetAgentByIdResponse struct {
 
 func (x *GetAgentByIdResponse) Reset() {
 	*x = GetAgentByIdResponse{}
-	mi := &file_agent_proto_msgTypes[2]
+	mi := &file_agent_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -154,7 +443,7 @@ func (x *GetAgentByIdResponse) String() string {
 func (*GetAgentByIdResponse) ProtoMessage() {}
 
 func (x *GetAgentByIdResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[2]
+	mi := &file_agent_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -167,7 +456,7 @@ func (x *GetAgentByIdResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAgentByIdResponse.ProtoReflect.Descriptor instead.
 func (*GetAgentByIdResponse) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{2}
+	return file_agent_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetAgentByIdResponse) GetAgent() *AgentInfo {
@@ -177,15 +466,19 @@ func (x *GetAgentByIdResponse) GetAgent() *AgentInfo {
 	return nil
 }
 
+// Фильтры объединяются через И, значения одного фильтра - через ИЛИ
 type GetAllAgentsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       *ListOptions           `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	Status        []AgentStatus          `protobuf:"varint,2,rep,packed,name=status,proto3,enum=api.AgentStatus" json:"status,omitempty"`
+	Location      []string               `protobuf:"bytes,3,rep,name=location,proto3" json:"location,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetAllAgentsRequest) Reset() {
 	*x = GetAllAgentsRequest{}
-	mi := &file_agent_proto_msgTypes[3]
+	mi := &file_agent_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -197,7 +490,7 @@ func (x *GetAllAgentsRequest) String() string {
 func (*GetAllAgentsRequest) ProtoMessage() {}
 
 func (x *GetAllAgentsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[3]
+	mi := &file_agent_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -210,19 +503,42 @@ func (x *GetAllAgentsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllAgentsRequest.ProtoReflect.Descriptor instead.
 func (*GetAllAgentsRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{3}
+	return file_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetAllAgentsRequest) GetOptions() *ListOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *GetAllAgentsRequest) GetStatus() []AgentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GetAllAgentsRequest) GetLocation() []string {
+	if x != nil {
+		return x.Location
+	}
+	return nil
 }
 
 type GetAllAgentsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agents        []*AgentInfo           `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Agents []*AgentInfo           `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	// Пустой на последней странице
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetAllAgentsResponse) Reset() {
 	*x = GetAllAgentsResponse{}
-	mi := &file_agent_proto_msgTypes[4]
+	mi := &file_agent_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -234,7 +550,7 @@ func (x *GetAllAgentsResponse) String() string {
 func (*GetAllAgentsResponse) ProtoMessage() {}
 
 func (x *GetAllAgentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[4]
+	mi := &file_agent_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -247,7 +563,7 @@ func (x *GetAllAgentsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllAgentsResponse.ProtoReflect.Descriptor instead.
 func (*GetAllAgentsResponse) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{4}
+	return file_agent_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetAllAgentsResponse) GetAgents() []*AgentInfo {
@@ -257,6 +573,13 @@ func (x *GetAllAgentsResponse) GetAgents() []*AgentInfo {
 	return nil
 }
 
+func (x *GetAllAgentsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 type PostAgentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Agent         *AgentInfo             `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
@@ -266,7 +589,7 @@ type PostAgentRequest struct {
 
 func (x *PostAgentRequest) Reset() {
 	*x = PostAgentRequest{}
-	mi := &file_agent_proto_msgTypes[5]
+	mi := &file_agent_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -278,7 +601,7 @@ func (x *PostAgentRequest) String() string {
 func (*PostAgentRequest) ProtoMessage() {}
 
 func (x *PostAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[5]
+	mi := &file_agent_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -291,7 +614,7 @@ func (x *PostAgentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PostAgentRequest.ProtoReflect.Descriptor instead.
 func (*PostAgentRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{5}
+	return file_agent_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *PostAgentRequest) GetAgent() *AgentInfo {
@@ -309,7 +632,7 @@ type PostAgentResponse struct {
 
 func (x *PostAgentResponse) Reset() {
 	*x = PostAgentResponse{}
-	mi := &file_agent_proto_msgTypes[6]
+	mi := &file_agent_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -321,7 +644,7 @@ func (x *PostAgentResponse) String() string {
 func (*PostAgentResponse) ProtoMessage() {}
 
 func (x *PostAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[6]
+	mi := &file_agent_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,7 +657,7 @@ func (x *PostAgentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PostAgentResponse.ProtoReflect.Descriptor instead.
 func (*PostAgentResponse) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{6}
+	return file_agent_proto_rawDescGZIP(), []int{9}
 }
 
 type PutAgentRequest struct {
@@ -347,7 +670,7 @@ type PutAgentRequest struct {
 
 func (x *PutAgentRequest) Reset() {
 	*x = PutAgentRequest{}
-	mi := &file_agent_proto_msgTypes[7]
+	mi := &file_agent_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -359,7 +682,7 @@ func (x *PutAgentRequest) String() string {
 func (*PutAgentRequest) ProtoMessage() {}
 
 func (x *PutAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[7]
+	mi := &file_agent_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,7 +695,7 @@ func (x *PutAgentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PutAgentRequest.ProtoReflect.Descriptor instead.
 func (*PutAgentRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{7}
+	return file_agent_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *PutAgentRequest) GetId() string {
@@ -397,7 +720,7 @@ type PutAgentResponse struct {
 
 func (x *PutAgentResponse) Reset() {
 	*x = PutAgentResponse{}
-	mi := &file_agent_proto_msgTypes[8]
+	mi := &file_agent_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -409,7 +732,7 @@ func (x *PutAgentResponse) String() string {
 func (*PutAgentResponse) ProtoMessage() {}
 
 func (x *PutAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[8]
+	mi := &file_agent_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -422,7 +745,7 @@ func (x *PutAgentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PutAgentResponse.ProtoReflect.Descriptor instead.
 func (*PutAgentResponse) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{8}
+	return file_agent_proto_rawDescGZIP(), []int{11}
 }
 
 type DeleteAgentRequest struct {
@@ -434,7 +757,7 @@ type DeleteAgentRequest struct {
 
 func (x *DeleteAgentRequest) Reset() {
 	*x = DeleteAgentRequest{}
-	mi := &file_agent_proto_msgTypes[9]
+	mi := &file_agent_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -446,7 +769,7 @@ func (x *DeleteAgentRequest) String() string {
 func (*DeleteAgentRequest) ProtoMessage() {}
 
 func (x *DeleteAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[9]
+	mi := &file_agent_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -459,7 +782,7 @@ func (x *DeleteAgentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteAgentRequest.ProtoReflect.Descriptor instead.
 func (*DeleteAgentRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{9}
+	return file_agent_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *DeleteAgentRequest) GetId() string {
@@ -477,7 +800,7 @@ type DeleteAgentResponse struct {
 
 func (x *DeleteAgentResponse) Reset() {
 	*x = DeleteAgentResponse{}
-	mi := &file_agent_proto_msgTypes[10]
+	mi := &file_agent_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -489,7 +812,7 @@ func (x *DeleteAgentResponse) String() string {
 func (*DeleteAgentResponse) ProtoMessage() {}
 
 func (x *DeleteAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[10]
+	mi := &file_agent_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -502,27 +825,388 @@ func (x *DeleteAgentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteAgentResponse.ProtoReflect.Descriptor instead.
 func (*DeleteAgentResponse) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{10}
+	return file_agent_proto_rawDescGZIP(), []int{13}
+}
+
+type RegisterAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Metrics       *HostMetrics           `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	Config        *AgentConfig           `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAgentRequest) Reset() {
+	*x = RegisterAgentRequest{}
+	mi := &file_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAgentRequest) ProtoMessage() {}
+
+func (x *RegisterAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAgentRequest.ProtoReflect.Descriptor instead.
+func (*RegisterAgentRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RegisterAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetMetrics() *HostMetrics {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *RegisterAgentRequest) GetConfig() *AgentConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type RegisterAgentResponse struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	AgentId                  string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	HeartbeatIntervalSeconds uint32                 `protobuf:"varint,2,opt,name=heartbeat_interval_seconds,json=heartbeatIntervalSeconds,proto3" json:"heartbeat_interval_seconds,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *RegisterAgentResponse) Reset() {
+	*x = RegisterAgentResponse{}
+	mi := &file_agent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAgentResponse) ProtoMessage() {}
+
+func (x *RegisterAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAgentResponse.ProtoReflect.Descriptor instead.
+func (*RegisterAgentResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RegisterAgentResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RegisterAgentResponse) GetHeartbeatIntervalSeconds() uint32 {
+	if x != nil {
+		return x.HeartbeatIntervalSeconds
+	}
+	return 0
+}
+
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Status        AgentStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=api.AgentStatus" json:"status,omitempty"`
+	Metrics       *HostMetrics           `protobuf:"bytes,3,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	Servers       []*ServerUsage         `protobuf:"bytes,4,rep,name=servers,proto3" json:"servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_agent_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HeartbeatRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetStatus() AgentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
+}
+
+func (x *HeartbeatRequest) GetMetrics() *HostMetrics {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetServers() []*ServerUsage {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+type HeartbeatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_agent_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{17}
+}
+
+// Пустая ревизия - сначала снимок всех агентов, иначе изменения после неё
+type WatchAgentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      string                 `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAgentsRequest) Reset() {
+	*x = WatchAgentsRequest{}
+	mi := &file_agent_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAgentsRequest) ProtoMessage() {}
+
+func (x *WatchAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAgentsRequest.ProtoReflect.Descriptor instead.
+func (*WatchAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WatchAgentsRequest) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+// Агент заполнен для добавления и изменения, id - для всех изменений, кроме synced
+type WatchAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          WatchEventType         `protobuf:"varint,1,opt,name=type,proto3,enum=api.WatchEventType" json:"type,omitempty"`
+	Revision      string                 `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Agent         *AgentInfo             `protobuf:"bytes,4,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAgentsResponse) Reset() {
+	*x = WatchAgentsResponse{}
+	mi := &file_agent_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAgentsResponse) ProtoMessage() {}
+
+func (x *WatchAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAgentsResponse.ProtoReflect.Descriptor instead.
+func (*WatchAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchAgentsResponse) GetType() WatchEventType {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEventType_WATCH_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *WatchAgentsResponse) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+func (x *WatchAgentsResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WatchAgentsResponse) GetAgent() *AgentInfo {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
 }
 
 var File_agent_proto protoreflect.FileDescriptor
 
 const file_agent_proto_rawDesc = "" +
 	"\n" +
-	"\vagent.proto\x12\x03api\x1a\n" +
-	"task.proto\x1a\rmetrics.proto\"\x9a\x01\n" +
+	"\vagent.proto\x12\x03api\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\n" +
+	"task.proto\x1a\rmetrics.proto\x1a\vwatch.proto\x1a\n" +
+	"list.proto\"\xbb\x02\n" +
 	"\tAgentInfo\x12\x19\n" +
-	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\x05R\x06status\x12.\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12(\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x10.api.AgentStatusR\x06status\x12.\n" +
 	"\rcurrent_tasks\x18\x03 \x03(\v2\t.api.TaskR\fcurrentTasks\x12*\n" +
-	"\ametrics\x18\x04 \x01(\v2\x10.api.HostMetricsR\ametrics\"%\n" +
+	"\ametrics\x18\x04 \x01(\v2\x10.api.HostMetricsR\ametrics\x127\n" +
+	"\tlast_seen\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x12(\n" +
+	"\x06config\x18\x06 \x01(\v2\x10.api.AgentConfigR\x06config\x12*\n" +
+	"\aservers\x18\a \x03(\v2\x10.api.ServerUsageR\aservers\"/\n" +
+	"\tPortRange\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\rR\x03min\x12\x10\n" +
+	"\x03max\x18\x02 \x01(\rR\x03max\"\x8f\x01\n" +
+	"\rAgentCapacity\x12\x1b\n" +
+	"\tcpu_cores\x18\x01 \x01(\x01R\bcpuCores\x12!\n" +
+	"\fmemory_bytes\x18\x02 \x01(\x04R\vmemoryBytes\x12\x1d\n" +
+	"\n" +
+	"disk_bytes\x18\x03 \x01(\x04R\tdiskBytes\x12\x1f\n" +
+	"\vmax_servers\x18\x04 \x01(\rR\n" +
+	"maxServers\"\xcc\x02\n" +
+	"\vAgentConfig\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x124\n" +
+	"\x06labels\x18\x02 \x03(\v2\x1c.api.AgentConfig.LabelsEntryR\x06labels\x12-\n" +
+	"\n" +
+	"port_range\x18\x03 \x01(\v2\x0e.api.PortRangeR\tportRange\x12.\n" +
+	"\bcapacity\x18\x04 \x01(\v2\x12.api.AgentCapacityR\bcapacity\x12\x1c\n" +
+	"\ttransport\x18\x05 \x01(\tR\ttransport\x12\x19\n" +
+	"\bdata_dir\x18\x06 \x01(\tR\adataDir\x12\x18\n" +
+	"\aruntime\x18\a \x01(\tR\aruntime\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"%\n" +
 	"\x13GetAgentByIdRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"<\n" +
 	"\x14GetAgentByIdResponse\x12$\n" +
-	"\x05agent\x18\x01 \x01(\v2\x0e.api.AgentInfoR\x05agent\"\x15\n" +
-	"\x13GetAllAgentsRequest\">\n" +
+	"\x05agent\x18\x01 \x01(\v2\x0e.api.AgentInfoR\x05agent\"\x87\x01\n" +
+	"\x13GetAllAgentsRequest\x12*\n" +
+	"\aoptions\x18\x01 \x01(\v2\x10.api.ListOptionsR\aoptions\x12(\n" +
+	"\x06status\x18\x02 \x03(\x0e2\x10.api.AgentStatusR\x06status\x12\x1a\n" +
+	"\blocation\x18\x03 \x03(\tR\blocation\"_\n" +
 	"\x14GetAllAgentsResponse\x12&\n" +
-	"\x06agents\x18\x01 \x03(\v2\x0e.api.AgentInfoR\x06agents\"8\n" +
+	"\x06agents\x18\x01 \x03(\v2\x0e.api.AgentInfoR\x06agents\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"8\n" +
 	"\x10PostAgentRequest\x12$\n" +
 	"\x05agent\x18\x01 \x01(\v2\x0e.api.AgentInfoR\x05agent\"\x13\n" +
 	"\x11PostAgentResponse\"G\n" +
@@ -532,13 +1216,41 @@ const file_agent_proto_rawDesc = "" +
 	"\x10PutAgentResponse\"$\n" +
 	"\x12DeleteAgentRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
-	"\x13DeleteAgentResponse2\xb5\x02\n" +
+	"\x13DeleteAgentResponse\"\x87\x01\n" +
+	"\x14RegisterAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12*\n" +
+	"\ametrics\x18\x02 \x01(\v2\x10.api.HostMetricsR\ametrics\x12(\n" +
+	"\x06config\x18\x03 \x01(\v2\x10.api.AgentConfigR\x06config\"p\n" +
+	"\x15RegisterAgentResponse\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12<\n" +
+	"\x1aheartbeat_interval_seconds\x18\x02 \x01(\rR\x18heartbeatIntervalSeconds\"\xaf\x01\n" +
+	"\x10HeartbeatRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12(\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x10.api.AgentStatusR\x06status\x12*\n" +
+	"\ametrics\x18\x03 \x01(\v2\x10.api.HostMetricsR\ametrics\x12*\n" +
+	"\aservers\x18\x04 \x03(\v2\x10.api.ServerUsageR\aservers\"\x13\n" +
+	"\x11HeartbeatResponse\"0\n" +
+	"\x12WatchAgentsRequest\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\tR\brevision\"\x90\x01\n" +
+	"\x13WatchAgentsResponse\x12'\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x13.api.WatchEventTypeR\x04type\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\tR\brevision\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12$\n" +
+	"\x05agent\x18\x04 \x01(\v2\x0e.api.AgentInfoR\x05agent*y\n" +
+	"\vAgentStatus\x12\x1c\n" +
+	"\x18AGENT_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13AGENT_STATUS_ONLINE\x10\x01\x12\x19\n" +
+	"\x15AGENT_STATUS_DEGRADED\x10\x02\x12\x18\n" +
+	"\x14AGENT_STATUS_OFFLINE\x10\x032\xf2\x03\n" +
 	"\fAgentService\x12>\n" +
 	"\aGetById\x12\x18.api.GetAgentByIdRequest\x1a\x19.api.GetAgentByIdResponse\x12=\n" +
 	"\x06GetAll\x12\x18.api.GetAllAgentsRequest\x1a\x19.api.GetAllAgentsResponse\x125\n" +
 	"\x04Post\x12\x15.api.PostAgentRequest\x1a\x16.api.PostAgentResponse\x122\n" +
 	"\x03Put\x12\x14.api.PutAgentRequest\x1a\x15.api.PutAgentResponse\x12;\n" +
-	"\x06Delete\x12\x17.api.DeleteAgentRequest\x1a\x18.api.DeleteAgentResponseB/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
+	"\x06Delete\x12\x17.api.DeleteAgentRequest\x1a\x18.api.DeleteAgentResponse\x12A\n" +
+	"\bRegister\x12\x19.api.RegisterAgentRequest\x1a\x1a.api.RegisterAgentResponse\x12:\n" +
+	"\tHeartbeat\x12\x15.api.HeartbeatRequest\x1a\x16.api.HeartbeatResponse\x12<\n" +
+	"\x05Watch\x12\x17.api.WatchAgentsRequest\x1a\x18.api.WatchAgentsResponse0\x01B/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
 
 var (
 	file_agent_proto_rawDescOnce sync.Once
@@ -552,44 +1264,82 @@ func file_agent_proto_rawDescGZIP() []byte {
 	return file_agent_proto_rawDescData
 }
 
-var file_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_agent_proto_goTypes = []any{
-	(*AgentInfo)(nil),            // 0: api.AgentInfo
-	(*GetAgentByIdRequest)(nil),  // 1: api.GetAgentByIdRequest
-	(*GetAgentByIdResponse)(nil), // 2: api.GetAgentByIdResponse
-	(*GetAllAgentsRequest)(nil),  // 3: api.GetAllAgentsRequest
-	(*GetAllAgentsResponse)(nil), // 4: api.GetAllAgentsResponse
-	(*PostAgentRequest)(nil),     // 5: api.PostAgentRequest
-	(*PostAgentResponse)(nil),    // 6: api.PostAgentResponse
-	(*PutAgentRequest)(nil),      // 7: api.PutAgentRequest
-	(*PutAgentResponse)(nil),     // 8: api.PutAgentResponse
-	(*DeleteAgentRequest)(nil),   // 9: api.DeleteAgentRequest
-	(*DeleteAgentResponse)(nil),  // 10: api.DeleteAgentResponse
-	(*Task)(nil),                 // 11: api.Task
-	(*HostMetrics)(nil),          // 12: api.HostMetrics
+	(AgentStatus)(0),              // 0: api.AgentStatus
+	(*AgentInfo)(nil),             // 1: api.AgentInfo
+	(*PortRange)(nil),             // 2: api.PortRange
+	(*AgentCapacity)(nil),         // 3: api.AgentCapacity
+	(*AgentConfig)(nil),           // 4: api.AgentConfig
+	(*GetAgentByIdRequest)(nil),   // 5: api.GetAgentByIdRequest
+	(*GetAgentByIdResponse)(nil),  // 6: api.GetAgentByIdResponse
+	(*GetAllAgentsRequest)(nil),   // 7: api.GetAllAgentsRequest
+	(*GetAllAgentsResponse)(nil),  // 8: api.GetAllAgentsResponse
+	(*PostAgentRequest)(nil),      // 9: api.PostAgentRequest
+	(*PostAgentResponse)(nil),     // 10: api.PostAgentResponse
+	(*PutAgentRequest)(nil),       // 11: api.PutAgentRequest
+	(*PutAgentResponse)(nil),      // 12: api.PutAgentResponse
+	(*DeleteAgentRequest)(nil),    // 13: api.DeleteAgentRequest
+	(*DeleteAgentResponse)(nil),   // 14: api.DeleteAgentResponse
+	(*RegisterAgentRequest)(nil),  // 15: api.RegisterAgentRequest
+	(*RegisterAgentResponse)(nil), // 16: api.RegisterAgentResponse
+	(*HeartbeatRequest)(nil),      // 17: api.HeartbeatRequest
+	(*HeartbeatResponse)(nil),     // 18: api.HeartbeatResponse
+	(*WatchAgentsRequest)(nil),    // 19: api.WatchAgentsRequest
+	(*WatchAgentsResponse)(nil),   // 20: api.WatchAgentsResponse
+	nil,                           // 21: api.AgentConfig.LabelsEntry
+	(*Task)(nil),                  // 22: api.Task
+	(*HostMetrics)(nil),           // 23: api.HostMetrics
+	(*timestamppb.Timestamp)(nil), // 24: google.protobuf.Timestamp
+	(*ServerUsage)(nil),           // 25: api.ServerUsage
+	(*ListOptions)(nil),           // 26: api.ListOptions
+	(WatchEventType)(0),           // 27: api.WatchEventType
 }
 var file_agent_proto_depIdxs = []int32{
-	11, // 0: api.AgentInfo.current_tasks:type_name -> api.Task
-	12, // 1: api.AgentInfo.metrics:type_name -> api.HostMetrics
-	0,  // 2: api.GetAgentByIdResponse.agent:type_name -> api.AgentInfo
-	0,  // 3: api.GetAllAgentsResponse.agents:type_name -> api.AgentInfo
-	0,  // 4: api.PostAgentRequest.agent:type_name -> api.AgentInfo
-	0,  // 5: api.PutAgentRequest.agent:type_name -> api.AgentInfo
-	1,  // 6: api.AgentService.GetById:input_type -> api.GetAgentByIdRequest
-	3,  // 7: api.AgentService.GetAll:input_type -> api.GetAllAgentsRequest
-	5,  // 8: api.AgentService.Post:input_type -> api.PostAgentRequest
-	7,  // 9: api.AgentService.Put:input_type -> api.PutAgentRequest
-	9,  // 10: api.AgentService.Delete:input_type -> api.DeleteAgentRequest
-	2,  // 11: api.AgentService.GetById:output_type -> api.GetAgentByIdResponse
-	4,  // 12: api.AgentService.GetAll:output_type -> api.GetAllAgentsResponse
-	6,  // 13: api.AgentService.Post:output_type -> api.PostAgentResponse
-	8,  // 14: api.AgentService.Put:output_type -> api.PutAgentResponse
-	10, // 15: api.AgentService.Delete:output_type -> api.DeleteAgentResponse
-	11, // [11:16] is the sub-list for method output_type
-	6,  // [6:11] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	0,  // 0: api.AgentInfo.status:type_name -> api.AgentStatus
+	22, // 1: api.AgentInfo.current_tasks:type_name -> api.Task
+	23, // 2: api.AgentInfo.metrics:type_name -> api.HostMetrics
+	24, // 3: api.AgentInfo.last_seen:type_name -> google.protobuf.Timestamp
+	4,  // 4: api.AgentInfo.config:type_name -> api.AgentConfig
+	25, // 5: api.AgentInfo.servers:type_name -> api.ServerUsage
+	21, // 6: api.AgentConfig.labels:type_name -> api.AgentConfig.LabelsEntry
+	2,  // 7: api.AgentConfig.port_range:type_name -> api.PortRange
+	3,  // 8: api.AgentConfig.capacity:type_name -> api.AgentCapacity
+	1,  // 9: api.GetAgentByIdResponse.agent:type_name -> api.AgentInfo
+	26, // 10: api.GetAllAgentsRequest.options:type_name -> api.ListOptions
+	0,  // 11: api.GetAllAgentsRequest.status:type_name -> api.AgentStatus
+	1,  // 12: api.GetAllAgentsResponse.agents:type_name -> api.AgentInfo
+	1,  // 13: api.PostAgentRequest.agent:type_name -> api.AgentInfo
+	1,  // 14: api.PutAgentRequest.agent:type_name -> api.AgentInfo
+	23, // 15: api.RegisterAgentRequest.metrics:type_name -> api.HostMetrics
+	4,  // 16: api.RegisterAgentRequest.config:type_name -> api.AgentConfig
+	0,  // 17: api.HeartbeatRequest.status:type_name -> api.AgentStatus
+	23, // 18: api.HeartbeatRequest.metrics:type_name -> api.HostMetrics
+	25, // 19: api.HeartbeatRequest.servers:type_name -> api.ServerUsage
+	27, // 20: api.WatchAgentsResponse.type:type_name -> api.WatchEventType
+	1,  // 21: api.WatchAgentsResponse.agent:type_name -> api.AgentInfo
+	5,  // 22: api.AgentService.GetById:input_type -> api.GetAgentByIdRequest
+	7,  // 23: api.AgentService.GetAll:input_type -> api.GetAllAgentsRequest
+	9,  // 24: api.AgentService.Post:input_type -> api.PostAgentRequest
+	11, // 25: api.AgentService.Put:input_type -> api.PutAgentRequest
+	13, // 26: api.AgentService.Delete:input_type -> api.DeleteAgentRequest
+	15, // 27: api.AgentService.Register:input_type -> api.RegisterAgentRequest
+	17, // 28: api.AgentService.Heartbeat:input_type -> api.HeartbeatRequest
+	19, // 29: api.AgentService.Watch:input_type -> api.WatchAgentsRequest
+	6,  // 30: api.AgentService.GetById:output_type -> api.GetAgentByIdResponse
+	8,  // 31: api.AgentService.GetAll:output_type -> api.GetAllAgentsResponse
+	10, // 32: api.AgentService.Post:output_type -> api.PostAgentResponse
+	12, // 33: api.AgentService.Put:output_type -> api.PutAgentResponse
+	14, // 34: api.AgentService.Delete:output_type -> api.DeleteAgentResponse
+	16, // 35: api.AgentService.Register:output_type -> api.RegisterAgentResponse
+	18, // 36: api.AgentService.Heartbeat:output_type -> api.HeartbeatResponse
+	20, // 37: api.AgentService.Watch:output_type -> api.WatchAgentsResponse
+	30, // [30:38] is the sub-list for method output_type
+	22, // [22:30] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_agent_proto_init() }
@@ -599,18 +1349,21 @@ func file_agent_proto_init() {
 	}
 	file_task_proto_init()
 	file_metrics_proto_init()
+	file_watch_proto_init()
+	file_list_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_agent_proto_rawDesc), len(file_agent_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      1,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_agent_proto_goTypes,
 		DependencyIndexes: file_agent_proto_depIdxs,
+		EnumInfos:         file_agent_proto_enumTypes,
 		MessageInfos:      file_agent_proto_msgTypes,
 	}.Build()
 	File_agent_proto = out.File
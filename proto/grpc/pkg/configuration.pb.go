@@ -131,6 +131,8 @@ type BaseConfig struct {
 	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	Port          uint32                 `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
 	Type          ConfigurationType      `protobuf:"varint,4,opt,name=type,proto3,enum=api.ConfigurationType" json:"type,omitempty"`
+	Placement     *Placement             `protobuf:"bytes,5,opt,name=placement,proto3" json:"placement,omitempty"`
+	Resources     *Resources             `protobuf:"bytes,6,opt,name=resources,proto3" json:"resources,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -193,40 +195,197 @@ func (x *BaseConfig) GetType() ConfigurationType {
 	return ConfigurationType_CONFIGURATION_TYPE_UNSPECIFIED
 }
 
+func (x *BaseConfig) GetPlacement() *Placement {
+	if x != nil {
+		return x.Placement
+	}
+	return nil
+}
+
+func (x *BaseConfig) GetResources() *Resources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+// Ограничения ресурсов сервера. 0 - без ограничения
+type Resources struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CpuCores      float64                `protobuf:"fixed64,1,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	CpuShares     uint32                 `protobuf:"varint,2,opt,name=cpu_shares,json=cpuShares,proto3" json:"cpu_shares,omitempty"`
+	MemoryBytes   uint64                 `protobuf:"varint,3,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`
+	DiskBytes     uint64                 `protobuf:"varint,4,opt,name=disk_bytes,json=diskBytes,proto3" json:"disk_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resources) Reset() {
+	*x = Resources{}
+	mi := &file_configuration_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resources) ProtoMessage() {}
+
+func (x *Resources) ProtoReflect() protoreflect.Message {
+	mi := &file_configuration_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resources.ProtoReflect.Descriptor instead.
+func (*Resources) Descriptor() ([]byte, []int) {
+	return file_configuration_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Resources) GetCpuCores() float64 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *Resources) GetCpuShares() uint32 {
+	if x != nil {
+		return x.CpuShares
+	}
+	return 0
+}
+
+func (x *Resources) GetMemoryBytes() uint64 {
+	if x != nil {
+		return x.MemoryBytes
+	}
+	return 0
+}
+
+func (x *Resources) GetDiskBytes() uint64 {
+	if x != nil {
+		return x.DiskBytes
+	}
+	return 0
+}
+
+// Ограничения размещения. Если agent_id пуст, агента выбирает планировщик
+type Placement struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Location       string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Labels         map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	MinMemoryBytes uint64                 `protobuf:"varint,3,opt,name=min_memory_bytes,json=minMemoryBytes,proto3" json:"min_memory_bytes,omitempty"`
+	MinCpuCores    float64                `protobuf:"fixed64,4,opt,name=min_cpu_cores,json=minCpuCores,proto3" json:"min_cpu_cores,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Placement) Reset() {
+	*x = Placement{}
+	mi := &file_configuration_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Placement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Placement) ProtoMessage() {}
+
+func (x *Placement) ProtoReflect() protoreflect.Message {
+	mi := &file_configuration_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Placement.ProtoReflect.Descriptor instead.
+func (*Placement) Descriptor() ([]byte, []int) {
+	return file_configuration_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Placement) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Placement) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Placement) GetMinMemoryBytes() uint64 {
+	if x != nil {
+		return x.MinMemoryBytes
+	}
+	return 0
+}
+
+func (x *Placement) GetMinCpuCores() float64 {
+	if x != nil {
+		return x.MinCpuCores
+	}
+	return 0
+}
+
 type FactorioServerSettings struct {
-	state                                protoimpl.MessageState `protogen:"open.v1"`
-	Name                                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description                          string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Tags                                 []string               `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
-	MaxPlayers                           int32                  `protobuf:"varint,4,opt,name=max_players,json=maxPlayers,proto3" json:"max_players,omitempty"`
-	Username                             string                 `protobuf:"bytes,5,opt,name=username,proto3" json:"username,omitempty"`
-	Token                                string                 `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`
-	RequireUserVerification              bool                   `protobuf:"varint,7,opt,name=require_user_verification,json=requireUserVerification,proto3" json:"require_user_verification,omitempty"`
-	MaxUploadInKilobytesPerSecond        int32                  `protobuf:"varint,8,opt,name=max_upload_in_kilobytes_per_second,json=maxUploadInKilobytesPerSecond,proto3" json:"max_upload_in_kilobytes_per_second,omitempty"`
-	MaxUploadSlots                       int32                  `protobuf:"varint,9,opt,name=max_upload_slots,json=maxUploadSlots,proto3" json:"max_upload_slots,omitempty"`
-	MinimumLatencyInTicks                int32                  `protobuf:"varint,10,opt,name=minimum_latency_in_ticks,json=minimumLatencyInTicks,proto3" json:"minimum_latency_in_ticks,omitempty"`
-	MaxHeartbeatsPerSecond               int32                  `protobuf:"varint,11,opt,name=max_heartbeats_per_second,json=maxHeartbeatsPerSecond,proto3" json:"max_heartbeats_per_second,omitempty"`
-	IgnorePlayerLimitForReturningPlayers bool                   `protobuf:"varint,12,opt,name=ignore_player_limit_for_returning_players,json=ignorePlayerLimitForReturningPlayers,proto3" json:"ignore_player_limit_for_returning_players,omitempty"`
-	AllowCommands                        string                 `protobuf:"bytes,13,opt,name=allow_commands,json=allowCommands,proto3" json:"allow_commands,omitempty"`
-	AutosaveInterval                     int32                  `protobuf:"varint,14,opt,name=autosave_interval,json=autosaveInterval,proto3" json:"autosave_interval,omitempty"`
-	AutosaveSlots                        int32                  `protobuf:"varint,15,opt,name=autosave_slots,json=autosaveSlots,proto3" json:"autosave_slots,omitempty"`
-	AfkAutokickInterval                  int32                  `protobuf:"varint,16,opt,name=afk_autokick_interval,json=afkAutokickInterval,proto3" json:"afk_autokick_interval,omitempty"`
-	AutoPause                            bool                   `protobuf:"varint,17,opt,name=auto_pause,json=autoPause,proto3" json:"auto_pause,omitempty"`
-	AutoPauseWhenPlayersConnect          bool                   `protobuf:"varint,18,opt,name=auto_pause_when_players_connect,json=autoPauseWhenPlayersConnect,proto3" json:"auto_pause_when_players_connect,omitempty"`
-	OnlyAdminsCanPauseTheGame            bool                   `protobuf:"varint,19,opt,name=only_admins_can_pause_the_game,json=onlyAdminsCanPauseTheGame,proto3" json:"only_admins_can_pause_the_game,omitempty"`
-	AutosaveOnlyOnServer                 bool                   `protobuf:"varint,20,opt,name=autosave_only_on_server,json=autosaveOnlyOnServer,proto3" json:"autosave_only_on_server,omitempty"`
-	NonBlockingSaving                    bool                   `protobuf:"varint,21,opt,name=non_blocking_saving,json=nonBlockingSaving,proto3" json:"non_blocking_saving,omitempty"`
-	MinimumSegmentSize                   int32                  `protobuf:"varint,22,opt,name=minimum_segment_size,json=minimumSegmentSize,proto3" json:"minimum_segment_size,omitempty"`
-	MinimumSegmentSizePeerCount          int32                  `protobuf:"varint,23,opt,name=minimum_segment_size_peer_count,json=minimumSegmentSizePeerCount,proto3" json:"minimum_segment_size_peer_count,omitempty"`
-	MaximumSegmentSize                   int32                  `protobuf:"varint,24,opt,name=maximum_segment_size,json=maximumSegmentSize,proto3" json:"maximum_segment_size,omitempty"`
-	MaximumSegmentSizePeerCount          int32                  `protobuf:"varint,25,opt,name=maximum_segment_size_peer_count,json=maximumSegmentSizePeerCount,proto3" json:"maximum_segment_size_peer_count,omitempty"`
-	unknownFields                        protoimpl.UnknownFields
-	sizeCache                            protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Tags        []string               `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	MaxPlayers  int32                  `protobuf:"varint,4,opt,name=max_players,json=maxPlayers,proto3" json:"max_players,omitempty"`
+	Username    string                 `protobuf:"bytes,5,opt,name=username,proto3" json:"username,omitempty"`
+	// Принимается при сохранении, но не возвращается. Пустой токен при обновлении оставляет сохранённый
+	Token                                string              `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`
+	RequireUserVerification              bool                `protobuf:"varint,7,opt,name=require_user_verification,json=requireUserVerification,proto3" json:"require_user_verification,omitempty"`
+	MaxUploadInKilobytesPerSecond        int32               `protobuf:"varint,8,opt,name=max_upload_in_kilobytes_per_second,json=maxUploadInKilobytesPerSecond,proto3" json:"max_upload_in_kilobytes_per_second,omitempty"`
+	MaxUploadSlots                       int32               `protobuf:"varint,9,opt,name=max_upload_slots,json=maxUploadSlots,proto3" json:"max_upload_slots,omitempty"`
+	MinimumLatencyInTicks                int32               `protobuf:"varint,10,opt,name=minimum_latency_in_ticks,json=minimumLatencyInTicks,proto3" json:"minimum_latency_in_ticks,omitempty"`
+	MaxHeartbeatsPerSecond               int32               `protobuf:"varint,11,opt,name=max_heartbeats_per_second,json=maxHeartbeatsPerSecond,proto3" json:"max_heartbeats_per_second,omitempty"`
+	IgnorePlayerLimitForReturningPlayers bool                `protobuf:"varint,12,opt,name=ignore_player_limit_for_returning_players,json=ignorePlayerLimitForReturningPlayers,proto3" json:"ignore_player_limit_for_returning_players,omitempty"`
+	AllowCommands                        string              `protobuf:"bytes,13,opt,name=allow_commands,json=allowCommands,proto3" json:"allow_commands,omitempty"`
+	AutosaveInterval                     int32               `protobuf:"varint,14,opt,name=autosave_interval,json=autosaveInterval,proto3" json:"autosave_interval,omitempty"`
+	AutosaveSlots                        int32               `protobuf:"varint,15,opt,name=autosave_slots,json=autosaveSlots,proto3" json:"autosave_slots,omitempty"`
+	AfkAutokickInterval                  int32               `protobuf:"varint,16,opt,name=afk_autokick_interval,json=afkAutokickInterval,proto3" json:"afk_autokick_interval,omitempty"`
+	AutoPause                            bool                `protobuf:"varint,17,opt,name=auto_pause,json=autoPause,proto3" json:"auto_pause,omitempty"`
+	AutoPauseWhenPlayersConnect          bool                `protobuf:"varint,18,opt,name=auto_pause_when_players_connect,json=autoPauseWhenPlayersConnect,proto3" json:"auto_pause_when_players_connect,omitempty"`
+	OnlyAdminsCanPauseTheGame            bool                `protobuf:"varint,19,opt,name=only_admins_can_pause_the_game,json=onlyAdminsCanPauseTheGame,proto3" json:"only_admins_can_pause_the_game,omitempty"`
+	AutosaveOnlyOnServer                 bool                `protobuf:"varint,20,opt,name=autosave_only_on_server,json=autosaveOnlyOnServer,proto3" json:"autosave_only_on_server,omitempty"`
+	NonBlockingSaving                    bool                `protobuf:"varint,21,opt,name=non_blocking_saving,json=nonBlockingSaving,proto3" json:"non_blocking_saving,omitempty"`
+	MinimumSegmentSize                   int32               `protobuf:"varint,22,opt,name=minimum_segment_size,json=minimumSegmentSize,proto3" json:"minimum_segment_size,omitempty"`
+	MinimumSegmentSizePeerCount          int32               `protobuf:"varint,23,opt,name=minimum_segment_size_peer_count,json=minimumSegmentSizePeerCount,proto3" json:"minimum_segment_size_peer_count,omitempty"`
+	MaximumSegmentSize                   int32               `protobuf:"varint,24,opt,name=maximum_segment_size,json=maximumSegmentSize,proto3" json:"maximum_segment_size,omitempty"`
+	MaximumSegmentSizePeerCount          int32               `protobuf:"varint,25,opt,name=maximum_segment_size_peer_count,json=maximumSegmentSizePeerCount,proto3" json:"maximum_segment_size_peer_count,omitempty"`
+	Visibility                           *FactorioVisibility `protobuf:"bytes,26,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	// Принимаются при сохранении, но не возвращаются. Пустой пароль при обновлении оставляет сохранённый
+	Password      string `protobuf:"bytes,27,opt,name=password,proto3" json:"password,omitempty"`
+	GamePassword  string `protobuf:"bytes,28,opt,name=game_password,json=gamePassword,proto3" json:"game_password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FactorioServerSettings) Reset() {
 	*x = FactorioServerSettings{}
-	mi := &file_configuration_proto_msgTypes[1]
+	mi := &file_configuration_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -238,7 +397,7 @@ func (x *FactorioServerSettings) String() string {
 func (*FactorioServerSettings) ProtoMessage() {}
 
 func (x *FactorioServerSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[1]
+	mi := &file_configuration_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -251,7 +410,7 @@ func (x *FactorioServerSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioServerSettings.ProtoReflect.Descriptor instead.
 func (*FactorioServerSettings) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{1}
+	return file_configuration_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *FactorioServerSettings) GetName() string {
@@ -429,6 +588,79 @@ func (x *FactorioServerSettings) GetMaximumSegmentSizePeerCount() int32 {
 	return 0
 }
 
+func (x *FactorioServerSettings) GetVisibility() *FactorioVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return nil
+}
+
+func (x *FactorioServerSettings) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *FactorioServerSettings) GetGamePassword() string {
+	if x != nil {
+		return x.GamePassword
+	}
+	return ""
+}
+
+type FactorioVisibility struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Public        bool                   `protobuf:"varint,1,opt,name=public,proto3" json:"public,omitempty"`
+	Lan           bool                   `protobuf:"varint,2,opt,name=lan,proto3" json:"lan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FactorioVisibility) Reset() {
+	*x = FactorioVisibility{}
+	mi := &file_configuration_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FactorioVisibility) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FactorioVisibility) ProtoMessage() {}
+
+func (x *FactorioVisibility) ProtoReflect() protoreflect.Message {
+	mi := &file_configuration_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FactorioVisibility.ProtoReflect.Descriptor instead.
+func (*FactorioVisibility) Descriptor() ([]byte, []int) {
+	return file_configuration_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FactorioVisibility) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *FactorioVisibility) GetLan() bool {
+	if x != nil {
+		return x.Lan
+	}
+	return false
+}
+
 type FactorioDifficultySettings struct {
 	state                     protoimpl.MessageState `protogen:"open.v1"`
 	TechnologyPriceMultiplier float32                `protobuf:"fixed32,1,opt,name=technology_price_multiplier,json=technologyPriceMultiplier,proto3" json:"technology_price_multiplier,omitempty"`
@@ -439,7 +671,7 @@ type FactorioDifficultySettings struct {
 
 func (x *FactorioDifficultySettings) Reset() {
 	*x = FactorioDifficultySettings{}
-	mi := &file_configuration_proto_msgTypes[2]
+	mi := &file_configuration_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -451,7 +683,7 @@ func (x *FactorioDifficultySettings) String() string {
 func (*FactorioDifficultySettings) ProtoMessage() {}
 
 func (x *FactorioDifficultySettings) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[2]
+	mi := &file_configuration_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -464,7 +696,7 @@ func (x *FactorioDifficultySettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioDifficultySettings.ProtoReflect.Descriptor instead.
 func (*FactorioDifficultySettings) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{2}
+	return file_configuration_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *FactorioDifficultySettings) GetTechnologyPriceMultiplier() float32 {
@@ -482,26 +714,27 @@ func (x *FactorioDifficultySettings) GetSpoilTimeModifier() float32 {
 }
 
 type FactorioPollutionSettings struct {
-	state                                   protoimpl.MessageState `protogen:"open.v1"`
-	Enabled                                 bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	DiffusionRatio                          float64                `protobuf:"fixed64,2,opt,name=diffusion_ratio,json=diffusionRatio,proto3" json:"diffusion_ratio,omitempty"`
-	MinToDiffuse                            int32                  `protobuf:"varint,3,opt,name=min_to_diffuse,json=minToDiffuse,proto3" json:"min_to_diffuse,omitempty"`
-	Ageing                                  int32                  `protobuf:"varint,4,opt,name=ageing,proto3" json:"ageing,omitempty"`
-	ExpectedMaxPerChunk                     int32                  `protobuf:"varint,5,opt,name=expected_max_per_chunk,json=expectedMaxPerChunk,proto3" json:"expected_max_per_chunk,omitempty"`
-	MinToShowPerChunk                       int32                  `protobuf:"varint,6,opt,name=min_to_show_per_chunk,json=minToShowPerChunk,proto3" json:"min_to_show_per_chunk,omitempty"`
-	MinPollutionToDamageTrees               int32                  `protobuf:"varint,7,opt,name=min_pollution_to_damage_trees,json=minPollutionToDamageTrees,proto3" json:"min_pollution_to_damage_trees,omitempty"`
-	PollutionWithMaxForestDamage            int32                  `protobuf:"varint,8,opt,name=pollution_with_max_forest_damage,json=pollutionWithMaxForestDamage,proto3" json:"pollution_with_max_forest_damage,omitempty"`
-	PollutionPerTreeDamage                  int32                  `protobuf:"varint,9,opt,name=pollution_per_tree_damage,json=pollutionPerTreeDamage,proto3" json:"pollution_per_tree_damage,omitempty"`
-	PollutionRestoredPerTreeDamage          int32                  `protobuf:"varint,10,opt,name=pollution_restored_per_tree_damage,json=pollutionRestoredPerTreeDamage,proto3" json:"pollution_restored_per_tree_damage,omitempty"`
-	MaxPollutionToRestoreTrees              int32                  `protobuf:"varint,11,opt,name=max_pollution_to_restore_trees,json=maxPollutionToRestoreTrees,proto3" json:"max_pollution_to_restore_trees,omitempty"`
-	EnemyAttackPollutionConsumptionModifier int32                  `protobuf:"varint,12,opt,name=enemy_attack_pollution_consumption_modifier,json=enemyAttackPollutionConsumptionModifier,proto3" json:"enemy_attack_pollution_consumption_modifier,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Незаданный флаг при развёртывании берётся из upstream-примера
+	Enabled                                 *bool   `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	DiffusionRatio                          float64 `protobuf:"fixed64,2,opt,name=diffusion_ratio,json=diffusionRatio,proto3" json:"diffusion_ratio,omitempty"`
+	MinToDiffuse                            int32   `protobuf:"varint,3,opt,name=min_to_diffuse,json=minToDiffuse,proto3" json:"min_to_diffuse,omitempty"`
+	Ageing                                  int32   `protobuf:"varint,4,opt,name=ageing,proto3" json:"ageing,omitempty"`
+	ExpectedMaxPerChunk                     int32   `protobuf:"varint,5,opt,name=expected_max_per_chunk,json=expectedMaxPerChunk,proto3" json:"expected_max_per_chunk,omitempty"`
+	MinToShowPerChunk                       int32   `protobuf:"varint,6,opt,name=min_to_show_per_chunk,json=minToShowPerChunk,proto3" json:"min_to_show_per_chunk,omitempty"`
+	MinPollutionToDamageTrees               int32   `protobuf:"varint,7,opt,name=min_pollution_to_damage_trees,json=minPollutionToDamageTrees,proto3" json:"min_pollution_to_damage_trees,omitempty"`
+	PollutionWithMaxForestDamage            int32   `protobuf:"varint,8,opt,name=pollution_with_max_forest_damage,json=pollutionWithMaxForestDamage,proto3" json:"pollution_with_max_forest_damage,omitempty"`
+	PollutionPerTreeDamage                  int32   `protobuf:"varint,9,opt,name=pollution_per_tree_damage,json=pollutionPerTreeDamage,proto3" json:"pollution_per_tree_damage,omitempty"`
+	PollutionRestoredPerTreeDamage          int32   `protobuf:"varint,10,opt,name=pollution_restored_per_tree_damage,json=pollutionRestoredPerTreeDamage,proto3" json:"pollution_restored_per_tree_damage,omitempty"`
+	MaxPollutionToRestoreTrees              int32   `protobuf:"varint,11,opt,name=max_pollution_to_restore_trees,json=maxPollutionToRestoreTrees,proto3" json:"max_pollution_to_restore_trees,omitempty"`
+	EnemyAttackPollutionConsumptionModifier int32   `protobuf:"varint,12,opt,name=enemy_attack_pollution_consumption_modifier,json=enemyAttackPollutionConsumptionModifier,proto3" json:"enemy_attack_pollution_consumption_modifier,omitempty"`
 	unknownFields                           protoimpl.UnknownFields
 	sizeCache                               protoimpl.SizeCache
 }
 
 func (x *FactorioPollutionSettings) Reset() {
 	*x = FactorioPollutionSettings{}
-	mi := &file_configuration_proto_msgTypes[3]
+	mi := &file_configuration_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -513,7 +746,7 @@ func (x *FactorioPollutionSettings) String() string {
 func (*FactorioPollutionSettings) ProtoMessage() {}
 
 func (x *FactorioPollutionSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[3]
+	mi := &file_configuration_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -526,12 +759,12 @@ func (x *FactorioPollutionSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioPollutionSettings.ProtoReflect.Descriptor instead.
 func (*FactorioPollutionSettings) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{3}
+	return file_configuration_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *FactorioPollutionSettings) GetEnabled() bool {
-	if x != nil {
-		return x.Enabled
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
 	}
 	return false
 }
@@ -614,18 +847,19 @@ func (x *FactorioPollutionSettings) GetEnemyAttackPollutionConsumptionModifier()
 }
 
 type FactorioEnemyEvolution struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Enabled         bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	TimeFactor      float64                `protobuf:"fixed64,2,opt,name=time_factor,json=timeFactor,proto3" json:"time_factor,omitempty"`
-	DestroyFactor   float64                `protobuf:"fixed64,3,opt,name=destroy_factor,json=destroyFactor,proto3" json:"destroy_factor,omitempty"`
-	PollutionFactor float64                `protobuf:"fixed64,4,opt,name=pollution_factor,json=pollutionFactor,proto3" json:"pollution_factor,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Незаданный флаг при развёртывании берётся из upstream-примера
+	Enabled         *bool   `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	TimeFactor      float64 `protobuf:"fixed64,2,opt,name=time_factor,json=timeFactor,proto3" json:"time_factor,omitempty"`
+	DestroyFactor   float64 `protobuf:"fixed64,3,opt,name=destroy_factor,json=destroyFactor,proto3" json:"destroy_factor,omitempty"`
+	PollutionFactor float64 `protobuf:"fixed64,4,opt,name=pollution_factor,json=pollutionFactor,proto3" json:"pollution_factor,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *FactorioEnemyEvolution) Reset() {
 	*x = FactorioEnemyEvolution{}
-	mi := &file_configuration_proto_msgTypes[4]
+	mi := &file_configuration_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -637,7 +871,7 @@ func (x *FactorioEnemyEvolution) String() string {
 func (*FactorioEnemyEvolution) ProtoMessage() {}
 
 func (x *FactorioEnemyEvolution) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[4]
+	mi := &file_configuration_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -650,12 +884,12 @@ func (x *FactorioEnemyEvolution) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioEnemyEvolution.ProtoReflect.Descriptor instead.
 func (*FactorioEnemyEvolution) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{4}
+	return file_configuration_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *FactorioEnemyEvolution) GetEnabled() bool {
-	if x != nil {
-		return x.Enabled
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
 	}
 	return false
 }
@@ -682,27 +916,28 @@ func (x *FactorioEnemyEvolution) GetPollutionFactor() float64 {
 }
 
 type FactorioEnemyExpansion struct {
-	state                            protoimpl.MessageState `protogen:"open.v1"`
-	Enabled                          bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	MaxExpansionDistance             int32                  `protobuf:"varint,2,opt,name=max_expansion_distance,json=maxExpansionDistance,proto3" json:"max_expansion_distance,omitempty"`
-	FriendlyBaseInfluenceRadius      int32                  `protobuf:"varint,3,opt,name=friendly_base_influence_radius,json=friendlyBaseInfluenceRadius,proto3" json:"friendly_base_influence_radius,omitempty"`
-	EnemyBuildingInfluenceRadius     int32                  `protobuf:"varint,4,opt,name=enemy_building_influence_radius,json=enemyBuildingInfluenceRadius,proto3" json:"enemy_building_influence_radius,omitempty"`
-	BuildingCoefficient              float64                `protobuf:"fixed64,5,opt,name=building_coefficient,json=buildingCoefficient,proto3" json:"building_coefficient,omitempty"`
-	OtherBaseCoefficient             float64                `protobuf:"fixed64,6,opt,name=other_base_coefficient,json=otherBaseCoefficient,proto3" json:"other_base_coefficient,omitempty"`
-	NeighbouringChunkCoefficient     float64                `protobuf:"fixed64,7,opt,name=neighbouring_chunk_coefficient,json=neighbouringChunkCoefficient,proto3" json:"neighbouring_chunk_coefficient,omitempty"`
-	NeighbouringBaseChunkCoefficient float64                `protobuf:"fixed64,8,opt,name=neighbouring_base_chunk_coefficient,json=neighbouringBaseChunkCoefficient,proto3" json:"neighbouring_base_chunk_coefficient,omitempty"`
-	MaxCollidingTilesCoefficient     float64                `protobuf:"fixed64,9,opt,name=max_colliding_tiles_coefficient,json=maxCollidingTilesCoefficient,proto3" json:"max_colliding_tiles_coefficient,omitempty"`
-	SettlerGroupMinSize              int32                  `protobuf:"varint,10,opt,name=settler_group_min_size,json=settlerGroupMinSize,proto3" json:"settler_group_min_size,omitempty"`
-	SettlerGroupMaxSize              int32                  `protobuf:"varint,11,opt,name=settler_group_max_size,json=settlerGroupMaxSize,proto3" json:"settler_group_max_size,omitempty"`
-	MinExpansionCooldown             int32                  `protobuf:"varint,12,opt,name=min_expansion_cooldown,json=minExpansionCooldown,proto3" json:"min_expansion_cooldown,omitempty"`
-	MaxExpansionCooldown             int32                  `protobuf:"varint,13,opt,name=max_expansion_cooldown,json=maxExpansionCooldown,proto3" json:"max_expansion_cooldown,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Незаданный флаг при развёртывании берётся из upstream-примера
+	Enabled                          *bool   `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	MaxExpansionDistance             int32   `protobuf:"varint,2,opt,name=max_expansion_distance,json=maxExpansionDistance,proto3" json:"max_expansion_distance,omitempty"`
+	FriendlyBaseInfluenceRadius      int32   `protobuf:"varint,3,opt,name=friendly_base_influence_radius,json=friendlyBaseInfluenceRadius,proto3" json:"friendly_base_influence_radius,omitempty"`
+	EnemyBuildingInfluenceRadius     int32   `protobuf:"varint,4,opt,name=enemy_building_influence_radius,json=enemyBuildingInfluenceRadius,proto3" json:"enemy_building_influence_radius,omitempty"`
+	BuildingCoefficient              float64 `protobuf:"fixed64,5,opt,name=building_coefficient,json=buildingCoefficient,proto3" json:"building_coefficient,omitempty"`
+	OtherBaseCoefficient             float64 `protobuf:"fixed64,6,opt,name=other_base_coefficient,json=otherBaseCoefficient,proto3" json:"other_base_coefficient,omitempty"`
+	NeighbouringChunkCoefficient     float64 `protobuf:"fixed64,7,opt,name=neighbouring_chunk_coefficient,json=neighbouringChunkCoefficient,proto3" json:"neighbouring_chunk_coefficient,omitempty"`
+	NeighbouringBaseChunkCoefficient float64 `protobuf:"fixed64,8,opt,name=neighbouring_base_chunk_coefficient,json=neighbouringBaseChunkCoefficient,proto3" json:"neighbouring_base_chunk_coefficient,omitempty"`
+	MaxCollidingTilesCoefficient     float64 `protobuf:"fixed64,9,opt,name=max_colliding_tiles_coefficient,json=maxCollidingTilesCoefficient,proto3" json:"max_colliding_tiles_coefficient,omitempty"`
+	SettlerGroupMinSize              int32   `protobuf:"varint,10,opt,name=settler_group_min_size,json=settlerGroupMinSize,proto3" json:"settler_group_min_size,omitempty"`
+	SettlerGroupMaxSize              int32   `protobuf:"varint,11,opt,name=settler_group_max_size,json=settlerGroupMaxSize,proto3" json:"settler_group_max_size,omitempty"`
+	MinExpansionCooldown             int32   `protobuf:"varint,12,opt,name=min_expansion_cooldown,json=minExpansionCooldown,proto3" json:"min_expansion_cooldown,omitempty"`
+	MaxExpansionCooldown             int32   `protobuf:"varint,13,opt,name=max_expansion_cooldown,json=maxExpansionCooldown,proto3" json:"max_expansion_cooldown,omitempty"`
 	unknownFields                    protoimpl.UnknownFields
 	sizeCache                        protoimpl.SizeCache
 }
 
 func (x *FactorioEnemyExpansion) Reset() {
 	*x = FactorioEnemyExpansion{}
-	mi := &file_configuration_proto_msgTypes[5]
+	mi := &file_configuration_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -714,7 +949,7 @@ func (x *FactorioEnemyExpansion) String() string {
 func (*FactorioEnemyExpansion) ProtoMessage() {}
 
 func (x *FactorioEnemyExpansion) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[5]
+	mi := &file_configuration_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -727,12 +962,12 @@ func (x *FactorioEnemyExpansion) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioEnemyExpansion.ProtoReflect.Descriptor instead.
 func (*FactorioEnemyExpansion) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{5}
+	return file_configuration_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *FactorioEnemyExpansion) GetEnabled() bool {
-	if x != nil {
-		return x.Enabled
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
 	}
 	return false
 }
@@ -842,7 +1077,7 @@ type FactorioUnitGroup struct {
 
 func (x *FactorioUnitGroup) Reset() {
 	*x = FactorioUnitGroup{}
-	mi := &file_configuration_proto_msgTypes[6]
+	mi := &file_configuration_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -854,7 +1089,7 @@ func (x *FactorioUnitGroup) String() string {
 func (*FactorioUnitGroup) ProtoMessage() {}
 
 func (x *FactorioUnitGroup) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[6]
+	mi := &file_configuration_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -867,7 +1102,7 @@ func (x *FactorioUnitGroup) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioUnitGroup.ProtoReflect.Descriptor instead.
 func (*FactorioUnitGroup) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{6}
+	return file_configuration_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *FactorioUnitGroup) GetMinGroupGatheringTime() int32 {
@@ -974,7 +1209,7 @@ type FactorioMapSettings struct {
 
 func (x *FactorioMapSettings) Reset() {
 	*x = FactorioMapSettings{}
-	mi := &file_configuration_proto_msgTypes[7]
+	mi := &file_configuration_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -986,7 +1221,7 @@ func (x *FactorioMapSettings) String() string {
 func (*FactorioMapSettings) ProtoMessage() {}
 
 func (x *FactorioMapSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[7]
+	mi := &file_configuration_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -999,7 +1234,7 @@ func (x *FactorioMapSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioMapSettings.ProtoReflect.Descriptor instead.
 func (*FactorioMapSettings) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{7}
+	return file_configuration_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *FactorioMapSettings) GetDifficultySettings() *FactorioDifficultySettings {
@@ -1048,7 +1283,7 @@ type FactorioResourceSettings struct {
 
 func (x *FactorioResourceSettings) Reset() {
 	*x = FactorioResourceSettings{}
-	mi := &file_configuration_proto_msgTypes[8]
+	mi := &file_configuration_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1060,7 +1295,7 @@ func (x *FactorioResourceSettings) String() string {
 func (*FactorioResourceSettings) ProtoMessage() {}
 
 func (x *FactorioResourceSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[8]
+	mi := &file_configuration_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1073,7 +1308,7 @@ func (x *FactorioResourceSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioResourceSettings.ProtoReflect.Descriptor instead.
 func (*FactorioResourceSettings) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{8}
+	return file_configuration_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *FactorioResourceSettings) GetFrequency() float32 {
@@ -1109,7 +1344,7 @@ type FactorioCliffSettings struct {
 
 func (x *FactorioCliffSettings) Reset() {
 	*x = FactorioCliffSettings{}
-	mi := &file_configuration_proto_msgTypes[9]
+	mi := &file_configuration_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1121,7 +1356,7 @@ func (x *FactorioCliffSettings) String() string {
 func (*FactorioCliffSettings) ProtoMessage() {}
 
 func (x *FactorioCliffSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[9]
+	mi := &file_configuration_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1134,7 +1369,7 @@ func (x *FactorioCliffSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioCliffSettings.ProtoReflect.Descriptor instead.
 func (*FactorioCliffSettings) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{9}
+	return file_configuration_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *FactorioCliffSettings) GetName() string {
@@ -1180,7 +1415,7 @@ type FactorioMapGenSettings struct {
 
 func (x *FactorioMapGenSettings) Reset() {
 	*x = FactorioMapGenSettings{}
-	mi := &file_configuration_proto_msgTypes[10]
+	mi := &file_configuration_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1192,7 +1427,7 @@ func (x *FactorioMapGenSettings) String() string {
 func (*FactorioMapGenSettings) ProtoMessage() {}
 
 func (x *FactorioMapGenSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[10]
+	mi := &file_configuration_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1205,7 +1440,7 @@ func (x *FactorioMapGenSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioMapGenSettings.ProtoReflect.Descriptor instead.
 func (*FactorioMapGenSettings) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{10}
+	return file_configuration_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *FactorioMapGenSettings) GetWidth() int32 {
@@ -1263,13 +1498,14 @@ type FactorioConfig struct {
 	Server        *FactorioServerSettings `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
 	Map           *FactorioMapSettings    `protobuf:"bytes,3,opt,name=map,proto3" json:"map,omitempty"`
 	MapGen        *FactorioMapGenSettings `protobuf:"bytes,4,opt,name=map_gen,json=mapGen,proto3" json:"map_gen,omitempty"`
+	RconPort      uint32                  `protobuf:"varint,5,opt,name=rcon_port,json=rconPort,proto3" json:"rcon_port,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FactorioConfig) Reset() {
 	*x = FactorioConfig{}
-	mi := &file_configuration_proto_msgTypes[11]
+	mi := &file_configuration_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1281,7 +1517,7 @@ func (x *FactorioConfig) String() string {
 func (*FactorioConfig) ProtoMessage() {}
 
 func (x *FactorioConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[11]
+	mi := &file_configuration_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1294,7 +1530,7 @@ func (x *FactorioConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FactorioConfig.ProtoReflect.Descriptor instead.
 func (*FactorioConfig) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{11}
+	return file_configuration_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *FactorioConfig) GetBase() *BaseConfig {
@@ -1325,6 +1561,13 @@ func (x *FactorioConfig) GetMapGen() *FactorioMapGenSettings {
 	return nil
 }
 
+func (x *FactorioConfig) GetRconPort() uint32 {
+	if x != nil {
+		return x.RconPort
+	}
+	return 0
+}
+
 type MinecraftConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Base          *BaseConfig            `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
@@ -1334,13 +1577,14 @@ type MinecraftConfig struct {
 	ServerName    string                 `protobuf:"bytes,5,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
 	MaxPlayers    uint32                 `protobuf:"varint,6,opt,name=max_players,json=maxPlayers,proto3" json:"max_players,omitempty"`
 	ViewDistance  int32                  `protobuf:"varint,7,opt,name=view_distance,json=viewDistance,proto3" json:"view_distance,omitempty"`
+	Properties    map[string]string      `protobuf:"bytes,8,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MinecraftConfig) Reset() {
 	*x = MinecraftConfig{}
-	mi := &file_configuration_proto_msgTypes[12]
+	mi := &file_configuration_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1352,7 +1596,7 @@ func (x *MinecraftConfig) String() string {
 func (*MinecraftConfig) ProtoMessage() {}
 
 func (x *MinecraftConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[12]
+	mi := &file_configuration_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1365,7 +1609,7 @@ func (x *MinecraftConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MinecraftConfig.ProtoReflect.Descriptor instead.
 func (*MinecraftConfig) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{12}
+	return file_configuration_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *MinecraftConfig) GetBase() *BaseConfig {
@@ -1417,6 +1661,95 @@ func (x *MinecraftConfig) GetViewDistance() int32 {
 	return 0
 }
 
+func (x *MinecraftConfig) GetProperties() map[string]string {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type Configuration struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Config:
+	//
+	//	*Configuration_Factorio
+	//	*Configuration_Minecraft
+	Config        isConfiguration_Config `protobuf_oneof:"config"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Configuration) Reset() {
+	*x = Configuration{}
+	mi := &file_configuration_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Configuration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Configuration) ProtoMessage() {}
+
+func (x *Configuration) ProtoReflect() protoreflect.Message {
+	mi := &file_configuration_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Configuration.ProtoReflect.Descriptor instead.
+func (*Configuration) Descriptor() ([]byte, []int) {
+	return file_configuration_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Configuration) GetConfig() isConfiguration_Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *Configuration) GetFactorio() *FactorioConfig {
+	if x != nil {
+		if x, ok := x.Config.(*Configuration_Factorio); ok {
+			return x.Factorio
+		}
+	}
+	return nil
+}
+
+func (x *Configuration) GetMinecraft() *MinecraftConfig {
+	if x != nil {
+		if x, ok := x.Config.(*Configuration_Minecraft); ok {
+			return x.Minecraft
+		}
+	}
+	return nil
+}
+
+type isConfiguration_Config interface {
+	isConfiguration_Config()
+}
+
+type Configuration_Factorio struct {
+	Factorio *FactorioConfig `protobuf:"bytes,1,opt,name=factorio,proto3,oneof"`
+}
+
+type Configuration_Minecraft struct {
+	Minecraft *MinecraftConfig `protobuf:"bytes,2,opt,name=minecraft,proto3,oneof"`
+}
+
+func (*Configuration_Factorio) isConfiguration_Config() {}
+
+func (*Configuration_Minecraft) isConfiguration_Config() {}
+
 type GetConfigByIdRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -1426,7 +1759,7 @@ type GetConfigByIdRequest struct {
 
 func (x *GetConfigByIdRequest) Reset() {
 	*x = GetConfigByIdRequest{}
-	mi := &file_configuration_proto_msgTypes[13]
+	mi := &file_configuration_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1438,7 +1771,7 @@ func (x *GetConfigByIdRequest) String() string {
 func (*GetConfigByIdRequest) ProtoMessage() {}
 
 func (x *GetConfigByIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[13]
+	mi := &file_configuration_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1451,7 +1784,7 @@ func (x *GetConfigByIdRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetConfigByIdRequest.ProtoReflect.Descriptor instead.
 func (*GetConfigByIdRequest) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{13}
+	return file_configuration_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetConfigByIdRequest) GetId() string {
@@ -1463,14 +1796,14 @@ func (x *GetConfigByIdRequest) GetId() string {
 
 type GetConfigByIdResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Configuration *FactorioConfig        `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	Configuration *Configuration         `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetConfigByIdResponse) Reset() {
 	*x = GetConfigByIdResponse{}
-	mi := &file_configuration_proto_msgTypes[14]
+	mi := &file_configuration_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1482,7 +1815,7 @@ func (x *GetConfigByIdResponse) String() string {
 func (*GetConfigByIdResponse) ProtoMessage() {}
 
 func (x *GetConfigByIdResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[14]
+	mi := &file_configuration_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1495,25 +1828,29 @@ func (x *GetConfigByIdResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetConfigByIdResponse.ProtoReflect.Descriptor instead.
 func (*GetConfigByIdResponse) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{14}
+	return file_configuration_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *GetConfigByIdResponse) GetConfiguration() *FactorioConfig {
+func (x *GetConfigByIdResponse) GetConfiguration() *Configuration {
 	if x != nil {
 		return x.Configuration
 	}
 	return nil
 }
 
+// Фильтры объединяются через И, значения одного фильтра - через ИЛИ
 type GetAllConfigurationsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       *ListOptions           `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	Type          []ConfigurationType    `protobuf:"varint,2,rep,packed,name=type,proto3,enum=api.ConfigurationType" json:"type,omitempty"`
+	AgentId       []string               `protobuf:"bytes,3,rep,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetAllConfigurationsRequest) Reset() {
 	*x = GetAllConfigurationsRequest{}
-	mi := &file_configuration_proto_msgTypes[15]
+	mi := &file_configuration_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1525,7 +1862,7 @@ func (x *GetAllConfigurationsRequest) String() string {
 func (*GetAllConfigurationsRequest) ProtoMessage() {}
 
 func (x *GetAllConfigurationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[15]
+	mi := &file_configuration_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1538,19 +1875,42 @@ func (x *GetAllConfigurationsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllConfigurationsRequest.ProtoReflect.Descriptor instead.
 func (*GetAllConfigurationsRequest) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{15}
+	return file_configuration_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetAllConfigurationsRequest) GetOptions() *ListOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *GetAllConfigurationsRequest) GetType() []ConfigurationType {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *GetAllConfigurationsRequest) GetAgentId() []string {
+	if x != nil {
+		return x.AgentId
+	}
+	return nil
 }
 
 type GetAllConfigurationsResponse struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
-	Configurations []*FactorioConfig      `protobuf:"bytes,1,rep,name=configurations,proto3" json:"configurations,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	Configurations []*Configuration       `protobuf:"bytes,1,rep,name=configurations,proto3" json:"configurations,omitempty"`
+	// Пустой на последней странице
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetAllConfigurationsResponse) Reset() {
 	*x = GetAllConfigurationsResponse{}
-	mi := &file_configuration_proto_msgTypes[16]
+	mi := &file_configuration_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1562,7 +1922,7 @@ func (x *GetAllConfigurationsResponse) String() string {
 func (*GetAllConfigurationsResponse) ProtoMessage() {}
 
 func (x *GetAllConfigurationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[16]
+	mi := &file_configuration_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1575,26 +1935,33 @@ func (x *GetAllConfigurationsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllConfigurationsResponse.ProtoReflect.Descriptor instead.
 func (*GetAllConfigurationsResponse) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{16}
+	return file_configuration_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *GetAllConfigurationsResponse) GetConfigurations() []*FactorioConfig {
+func (x *GetAllConfigurationsResponse) GetConfigurations() []*Configuration {
 	if x != nil {
 		return x.Configurations
 	}
 	return nil
 }
 
+func (x *GetAllConfigurationsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 type PostConfigurationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Configuration *FactorioConfig        `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	Configuration *Configuration         `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PostConfigurationRequest) Reset() {
 	*x = PostConfigurationRequest{}
-	mi := &file_configuration_proto_msgTypes[17]
+	mi := &file_configuration_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1606,7 +1973,7 @@ func (x *PostConfigurationRequest) String() string {
 func (*PostConfigurationRequest) ProtoMessage() {}
 
 func (x *PostConfigurationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[17]
+	mi := &file_configuration_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1619,10 +1986,10 @@ func (x *PostConfigurationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PostConfigurationRequest.ProtoReflect.Descriptor instead.
 func (*PostConfigurationRequest) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{17}
+	return file_configuration_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *PostConfigurationRequest) GetConfiguration() *FactorioConfig {
+func (x *PostConfigurationRequest) GetConfiguration() *Configuration {
 	if x != nil {
 		return x.Configuration
 	}
@@ -1637,7 +2004,7 @@ type PostConfigurationResponse struct {
 
 func (x *PostConfigurationResponse) Reset() {
 	*x = PostConfigurationResponse{}
-	mi := &file_configuration_proto_msgTypes[18]
+	mi := &file_configuration_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1649,7 +2016,7 @@ func (x *PostConfigurationResponse) String() string {
 func (*PostConfigurationResponse) ProtoMessage() {}
 
 func (x *PostConfigurationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[18]
+	mi := &file_configuration_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1662,20 +2029,20 @@ func (x *PostConfigurationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PostConfigurationResponse.ProtoReflect.Descriptor instead.
 func (*PostConfigurationResponse) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{18}
+	return file_configuration_proto_rawDescGZIP(), []int{22}
 }
 
 type PutConfigurationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Configuration *FactorioConfig        `protobuf:"bytes,2,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	Configuration *Configuration         `protobuf:"bytes,2,opt,name=configuration,proto3" json:"configuration,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PutConfigurationRequest) Reset() {
 	*x = PutConfigurationRequest{}
-	mi := &file_configuration_proto_msgTypes[19]
+	mi := &file_configuration_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1687,7 +2054,7 @@ func (x *PutConfigurationRequest) String() string {
 func (*PutConfigurationRequest) ProtoMessage() {}
 
 func (x *PutConfigurationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[19]
+	mi := &file_configuration_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1700,7 +2067,7 @@ func (x *PutConfigurationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PutConfigurationRequest.ProtoReflect.Descriptor instead.
 func (*PutConfigurationRequest) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{19}
+	return file_configuration_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *PutConfigurationRequest) GetId() string {
@@ -1710,7 +2077,7 @@ func (x *PutConfigurationRequest) GetId() string {
 	return ""
 }
 
-func (x *PutConfigurationRequest) GetConfiguration() *FactorioConfig {
+func (x *PutConfigurationRequest) GetConfiguration() *Configuration {
 	if x != nil {
 		return x.Configuration
 	}
@@ -1725,7 +2092,7 @@ type PutConfigurationResponse struct {
 
 func (x *PutConfigurationResponse) Reset() {
 	*x = PutConfigurationResponse{}
-	mi := &file_configuration_proto_msgTypes[20]
+	mi := &file_configuration_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1737,7 +2104,7 @@ func (x *PutConfigurationResponse) String() string {
 func (*PutConfigurationResponse) ProtoMessage() {}
 
 func (x *PutConfigurationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[20]
+	mi := &file_configuration_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1750,7 +2117,7 @@ func (x *PutConfigurationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PutConfigurationResponse.ProtoReflect.Descriptor instead.
 func (*PutConfigurationResponse) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{20}
+	return file_configuration_proto_rawDescGZIP(), []int{24}
 }
 
 type DeleteConfigurationRequest struct {
@@ -1762,7 +2129,7 @@ type DeleteConfigurationRequest struct {
 
 func (x *DeleteConfigurationRequest) Reset() {
 	*x = DeleteConfigurationRequest{}
-	mi := &file_configuration_proto_msgTypes[21]
+	mi := &file_configuration_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1774,7 +2141,7 @@ func (x *DeleteConfigurationRequest) String() string {
 func (*DeleteConfigurationRequest) ProtoMessage() {}
 
 func (x *DeleteConfigurationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[21]
+	mi := &file_configuration_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1787,7 +2154,7 @@ func (x *DeleteConfigurationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteConfigurationRequest.ProtoReflect.Descriptor instead.
 func (*DeleteConfigurationRequest) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{21}
+	return file_configuration_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *DeleteConfigurationRequest) GetId() string {
@@ -1805,7 +2172,7 @@ type DeleteConfigurationResponse struct {
 
 func (x *DeleteConfigurationResponse) Reset() {
 	*x = DeleteConfigurationResponse{}
-	mi := &file_configuration_proto_msgTypes[22]
+	mi := &file_configuration_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1817,7 +2184,7 @@ func (x *DeleteConfigurationResponse) String() string {
 func (*DeleteConfigurationResponse) ProtoMessage() {}
 
 func (x *DeleteConfigurationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_configuration_proto_msgTypes[22]
+	mi := &file_configuration_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1830,20 +2197,153 @@ func (x *DeleteConfigurationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteConfigurationResponse.ProtoReflect.Descriptor instead.
 func (*DeleteConfigurationResponse) Descriptor() ([]byte, []int) {
-	return file_configuration_proto_rawDescGZIP(), []int{22}
+	return file_configuration_proto_rawDescGZIP(), []int{26}
+}
+
+// Пустая ревизия - сначала снимок всех конфигураций, иначе изменения после неё
+type WatchConfigurationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      string                 `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchConfigurationsRequest) Reset() {
+	*x = WatchConfigurationsRequest{}
+	mi := &file_configuration_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchConfigurationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchConfigurationsRequest) ProtoMessage() {}
+
+func (x *WatchConfigurationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_configuration_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchConfigurationsRequest.ProtoReflect.Descriptor instead.
+func (*WatchConfigurationsRequest) Descriptor() ([]byte, []int) {
+	return file_configuration_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *WatchConfigurationsRequest) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+// Конфигурация заполнена для добавления и изменения, id - для всех изменений, кроме synced
+type WatchConfigurationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          WatchEventType         `protobuf:"varint,1,opt,name=type,proto3,enum=api.WatchEventType" json:"type,omitempty"`
+	Revision      string                 `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Configuration *Configuration         `protobuf:"bytes,4,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchConfigurationsResponse) Reset() {
+	*x = WatchConfigurationsResponse{}
+	mi := &file_configuration_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchConfigurationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchConfigurationsResponse) ProtoMessage() {}
+
+func (x *WatchConfigurationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_configuration_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchConfigurationsResponse.ProtoReflect.Descriptor instead.
+func (*WatchConfigurationsResponse) Descriptor() ([]byte, []int) {
+	return file_configuration_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WatchConfigurationsResponse) GetType() WatchEventType {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEventType_WATCH_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *WatchConfigurationsResponse) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+func (x *WatchConfigurationsResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WatchConfigurationsResponse) GetConfiguration() *Configuration {
+	if x != nil {
+		return x.Configuration
+	}
+	return nil
 }
 
 var File_configuration_proto protoreflect.FileDescriptor
 
 const file_configuration_proto_rawDesc = "" +
 	"\n" +
-	"\x13configuration.proto\x12\x03api\"w\n" +
+	"\x13configuration.proto\x12\x03api\x1a\vwatch.proto\x1a\n" +
+	"list.proto\"\xd3\x01\n" +
 	"\n" +
 	"BaseConfig\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
 	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x12\n" +
 	"\x04port\x18\x03 \x01(\rR\x04port\x12*\n" +
-	"\x04type\x18\x04 \x01(\x0e2\x16.api.ConfigurationTypeR\x04type\"\xe1\t\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x16.api.ConfigurationTypeR\x04type\x12,\n" +
+	"\tplacement\x18\x05 \x01(\v2\x0e.api.PlacementR\tplacement\x12,\n" +
+	"\tresources\x18\x06 \x01(\v2\x0e.api.ResourcesR\tresources\"\x89\x01\n" +
+	"\tResources\x12\x1b\n" +
+	"\tcpu_cores\x18\x01 \x01(\x01R\bcpuCores\x12\x1d\n" +
+	"\n" +
+	"cpu_shares\x18\x02 \x01(\rR\tcpuShares\x12!\n" +
+	"\fmemory_bytes\x18\x03 \x01(\x04R\vmemoryBytes\x12\x1d\n" +
+	"\n" +
+	"disk_bytes\x18\x04 \x01(\x04R\tdiskBytes\"\xe4\x01\n" +
+	"\tPlacement\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x122\n" +
+	"\x06labels\x18\x02 \x03(\v2\x1a.api.Placement.LabelsEntryR\x06labels\x12(\n" +
+	"\x10min_memory_bytes\x18\x03 \x01(\x04R\x0eminMemoryBytes\x12\"\n" +
+	"\rmin_cpu_cores\x18\x04 \x01(\x01R\vminCpuCores\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xdb\n" +
+	"\n" +
 	"\x16FactorioServerSettings\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
@@ -1872,12 +2372,20 @@ const file_configuration_proto_rawDesc = "" +
 	"\x14minimum_segment_size\x18\x16 \x01(\x05R\x12minimumSegmentSize\x12D\n" +
 	"\x1fminimum_segment_size_peer_count\x18\x17 \x01(\x05R\x1bminimumSegmentSizePeerCount\x120\n" +
 	"\x14maximum_segment_size\x18\x18 \x01(\x05R\x12maximumSegmentSize\x12D\n" +
-	"\x1fmaximum_segment_size_peer_count\x18\x19 \x01(\x05R\x1bmaximumSegmentSizePeerCount\"\x8c\x01\n" +
+	"\x1fmaximum_segment_size_peer_count\x18\x19 \x01(\x05R\x1bmaximumSegmentSizePeerCount\x127\n" +
+	"\n" +
+	"visibility\x18\x1a \x01(\v2\x17.api.FactorioVisibilityR\n" +
+	"visibility\x12\x1a\n" +
+	"\bpassword\x18\x1b \x01(\tR\bpassword\x12#\n" +
+	"\rgame_password\x18\x1c \x01(\tR\fgamePassword\">\n" +
+	"\x12FactorioVisibility\x12\x16\n" +
+	"\x06public\x18\x01 \x01(\bR\x06public\x12\x10\n" +
+	"\x03lan\x18\x02 \x01(\bR\x03lan\"\x8c\x01\n" +
 	"\x1aFactorioDifficultySettings\x12>\n" +
 	"\x1btechnology_price_multiplier\x18\x01 \x01(\x02R\x19technologyPriceMultiplier\x12.\n" +
-	"\x13spoil_time_modifier\x18\x02 \x01(\x02R\x11spoilTimeModifier\"\xb6\x05\n" +
-	"\x19FactorioPollutionSettings\x12\x18\n" +
-	"\aenabled\x18\x01 \x01(\bR\aenabled\x12'\n" +
+	"\x13spoil_time_modifier\x18\x02 \x01(\x02R\x11spoilTimeModifier\"\xc7\x05\n" +
+	"\x19FactorioPollutionSettings\x12\x1d\n" +
+	"\aenabled\x18\x01 \x01(\bH\x00R\aenabled\x88\x01\x01\x12'\n" +
 	"\x0fdiffusion_ratio\x18\x02 \x01(\x01R\x0ediffusionRatio\x12$\n" +
 	"\x0emin_to_diffuse\x18\x03 \x01(\x05R\fminToDiffuse\x12\x16\n" +
 	"\x06ageing\x18\x04 \x01(\x05R\x06ageing\x123\n" +
@@ -1889,15 +2397,19 @@ const file_configuration_proto_rawDesc = "" +
 	"\"pollution_restored_per_tree_damage\x18\n" +
 	" \x01(\x05R\x1epollutionRestoredPerTreeDamage\x12B\n" +
 	"\x1emax_pollution_to_restore_trees\x18\v \x01(\x05R\x1amaxPollutionToRestoreTrees\x12\\\n" +
-	"+enemy_attack_pollution_consumption_modifier\x18\f \x01(\x05R'enemyAttackPollutionConsumptionModifier\"\xa5\x01\n" +
-	"\x16FactorioEnemyEvolution\x12\x18\n" +
-	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1f\n" +
+	"+enemy_attack_pollution_consumption_modifier\x18\f \x01(\x05R'enemyAttackPollutionConsumptionModifierB\n" +
+	"\n" +
+	"\b_enabled\"\xb6\x01\n" +
+	"\x16FactorioEnemyEvolution\x12\x1d\n" +
+	"\aenabled\x18\x01 \x01(\bH\x00R\aenabled\x88\x01\x01\x12\x1f\n" +
 	"\vtime_factor\x18\x02 \x01(\x01R\n" +
 	"timeFactor\x12%\n" +
 	"\x0edestroy_factor\x18\x03 \x01(\x01R\rdestroyFactor\x12)\n" +
-	"\x10pollution_factor\x18\x04 \x01(\x01R\x0fpollutionFactor\"\x8f\x06\n" +
-	"\x16FactorioEnemyExpansion\x12\x18\n" +
-	"\aenabled\x18\x01 \x01(\bR\aenabled\x124\n" +
+	"\x10pollution_factor\x18\x04 \x01(\x01R\x0fpollutionFactorB\n" +
+	"\n" +
+	"\b_enabled\"\xa0\x06\n" +
+	"\x16FactorioEnemyExpansion\x12\x1d\n" +
+	"\aenabled\x18\x01 \x01(\bH\x00R\aenabled\x88\x01\x01\x124\n" +
 	"\x16max_expansion_distance\x18\x02 \x01(\x05R\x14maxExpansionDistance\x12C\n" +
 	"\x1efriendly_base_influence_radius\x18\x03 \x01(\x05R\x1bfriendlyBaseInfluenceRadius\x12E\n" +
 	"\x1fenemy_building_influence_radius\x18\x04 \x01(\x05R\x1cenemyBuildingInfluenceRadius\x121\n" +
@@ -1910,7 +2422,9 @@ const file_configuration_proto_rawDesc = "" +
 	" \x01(\x05R\x13settlerGroupMinSize\x123\n" +
 	"\x16settler_group_max_size\x18\v \x01(\x05R\x13settlerGroupMaxSize\x124\n" +
 	"\x16min_expansion_cooldown\x18\f \x01(\x05R\x14minExpansionCooldown\x124\n" +
-	"\x16max_expansion_cooldown\x18\r \x01(\x05R\x14maxExpansionCooldown\"\x93\x06\n" +
+	"\x16max_expansion_cooldown\x18\r \x01(\x05R\x14maxExpansionCooldownB\n" +
+	"\n" +
+	"\b_enabled\"\x93\x06\n" +
 	"\x11FactorioUnitGroup\x127\n" +
 	"\x18min_group_gathering_time\x18\x01 \x01(\x05R\x15minGroupGatheringTime\x127\n" +
 	"\x18max_group_gathering_time\x18\x02 \x01(\x05R\x15maxGroupGatheringTime\x12A\n" +
@@ -1952,12 +2466,13 @@ const file_configuration_proto_rawDesc = "" +
 	"\x04seed\x18\a \x01(\x05R\x04seed\x1ac\n" +
 	"\x16AutoplaceControlsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x123\n" +
-	"\x05value\x18\x02 \x01(\v2\x1d.api.FactorioResourceSettingsR\x05value:\x028\x01\"\xcc\x01\n" +
+	"\x05value\x18\x02 \x01(\v2\x1d.api.FactorioResourceSettingsR\x05value:\x028\x01\"\xe9\x01\n" +
 	"\x0eFactorioConfig\x12#\n" +
 	"\x04base\x18\x01 \x01(\v2\x0f.api.BaseConfigR\x04base\x123\n" +
 	"\x06server\x18\x02 \x01(\v2\x1b.api.FactorioServerSettingsR\x06server\x12*\n" +
 	"\x03map\x18\x03 \x01(\v2\x18.api.FactorioMapSettingsR\x03map\x124\n" +
-	"\amap_gen\x18\x04 \x01(\v2\x1b.api.FactorioMapGenSettingsR\x06mapGen\"\x82\x02\n" +
+	"\amap_gen\x18\x04 \x01(\v2\x1b.api.FactorioMapGenSettingsR\x06mapGen\x12\x1b\n" +
+	"\trcon_port\x18\x05 \x01(\rR\brconPort\"\x87\x03\n" +
 	"\x0fMinecraftConfig\x12#\n" +
 	"\x04base\x18\x01 \x01(\v2\x0f.api.BaseConfigR\x04base\x12\x12\n" +
 	"\x04seed\x18\x02 \x01(\tR\x04seed\x12\x1b\n" +
@@ -1967,24 +2482,46 @@ const file_configuration_proto_rawDesc = "" +
 	"serverName\x12\x1f\n" +
 	"\vmax_players\x18\x06 \x01(\rR\n" +
 	"maxPlayers\x12#\n" +
-	"\rview_distance\x18\a \x01(\x05R\fviewDistance\"&\n" +
+	"\rview_distance\x18\a \x01(\x05R\fviewDistance\x12D\n" +
+	"\n" +
+	"properties\x18\b \x03(\v2$.api.MinecraftConfig.PropertiesEntryR\n" +
+	"properties\x1a=\n" +
+	"\x0fPropertiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x82\x01\n" +
+	"\rConfiguration\x121\n" +
+	"\bfactorio\x18\x01 \x01(\v2\x13.api.FactorioConfigH\x00R\bfactorio\x124\n" +
+	"\tminecraft\x18\x02 \x01(\v2\x14.api.MinecraftConfigH\x00R\tminecraftB\b\n" +
+	"\x06config\"&\n" +
 	"\x14GetConfigByIdRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"R\n" +
-	"\x15GetConfigByIdResponse\x129\n" +
-	"\rconfiguration\x18\x01 \x01(\v2\x13.api.FactorioConfigR\rconfiguration\"\x1d\n" +
-	"\x1bGetAllConfigurationsRequest\"[\n" +
-	"\x1cGetAllConfigurationsResponse\x12;\n" +
-	"\x0econfigurations\x18\x01 \x03(\v2\x13.api.FactorioConfigR\x0econfigurations\"U\n" +
-	"\x18PostConfigurationRequest\x129\n" +
-	"\rconfiguration\x18\x01 \x01(\v2\x13.api.FactorioConfigR\rconfiguration\"\x1b\n" +
-	"\x19PostConfigurationResponse\"d\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Q\n" +
+	"\x15GetConfigByIdResponse\x128\n" +
+	"\rconfiguration\x18\x01 \x01(\v2\x12.api.ConfigurationR\rconfiguration\"\x90\x01\n" +
+	"\x1bGetAllConfigurationsRequest\x12*\n" +
+	"\aoptions\x18\x01 \x01(\v2\x10.api.ListOptionsR\aoptions\x12*\n" +
+	"\x04type\x18\x02 \x03(\x0e2\x16.api.ConfigurationTypeR\x04type\x12\x19\n" +
+	"\bagent_id\x18\x03 \x03(\tR\aagentId\"{\n" +
+	"\x1cGetAllConfigurationsResponse\x12:\n" +
+	"\x0econfigurations\x18\x01 \x03(\v2\x12.api.ConfigurationR\x0econfigurations\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"T\n" +
+	"\x18PostConfigurationRequest\x128\n" +
+	"\rconfiguration\x18\x01 \x01(\v2\x12.api.ConfigurationR\rconfiguration\"\x1b\n" +
+	"\x19PostConfigurationResponse\"c\n" +
 	"\x17PutConfigurationRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
-	"\rconfiguration\x18\x02 \x01(\v2\x13.api.FactorioConfigR\rconfiguration\"\x1a\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x128\n" +
+	"\rconfiguration\x18\x02 \x01(\v2\x12.api.ConfigurationR\rconfiguration\"\x1a\n" +
 	"\x18PutConfigurationResponse\",\n" +
 	"\x1aDeleteConfigurationRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"\x1d\n" +
-	"\x1bDeleteConfigurationResponse*z\n" +
+	"\x1bDeleteConfigurationResponse\"8\n" +
+	"\x1aWatchConfigurationsRequest\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\tR\brevision\"\xac\x01\n" +
+	"\x1bWatchConfigurationsResponse\x12'\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x13.api.WatchEventTypeR\x04type\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\tR\brevision\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x128\n" +
+	"\rconfiguration\x18\x04 \x01(\v2\x12.api.ConfigurationR\rconfiguration*z\n" +
 	"\x11ConfigurationType\x12\"\n" +
 	"\x1eCONFIGURATION_TYPE_UNSPECIFIED\x10\x00\x12\x1f\n" +
 	"\x1bCONFIGURATION_TYPE_FACTORIO\x10\x01\x12 \n" +
@@ -1994,13 +2531,14 @@ const file_configuration_proto_rawDesc = "" +
 	"\x1bMINECRAFT_GAMEMODE_SURVIVAL\x10\x01\x12\x1f\n" +
 	"\x1bMINECRAFT_GAMEMODE_CREATIVE\x10\x02\x12 \n" +
 	"\x1cMINECRAFT_GAMEMODE_ADVENTURE\x10\x03\x12 \n" +
-	"\x1cMINECRAFT_GAMEMODE_SPECTATOR\x10\x042\xff\x02\n" +
+	"\x1cMINECRAFT_GAMEMODE_SPECTATOR\x10\x042\xcd\x03\n" +
 	"\x14ConfigurationService\x12@\n" +
 	"\aGetById\x12\x19.api.GetConfigByIdRequest\x1a\x1a.api.GetConfigByIdResponse\x12M\n" +
 	"\x06GetAll\x12 .api.GetAllConfigurationsRequest\x1a!.api.GetAllConfigurationsResponse\x12E\n" +
 	"\x04Post\x12\x1d.api.PostConfigurationRequest\x1a\x1e.api.PostConfigurationResponse\x12B\n" +
 	"\x03Put\x12\x1c.api.PutConfigurationRequest\x1a\x1d.api.PutConfigurationResponse\x12K\n" +
-	"\x06Delete\x12\x1f.api.DeleteConfigurationRequest\x1a .api.DeleteConfigurationResponseB/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
+	"\x06Delete\x12\x1f.api.DeleteConfigurationRequest\x1a .api.DeleteConfigurationResponse\x12L\n" +
+	"\x05Watch\x12\x1f.api.WatchConfigurationsRequest\x1a .api.WatchConfigurationsResponse0\x01B/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
 
 var (
 	file_configuration_proto_rawDescOnce sync.Once
@@ -2015,70 +2553,93 @@ func file_configuration_proto_rawDescGZIP() []byte {
 }
 
 var file_configuration_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_configuration_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_configuration_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_configuration_proto_goTypes = []any{
 	(ConfigurationType)(0),               // 0: api.ConfigurationType
 	(MinecraftGamemode)(0),               // 1: api.MinecraftGamemode
 	(*BaseConfig)(nil),                   // 2: api.BaseConfig
-	(*FactorioServerSettings)(nil),       // 3: api.FactorioServerSettings
-	(*FactorioDifficultySettings)(nil),   // 4: api.FactorioDifficultySettings
-	(*FactorioPollutionSettings)(nil),    // 5: api.FactorioPollutionSettings
-	(*FactorioEnemyEvolution)(nil),       // 6: api.FactorioEnemyEvolution
-	(*FactorioEnemyExpansion)(nil),       // 7: api.FactorioEnemyExpansion
-	(*FactorioUnitGroup)(nil),            // 8: api.FactorioUnitGroup
-	(*FactorioMapSettings)(nil),          // 9: api.FactorioMapSettings
-	(*FactorioResourceSettings)(nil),     // 10: api.FactorioResourceSettings
-	(*FactorioCliffSettings)(nil),        // 11: api.FactorioCliffSettings
-	(*FactorioMapGenSettings)(nil),       // 12: api.FactorioMapGenSettings
-	(*FactorioConfig)(nil),               // 13: api.FactorioConfig
-	(*MinecraftConfig)(nil),              // 14: api.MinecraftConfig
-	(*GetConfigByIdRequest)(nil),         // 15: api.GetConfigByIdRequest
-	(*GetConfigByIdResponse)(nil),        // 16: api.GetConfigByIdResponse
-	(*GetAllConfigurationsRequest)(nil),  // 17: api.GetAllConfigurationsRequest
-	(*GetAllConfigurationsResponse)(nil), // 18: api.GetAllConfigurationsResponse
-	(*PostConfigurationRequest)(nil),     // 19: api.PostConfigurationRequest
-	(*PostConfigurationResponse)(nil),    // 20: api.PostConfigurationResponse
-	(*PutConfigurationRequest)(nil),      // 21: api.PutConfigurationRequest
-	(*PutConfigurationResponse)(nil),     // 22: api.PutConfigurationResponse
-	(*DeleteConfigurationRequest)(nil),   // 23: api.DeleteConfigurationRequest
-	(*DeleteConfigurationResponse)(nil),  // 24: api.DeleteConfigurationResponse
-	nil,                                  // 25: api.FactorioMapGenSettings.AutoplaceControlsEntry
+	(*Resources)(nil),                    // 3: api.Resources
+	(*Placement)(nil),                    // 4: api.Placement
+	(*FactorioServerSettings)(nil),       // 5: api.FactorioServerSettings
+	(*FactorioVisibility)(nil),           // 6: api.FactorioVisibility
+	(*FactorioDifficultySettings)(nil),   // 7: api.FactorioDifficultySettings
+	(*FactorioPollutionSettings)(nil),    // 8: api.FactorioPollutionSettings
+	(*FactorioEnemyEvolution)(nil),       // 9: api.FactorioEnemyEvolution
+	(*FactorioEnemyExpansion)(nil),       // 10: api.FactorioEnemyExpansion
+	(*FactorioUnitGroup)(nil),            // 11: api.FactorioUnitGroup
+	(*FactorioMapSettings)(nil),          // 12: api.FactorioMapSettings
+	(*FactorioResourceSettings)(nil),     // 13: api.FactorioResourceSettings
+	(*FactorioCliffSettings)(nil),        // 14: api.FactorioCliffSettings
+	(*FactorioMapGenSettings)(nil),       // 15: api.FactorioMapGenSettings
+	(*FactorioConfig)(nil),               // 16: api.FactorioConfig
+	(*MinecraftConfig)(nil),              // 17: api.MinecraftConfig
+	(*Configuration)(nil),                // 18: api.Configuration
+	(*GetConfigByIdRequest)(nil),         // 19: api.GetConfigByIdRequest
+	(*GetConfigByIdResponse)(nil),        // 20: api.GetConfigByIdResponse
+	(*GetAllConfigurationsRequest)(nil),  // 21: api.GetAllConfigurationsRequest
+	(*GetAllConfigurationsResponse)(nil), // 22: api.GetAllConfigurationsResponse
+	(*PostConfigurationRequest)(nil),     // 23: api.PostConfigurationRequest
+	(*PostConfigurationResponse)(nil),    // 24: api.PostConfigurationResponse
+	(*PutConfigurationRequest)(nil),      // 25: api.PutConfigurationRequest
+	(*PutConfigurationResponse)(nil),     // 26: api.PutConfigurationResponse
+	(*DeleteConfigurationRequest)(nil),   // 27: api.DeleteConfigurationRequest
+	(*DeleteConfigurationResponse)(nil),  // 28: api.DeleteConfigurationResponse
+	(*WatchConfigurationsRequest)(nil),   // 29: api.WatchConfigurationsRequest
+	(*WatchConfigurationsResponse)(nil),  // 30: api.WatchConfigurationsResponse
+	nil,                                  // 31: api.Placement.LabelsEntry
+	nil,                                  // 32: api.FactorioMapGenSettings.AutoplaceControlsEntry
+	nil,                                  // 33: api.MinecraftConfig.PropertiesEntry
+	(*ListOptions)(nil),                  // 34: api.ListOptions
+	(WatchEventType)(0),                  // 35: api.WatchEventType
 }
 var file_configuration_proto_depIdxs = []int32{
 	0,  // 0: api.BaseConfig.type:type_name -> api.ConfigurationType
-	4,  // 1: api.FactorioMapSettings.difficulty_settings:type_name -> api.FactorioDifficultySettings
-	5,  // 2: api.FactorioMapSettings.pollution:type_name -> api.FactorioPollutionSettings
-	6,  // 3: api.FactorioMapSettings.enemy_evolution:type_name -> api.FactorioEnemyEvolution
-	7,  // 4: api.FactorioMapSettings.enemy_expansion:type_name -> api.FactorioEnemyExpansion
-	8,  // 5: api.FactorioMapSettings.unit_group:type_name -> api.FactorioUnitGroup
-	25, // 6: api.FactorioMapGenSettings.autoplace_controls:type_name -> api.FactorioMapGenSettings.AutoplaceControlsEntry
-	11, // 7: api.FactorioMapGenSettings.cliff_settings:type_name -> api.FactorioCliffSettings
-	2,  // 8: api.FactorioConfig.base:type_name -> api.BaseConfig
-	3,  // 9: api.FactorioConfig.server:type_name -> api.FactorioServerSettings
-	9,  // 10: api.FactorioConfig.map:type_name -> api.FactorioMapSettings
-	12, // 11: api.FactorioConfig.map_gen:type_name -> api.FactorioMapGenSettings
-	2,  // 12: api.MinecraftConfig.base:type_name -> api.BaseConfig
-	1,  // 13: api.MinecraftConfig.gamemode:type_name -> api.MinecraftGamemode
-	13, // 14: api.GetConfigByIdResponse.configuration:type_name -> api.FactorioConfig
-	13, // 15: api.GetAllConfigurationsResponse.configurations:type_name -> api.FactorioConfig
-	13, // 16: api.PostConfigurationRequest.configuration:type_name -> api.FactorioConfig
-	13, // 17: api.PutConfigurationRequest.configuration:type_name -> api.FactorioConfig
-	10, // 18: api.FactorioMapGenSettings.AutoplaceControlsEntry.value:type_name -> api.FactorioResourceSettings
-	15, // 19: api.ConfigurationService.GetById:input_type -> api.GetConfigByIdRequest
-	17, // 20: api.ConfigurationService.GetAll:input_type -> api.GetAllConfigurationsRequest
-	19, // 21: api.ConfigurationService.Post:input_type -> api.PostConfigurationRequest
-	21, // 22: api.ConfigurationService.Put:input_type -> api.PutConfigurationRequest
-	23, // 23: api.ConfigurationService.Delete:input_type -> api.DeleteConfigurationRequest
-	16, // 24: api.ConfigurationService.GetById:output_type -> api.GetConfigByIdResponse
-	18, // 25: api.ConfigurationService.GetAll:output_type -> api.GetAllConfigurationsResponse
-	20, // 26: api.ConfigurationService.Post:output_type -> api.PostConfigurationResponse
-	22, // 27: api.ConfigurationService.Put:output_type -> api.PutConfigurationResponse
-	24, // 28: api.ConfigurationService.Delete:output_type -> api.DeleteConfigurationResponse
-	24, // [24:29] is the sub-list for method output_type
-	19, // [19:24] is the sub-list for method input_type
-	19, // [19:19] is the sub-list for extension type_name
-	19, // [19:19] is the sub-list for extension extendee
-	0,  // [0:19] is the sub-list for field type_name
+	4,  // 1: api.BaseConfig.placement:type_name -> api.Placement
+	3,  // 2: api.BaseConfig.resources:type_name -> api.Resources
+	31, // 3: api.Placement.labels:type_name -> api.Placement.LabelsEntry
+	6,  // 4: api.FactorioServerSettings.visibility:type_name -> api.FactorioVisibility
+	7,  // 5: api.FactorioMapSettings.difficulty_settings:type_name -> api.FactorioDifficultySettings
+	8,  // 6: api.FactorioMapSettings.pollution:type_name -> api.FactorioPollutionSettings
+	9,  // 7: api.FactorioMapSettings.enemy_evolution:type_name -> api.FactorioEnemyEvolution
+	10, // 8: api.FactorioMapSettings.enemy_expansion:type_name -> api.FactorioEnemyExpansion
+	11, // 9: api.FactorioMapSettings.unit_group:type_name -> api.FactorioUnitGroup
+	32, // 10: api.FactorioMapGenSettings.autoplace_controls:type_name -> api.FactorioMapGenSettings.AutoplaceControlsEntry
+	14, // 11: api.FactorioMapGenSettings.cliff_settings:type_name -> api.FactorioCliffSettings
+	2,  // 12: api.FactorioConfig.base:type_name -> api.BaseConfig
+	5,  // 13: api.FactorioConfig.server:type_name -> api.FactorioServerSettings
+	12, // 14: api.FactorioConfig.map:type_name -> api.FactorioMapSettings
+	15, // 15: api.FactorioConfig.map_gen:type_name -> api.FactorioMapGenSettings
+	2,  // 16: api.MinecraftConfig.base:type_name -> api.BaseConfig
+	1,  // 17: api.MinecraftConfig.gamemode:type_name -> api.MinecraftGamemode
+	33, // 18: api.MinecraftConfig.properties:type_name -> api.MinecraftConfig.PropertiesEntry
+	16, // 19: api.Configuration.factorio:type_name -> api.FactorioConfig
+	17, // 20: api.Configuration.minecraft:type_name -> api.MinecraftConfig
+	18, // 21: api.GetConfigByIdResponse.configuration:type_name -> api.Configuration
+	34, // 22: api.GetAllConfigurationsRequest.options:type_name -> api.ListOptions
+	0,  // 23: api.GetAllConfigurationsRequest.type:type_name -> api.ConfigurationType
+	18, // 24: api.GetAllConfigurationsResponse.configurations:type_name -> api.Configuration
+	18, // 25: api.PostConfigurationRequest.configuration:type_name -> api.Configuration
+	18, // 26: api.PutConfigurationRequest.configuration:type_name -> api.Configuration
+	35, // 27: api.WatchConfigurationsResponse.type:type_name -> api.WatchEventType
+	18, // 28: api.WatchConfigurationsResponse.configuration:type_name -> api.Configuration
+	13, // 29: api.FactorioMapGenSettings.AutoplaceControlsEntry.value:type_name -> api.FactorioResourceSettings
+	19, // 30: api.ConfigurationService.GetById:input_type -> api.GetConfigByIdRequest
+	21, // 31: api.ConfigurationService.GetAll:input_type -> api.GetAllConfigurationsRequest
+	23, // 32: api.ConfigurationService.Post:input_type -> api.PostConfigurationRequest
+	25, // 33: api.ConfigurationService.Put:input_type -> api.PutConfigurationRequest
+	27, // 34: api.ConfigurationService.Delete:input_type -> api.DeleteConfigurationRequest
+	29, // 35: api.ConfigurationService.Watch:input_type -> api.WatchConfigurationsRequest
+	20, // 36: api.ConfigurationService.GetById:output_type -> api.GetConfigByIdResponse
+	22, // 37: api.ConfigurationService.GetAll:output_type -> api.GetAllConfigurationsResponse
+	24, // 38: api.ConfigurationService.Post:output_type -> api.PostConfigurationResponse
+	26, // 39: api.ConfigurationService.Put:output_type -> api.PutConfigurationResponse
+	28, // 40: api.ConfigurationService.Delete:output_type -> api.DeleteConfigurationResponse
+	30, // 41: api.ConfigurationService.Watch:output_type -> api.WatchConfigurationsResponse
+	36, // [36:42] is the sub-list for method output_type
+	30, // [30:36] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_configuration_proto_init() }
@@ -2086,13 +2647,22 @@ func file_configuration_proto_init() {
 	if File_configuration_proto != nil {
 		return
 	}
+	file_watch_proto_init()
+	file_list_proto_init()
+	file_configuration_proto_msgTypes[6].OneofWrappers = []any{}
+	file_configuration_proto_msgTypes[7].OneofWrappers = []any{}
+	file_configuration_proto_msgTypes[8].OneofWrappers = []any{}
+	file_configuration_proto_msgTypes[16].OneofWrappers = []any{
+		(*Configuration_Factorio)(nil),
+		(*Configuration_Minecraft)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_configuration_proto_rawDesc), len(file_configuration_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   24,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
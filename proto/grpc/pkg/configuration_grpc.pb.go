@@ -24,6 +24,7 @@ const (
 	ConfigurationService_Post_FullMethodName    = "/api.ConfigurationService/Post"
 	ConfigurationService_Put_FullMethodName     = "/api.ConfigurationService/Put"
 	ConfigurationService_Delete_FullMethodName  = "/api.ConfigurationService/Delete"
+	ConfigurationService_Watch_FullMethodName   = "/api.ConfigurationService/Watch"
 )
 
 // ConfigurationServiceClient is the client API for ConfigurationService service.
@@ -35,6 +36,7 @@ type ConfigurationServiceClient interface {
 	Post(ctx context.Context, in *PostConfigurationRequest, opts ...grpc.CallOption) (*PostConfigurationResponse, error)
 	Put(ctx context.Context, in *PutConfigurationRequest, opts ...grpc.CallOption) (*PutConfigurationResponse, error)
 	Delete(ctx context.Context, in *DeleteConfigurationRequest, opts ...grpc.CallOption) (*DeleteConfigurationResponse, error)
+	Watch(ctx context.Context, in *WatchConfigurationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchConfigurationsResponse], error)
 }
 
 type configurationServiceClient struct {
@@ -95,6 +97,25 @@ func (c *configurationServiceClient) Delete(ctx context.Context, in *DeleteConfi
 	return out, nil
 }
 
+func (c *configurationServiceClient) Watch(ctx context.Context, in *WatchConfigurationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchConfigurationsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ConfigurationService_ServiceDesc.Streams[0], ConfigurationService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchConfigurationsRequest, WatchConfigurationsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ConfigurationService_WatchClient = grpc.ServerStreamingClient[WatchConfigurationsResponse]
+
 // ConfigurationServiceServer is the server API for ConfigurationService service.
 // All implementations must embed UnimplementedConfigurationServiceServer
 // for forward compatibility.
@@ -104,6 +125,7 @@ type ConfigurationServiceServer interface {
 	Post(context.Context, *PostConfigurationRequest) (*PostConfigurationResponse, error)
 	Put(context.Context, *PutConfigurationRequest) (*PutConfigurationResponse, error)
 	Delete(context.Context, *DeleteConfigurationRequest) (*DeleteConfigurationResponse, error)
+	Watch(*WatchConfigurationsRequest, grpc.ServerStreamingServer[WatchConfigurationsResponse]) error
 	mustEmbedUnimplementedConfigurationServiceServer()
 }
 
@@ -129,6 +151,9 @@ func (UnimplementedConfigurationServiceServer) Put(context.Context, *PutConfigur
 func (UnimplementedConfigurationServiceServer) Delete(context.Context, *DeleteConfigurationRequest) (*DeleteConfigurationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedConfigurationServiceServer) Watch(*WatchConfigurationsRequest, grpc.ServerStreamingServer[WatchConfigurationsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedConfigurationServiceServer) mustEmbedUnimplementedConfigurationServiceServer() {}
 func (UnimplementedConfigurationServiceServer) testEmbeddedByValue()                              {}
 
@@ -240,6 +265,17 @@ func _ConfigurationService_Delete_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ConfigurationService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchConfigurationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConfigurationServiceServer).Watch(m, &grpc.GenericServerStream[WatchConfigurationsRequest, WatchConfigurationsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ConfigurationService_WatchServer = grpc.ServerStreamingServer[WatchConfigurationsResponse]
+
 // ConfigurationService_ServiceDesc is the grpc.ServiceDesc for ConfigurationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -268,6 +304,12 @@ var ConfigurationService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ConfigurationService_Delete_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _ConfigurationService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "configuration.proto",
 }
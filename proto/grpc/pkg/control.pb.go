@@ -0,0 +1,876 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: control.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CommandType int32
+
+const (
+	CommandType_COMMAND_TYPE_UNSPECIFIED CommandType = 0
+	CommandType_COMMAND_TYPE_DEPLOY      CommandType = 1
+	CommandType_COMMAND_TYPE_START       CommandType = 2
+	CommandType_COMMAND_TYPE_STOP        CommandType = 3
+	CommandType_COMMAND_TYPE_FETCH_LOGS  CommandType = 4
+	CommandType_COMMAND_TYPE_CONSOLE     CommandType = 5
+	CommandType_COMMAND_TYPE_PROBE       CommandType = 6
+	// Данные - Resources, применяются к запущенному контейнеру
+	CommandType_COMMAND_TYPE_UPDATE_RESOURCES CommandType = 7
+)
+
+// Enum value maps for CommandType.
+var (
+	CommandType_name = map[int32]string{
+		0: "COMMAND_TYPE_UNSPECIFIED",
+		1: "COMMAND_TYPE_DEPLOY",
+		2: "COMMAND_TYPE_START",
+		3: "COMMAND_TYPE_STOP",
+		4: "COMMAND_TYPE_FETCH_LOGS",
+		5: "COMMAND_TYPE_CONSOLE",
+		6: "COMMAND_TYPE_PROBE",
+		7: "COMMAND_TYPE_UPDATE_RESOURCES",
+	}
+	CommandType_value = map[string]int32{
+		"COMMAND_TYPE_UNSPECIFIED":      0,
+		"COMMAND_TYPE_DEPLOY":           1,
+		"COMMAND_TYPE_START":            2,
+		"COMMAND_TYPE_STOP":             3,
+		"COMMAND_TYPE_FETCH_LOGS":       4,
+		"COMMAND_TYPE_CONSOLE":          5,
+		"COMMAND_TYPE_PROBE":            6,
+		"COMMAND_TYPE_UPDATE_RESOURCES": 7,
+	}
+)
+
+func (x CommandType) Enum() *CommandType {
+	p := new(CommandType)
+	*p = x
+	return p
+}
+
+func (x CommandType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CommandType) Descriptor() protoreflect.EnumDescriptor {
+	return file_control_proto_enumTypes[0].Descriptor()
+}
+
+func (CommandType) Type() protoreflect.EnumType {
+	return &file_control_proto_enumTypes[0]
+}
+
+func (x CommandType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CommandType.Descriptor instead.
+func (CommandType) EnumDescriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{0}
+}
+
+type Command struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Type            CommandType            `protobuf:"varint,1,opt,name=type,proto3,enum=api.CommandType" json:"type,omitempty"`
+	ConfigurationId string                 `protobuf:"bytes,2,opt,name=configuration_id,json=configurationId,proto3" json:"configuration_id,omitempty"`
+	Payload         []byte                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	Deadline        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Command) Reset() {
+	*x = Command{}
+	mi := &file_control_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Command) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Command) ProtoMessage() {}
+
+func (x *Command) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Command.ProtoReflect.Descriptor instead.
+func (*Command) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Command) GetType() CommandType {
+	if x != nil {
+		return x.Type
+	}
+	return CommandType_COMMAND_TYPE_UNSPECIFIED
+}
+
+func (x *Command) GetConfigurationId() string {
+	if x != nil {
+		return x.ConfigurationId
+	}
+	return ""
+}
+
+func (x *Command) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Command) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+// Данные команды COMMAND_TYPE_CONSOLE: команда для RCON игрового сервера на хосте агента
+type RconCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Port          uint32                 `protobuf:"varint,1,opt,name=port,proto3" json:"port,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Command       string                 `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RconCommand) Reset() {
+	*x = RconCommand{}
+	mi := &file_control_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RconCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RconCommand) ProtoMessage() {}
+
+func (x *RconCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RconCommand.ProtoReflect.Descriptor instead.
+func (*RconCommand) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RconCommand) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *RconCommand) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *RconCommand) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+type CommandAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandAck) Reset() {
+	*x = CommandAck{}
+	mi := &file_control_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandAck) ProtoMessage() {}
+
+func (x *CommandAck) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandAck.ProtoReflect.Descriptor instead.
+func (*CommandAck) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{2}
+}
+
+type CommandResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Output        []byte                 `protobuf:"bytes,2,opt,name=output,proto3" json:"output,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandResult) Reset() {
+	*x = CommandResult{}
+	mi := &file_control_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResult) ProtoMessage() {}
+
+func (x *CommandResult) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResult.ProtoReflect.Descriptor instead.
+func (*CommandResult) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CommandResult) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *CommandResult) GetOutput() []byte {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+func (x *CommandResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Отменяет выполняющийся запрос с тем же request id
+type CancelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	mi := &file_control_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{4}
+}
+
+type AgentHello struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentHello) Reset() {
+	*x = AgentHello{}
+	mi := &file_control_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentHello) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentHello) ProtoMessage() {}
+
+func (x *AgentHello) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentHello.ProtoReflect.Descriptor instead.
+func (*AgentHello) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AgentHello) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type ServerMessage struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RequestId string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Types that are valid to be assigned to Body:
+	//
+	//	*ServerMessage_Command
+	//	*ServerMessage_Logs
+	//	*ServerMessage_Cancel
+	Body          isServerMessage_Body `protobuf_oneof:"body"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerMessage) Reset() {
+	*x = ServerMessage{}
+	mi := &file_control_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerMessage) ProtoMessage() {}
+
+func (x *ServerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerMessage.ProtoReflect.Descriptor instead.
+func (*ServerMessage) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ServerMessage) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ServerMessage) GetBody() isServerMessage_Body {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetCommand() *Command {
+	if x != nil {
+		if x, ok := x.Body.(*ServerMessage_Command); ok {
+			return x.Command
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetLogs() *LogsRequest {
+	if x != nil {
+		if x, ok := x.Body.(*ServerMessage_Logs); ok {
+			return x.Logs
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetCancel() *CancelRequest {
+	if x != nil {
+		if x, ok := x.Body.(*ServerMessage_Cancel); ok {
+			return x.Cancel
+		}
+	}
+	return nil
+}
+
+type isServerMessage_Body interface {
+	isServerMessage_Body()
+}
+
+type ServerMessage_Command struct {
+	Command *Command `protobuf:"bytes,2,opt,name=command,proto3,oneof"`
+}
+
+type ServerMessage_Logs struct {
+	Logs *LogsRequest `protobuf:"bytes,3,opt,name=logs,proto3,oneof"`
+}
+
+type ServerMessage_Cancel struct {
+	Cancel *CancelRequest `protobuf:"bytes,4,opt,name=cancel,proto3,oneof"`
+}
+
+func (*ServerMessage_Command) isServerMessage_Body() {}
+
+func (*ServerMessage_Logs) isServerMessage_Body() {}
+
+func (*ServerMessage_Cancel) isServerMessage_Body() {}
+
+type AgentMessage struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RequestId string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Types that are valid to be assigned to Body:
+	//
+	//	*AgentMessage_Hello
+	//	*AgentMessage_Ack
+	//	*AgentMessage_Result
+	//	*AgentMessage_Logs
+	Body          isAgentMessage_Body `protobuf_oneof:"body"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentMessage) Reset() {
+	*x = AgentMessage{}
+	mi := &file_control_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentMessage) ProtoMessage() {}
+
+func (x *AgentMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentMessage.ProtoReflect.Descriptor instead.
+func (*AgentMessage) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AgentMessage) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AgentMessage) GetBody() isAgentMessage_Body {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *AgentMessage) GetHello() *AgentHello {
+	if x != nil {
+		if x, ok := x.Body.(*AgentMessage_Hello); ok {
+			return x.Hello
+		}
+	}
+	return nil
+}
+
+func (x *AgentMessage) GetAck() *CommandAck {
+	if x != nil {
+		if x, ok := x.Body.(*AgentMessage_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+func (x *AgentMessage) GetResult() *CommandResult {
+	if x != nil {
+		if x, ok := x.Body.(*AgentMessage_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *AgentMessage) GetLogs() *LogChunk {
+	if x != nil {
+		if x, ok := x.Body.(*AgentMessage_Logs); ok {
+			return x.Logs
+		}
+	}
+	return nil
+}
+
+type isAgentMessage_Body interface {
+	isAgentMessage_Body()
+}
+
+type AgentMessage_Hello struct {
+	Hello *AgentHello `protobuf:"bytes,2,opt,name=hello,proto3,oneof"`
+}
+
+type AgentMessage_Ack struct {
+	Ack *CommandAck `protobuf:"bytes,3,opt,name=ack,proto3,oneof"`
+}
+
+type AgentMessage_Result struct {
+	Result *CommandResult `protobuf:"bytes,4,opt,name=result,proto3,oneof"`
+}
+
+type AgentMessage_Logs struct {
+	Logs *LogChunk `protobuf:"bytes,5,opt,name=logs,proto3,oneof"`
+}
+
+func (*AgentMessage_Hello) isAgentMessage_Body() {}
+
+func (*AgentMessage_Ack) isAgentMessage_Body() {}
+
+func (*AgentMessage_Result) isAgentMessage_Body() {}
+
+func (*AgentMessage_Logs) isAgentMessage_Body() {}
+
+type ExecuteCommandRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AgentId        string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Command        *Command               `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	TimeoutSeconds uint32                 `protobuf:"varint,3,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExecuteCommandRequest) Reset() {
+	*x = ExecuteCommandRequest{}
+	mi := &file_control_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteCommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteCommandRequest) ProtoMessage() {}
+
+func (x *ExecuteCommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteCommandRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteCommandRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ExecuteCommandRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ExecuteCommandRequest) GetCommand() *Command {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *ExecuteCommandRequest) GetTimeoutSeconds() uint32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+type ExecuteCommandResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *CommandResult         `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteCommandResponse) Reset() {
+	*x = ExecuteCommandResponse{}
+	mi := &file_control_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteCommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteCommandResponse) ProtoMessage() {}
+
+func (x *ExecuteCommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteCommandResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteCommandResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExecuteCommandResponse) GetResult() *CommandResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+var File_control_proto protoreflect.FileDescriptor
+
+const file_control_proto_rawDesc = "" +
+	"\n" +
+	"\rcontrol.proto\x12\x03api\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\n" +
+	"logs.proto\x1a\x13configuration.proto\"\xac\x01\n" +
+	"\aCommand\x12$\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x10.api.CommandTypeR\x04type\x12)\n" +
+	"\x10configuration_id\x18\x02 \x01(\tR\x0fconfigurationId\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\x126\n" +
+	"\bdeadline\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\"W\n" +
+	"\vRconCommand\x12\x12\n" +
+	"\x04port\x18\x01 \x01(\rR\x04port\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\"\f\n" +
+	"\n" +
+	"CommandAck\"M\n" +
+	"\rCommandResult\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x16\n" +
+	"\x06output\x18\x02 \x01(\fR\x06output\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x0f\n" +
+	"\rCancelRequest\"'\n" +
+	"\n" +
+	"AgentHello\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"\xb6\x01\n" +
+	"\rServerMessage\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12(\n" +
+	"\acommand\x18\x02 \x01(\v2\f.api.CommandH\x00R\acommand\x12&\n" +
+	"\x04logs\x18\x03 \x01(\v2\x10.api.LogsRequestH\x00R\x04logs\x12,\n" +
+	"\x06cancel\x18\x04 \x01(\v2\x12.api.CancelRequestH\x00R\x06cancelB\x06\n" +
+	"\x04body\"\xd6\x01\n" +
+	"\fAgentMessage\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12'\n" +
+	"\x05hello\x18\x02 \x01(\v2\x0f.api.AgentHelloH\x00R\x05hello\x12#\n" +
+	"\x03ack\x18\x03 \x01(\v2\x0f.api.CommandAckH\x00R\x03ack\x12,\n" +
+	"\x06result\x18\x04 \x01(\v2\x12.api.CommandResultH\x00R\x06result\x12#\n" +
+	"\x04logs\x18\x05 \x01(\v2\r.api.LogChunkH\x00R\x04logsB\x06\n" +
+	"\x04body\"\x83\x01\n" +
+	"\x15ExecuteCommandRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12&\n" +
+	"\acommand\x18\x02 \x01(\v2\f.api.CommandR\acommand\x12'\n" +
+	"\x0ftimeout_seconds\x18\x03 \x01(\rR\x0etimeoutSeconds\"D\n" +
+	"\x16ExecuteCommandResponse\x12*\n" +
+	"\x06result\x18\x01 \x01(\v2\x12.api.CommandResultR\x06result*\xe5\x01\n" +
+	"\vCommandType\x12\x1c\n" +
+	"\x18COMMAND_TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13COMMAND_TYPE_DEPLOY\x10\x01\x12\x16\n" +
+	"\x12COMMAND_TYPE_START\x10\x02\x12\x15\n" +
+	"\x11COMMAND_TYPE_STOP\x10\x03\x12\x1b\n" +
+	"\x17COMMAND_TYPE_FETCH_LOGS\x10\x04\x12\x18\n" +
+	"\x14COMMAND_TYPE_CONSOLE\x10\x05\x12\x16\n" +
+	"\x12COMMAND_TYPE_PROBE\x10\x06\x12!\n" +
+	"\x1dCOMMAND_TYPE_UPDATE_RESOURCES\x10\a2\x88\x01\n" +
+	"\fAgentControl\x124\n" +
+	"\aConnect\x12\x11.api.AgentMessage\x1a\x12.api.ServerMessage(\x010\x01\x12B\n" +
+	"\aExecute\x12\x1a.api.ExecuteCommandRequest\x1a\x1b.api.ExecuteCommandResponseB/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
+
+var (
+	file_control_proto_rawDescOnce sync.Once
+	file_control_proto_rawDescData []byte
+)
+
+func file_control_proto_rawDescGZIP() []byte {
+	file_control_proto_rawDescOnce.Do(func() {
+		file_control_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_control_proto_rawDesc), len(file_control_proto_rawDesc)))
+	})
+	return file_control_proto_rawDescData
+}
+
+var file_control_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_control_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_control_proto_goTypes = []any{
+	(CommandType)(0),               // 0: api.CommandType
+	(*Command)(nil),                // 1: api.Command
+	(*RconCommand)(nil),            // 2: api.RconCommand
+	(*CommandAck)(nil),             // 3: api.CommandAck
+	(*CommandResult)(nil),          // 4: api.CommandResult
+	(*CancelRequest)(nil),          // 5: api.CancelRequest
+	(*AgentHello)(nil),             // 6: api.AgentHello
+	(*ServerMessage)(nil),          // 7: api.ServerMessage
+	(*AgentMessage)(nil),           // 8: api.AgentMessage
+	(*ExecuteCommandRequest)(nil),  // 9: api.ExecuteCommandRequest
+	(*ExecuteCommandResponse)(nil), // 10: api.ExecuteCommandResponse
+	(*timestamppb.Timestamp)(nil),  // 11: google.protobuf.Timestamp
+	(*LogsRequest)(nil),            // 12: api.LogsRequest
+	(*LogChunk)(nil),               // 13: api.LogChunk
+}
+var file_control_proto_depIdxs = []int32{
+	0,  // 0: api.Command.type:type_name -> api.CommandType
+	11, // 1: api.Command.deadline:type_name -> google.protobuf.Timestamp
+	1,  // 2: api.ServerMessage.command:type_name -> api.Command
+	12, // 3: api.ServerMessage.logs:type_name -> api.LogsRequest
+	5,  // 4: api.ServerMessage.cancel:type_name -> api.CancelRequest
+	6,  // 5: api.AgentMessage.hello:type_name -> api.AgentHello
+	3,  // 6: api.AgentMessage.ack:type_name -> api.CommandAck
+	4,  // 7: api.AgentMessage.result:type_name -> api.CommandResult
+	13, // 8: api.AgentMessage.logs:type_name -> api.LogChunk
+	1,  // 9: api.ExecuteCommandRequest.command:type_name -> api.Command
+	4,  // 10: api.ExecuteCommandResponse.result:type_name -> api.CommandResult
+	8,  // 11: api.AgentControl.Connect:input_type -> api.AgentMessage
+	9,  // 12: api.AgentControl.Execute:input_type -> api.ExecuteCommandRequest
+	7,  // 13: api.AgentControl.Connect:output_type -> api.ServerMessage
+	10, // 14: api.AgentControl.Execute:output_type -> api.ExecuteCommandResponse
+	13, // [13:15] is the sub-list for method output_type
+	11, // [11:13] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_control_proto_init() }
+func file_control_proto_init() {
+	if File_control_proto != nil {
+		return
+	}
+	file_logs_proto_init()
+	file_configuration_proto_init()
+	file_control_proto_msgTypes[6].OneofWrappers = []any{
+		(*ServerMessage_Command)(nil),
+		(*ServerMessage_Logs)(nil),
+		(*ServerMessage_Cancel)(nil),
+	}
+	file_control_proto_msgTypes[7].OneofWrappers = []any{
+		(*AgentMessage_Hello)(nil),
+		(*AgentMessage_Ack)(nil),
+		(*AgentMessage_Result)(nil),
+		(*AgentMessage_Logs)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_control_proto_rawDesc), len(file_control_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_control_proto_goTypes,
+		DependencyIndexes: file_control_proto_depIdxs,
+		EnumInfos:         file_control_proto_enumTypes,
+		MessageInfos:      file_control_proto_msgTypes,
+	}.Build()
+	File_control_proto = out.File
+	file_control_proto_goTypes = nil
+	file_control_proto_depIdxs = nil
+}
@@ -0,0 +1,154 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.31.0
+// source: control.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AgentControl_Connect_FullMethodName = "/api.AgentControl/Connect"
+	AgentControl_Execute_FullMethodName = "/api.AgentControl/Execute"
+)
+
+// AgentControlClient is the client API for AgentControl service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AgentControlClient interface {
+	Connect(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AgentMessage, ServerMessage], error)
+	Execute(ctx context.Context, in *ExecuteCommandRequest, opts ...grpc.CallOption) (*ExecuteCommandResponse, error)
+}
+
+type agentControlClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAgentControlClient(cc grpc.ClientConnInterface) AgentControlClient {
+	return &agentControlClient{cc}
+}
+
+func (c *agentControlClient) Connect(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AgentMessage, ServerMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AgentControl_ServiceDesc.Streams[0], AgentControl_Connect_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AgentMessage, ServerMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentControl_ConnectClient = grpc.BidiStreamingClient[AgentMessage, ServerMessage]
+
+func (c *agentControlClient) Execute(ctx context.Context, in *ExecuteCommandRequest, opts ...grpc.CallOption) (*ExecuteCommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteCommandResponse)
+	err := c.cc.Invoke(ctx, AgentControl_Execute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AgentControlServer is the server API for AgentControl service.
+// All implementations must embed UnimplementedAgentControlServer
+// for forward compatibility.
+type AgentControlServer interface {
+	Connect(grpc.BidiStreamingServer[AgentMessage, ServerMessage]) error
+	Execute(context.Context, *ExecuteCommandRequest) (*ExecuteCommandResponse, error)
+	mustEmbedUnimplementedAgentControlServer()
+}
+
+// UnimplementedAgentControlServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAgentControlServer struct{}
+
+func (UnimplementedAgentControlServer) Connect(grpc.BidiStreamingServer[AgentMessage, ServerMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+func (UnimplementedAgentControlServer) Execute(context.Context, *ExecuteCommandRequest) (*ExecuteCommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedAgentControlServer) mustEmbedUnimplementedAgentControlServer() {}
+func (UnimplementedAgentControlServer) testEmbeddedByValue()                      {}
+
+// UnsafeAgentControlServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AgentControlServer will
+// result in compilation errors.
+type UnsafeAgentControlServer interface {
+	mustEmbedUnimplementedAgentControlServer()
+}
+
+func RegisterAgentControlServer(s grpc.ServiceRegistrar, srv AgentControlServer) {
+	// If the following call pancis, it indicates UnimplementedAgentControlServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AgentControl_ServiceDesc, srv)
+}
+
+func _AgentControl_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentControlServer).Connect(&grpc.GenericServerStream[AgentMessage, ServerMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentControl_ConnectServer = grpc.BidiStreamingServer[AgentMessage, ServerMessage]
+
+func _AgentControl_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentControlServer).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentControl_Execute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentControlServer).Execute(ctx, req.(*ExecuteCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AgentControl_ServiceDesc is the grpc.ServiceDesc for AgentControl service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AgentControl_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.AgentControl",
+	HandlerType: (*AgentControlServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Execute",
+			Handler:    _AgentControl_Execute_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _AgentControl_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "control.proto",
+}
@@ -0,0 +1,598 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: health.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type HealthStatus int32
+
+const (
+	HealthStatus_HEALTH_STATUS_UNKNOWN   HealthStatus = 0
+	HealthStatus_HEALTH_STATUS_HEALTHY   HealthStatus = 1
+	HealthStatus_HEALTH_STATUS_UNHEALTHY HealthStatus = 2
+)
+
+// Enum value maps for HealthStatus.
+var (
+	HealthStatus_name = map[int32]string{
+		0: "HEALTH_STATUS_UNKNOWN",
+		1: "HEALTH_STATUS_HEALTHY",
+		2: "HEALTH_STATUS_UNHEALTHY",
+	}
+	HealthStatus_value = map[string]int32{
+		"HEALTH_STATUS_UNKNOWN":   0,
+		"HEALTH_STATUS_HEALTHY":   1,
+		"HEALTH_STATUS_UNHEALTHY": 2,
+	}
+)
+
+func (x HealthStatus) Enum() *HealthStatus {
+	p := new(HealthStatus)
+	*p = x
+	return p
+}
+
+func (x HealthStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HealthStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_health_proto_enumTypes[0].Descriptor()
+}
+
+func (HealthStatus) Type() protoreflect.EnumType {
+	return &file_health_proto_enumTypes[0]
+}
+
+func (x HealthStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HealthStatus.Descriptor instead.
+func (HealthStatus) EnumDescriptor() ([]byte, []int) {
+	return file_health_proto_rawDescGZIP(), []int{0}
+}
+
+// Данные команды COMMAND_TYPE_PROBE: как проверить игровой сервер с хоста агента
+type ProbeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Game          string                 `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	Port          uint32                 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	RconPort      uint32                 `protobuf:"varint,3,opt,name=rcon_port,json=rconPort,proto3" json:"rcon_port,omitempty"`
+	RconPassword  string                 `protobuf:"bytes,4,opt,name=rcon_password,json=rconPassword,proto3" json:"rcon_password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProbeRequest) Reset() {
+	*x = ProbeRequest{}
+	mi := &file_health_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeRequest) ProtoMessage() {}
+
+func (x *ProbeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_health_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeRequest.ProtoReflect.Descriptor instead.
+func (*ProbeRequest) Descriptor() ([]byte, []int) {
+	return file_health_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProbeRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+func (x *ProbeRequest) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *ProbeRequest) GetRconPort() uint32 {
+	if x != nil {
+		return x.RconPort
+	}
+	return 0
+}
+
+func (x *ProbeRequest) GetRconPassword() string {
+	if x != nil {
+		return x.RconPassword
+	}
+	return ""
+}
+
+// Вывод команды COMMAND_TYPE_PROBE
+type ProbeResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LatencyMs     uint32                 `protobuf:"varint,1,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Motd          string                 `protobuf:"bytes,3,opt,name=motd,proto3" json:"motd,omitempty"`
+	PlayersOnline uint32                 `protobuf:"varint,4,opt,name=players_online,json=playersOnline,proto3" json:"players_online,omitempty"`
+	PlayersMax    uint32                 `protobuf:"varint,5,opt,name=players_max,json=playersMax,proto3" json:"players_max,omitempty"`
+	Players       []string               `protobuf:"bytes,6,rep,name=players,proto3" json:"players,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProbeResult) Reset() {
+	*x = ProbeResult{}
+	mi := &file_health_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeResult) ProtoMessage() {}
+
+func (x *ProbeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_health_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeResult.ProtoReflect.Descriptor instead.
+func (*ProbeResult) Descriptor() ([]byte, []int) {
+	return file_health_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProbeResult) GetLatencyMs() uint32 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *ProbeResult) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ProbeResult) GetMotd() string {
+	if x != nil {
+		return x.Motd
+	}
+	return ""
+}
+
+func (x *ProbeResult) GetPlayersOnline() uint32 {
+	if x != nil {
+		return x.PlayersOnline
+	}
+	return 0
+}
+
+func (x *ProbeResult) GetPlayersMax() uint32 {
+	if x != nil {
+		return x.PlayersMax
+	}
+	return 0
+}
+
+func (x *ProbeResult) GetPlayers() []string {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+type ServerHealth struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ConfigurationId string                 `protobuf:"bytes,1,opt,name=configuration_id,json=configurationId,proto3" json:"configuration_id,omitempty"`
+	Status          HealthStatus           `protobuf:"varint,2,opt,name=status,proto3,enum=api.HealthStatus" json:"status,omitempty"`
+	CheckedAt       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	ChangedAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	Failures        uint32                 `protobuf:"varint,5,opt,name=failures,proto3" json:"failures,omitempty"`
+	Error           string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Result          *ProbeResult           `protobuf:"bytes,7,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ServerHealth) Reset() {
+	*x = ServerHealth{}
+	mi := &file_health_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerHealth) ProtoMessage() {}
+
+func (x *ServerHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_health_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerHealth.ProtoReflect.Descriptor instead.
+func (*ServerHealth) Descriptor() ([]byte, []int) {
+	return file_health_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ServerHealth) GetConfigurationId() string {
+	if x != nil {
+		return x.ConfigurationId
+	}
+	return ""
+}
+
+func (x *ServerHealth) GetStatus() HealthStatus {
+	if x != nil {
+		return x.Status
+	}
+	return HealthStatus_HEALTH_STATUS_UNKNOWN
+}
+
+func (x *ServerHealth) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+func (x *ServerHealth) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+func (x *ServerHealth) GetFailures() uint32 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *ServerHealth) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ServerHealth) GetResult() *ProbeResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type GetServerHealthRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ConfigurationId string                 `protobuf:"bytes,1,opt,name=configuration_id,json=configurationId,proto3" json:"configuration_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetServerHealthRequest) Reset() {
+	*x = GetServerHealthRequest{}
+	mi := &file_health_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServerHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServerHealthRequest) ProtoMessage() {}
+
+func (x *GetServerHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_health_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServerHealthRequest.ProtoReflect.Descriptor instead.
+func (*GetServerHealthRequest) Descriptor() ([]byte, []int) {
+	return file_health_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetServerHealthRequest) GetConfigurationId() string {
+	if x != nil {
+		return x.ConfigurationId
+	}
+	return ""
+}
+
+type GetServerHealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Health        *ServerHealth          `protobuf:"bytes,1,opt,name=health,proto3" json:"health,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServerHealthResponse) Reset() {
+	*x = GetServerHealthResponse{}
+	mi := &file_health_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServerHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServerHealthResponse) ProtoMessage() {}
+
+func (x *GetServerHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_health_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServerHealthResponse.ProtoReflect.Descriptor instead.
+func (*GetServerHealthResponse) Descriptor() ([]byte, []int) {
+	return file_health_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetServerHealthResponse) GetHealth() *ServerHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+type GetAllServerHealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllServerHealthRequest) Reset() {
+	*x = GetAllServerHealthRequest{}
+	mi := &file_health_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllServerHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllServerHealthRequest) ProtoMessage() {}
+
+func (x *GetAllServerHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_health_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllServerHealthRequest.ProtoReflect.Descriptor instead.
+func (*GetAllServerHealthRequest) Descriptor() ([]byte, []int) {
+	return file_health_proto_rawDescGZIP(), []int{5}
+}
+
+type GetAllServerHealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Health        []*ServerHealth        `protobuf:"bytes,1,rep,name=health,proto3" json:"health,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllServerHealthResponse) Reset() {
+	*x = GetAllServerHealthResponse{}
+	mi := &file_health_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllServerHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllServerHealthResponse) ProtoMessage() {}
+
+func (x *GetAllServerHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_health_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllServerHealthResponse.ProtoReflect.Descriptor instead.
+func (*GetAllServerHealthResponse) Descriptor() ([]byte, []int) {
+	return file_health_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetAllServerHealthResponse) GetHealth() []*ServerHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+var File_health_proto protoreflect.FileDescriptor
+
+const file_health_proto_rawDesc = "" +
+	"\n" +
+	"\fhealth.proto\x12\x03api\x1a\x1fgoogle/protobuf/timestamp.proto\"x\n" +
+	"\fProbeRequest\x12\x12\n" +
+	"\x04game\x18\x01 \x01(\tR\x04game\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\rR\x04port\x12\x1b\n" +
+	"\trcon_port\x18\x03 \x01(\rR\brconPort\x12#\n" +
+	"\rrcon_password\x18\x04 \x01(\tR\frconPassword\"\xbc\x01\n" +
+	"\vProbeResult\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x01 \x01(\rR\tlatencyMs\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x12\n" +
+	"\x04motd\x18\x03 \x01(\tR\x04motd\x12%\n" +
+	"\x0eplayers_online\x18\x04 \x01(\rR\rplayersOnline\x12\x1f\n" +
+	"\vplayers_max\x18\x05 \x01(\rR\n" +
+	"playersMax\x12\x18\n" +
+	"\aplayers\x18\x06 \x03(\tR\aplayers\"\xb6\x02\n" +
+	"\fServerHealth\x12)\n" +
+	"\x10configuration_id\x18\x01 \x01(\tR\x0fconfigurationId\x12)\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x11.api.HealthStatusR\x06status\x129\n" +
+	"\n" +
+	"checked_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\x129\n" +
+	"\n" +
+	"changed_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\x12\x1a\n" +
+	"\bfailures\x18\x05 \x01(\rR\bfailures\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12(\n" +
+	"\x06result\x18\a \x01(\v2\x10.api.ProbeResultR\x06result\"C\n" +
+	"\x16GetServerHealthRequest\x12)\n" +
+	"\x10configuration_id\x18\x01 \x01(\tR\x0fconfigurationId\"D\n" +
+	"\x17GetServerHealthResponse\x12)\n" +
+	"\x06health\x18\x01 \x01(\v2\x11.api.ServerHealthR\x06health\"\x1b\n" +
+	"\x19GetAllServerHealthRequest\"G\n" +
+	"\x1aGetAllServerHealthResponse\x12)\n" +
+	"\x06health\x18\x01 \x03(\v2\x11.api.ServerHealthR\x06health*a\n" +
+	"\fHealthStatus\x12\x19\n" +
+	"\x15HEALTH_STATUS_UNKNOWN\x10\x00\x12\x19\n" +
+	"\x15HEALTH_STATUS_HEALTHY\x10\x01\x12\x1b\n" +
+	"\x17HEALTH_STATUS_UNHEALTHY\x10\x022\xba\x01\n" +
+	"\x13ServerHealthService\x12L\n" +
+	"\x0fGetServerHealth\x12\x1b.api.GetServerHealthRequest\x1a\x1c.api.GetServerHealthResponse\x12U\n" +
+	"\x12GetAllServerHealth\x12\x1e.api.GetAllServerHealthRequest\x1a\x1f.api.GetAllServerHealthResponseB/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
+
+var (
+	file_health_proto_rawDescOnce sync.Once
+	file_health_proto_rawDescData []byte
+)
+
+func file_health_proto_rawDescGZIP() []byte {
+	file_health_proto_rawDescOnce.Do(func() {
+		file_health_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_health_proto_rawDesc), len(file_health_proto_rawDesc)))
+	})
+	return file_health_proto_rawDescData
+}
+
+var file_health_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_health_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_health_proto_goTypes = []any{
+	(HealthStatus)(0),                  // 0: api.HealthStatus
+	(*ProbeRequest)(nil),               // 1: api.ProbeRequest
+	(*ProbeResult)(nil),                // 2: api.ProbeResult
+	(*ServerHealth)(nil),               // 3: api.ServerHealth
+	(*GetServerHealthRequest)(nil),     // 4: api.GetServerHealthRequest
+	(*GetServerHealthResponse)(nil),    // 5: api.GetServerHealthResponse
+	(*GetAllServerHealthRequest)(nil),  // 6: api.GetAllServerHealthRequest
+	(*GetAllServerHealthResponse)(nil), // 7: api.GetAllServerHealthResponse
+	(*timestamppb.Timestamp)(nil),      // 8: google.protobuf.Timestamp
+}
+var file_health_proto_depIdxs = []int32{
+	0, // 0: api.ServerHealth.status:type_name -> api.HealthStatus
+	8, // 1: api.ServerHealth.checked_at:type_name -> google.protobuf.Timestamp
+	8, // 2: api.ServerHealth.changed_at:type_name -> google.protobuf.Timestamp
+	2, // 3: api.ServerHealth.result:type_name -> api.ProbeResult
+	3, // 4: api.GetServerHealthResponse.health:type_name -> api.ServerHealth
+	3, // 5: api.GetAllServerHealthResponse.health:type_name -> api.ServerHealth
+	4, // 6: api.ServerHealthService.GetServerHealth:input_type -> api.GetServerHealthRequest
+	6, // 7: api.ServerHealthService.GetAllServerHealth:input_type -> api.GetAllServerHealthRequest
+	5, // 8: api.ServerHealthService.GetServerHealth:output_type -> api.GetServerHealthResponse
+	7, // 9: api.ServerHealthService.GetAllServerHealth:output_type -> api.GetAllServerHealthResponse
+	8, // [8:10] is the sub-list for method output_type
+	6, // [6:8] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_health_proto_init() }
+func file_health_proto_init() {
+	if File_health_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_health_proto_rawDesc), len(file_health_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_health_proto_goTypes,
+		DependencyIndexes: file_health_proto_depIdxs,
+		EnumInfos:         file_health_proto_enumTypes,
+		MessageInfos:      file_health_proto_msgTypes,
+	}.Build()
+	File_health_proto = out.File
+	file_health_proto_goTypes = nil
+	file_health_proto_depIdxs = nil
+}
@@ -0,0 +1,159 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.31.0
+// source: health.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ServerHealthService_GetServerHealth_FullMethodName    = "/api.ServerHealthService/GetServerHealth"
+	ServerHealthService_GetAllServerHealth_FullMethodName = "/api.ServerHealthService/GetAllServerHealth"
+)
+
+// ServerHealthServiceClient is the client API for ServerHealthService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ServerHealthServiceClient interface {
+	GetServerHealth(ctx context.Context, in *GetServerHealthRequest, opts ...grpc.CallOption) (*GetServerHealthResponse, error)
+	GetAllServerHealth(ctx context.Context, in *GetAllServerHealthRequest, opts ...grpc.CallOption) (*GetAllServerHealthResponse, error)
+}
+
+type serverHealthServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewServerHealthServiceClient(cc grpc.ClientConnInterface) ServerHealthServiceClient {
+	return &serverHealthServiceClient{cc}
+}
+
+func (c *serverHealthServiceClient) GetServerHealth(ctx context.Context, in *GetServerHealthRequest, opts ...grpc.CallOption) (*GetServerHealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetServerHealthResponse)
+	err := c.cc.Invoke(ctx, ServerHealthService_GetServerHealth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverHealthServiceClient) GetAllServerHealth(ctx context.Context, in *GetAllServerHealthRequest, opts ...grpc.CallOption) (*GetAllServerHealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAllServerHealthResponse)
+	err := c.cc.Invoke(ctx, ServerHealthService_GetAllServerHealth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ServerHealthServiceServer is the server API for ServerHealthService service.
+// All implementations must embed UnimplementedServerHealthServiceServer
+// for forward compatibility.
+type ServerHealthServiceServer interface {
+	GetServerHealth(context.Context, *GetServerHealthRequest) (*GetServerHealthResponse, error)
+	GetAllServerHealth(context.Context, *GetAllServerHealthRequest) (*GetAllServerHealthResponse, error)
+	mustEmbedUnimplementedServerHealthServiceServer()
+}
+
+// UnimplementedServerHealthServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedServerHealthServiceServer struct{}
+
+func (UnimplementedServerHealthServiceServer) GetServerHealth(context.Context, *GetServerHealthRequest) (*GetServerHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServerHealth not implemented")
+}
+func (UnimplementedServerHealthServiceServer) GetAllServerHealth(context.Context, *GetAllServerHealthRequest) (*GetAllServerHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllServerHealth not implemented")
+}
+func (UnimplementedServerHealthServiceServer) mustEmbedUnimplementedServerHealthServiceServer() {}
+func (UnimplementedServerHealthServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeServerHealthServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ServerHealthServiceServer will
+// result in compilation errors.
+type UnsafeServerHealthServiceServer interface {
+	mustEmbedUnimplementedServerHealthServiceServer()
+}
+
+func RegisterServerHealthServiceServer(s grpc.ServiceRegistrar, srv ServerHealthServiceServer) {
+	// If the following call pancis, it indicates UnimplementedServerHealthServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ServerHealthService_ServiceDesc, srv)
+}
+
+func _ServerHealthService_GetServerHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServerHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerHealthServiceServer).GetServerHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerHealthService_GetServerHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerHealthServiceServer).GetServerHealth(ctx, req.(*GetServerHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerHealthService_GetAllServerHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllServerHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerHealthServiceServer).GetAllServerHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerHealthService_GetAllServerHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerHealthServiceServer).GetAllServerHealth(ctx, req.(*GetAllServerHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ServerHealthService_ServiceDesc is the grpc.ServiceDesc for ServerHealthService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ServerHealthService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.ServerHealthService",
+	HandlerType: (*ServerHealthServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetServerHealth",
+			Handler:    _ServerHealthService_GetServerHealth_Handler,
+		},
+		{
+			MethodName: "GetAllServerHealth",
+			Handler:    _ServerHealthService_GetAllServerHealth_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "health.proto",
+}
@@ -9,6 +9,7 @@ package api
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -26,6 +27,13 @@ type HostMetrics struct {
 	CpuUsage      float32                `protobuf:"fixed32,1,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
 	RamAvailable  uint64                 `protobuf:"varint,2,opt,name=ram_available,json=ramAvailable,proto3" json:"ram_available,omitempty"`
 	RamTotal      uint64                 `protobuf:"varint,3,opt,name=ram_total,json=ramTotal,proto3" json:"ram_total,omitempty"`
+	LoadAvg_1     float32                `protobuf:"fixed32,4,opt,name=load_avg_1,json=loadAvg1,proto3" json:"load_avg_1,omitempty"`
+	LoadAvg_5     float32                `protobuf:"fixed32,5,opt,name=load_avg_5,json=loadAvg5,proto3" json:"load_avg_5,omitempty"`
+	LoadAvg_15    float32                `protobuf:"fixed32,6,opt,name=load_avg_15,json=loadAvg15,proto3" json:"load_avg_15,omitempty"`
+	DiskTotal     uint64                 `protobuf:"varint,7,opt,name=disk_total,json=diskTotal,proto3" json:"disk_total,omitempty"`
+	DiskAvailable uint64                 `protobuf:"varint,8,opt,name=disk_available,json=diskAvailable,proto3" json:"disk_available,omitempty"`
+	NetRxBytes    uint64                 `protobuf:"varint,9,opt,name=net_rx_bytes,json=netRxBytes,proto3" json:"net_rx_bytes,omitempty"`
+	NetTxBytes    uint64                 `protobuf:"varint,10,opt,name=net_tx_bytes,json=netTxBytes,proto3" json:"net_tx_bytes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -81,15 +89,211 @@ func (x *HostMetrics) GetRamTotal() uint64 {
 	return 0
 }
 
+func (x *HostMetrics) GetLoadAvg_1() float32 {
+	if x != nil {
+		return x.LoadAvg_1
+	}
+	return 0
+}
+
+func (x *HostMetrics) GetLoadAvg_5() float32 {
+	if x != nil {
+		return x.LoadAvg_5
+	}
+	return 0
+}
+
+func (x *HostMetrics) GetLoadAvg_15() float32 {
+	if x != nil {
+		return x.LoadAvg_15
+	}
+	return 0
+}
+
+func (x *HostMetrics) GetDiskTotal() uint64 {
+	if x != nil {
+		return x.DiskTotal
+	}
+	return 0
+}
+
+func (x *HostMetrics) GetDiskAvailable() uint64 {
+	if x != nil {
+		return x.DiskAvailable
+	}
+	return 0
+}
+
+func (x *HostMetrics) GetNetRxBytes() uint64 {
+	if x != nil {
+		return x.NetRxBytes
+	}
+	return 0
+}
+
+func (x *HostMetrics) GetNetTxBytes() uint64 {
+	if x != nil {
+		return x.NetTxBytes
+	}
+	return 0
+}
+
+// Потребление ресурсов игровым сервером по данным среды запуска
+type ServerUsage struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ConfigurationId string                 `protobuf:"bytes,1,opt,name=configuration_id,json=configurationId,proto3" json:"configuration_id,omitempty"`
+	Running         bool                   `protobuf:"varint,2,opt,name=running,proto3" json:"running,omitempty"`
+	CpuUsage        float32                `protobuf:"fixed32,3,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
+	MemoryUsage     uint64                 `protobuf:"varint,4,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"`
+	MemoryLimit     uint64                 `protobuf:"varint,5,opt,name=memory_limit,json=memoryLimit,proto3" json:"memory_limit,omitempty"`
+	DiskUsage       uint64                 `protobuf:"varint,6,opt,name=disk_usage,json=diskUsage,proto3" json:"disk_usage,omitempty"`
+	NetRxBytes      uint64                 `protobuf:"varint,7,opt,name=net_rx_bytes,json=netRxBytes,proto3" json:"net_rx_bytes,omitempty"`
+	NetTxBytes      uint64                 `protobuf:"varint,8,opt,name=net_tx_bytes,json=netTxBytes,proto3" json:"net_tx_bytes,omitempty"`
+	// Когда контейнер последний раз был убит OOM killer
+	OomKilledAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=oom_killed_at,json=oomKilledAt,proto3" json:"oom_killed_at,omitempty"`
+	// Ревизия развёрнутой конфигурации из метки контейнера
+	Revision      string `protobuf:"bytes,10,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerUsage) Reset() {
+	*x = ServerUsage{}
+	mi := &file_metrics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerUsage) ProtoMessage() {}
+
+func (x *ServerUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerUsage.ProtoReflect.Descriptor instead.
+func (*ServerUsage) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ServerUsage) GetConfigurationId() string {
+	if x != nil {
+		return x.ConfigurationId
+	}
+	return ""
+}
+
+func (x *ServerUsage) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *ServerUsage) GetCpuUsage() float32 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *ServerUsage) GetMemoryUsage() uint64 {
+	if x != nil {
+		return x.MemoryUsage
+	}
+	return 0
+}
+
+func (x *ServerUsage) GetMemoryLimit() uint64 {
+	if x != nil {
+		return x.MemoryLimit
+	}
+	return 0
+}
+
+func (x *ServerUsage) GetDiskUsage() uint64 {
+	if x != nil {
+		return x.DiskUsage
+	}
+	return 0
+}
+
+func (x *ServerUsage) GetNetRxBytes() uint64 {
+	if x != nil {
+		return x.NetRxBytes
+	}
+	return 0
+}
+
+func (x *ServerUsage) GetNetTxBytes() uint64 {
+	if x != nil {
+		return x.NetTxBytes
+	}
+	return 0
+}
+
+func (x *ServerUsage) GetOomKilledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OomKilledAt
+	}
+	return nil
+}
+
+func (x *ServerUsage) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
 var File_metrics_proto protoreflect.FileDescriptor
 
 const file_metrics_proto_rawDesc = "" +
 	"\n" +
-	"\rmetrics.proto\x12\x03api\"l\n" +
+	"\rmetrics.proto\x12\x03api\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd2\x02\n" +
 	"\vHostMetrics\x12\x1b\n" +
 	"\tcpu_usage\x18\x01 \x01(\x02R\bcpuUsage\x12#\n" +
 	"\rram_available\x18\x02 \x01(\x04R\framAvailable\x12\x1b\n" +
-	"\tram_total\x18\x03 \x01(\x04R\bramTotalB/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
+	"\tram_total\x18\x03 \x01(\x04R\bramTotal\x12\x1c\n" +
+	"\n" +
+	"load_avg_1\x18\x04 \x01(\x02R\bloadAvg1\x12\x1c\n" +
+	"\n" +
+	"load_avg_5\x18\x05 \x01(\x02R\bloadAvg5\x12\x1e\n" +
+	"\vload_avg_15\x18\x06 \x01(\x02R\tloadAvg15\x12\x1d\n" +
+	"\n" +
+	"disk_total\x18\a \x01(\x04R\tdiskTotal\x12%\n" +
+	"\x0edisk_available\x18\b \x01(\x04R\rdiskAvailable\x12 \n" +
+	"\fnet_rx_bytes\x18\t \x01(\x04R\n" +
+	"netRxBytes\x12 \n" +
+	"\fnet_tx_bytes\x18\n" +
+	" \x01(\x04R\n" +
+	"netTxBytes\"\xf4\x02\n" +
+	"\vServerUsage\x12)\n" +
+	"\x10configuration_id\x18\x01 \x01(\tR\x0fconfigurationId\x12\x18\n" +
+	"\arunning\x18\x02 \x01(\bR\arunning\x12\x1b\n" +
+	"\tcpu_usage\x18\x03 \x01(\x02R\bcpuUsage\x12!\n" +
+	"\fmemory_usage\x18\x04 \x01(\x04R\vmemoryUsage\x12!\n" +
+	"\fmemory_limit\x18\x05 \x01(\x04R\vmemoryLimit\x12\x1d\n" +
+	"\n" +
+	"disk_usage\x18\x06 \x01(\x04R\tdiskUsage\x12 \n" +
+	"\fnet_rx_bytes\x18\a \x01(\x04R\n" +
+	"netRxBytes\x12 \n" +
+	"\fnet_tx_bytes\x18\b \x01(\x04R\n" +
+	"netTxBytes\x12>\n" +
+	"\room_killed_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\voomKilledAt\x12\x1a\n" +
+	"\brevision\x18\n" +
+	" \x01(\tR\brevisionB/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
 
 var (
 	file_metrics_proto_rawDescOnce sync.Once
@@ -103,16 +307,19 @@ func file_metrics_proto_rawDescGZIP() []byte {
 	return file_metrics_proto_rawDescData
 }
 
-var file_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_metrics_proto_goTypes = []any{
-	(*HostMetrics)(nil), // 0: api.HostMetrics
+	(*HostMetrics)(nil),           // 0: api.HostMetrics
+	(*ServerUsage)(nil),           // 1: api.ServerUsage
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
 }
 var file_metrics_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: api.ServerUsage.oom_killed_at:type_name -> google.protobuf.Timestamp
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_metrics_proto_init() }
@@ -126,7 +333,7 @@ func file_metrics_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_metrics_proto_rawDesc), len(file_metrics_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
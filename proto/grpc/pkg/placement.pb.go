@@ -0,0 +1,300 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: placement.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PlacementRejection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Reasons       []string               `protobuf:"bytes,2,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlacementRejection) Reset() {
+	*x = PlacementRejection{}
+	mi := &file_placement_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlacementRejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlacementRejection) ProtoMessage() {}
+
+func (x *PlacementRejection) ProtoReflect() protoreflect.Message {
+	mi := &file_placement_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlacementRejection.ProtoReflect.Descriptor instead.
+func (*PlacementRejection) Descriptor() ([]byte, []int) {
+	return file_placement_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PlacementRejection) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *PlacementRejection) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+// Решение планировщика: выбранный агент или причины отказа каждого агента
+type PlacementDecision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Rejected      []*PlacementRejection  `protobuf:"bytes,3,rep,name=rejected,proto3" json:"rejected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlacementDecision) Reset() {
+	*x = PlacementDecision{}
+	mi := &file_placement_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlacementDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlacementDecision) ProtoMessage() {}
+
+func (x *PlacementDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_placement_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlacementDecision.ProtoReflect.Descriptor instead.
+func (*PlacementDecision) Descriptor() ([]byte, []int) {
+	return file_placement_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PlacementDecision) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *PlacementDecision) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PlacementDecision) GetRejected() []*PlacementRejection {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+type PlaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Configuration *Configuration         `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceRequest) Reset() {
+	*x = PlaceRequest{}
+	mi := &file_placement_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceRequest) ProtoMessage() {}
+
+func (x *PlaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_placement_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceRequest.ProtoReflect.Descriptor instead.
+func (*PlaceRequest) Descriptor() ([]byte, []int) {
+	return file_placement_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PlaceRequest) GetConfiguration() *Configuration {
+	if x != nil {
+		return x.Configuration
+	}
+	return nil
+}
+
+type PlaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Decision      *PlacementDecision     `protobuf:"bytes,1,opt,name=decision,proto3" json:"decision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceResponse) Reset() {
+	*x = PlaceResponse{}
+	mi := &file_placement_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceResponse) ProtoMessage() {}
+
+func (x *PlaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_placement_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceResponse.ProtoReflect.Descriptor instead.
+func (*PlaceResponse) Descriptor() ([]byte, []int) {
+	return file_placement_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PlaceResponse) GetDecision() *PlacementDecision {
+	if x != nil {
+		return x.Decision
+	}
+	return nil
+}
+
+var File_placement_proto protoreflect.FileDescriptor
+
+const file_placement_proto_rawDesc = "" +
+	"\n" +
+	"\x0fplacement.proto\x12\x03api\x1a\x13configuration.proto\"I\n" +
+	"\x12PlacementRejection\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x18\n" +
+	"\areasons\x18\x02 \x03(\tR\areasons\"{\n" +
+	"\x11PlacementDecision\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x123\n" +
+	"\brejected\x18\x03 \x03(\v2\x17.api.PlacementRejectionR\brejected\"H\n" +
+	"\fPlaceRequest\x128\n" +
+	"\rconfiguration\x18\x01 \x01(\v2\x12.api.ConfigurationR\rconfiguration\"C\n" +
+	"\rPlaceResponse\x122\n" +
+	"\bdecision\x18\x01 \x01(\v2\x16.api.PlacementDecisionR\bdecision2B\n" +
+	"\x10SchedulerService\x12.\n" +
+	"\x05Place\x12\x11.api.PlaceRequest\x1a\x12.api.PlaceResponseB/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
+
+var (
+	file_placement_proto_rawDescOnce sync.Once
+	file_placement_proto_rawDescData []byte
+)
+
+func file_placement_proto_rawDescGZIP() []byte {
+	file_placement_proto_rawDescOnce.Do(func() {
+		file_placement_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_placement_proto_rawDesc), len(file_placement_proto_rawDesc)))
+	})
+	return file_placement_proto_rawDescData
+}
+
+var file_placement_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_placement_proto_goTypes = []any{
+	(*PlacementRejection)(nil), // 0: api.PlacementRejection
+	(*PlacementDecision)(nil),  // 1: api.PlacementDecision
+	(*PlaceRequest)(nil),       // 2: api.PlaceRequest
+	(*PlaceResponse)(nil),      // 3: api.PlaceResponse
+	(*Configuration)(nil),      // 4: api.Configuration
+}
+var file_placement_proto_depIdxs = []int32{
+	0, // 0: api.PlacementDecision.rejected:type_name -> api.PlacementRejection
+	4, // 1: api.PlaceRequest.configuration:type_name -> api.Configuration
+	1, // 2: api.PlaceResponse.decision:type_name -> api.PlacementDecision
+	2, // 3: api.SchedulerService.Place:input_type -> api.PlaceRequest
+	3, // 4: api.SchedulerService.Place:output_type -> api.PlaceResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_placement_proto_init() }
+func file_placement_proto_init() {
+	if File_placement_proto != nil {
+		return
+	}
+	file_configuration_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_placement_proto_rawDesc), len(file_placement_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_placement_proto_goTypes,
+		DependencyIndexes: file_placement_proto_depIdxs,
+		MessageInfos:      file_placement_proto_msgTypes,
+	}.Build()
+	File_placement_proto = out.File
+	file_placement_proto_goTypes = nil
+	file_placement_proto_depIdxs = nil
+}
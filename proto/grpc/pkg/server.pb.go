@@ -0,0 +1,991 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: server.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DesiredState int32
+
+const (
+	DesiredState_DESIRED_STATE_UNSPECIFIED DesiredState = 0
+	DesiredState_DESIRED_STATE_RUNNING     DesiredState = 1
+	DesiredState_DESIRED_STATE_STOPPED     DesiredState = 2
+	DesiredState_DESIRED_STATE_DELETED     DesiredState = 3
+)
+
+// Enum value maps for DesiredState.
+var (
+	DesiredState_name = map[int32]string{
+		0: "DESIRED_STATE_UNSPECIFIED",
+		1: "DESIRED_STATE_RUNNING",
+		2: "DESIRED_STATE_STOPPED",
+		3: "DESIRED_STATE_DELETED",
+	}
+	DesiredState_value = map[string]int32{
+		"DESIRED_STATE_UNSPECIFIED": 0,
+		"DESIRED_STATE_RUNNING":     1,
+		"DESIRED_STATE_STOPPED":     2,
+		"DESIRED_STATE_DELETED":     3,
+	}
+)
+
+func (x DesiredState) Enum() *DesiredState {
+	p := new(DesiredState)
+	*p = x
+	return p
+}
+
+func (x DesiredState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DesiredState) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_proto_enumTypes[0].Descriptor()
+}
+
+func (DesiredState) Type() protoreflect.EnumType {
+	return &file_server_proto_enumTypes[0]
+}
+
+func (x DesiredState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DesiredState.Descriptor instead.
+func (DesiredState) EnumDescriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{0}
+}
+
+type ServerState int32
+
+const (
+	ServerState_SERVER_STATE_UNKNOWN ServerState = 0
+	ServerState_SERVER_STATE_ABSENT  ServerState = 1
+	ServerState_SERVER_STATE_RUNNING ServerState = 2
+	ServerState_SERVER_STATE_STOPPED ServerState = 3
+)
+
+// Enum value maps for ServerState.
+var (
+	ServerState_name = map[int32]string{
+		0: "SERVER_STATE_UNKNOWN",
+		1: "SERVER_STATE_ABSENT",
+		2: "SERVER_STATE_RUNNING",
+		3: "SERVER_STATE_STOPPED",
+	}
+	ServerState_value = map[string]int32{
+		"SERVER_STATE_UNKNOWN": 0,
+		"SERVER_STATE_ABSENT":  1,
+		"SERVER_STATE_RUNNING": 2,
+		"SERVER_STATE_STOPPED": 3,
+	}
+)
+
+func (x ServerState) Enum() *ServerState {
+	p := new(ServerState)
+	*p = x
+	return p
+}
+
+func (x ServerState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ServerState) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_proto_enumTypes[1].Descriptor()
+}
+
+func (ServerState) Type() protoreflect.EnumType {
+	return &file_server_proto_enumTypes[1]
+}
+
+func (x ServerState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ServerState.Descriptor instead.
+func (ServerState) EnumDescriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{1}
+}
+
+// Конфигурация, развёрнутая на агенте, с желаемым и фактическим состоянием
+type Server struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId          string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	DesiredState     DesiredState           `protobuf:"varint,3,opt,name=desired_state,json=desiredState,proto3,enum=api.DesiredState" json:"desired_state,omitempty"`
+	ObservedState    ServerState            `protobuf:"varint,4,opt,name=observed_state,json=observedState,proto3,enum=api.ServerState" json:"observed_state,omitempty"`
+	ObservedRevision string                 `protobuf:"bytes,5,opt,name=observed_revision,json=observedRevision,proto3" json:"observed_revision,omitempty"`
+	DesiredRevision  string                 `protobuf:"bytes,6,opt,name=desired_revision,json=desiredRevision,proto3" json:"desired_revision,omitempty"`
+	ObservedAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=observed_at,json=observedAt,proto3" json:"observed_at,omitempty"`
+	TaskId           string                 `protobuf:"bytes,8,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Error            string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Server) Reset() {
+	*x = Server{}
+	mi := &file_server_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server) ProtoMessage() {}
+
+func (x *Server) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server.ProtoReflect.Descriptor instead.
+func (*Server) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Server) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Server) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *Server) GetDesiredState() DesiredState {
+	if x != nil {
+		return x.DesiredState
+	}
+	return DesiredState_DESIRED_STATE_UNSPECIFIED
+}
+
+func (x *Server) GetObservedState() ServerState {
+	if x != nil {
+		return x.ObservedState
+	}
+	return ServerState_SERVER_STATE_UNKNOWN
+}
+
+func (x *Server) GetObservedRevision() string {
+	if x != nil {
+		return x.ObservedRevision
+	}
+	return ""
+}
+
+func (x *Server) GetDesiredRevision() string {
+	if x != nil {
+		return x.DesiredRevision
+	}
+	return ""
+}
+
+func (x *Server) GetObservedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ObservedAt
+	}
+	return nil
+}
+
+func (x *Server) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *Server) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Server) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Server) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type DeploymentPort struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Port          uint32                 `protobuf:"varint,1,opt,name=port,proto3" json:"port,omitempty"`
+	Protocol      string                 `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeploymentPort) Reset() {
+	*x = DeploymentPort{}
+	mi := &file_server_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeploymentPort) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeploymentPort) ProtoMessage() {}
+
+func (x *DeploymentPort) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeploymentPort.ProtoReflect.Descriptor instead.
+func (*DeploymentPort) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DeploymentPort) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *DeploymentPort) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+// Payload задачи deploy в формате protojson: всё, что нужно агенту для запуска сервера
+type Deployment struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Revision string                 `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	Image    string                 `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Env      map[string]string      `protobuf:"bytes,3,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Ports    []*DeploymentPort      `protobuf:"bytes,4,rep,name=ports,proto3" json:"ports,omitempty"`
+	// Файлы по путям относительно каталога данных сервера
+	Files     map[string][]byte `protobuf:"bytes,5,rep,name=files,proto3" json:"files,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DataPath  string            `protobuf:"bytes,6,opt,name=data_path,json=dataPath,proto3" json:"data_path,omitempty"`
+	Resources *Resources        `protobuf:"bytes,7,opt,name=resources,proto3" json:"resources,omitempty"`
+	// Запустить сервер после развёртывания
+	Start         bool `protobuf:"varint,8,opt,name=start,proto3" json:"start,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Deployment) Reset() {
+	*x = Deployment{}
+	mi := &file_server_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Deployment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Deployment) ProtoMessage() {}
+
+func (x *Deployment) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Deployment.ProtoReflect.Descriptor instead.
+func (*Deployment) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Deployment) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+func (x *Deployment) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Deployment) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *Deployment) GetPorts() []*DeploymentPort {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
+}
+
+func (x *Deployment) GetFiles() map[string][]byte {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *Deployment) GetDataPath() string {
+	if x != nil {
+		return x.DataPath
+	}
+	return ""
+}
+
+func (x *Deployment) GetResources() *Resources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *Deployment) GetStart() bool {
+	if x != nil {
+		return x.Start
+	}
+	return false
+}
+
+type GetServerByIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServerByIdRequest) Reset() {
+	*x = GetServerByIdRequest{}
+	mi := &file_server_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServerByIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServerByIdRequest) ProtoMessage() {}
+
+func (x *GetServerByIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServerByIdRequest.ProtoReflect.Descriptor instead.
+func (*GetServerByIdRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetServerByIdRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetServerByIdResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        *Server                `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServerByIdResponse) Reset() {
+	*x = GetServerByIdResponse{}
+	mi := &file_server_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServerByIdResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServerByIdResponse) ProtoMessage() {}
+
+func (x *GetServerByIdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServerByIdResponse.ProtoReflect.Descriptor instead.
+func (*GetServerByIdResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetServerByIdResponse) GetServer() *Server {
+	if x != nil {
+		return x.Server
+	}
+	return nil
+}
+
+type GetAllServersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllServersRequest) Reset() {
+	*x = GetAllServersRequest{}
+	mi := &file_server_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllServersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllServersRequest) ProtoMessage() {}
+
+func (x *GetAllServersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllServersRequest.ProtoReflect.Descriptor instead.
+func (*GetAllServersRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{5}
+}
+
+type GetAllServersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Servers       []*Server              `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllServersResponse) Reset() {
+	*x = GetAllServersResponse{}
+	mi := &file_server_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllServersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllServersResponse) ProtoMessage() {}
+
+func (x *GetAllServersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllServersResponse.ProtoReflect.Descriptor instead.
+func (*GetAllServersResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetAllServersResponse) GetServers() []*Server {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+type PostServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        *Server                `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostServerRequest) Reset() {
+	*x = PostServerRequest{}
+	mi := &file_server_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostServerRequest) ProtoMessage() {}
+
+func (x *PostServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostServerRequest.ProtoReflect.Descriptor instead.
+func (*PostServerRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PostServerRequest) GetServer() *Server {
+	if x != nil {
+		return x.Server
+	}
+	return nil
+}
+
+type PostServerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostServerResponse) Reset() {
+	*x = PostServerResponse{}
+	mi := &file_server_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostServerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostServerResponse) ProtoMessage() {}
+
+func (x *PostServerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostServerResponse.ProtoReflect.Descriptor instead.
+func (*PostServerResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{8}
+}
+
+type PutServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Server        *Server                `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutServerRequest) Reset() {
+	*x = PutServerRequest{}
+	mi := &file_server_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutServerRequest) ProtoMessage() {}
+
+func (x *PutServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutServerRequest.ProtoReflect.Descriptor instead.
+func (*PutServerRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PutServerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PutServerRequest) GetServer() *Server {
+	if x != nil {
+		return x.Server
+	}
+	return nil
+}
+
+type PutServerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutServerResponse) Reset() {
+	*x = PutServerResponse{}
+	mi := &file_server_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutServerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutServerResponse) ProtoMessage() {}
+
+func (x *PutServerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutServerResponse.ProtoReflect.Descriptor instead.
+func (*PutServerResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{10}
+}
+
+// Переводит сервер в состояние deleted: запись удаляется после удаления контейнера
+type DeleteServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServerRequest) Reset() {
+	*x = DeleteServerRequest{}
+	mi := &file_server_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServerRequest) ProtoMessage() {}
+
+func (x *DeleteServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServerRequest.ProtoReflect.Descriptor instead.
+func (*DeleteServerRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteServerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteServerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServerResponse) Reset() {
+	*x = DeleteServerResponse{}
+	mi := &file_server_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServerResponse) ProtoMessage() {}
+
+func (x *DeleteServerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServerResponse.ProtoReflect.Descriptor instead.
+func (*DeleteServerResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{12}
+}
+
+var File_server_proto protoreflect.FileDescriptor
+
+const file_server_proto_rawDesc = "" +
+	"\n" +
+	"\fserver.proto\x12\x03api\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x13configuration.proto\"\xde\x03\n" +
+	"\x06Server\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x126\n" +
+	"\rdesired_state\x18\x03 \x01(\x0e2\x11.api.DesiredStateR\fdesiredState\x127\n" +
+	"\x0eobserved_state\x18\x04 \x01(\x0e2\x10.api.ServerStateR\robservedState\x12+\n" +
+	"\x11observed_revision\x18\x05 \x01(\tR\x10observedRevision\x12)\n" +
+	"\x10desired_revision\x18\x06 \x01(\tR\x0fdesiredRevision\x12;\n" +
+	"\vobserved_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"observedAt\x12\x17\n" +
+	"\atask_id\x18\b \x01(\tR\x06taskId\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"@\n" +
+	"\x0eDeploymentPort\x12\x12\n" +
+	"\x04port\x18\x01 \x01(\rR\x04port\x12\x1a\n" +
+	"\bprotocol\x18\x02 \x01(\tR\bprotocol\"\x9a\x03\n" +
+	"\n" +
+	"Deployment\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\tR\brevision\x12\x14\n" +
+	"\x05image\x18\x02 \x01(\tR\x05image\x12*\n" +
+	"\x03env\x18\x03 \x03(\v2\x18.api.Deployment.EnvEntryR\x03env\x12)\n" +
+	"\x05ports\x18\x04 \x03(\v2\x13.api.DeploymentPortR\x05ports\x120\n" +
+	"\x05files\x18\x05 \x03(\v2\x1a.api.Deployment.FilesEntryR\x05files\x12\x1b\n" +
+	"\tdata_path\x18\x06 \x01(\tR\bdataPath\x12,\n" +
+	"\tresources\x18\a \x01(\v2\x0e.api.ResourcesR\tresources\x12\x14\n" +
+	"\x05start\x18\b \x01(\bR\x05start\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"FilesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01\"&\n" +
+	"\x14GetServerByIdRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"<\n" +
+	"\x15GetServerByIdResponse\x12#\n" +
+	"\x06server\x18\x01 \x01(\v2\v.api.ServerR\x06server\"\x16\n" +
+	"\x14GetAllServersRequest\">\n" +
+	"\x15GetAllServersResponse\x12%\n" +
+	"\aservers\x18\x01 \x03(\v2\v.api.ServerR\aservers\"8\n" +
+	"\x11PostServerRequest\x12#\n" +
+	"\x06server\x18\x01 \x01(\v2\v.api.ServerR\x06server\"\x14\n" +
+	"\x12PostServerResponse\"G\n" +
+	"\x10PutServerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\x06server\x18\x02 \x01(\v2\v.api.ServerR\x06server\"\x13\n" +
+	"\x11PutServerResponse\"%\n" +
+	"\x13DeleteServerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x16\n" +
+	"\x14DeleteServerResponse*~\n" +
+	"\fDesiredState\x12\x1d\n" +
+	"\x19DESIRED_STATE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15DESIRED_STATE_RUNNING\x10\x01\x12\x19\n" +
+	"\x15DESIRED_STATE_STOPPED\x10\x02\x12\x19\n" +
+	"\x15DESIRED_STATE_DELETED\x10\x03*t\n" +
+	"\vServerState\x12\x18\n" +
+	"\x14SERVER_STATE_UNKNOWN\x10\x00\x12\x17\n" +
+	"\x13SERVER_STATE_ABSENT\x10\x01\x12\x18\n" +
+	"\x14SERVER_STATE_RUNNING\x10\x02\x12\x18\n" +
+	"\x14SERVER_STATE_STOPPED\x10\x032\xc0\x02\n" +
+	"\rServerService\x12@\n" +
+	"\aGetById\x12\x19.api.GetServerByIdRequest\x1a\x1a.api.GetServerByIdResponse\x12?\n" +
+	"\x06GetAll\x12\x19.api.GetAllServersRequest\x1a\x1a.api.GetAllServersResponse\x127\n" +
+	"\x04Post\x12\x16.api.PostServerRequest\x1a\x17.api.PostServerResponse\x124\n" +
+	"\x03Put\x12\x15.api.PutServerRequest\x1a\x16.api.PutServerResponse\x12=\n" +
+	"\x06Delete\x12\x18.api.DeleteServerRequest\x1a\x19.api.DeleteServerResponseB/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
+
+var (
+	file_server_proto_rawDescOnce sync.Once
+	file_server_proto_rawDescData []byte
+)
+
+func file_server_proto_rawDescGZIP() []byte {
+	file_server_proto_rawDescOnce.Do(func() {
+		file_server_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_server_proto_rawDesc), len(file_server_proto_rawDesc)))
+	})
+	return file_server_proto_rawDescData
+}
+
+var file_server_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_server_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_server_proto_goTypes = []any{
+	(DesiredState)(0),             // 0: api.DesiredState
+	(ServerState)(0),              // 1: api.ServerState
+	(*Server)(nil),                // 2: api.Server
+	(*DeploymentPort)(nil),        // 3: api.DeploymentPort
+	(*Deployment)(nil),            // 4: api.Deployment
+	(*GetServerByIdRequest)(nil),  // 5: api.GetServerByIdRequest
+	(*GetServerByIdResponse)(nil), // 6: api.GetServerByIdResponse
+	(*GetAllServersRequest)(nil),  // 7: api.GetAllServersRequest
+	(*GetAllServersResponse)(nil), // 8: api.GetAllServersResponse
+	(*PostServerRequest)(nil),     // 9: api.PostServerRequest
+	(*PostServerResponse)(nil),    // 10: api.PostServerResponse
+	(*PutServerRequest)(nil),      // 11: api.PutServerRequest
+	(*PutServerResponse)(nil),     // 12: api.PutServerResponse
+	(*DeleteServerRequest)(nil),   // 13: api.DeleteServerRequest
+	(*DeleteServerResponse)(nil),  // 14: api.DeleteServerResponse
+	nil,                           // 15: api.Deployment.EnvEntry
+	nil,                           // 16: api.Deployment.FilesEntry
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+	(*Resources)(nil),             // 18: api.Resources
+}
+var file_server_proto_depIdxs = []int32{
+	0,  // 0: api.Server.desired_state:type_name -> api.DesiredState
+	1,  // 1: api.Server.observed_state:type_name -> api.ServerState
+	17, // 2: api.Server.observed_at:type_name -> google.protobuf.Timestamp
+	17, // 3: api.Server.created_at:type_name -> google.protobuf.Timestamp
+	17, // 4: api.Server.updated_at:type_name -> google.protobuf.Timestamp
+	15, // 5: api.Deployment.env:type_name -> api.Deployment.EnvEntry
+	3,  // 6: api.Deployment.ports:type_name -> api.DeploymentPort
+	16, // 7: api.Deployment.files:type_name -> api.Deployment.FilesEntry
+	18, // 8: api.Deployment.resources:type_name -> api.Resources
+	2,  // 9: api.GetServerByIdResponse.server:type_name -> api.Server
+	2,  // 10: api.GetAllServersResponse.servers:type_name -> api.Server
+	2,  // 11: api.PostServerRequest.server:type_name -> api.Server
+	2,  // 12: api.PutServerRequest.server:type_name -> api.Server
+	5,  // 13: api.ServerService.GetById:input_type -> api.GetServerByIdRequest
+	7,  // 14: api.ServerService.GetAll:input_type -> api.GetAllServersRequest
+	9,  // 15: api.ServerService.Post:input_type -> api.PostServerRequest
+	11, // 16: api.ServerService.Put:input_type -> api.PutServerRequest
+	13, // 17: api.ServerService.Delete:input_type -> api.DeleteServerRequest
+	6,  // 18: api.ServerService.GetById:output_type -> api.GetServerByIdResponse
+	8,  // 19: api.ServerService.GetAll:output_type -> api.GetAllServersResponse
+	10, // 20: api.ServerService.Post:output_type -> api.PostServerResponse
+	12, // 21: api.ServerService.Put:output_type -> api.PutServerResponse
+	14, // 22: api.ServerService.Delete:output_type -> api.DeleteServerResponse
+	18, // [18:23] is the sub-list for method output_type
+	13, // [13:18] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_server_proto_init() }
+func file_server_proto_init() {
+	if File_server_proto != nil {
+		return
+	}
+	file_configuration_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_server_proto_rawDesc), len(file_server_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_server_proto_goTypes,
+		DependencyIndexes: file_server_proto_depIdxs,
+		EnumInfos:         file_server_proto_enumTypes,
+		MessageInfos:      file_server_proto_msgTypes,
+	}.Build()
+	File_server_proto = out.File
+	file_server_proto_goTypes = nil
+	file_server_proto_depIdxs = nil
+}
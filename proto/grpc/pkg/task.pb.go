@@ -9,6 +9,7 @@ package api
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -29,6 +30,9 @@ const (
 	TaskStatus_TASK_STATUS_IN_PROGRESS TaskStatus = 2
 	TaskStatus_TASK_STATUS_OK          TaskStatus = 3
 	TaskStatus_TASK_STATUS_DELETED     TaskStatus = 4
+	TaskStatus_TASK_STATUS_FAILED      TaskStatus = 5
+	TaskStatus_TASK_STATUS_CANCELLED   TaskStatus = 6
+	TaskStatus_TASK_STATUS_TIMED_OUT   TaskStatus = 7
 )
 
 // Enum value maps for TaskStatus.
@@ -39,6 +43,9 @@ var (
 		2: "TASK_STATUS_IN_PROGRESS",
 		3: "TASK_STATUS_OK",
 		4: "TASK_STATUS_DELETED",
+		5: "TASK_STATUS_FAILED",
+		6: "TASK_STATUS_CANCELLED",
+		7: "TASK_STATUS_TIMED_OUT",
 	}
 	TaskStatus_value = map[string]int32{
 		"TASK_STATUS_UNSPECIFIED": 0,
@@ -46,6 +53,9 @@ var (
 		"TASK_STATUS_IN_PROGRESS": 2,
 		"TASK_STATUS_OK":          3,
 		"TASK_STATUS_DELETED":     4,
+		"TASK_STATUS_FAILED":      5,
+		"TASK_STATUS_CANCELLED":   6,
+		"TASK_STATUS_TIMED_OUT":   7,
 	}
 )
 
@@ -76,18 +86,159 @@ func (TaskStatus) EnumDescriptor() ([]byte, []int) {
 	return file_task_proto_rawDescGZIP(), []int{0}
 }
 
-type Task struct {
+type TaskAction int32
+
+const (
+	TaskAction_TASK_ACTION_UNSPECIFIED TaskAction = 0
+	TaskAction_TASK_ACTION_DEPLOY      TaskAction = 1
+	TaskAction_TASK_ACTION_START       TaskAction = 2
+	TaskAction_TASK_ACTION_STOP        TaskAction = 3
+	TaskAction_TASK_ACTION_RESTART     TaskAction = 4
+	TaskAction_TASK_ACTION_BACKUP      TaskAction = 5
+	TaskAction_TASK_ACTION_DELETE      TaskAction = 6
+)
+
+// Enum value maps for TaskAction.
+var (
+	TaskAction_name = map[int32]string{
+		0: "TASK_ACTION_UNSPECIFIED",
+		1: "TASK_ACTION_DEPLOY",
+		2: "TASK_ACTION_START",
+		3: "TASK_ACTION_STOP",
+		4: "TASK_ACTION_RESTART",
+		5: "TASK_ACTION_BACKUP",
+		6: "TASK_ACTION_DELETE",
+	}
+	TaskAction_value = map[string]int32{
+		"TASK_ACTION_UNSPECIFIED": 0,
+		"TASK_ACTION_DEPLOY":      1,
+		"TASK_ACTION_START":       2,
+		"TASK_ACTION_STOP":        3,
+		"TASK_ACTION_RESTART":     4,
+		"TASK_ACTION_BACKUP":      5,
+		"TASK_ACTION_DELETE":      6,
+	}
+)
+
+func (x TaskAction) Enum() *TaskAction {
+	p := new(TaskAction)
+	*p = x
+	return p
+}
+
+func (x TaskAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_task_proto_enumTypes[1].Descriptor()
+}
+
+func (TaskAction) Type() protoreflect.EnumType {
+	return &file_task_proto_enumTypes[1]
+}
+
+func (x TaskAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskAction.Descriptor instead.
+func (TaskAction) EnumDescriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{1}
+}
+
+type TaskTransition struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Status        TaskStatus             `protobuf:"varint,2,opt,name=status,proto3,enum=api.TaskStatus" json:"status,omitempty"`
-	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	From          TaskStatus             `protobuf:"varint,1,opt,name=from,proto3,enum=api.TaskStatus" json:"from,omitempty"`
+	To            TaskStatus             `protobuf:"varint,2,opt,name=to,proto3,enum=api.TaskStatus" json:"to,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=at,proto3" json:"at,omitempty"`
+	Actor         string                 `protobuf:"bytes,4,opt,name=actor,proto3" json:"actor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *TaskTransition) Reset() {
+	*x = TaskTransition{}
+	mi := &file_task_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskTransition) ProtoMessage() {}
+
+func (x *TaskTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskTransition.ProtoReflect.Descriptor instead.
+func (*TaskTransition) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TaskTransition) GetFrom() TaskStatus {
+	if x != nil {
+		return x.From
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *TaskTransition) GetTo() TaskStatus {
+	if x != nil {
+		return x.To
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *TaskTransition) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *TaskTransition) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+type Task struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status          TaskStatus             `protobuf:"varint,2,opt,name=status,proto3,enum=api.TaskStatus" json:"status,omitempty"`
+	Type            string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	AgentId         string                 `protobuf:"bytes,4,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Action          TaskAction             `protobuf:"varint,5,opt,name=action,proto3,enum=api.TaskAction" json:"action,omitempty"`
+	ConfigurationId string                 `protobuf:"bytes,6,opt,name=configuration_id,json=configurationId,proto3" json:"configuration_id,omitempty"`
+	Payload         []byte                 `protobuf:"bytes,7,opt,name=payload,proto3" json:"payload,omitempty"`
+	Result          []byte                 `protobuf:"bytes,8,opt,name=result,proto3" json:"result,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Attempts        uint32                 `protobuf:"varint,12,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Error           string                 `protobuf:"bytes,13,opt,name=error,proto3" json:"error,omitempty"`
+	Transitions     []*TaskTransition      `protobuf:"bytes,14,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	Priority        int32                  `protobuf:"varint,15,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *Task) Reset() {
 	*x = Task{}
-	mi := &file_task_proto_msgTypes[0]
+	mi := &file_task_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -99,7 +250,7 @@ func (x *Task) String() string {
 func (*Task) ProtoMessage() {}
 
 func (x *Task) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[0]
+	mi := &file_task_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -112,7 +263,7 @@ func (x *Task) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Task.ProtoReflect.Descriptor instead.
 func (*Task) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{0}
+	return file_task_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Task) GetId() string {
@@ -136,6 +287,90 @@ func (x *Task) GetType() string {
 	return ""
 }
 
+func (x *Task) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *Task) GetAction() TaskAction {
+	if x != nil {
+		return x.Action
+	}
+	return TaskAction_TASK_ACTION_UNSPECIFIED
+}
+
+func (x *Task) GetConfigurationId() string {
+	if x != nil {
+		return x.ConfigurationId
+	}
+	return ""
+}
+
+func (x *Task) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Task) GetResult() []byte {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Task) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *Task) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Task) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Task) GetTransitions() []*TaskTransition {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+func (x *Task) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
 type GetTaskByIdRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -145,7 +380,7 @@ type GetTaskByIdRequest struct {
 
 func (x *GetTaskByIdRequest) Reset() {
 	*x = GetTaskByIdRequest{}
-	mi := &file_task_proto_msgTypes[1]
+	mi := &file_task_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -157,7 +392,7 @@ func (x *GetTaskByIdRequest) String() string {
 func (*GetTaskByIdRequest) ProtoMessage() {}
 
 func (x *GetTaskByIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[1]
+	mi := &file_task_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -170,7 +405,7 @@ func (x *GetTaskByIdRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskByIdRequest.ProtoReflect.Descriptor instead.
 func (*GetTaskByIdRequest) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{1}
+	return file_task_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *GetTaskByIdRequest) GetId() string {
@@ -189,7 +424,7 @@ type GetTaskByIdResponse struct {
 
 func (x *GetTaskByIdResponse) Reset() {
 	*x = GetTaskByIdResponse{}
-	mi := &file_task_proto_msgTypes[2]
+	mi := &file_task_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -201,7 +436,7 @@ func (x *GetTaskByIdResponse) String() string {
 func (*GetTaskByIdResponse) ProtoMessage() {}
 
 func (x *GetTaskByIdResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[2]
+	mi := &file_task_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -214,7 +449,7 @@ func (x *GetTaskByIdResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskByIdResponse.ProtoReflect.Descriptor instead.
 func (*GetTaskByIdResponse) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{2}
+	return file_task_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetTaskByIdResponse) GetTask() *Task {
@@ -224,15 +459,26 @@ func (x *GetTaskByIdResponse) GetTask() *Task {
 	return nil
 }
 
+// Фильтры объединяются через И, значения одного фильтра - через ИЛИ.
+// По умолчанию сначала новые задачи
 type GetAllTasksRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Options         *ListOptions           `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	Status          []TaskStatus           `protobuf:"varint,2,rep,packed,name=status,proto3,enum=api.TaskStatus" json:"status,omitempty"`
+	Type            []string               `protobuf:"bytes,3,rep,name=type,proto3" json:"type,omitempty"`
+	Action          []TaskAction           `protobuf:"varint,4,rep,packed,name=action,proto3,enum=api.TaskAction" json:"action,omitempty"`
+	AgentId         []string               `protobuf:"bytes,5,rep,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ConfigurationId []string               `protobuf:"bytes,6,rep,name=configuration_id,json=configurationId,proto3" json:"configuration_id,omitempty"`
+	// Созданные не раньше created_from и раньше created_to
+	CreatedFrom   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetAllTasksRequest) Reset() {
 	*x = GetAllTasksRequest{}
-	mi := &file_task_proto_msgTypes[3]
+	mi := &file_task_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -244,7 +490,7 @@ func (x *GetAllTasksRequest) String() string {
 func (*GetAllTasksRequest) ProtoMessage() {}
 
 func (x *GetAllTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[3]
+	mi := &file_task_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -257,19 +503,77 @@ func (x *GetAllTasksRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllTasksRequest.ProtoReflect.Descriptor instead.
 func (*GetAllTasksRequest) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{3}
+	return file_task_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetAllTasksRequest) GetOptions() *ListOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *GetAllTasksRequest) GetStatus() []TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GetAllTasksRequest) GetType() []string {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *GetAllTasksRequest) GetAction() []TaskAction {
+	if x != nil {
+		return x.Action
+	}
+	return nil
+}
+
+func (x *GetAllTasksRequest) GetAgentId() []string {
+	if x != nil {
+		return x.AgentId
+	}
+	return nil
+}
+
+func (x *GetAllTasksRequest) GetConfigurationId() []string {
+	if x != nil {
+		return x.ConfigurationId
+	}
+	return nil
+}
+
+func (x *GetAllTasksRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *GetAllTasksRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
 }
 
 type GetAllTasksResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tasks []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// Пустой на последней странице
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetAllTasksResponse) Reset() {
 	*x = GetAllTasksResponse{}
-	mi := &file_task_proto_msgTypes[4]
+	mi := &file_task_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -281,7 +585,7 @@ func (x *GetAllTasksResponse) String() string {
 func (*GetAllTasksResponse) ProtoMessage() {}
 
 func (x *GetAllTasksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[4]
+	mi := &file_task_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -294,7 +598,7 @@ func (x *GetAllTasksResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllTasksResponse.ProtoReflect.Descriptor instead.
 func (*GetAllTasksResponse) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{4}
+	return file_task_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetAllTasksResponse) GetTasks() []*Task {
@@ -304,6 +608,13 @@ func (x *GetAllTasksResponse) GetTasks() []*Task {
 	return nil
 }
 
+func (x *GetAllTasksResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 type PostTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
@@ -313,7 +624,7 @@ type PostTaskRequest struct {
 
 func (x *PostTaskRequest) Reset() {
 	*x = PostTaskRequest{}
-	mi := &file_task_proto_msgTypes[5]
+	mi := &file_task_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -325,7 +636,7 @@ func (x *PostTaskRequest) String() string {
 func (*PostTaskRequest) ProtoMessage() {}
 
 func (x *PostTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[5]
+	mi := &file_task_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -338,7 +649,7 @@ func (x *PostTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PostTaskRequest.ProtoReflect.Descriptor instead.
 func (*PostTaskRequest) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{5}
+	return file_task_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PostTaskRequest) GetTask() *Task {
@@ -356,7 +667,7 @@ type PostTaskResponse struct {
 
 func (x *PostTaskResponse) Reset() {
 	*x = PostTaskResponse{}
-	mi := &file_task_proto_msgTypes[6]
+	mi := &file_task_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -368,7 +679,7 @@ func (x *PostTaskResponse) String() string {
 func (*PostTaskResponse) ProtoMessage() {}
 
 func (x *PostTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[6]
+	mi := &file_task_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -381,7 +692,7 @@ func (x *PostTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PostTaskResponse.ProtoReflect.Descriptor instead.
 func (*PostTaskResponse) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{6}
+	return file_task_proto_rawDescGZIP(), []int{7}
 }
 
 type PutTaskRequest struct {
@@ -394,7 +705,7 @@ type PutTaskRequest struct {
 
 func (x *PutTaskRequest) Reset() {
 	*x = PutTaskRequest{}
-	mi := &file_task_proto_msgTypes[7]
+	mi := &file_task_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -406,7 +717,7 @@ func (x *PutTaskRequest) String() string {
 func (*PutTaskRequest) ProtoMessage() {}
 
 func (x *PutTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[7]
+	mi := &file_task_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -419,7 +730,7 @@ func (x *PutTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PutTaskRequest.ProtoReflect.Descriptor instead.
 func (*PutTaskRequest) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{7}
+	return file_task_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *PutTaskRequest) GetId() string {
@@ -444,7 +755,7 @@ type PutTaskResponse struct {
 
 func (x *PutTaskResponse) Reset() {
 	*x = PutTaskResponse{}
-	mi := &file_task_proto_msgTypes[8]
+	mi := &file_task_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -456,7 +767,7 @@ func (x *PutTaskResponse) String() string {
 func (*PutTaskResponse) ProtoMessage() {}
 
 func (x *PutTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[8]
+	mi := &file_task_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -469,7 +780,7 @@ func (x *PutTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PutTaskResponse.ProtoReflect.Descriptor instead.
 func (*PutTaskResponse) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{8}
+	return file_task_proto_rawDescGZIP(), []int{9}
 }
 
 type DeleteTaskRequest struct {
@@ -481,7 +792,7 @@ type DeleteTaskRequest struct {
 
 func (x *DeleteTaskRequest) Reset() {
 	*x = DeleteTaskRequest{}
-	mi := &file_task_proto_msgTypes[9]
+	mi := &file_task_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -493,7 +804,7 @@ func (x *DeleteTaskRequest) String() string {
 func (*DeleteTaskRequest) ProtoMessage() {}
 
 func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[9]
+	mi := &file_task_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -506,7 +817,7 @@ func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
 func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{9}
+	return file_task_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DeleteTaskRequest) GetId() string {
@@ -524,7 +835,7 @@ type DeleteTaskResponse struct {
 
 func (x *DeleteTaskResponse) Reset() {
 	*x = DeleteTaskResponse{}
-	mi := &file_task_proto_msgTypes[10]
+	mi := &file_task_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -536,7 +847,7 @@ func (x *DeleteTaskResponse) String() string {
 func (*DeleteTaskResponse) ProtoMessage() {}
 
 func (x *DeleteTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[10]
+	mi := &file_task_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -549,26 +860,565 @@ func (x *DeleteTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteTaskResponse.ProtoReflect.Descriptor instead.
 func (*DeleteTaskResponse) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{10}
+	return file_task_proto_rawDescGZIP(), []int{11}
 }
 
-var File_task_proto protoreflect.FileDescriptor
+type LeaseTasksRequest struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	AgentId                  string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Types                    []string               `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`
+	MaxTasks                 uint32                 `protobuf:"varint,3,opt,name=max_tasks,json=maxTasks,proto3" json:"max_tasks,omitempty"`
+	VisibilityTimeoutSeconds uint32                 `protobuf:"varint,4,opt,name=visibility_timeout_seconds,json=visibilityTimeoutSeconds,proto3" json:"visibility_timeout_seconds,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
 
-const file_task_proto_rawDesc = "" +
-	"\n" +
-	"\n" +
-	"task.proto\x12\x03api\"S\n" +
-	"\x04Task\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
-	"\x06status\x18\x02 \x01(\x0e2\x0f.api.TaskStatusR\x06status\x12\x12\n" +
-	"\x04type\x18\x03 \x01(\tR\x04type\"$\n" +
-	"\x12GetTaskByIdRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"4\n" +
-	"\x13GetTaskByIdResponse\x12\x1d\n" +
-	"\x04task\x18\x01 \x01(\v2\t.api.TaskR\x04task\"\x14\n" +
-	"\x12GetAllTasksRequest\"6\n" +
-	"\x13GetAllTasksResponse\x12\x1f\n" +
-	"\x05tasks\x18\x01 \x03(\v2\t.api.TaskR\x05tasks\"0\n" +
+func (x *LeaseTasksRequest) Reset() {
+	*x = LeaseTasksRequest{}
+	mi := &file_task_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseTasksRequest) ProtoMessage() {}
+
+func (x *LeaseTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseTasksRequest.ProtoReflect.Descriptor instead.
+func (*LeaseTasksRequest) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LeaseTasksRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *LeaseTasksRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *LeaseTasksRequest) GetMaxTasks() uint32 {
+	if x != nil {
+		return x.MaxTasks
+	}
+	return 0
+}
+
+func (x *LeaseTasksRequest) GetVisibilityTimeoutSeconds() uint32 {
+	if x != nil {
+		return x.VisibilityTimeoutSeconds
+	}
+	return 0
+}
+
+type LeaseTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseTasksResponse) Reset() {
+	*x = LeaseTasksResponse{}
+	mi := &file_task_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseTasksResponse) ProtoMessage() {}
+
+func (x *LeaseTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseTasksResponse.ProtoReflect.Descriptor instead.
+func (*LeaseTasksResponse) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LeaseTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type ReportTaskRequest struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Id                       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId                  string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Status                   TaskStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=api.TaskStatus" json:"status,omitempty"`
+	Error                    string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	VisibilityTimeoutSeconds uint32                 `protobuf:"varint,5,opt,name=visibility_timeout_seconds,json=visibilityTimeoutSeconds,proto3" json:"visibility_timeout_seconds,omitempty"`
+	Result                   []byte                 `protobuf:"bytes,6,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *ReportTaskRequest) Reset() {
+	*x = ReportTaskRequest{}
+	mi := &file_task_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportTaskRequest) ProtoMessage() {}
+
+func (x *ReportTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportTaskRequest.ProtoReflect.Descriptor instead.
+func (*ReportTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReportTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReportTaskRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ReportTaskRequest) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *ReportTaskRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ReportTaskRequest) GetVisibilityTimeoutSeconds() uint32 {
+	if x != nil {
+		return x.VisibilityTimeoutSeconds
+	}
+	return 0
+}
+
+func (x *ReportTaskRequest) GetResult() []byte {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ReportTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportTaskResponse) Reset() {
+	*x = ReportTaskResponse{}
+	mi := &file_task_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportTaskResponse) ProtoMessage() {}
+
+func (x *ReportTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportTaskResponse.ProtoReflect.Descriptor instead.
+func (*ReportTaskResponse) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{15}
+}
+
+type GetDeadLettersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeadLettersRequest) Reset() {
+	*x = GetDeadLettersRequest{}
+	mi := &file_task_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeadLettersRequest) ProtoMessage() {}
+
+func (x *GetDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*GetDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{16}
+}
+
+type GetDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeadLettersResponse) Reset() {
+	*x = GetDeadLettersResponse{}
+	mi := &file_task_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeadLettersResponse) ProtoMessage() {}
+
+func (x *GetDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*GetDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetDeadLettersResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type RequeueDeadLetterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueDeadLetterRequest) Reset() {
+	*x = RequeueDeadLetterRequest{}
+	mi := &file_task_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadLetterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadLetterRequest) ProtoMessage() {}
+
+func (x *RequeueDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RequeueDeadLetterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RequeueDeadLetterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueDeadLetterResponse) Reset() {
+	*x = RequeueDeadLetterResponse{}
+	mi := &file_task_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadLetterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadLetterResponse) ProtoMessage() {}
+
+func (x *RequeueDeadLetterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueDeadLetterResponse.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLetterResponse) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{19}
+}
+
+// Пустая ревизия - сначала снимок всех задач, иначе изменения после неё
+type WatchTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      string                 `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTasksRequest) Reset() {
+	*x = WatchTasksRequest{}
+	mi := &file_task_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTasksRequest) ProtoMessage() {}
+
+func (x *WatchTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTasksRequest.ProtoReflect.Descriptor instead.
+func (*WatchTasksRequest) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WatchTasksRequest) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+// Задача заполнена для добавления и изменения, id - для всех изменений, кроме synced
+type WatchTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          WatchEventType         `protobuf:"varint,1,opt,name=type,proto3,enum=api.WatchEventType" json:"type,omitempty"`
+	Revision      string                 `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Task          *Task                  `protobuf:"bytes,4,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTasksResponse) Reset() {
+	*x = WatchTasksResponse{}
+	mi := &file_task_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTasksResponse) ProtoMessage() {}
+
+func (x *WatchTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTasksResponse.ProtoReflect.Descriptor instead.
+func (*WatchTasksResponse) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WatchTasksResponse) GetType() WatchEventType {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEventType_WATCH_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *WatchTasksResponse) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+func (x *WatchTasksResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WatchTasksResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+var File_task_proto protoreflect.FileDescriptor
+
+const file_task_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"task.proto\x12\x03api\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\vwatch.proto\x1a\n" +
+	"list.proto\"\x98\x01\n" +
+	"\x0eTaskTransition\x12#\n" +
+	"\x04from\x18\x01 \x01(\x0e2\x0f.api.TaskStatusR\x04from\x12\x1f\n" +
+	"\x02to\x18\x02 \x01(\x0e2\x0f.api.TaskStatusR\x02to\x12*\n" +
+	"\x02at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x14\n" +
+	"\x05actor\x18\x04 \x01(\tR\x05actor\"\xac\x04\n" +
+	"\x04Task\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x0f.api.TaskStatusR\x06status\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x19\n" +
+	"\bagent_id\x18\x04 \x01(\tR\aagentId\x12'\n" +
+	"\x06action\x18\x05 \x01(\x0e2\x0f.api.TaskActionR\x06action\x12)\n" +
+	"\x10configuration_id\x18\x06 \x01(\tR\x0fconfigurationId\x12\x18\n" +
+	"\apayload\x18\a \x01(\fR\apayload\x12\x16\n" +
+	"\x06result\x18\b \x01(\fR\x06result\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"started_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12\x1a\n" +
+	"\battempts\x18\f \x01(\rR\battempts\x12\x14\n" +
+	"\x05error\x18\r \x01(\tR\x05error\x125\n" +
+	"\vtransitions\x18\x0e \x03(\v2\x13.api.TaskTransitionR\vtransitions\x12\x1a\n" +
+	"\bpriority\x18\x0f \x01(\x05R\bpriority\"$\n" +
+	"\x12GetTaskByIdRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"4\n" +
+	"\x13GetTaskByIdResponse\x12\x1d\n" +
+	"\x04task\x18\x01 \x01(\v2\t.api.TaskR\x04task\"\xe6\x02\n" +
+	"\x12GetAllTasksRequest\x12*\n" +
+	"\aoptions\x18\x01 \x01(\v2\x10.api.ListOptionsR\aoptions\x12'\n" +
+	"\x06status\x18\x02 \x03(\x0e2\x0f.api.TaskStatusR\x06status\x12\x12\n" +
+	"\x04type\x18\x03 \x03(\tR\x04type\x12'\n" +
+	"\x06action\x18\x04 \x03(\x0e2\x0f.api.TaskActionR\x06action\x12\x19\n" +
+	"\bagent_id\x18\x05 \x03(\tR\aagentId\x12)\n" +
+	"\x10configuration_id\x18\x06 \x03(\tR\x0fconfigurationId\x12=\n" +
+	"\fcreated_from\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\"W\n" +
+	"\x13GetAllTasksResponse\x12\x1f\n" +
+	"\x05tasks\x18\x01 \x03(\v2\t.api.TaskR\x05tasks\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"0\n" +
 	"\x0fPostTaskRequest\x12\x1d\n" +
 	"\x04task\x18\x01 \x01(\v2\t.api.TaskR\x04task\"\x12\n" +
 	"\x10PostTaskResponse\"?\n" +
@@ -578,20 +1428,65 @@ const file_task_proto_rawDesc = "" +
 	"\x0fPutTaskResponse\"#\n" +
 	"\x11DeleteTaskRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
-	"\x12DeleteTaskResponse*\x8b\x01\n" +
+	"\x12DeleteTaskResponse\"\x9f\x01\n" +
+	"\x11LeaseTasksRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05types\x18\x02 \x03(\tR\x05types\x12\x1b\n" +
+	"\tmax_tasks\x18\x03 \x01(\rR\bmaxTasks\x12<\n" +
+	"\x1avisibility_timeout_seconds\x18\x04 \x01(\rR\x18visibilityTimeoutSeconds\"5\n" +
+	"\x12LeaseTasksResponse\x12\x1f\n" +
+	"\x05tasks\x18\x01 \x03(\v2\t.api.TaskR\x05tasks\"\xd3\x01\n" +
+	"\x11ReportTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12'\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x0f.api.TaskStatusR\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12<\n" +
+	"\x1avisibility_timeout_seconds\x18\x05 \x01(\rR\x18visibilityTimeoutSeconds\x12\x16\n" +
+	"\x06result\x18\x06 \x01(\fR\x06result\"\x14\n" +
+	"\x12ReportTaskResponse\"\x17\n" +
+	"\x15GetDeadLettersRequest\"9\n" +
+	"\x16GetDeadLettersResponse\x12\x1f\n" +
+	"\x05tasks\x18\x01 \x03(\v2\t.api.TaskR\x05tasks\"*\n" +
+	"\x18RequeueDeadLetterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1b\n" +
+	"\x19RequeueDeadLetterResponse\"/\n" +
+	"\x11WatchTasksRequest\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\tR\brevision\"\x88\x01\n" +
+	"\x12WatchTasksResponse\x12'\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x13.api.WatchEventTypeR\x04type\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\tR\brevision\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x1d\n" +
+	"\x04task\x18\x04 \x01(\v2\t.api.TaskR\x04task*\xd9\x01\n" +
 	"\n" +
 	"TaskStatus\x12\x1b\n" +
 	"\x17TASK_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
 	"\x12TASK_STATUS_QUEUED\x10\x01\x12\x1b\n" +
 	"\x17TASK_STATUS_IN_PROGRESS\x10\x02\x12\x12\n" +
 	"\x0eTASK_STATUS_OK\x10\x03\x12\x17\n" +
-	"\x13TASK_STATUS_DELETED\x10\x042\xaa\x02\n" +
+	"\x13TASK_STATUS_DELETED\x10\x04\x12\x16\n" +
+	"\x12TASK_STATUS_FAILED\x10\x05\x12\x19\n" +
+	"\x15TASK_STATUS_CANCELLED\x10\x06\x12\x19\n" +
+	"\x15TASK_STATUS_TIMED_OUT\x10\a*\xb7\x01\n" +
+	"\n" +
+	"TaskAction\x12\x1b\n" +
+	"\x17TASK_ACTION_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12TASK_ACTION_DEPLOY\x10\x01\x12\x15\n" +
+	"\x11TASK_ACTION_START\x10\x02\x12\x14\n" +
+	"\x10TASK_ACTION_STOP\x10\x03\x12\x17\n" +
+	"\x13TASK_ACTION_RESTART\x10\x04\x12\x16\n" +
+	"\x12TASK_ACTION_BACKUP\x10\x05\x12\x16\n" +
+	"\x12TASK_ACTION_DELETE\x10\x062\xfa\x04\n" +
 	"\vTaskService\x12<\n" +
 	"\aGetById\x12\x17.api.GetTaskByIdRequest\x1a\x18.api.GetTaskByIdResponse\x12;\n" +
 	"\x06GetAll\x12\x17.api.GetAllTasksRequest\x1a\x18.api.GetAllTasksResponse\x123\n" +
 	"\x04Post\x12\x14.api.PostTaskRequest\x1a\x15.api.PostTaskResponse\x120\n" +
 	"\x03Put\x12\x13.api.PutTaskRequest\x1a\x14.api.PutTaskResponse\x129\n" +
-	"\x06Delete\x12\x16.api.DeleteTaskRequest\x1a\x17.api.DeleteTaskResponseB/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
+	"\x06Delete\x12\x16.api.DeleteTaskRequest\x1a\x17.api.DeleteTaskResponse\x128\n" +
+	"\x05Lease\x12\x16.api.LeaseTasksRequest\x1a\x17.api.LeaseTasksResponse\x129\n" +
+	"\x06Report\x12\x16.api.ReportTaskRequest\x1a\x17.api.ReportTaskResponse\x12I\n" +
+	"\x0eGetDeadLetters\x12\x1a.api.GetDeadLettersRequest\x1a\x1b.api.GetDeadLettersResponse\x12R\n" +
+	"\x11RequeueDeadLetter\x12\x1d.api.RequeueDeadLetterRequest\x1a\x1e.api.RequeueDeadLetterResponse\x12:\n" +
+	"\x05Watch\x12\x16.api.WatchTasksRequest\x1a\x17.api.WatchTasksResponse0\x01B/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
 
 var (
 	file_task_proto_rawDescOnce sync.Once
@@ -605,43 +1500,86 @@ func file_task_proto_rawDescGZIP() []byte {
 	return file_task_proto_rawDescData
 }
 
-var file_task_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_task_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_task_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_task_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_task_proto_goTypes = []any{
-	(TaskStatus)(0),             // 0: api.TaskStatus
-	(*Task)(nil),                // 1: api.Task
-	(*GetTaskByIdRequest)(nil),  // 2: api.GetTaskByIdRequest
-	(*GetTaskByIdResponse)(nil), // 3: api.GetTaskByIdResponse
-	(*GetAllTasksRequest)(nil),  // 4: api.GetAllTasksRequest
-	(*GetAllTasksResponse)(nil), // 5: api.GetAllTasksResponse
-	(*PostTaskRequest)(nil),     // 6: api.PostTaskRequest
-	(*PostTaskResponse)(nil),    // 7: api.PostTaskResponse
-	(*PutTaskRequest)(nil),      // 8: api.PutTaskRequest
-	(*PutTaskResponse)(nil),     // 9: api.PutTaskResponse
-	(*DeleteTaskRequest)(nil),   // 10: api.DeleteTaskRequest
-	(*DeleteTaskResponse)(nil),  // 11: api.DeleteTaskResponse
+	(TaskStatus)(0),                   // 0: api.TaskStatus
+	(TaskAction)(0),                   // 1: api.TaskAction
+	(*TaskTransition)(nil),            // 2: api.TaskTransition
+	(*Task)(nil),                      // 3: api.Task
+	(*GetTaskByIdRequest)(nil),        // 4: api.GetTaskByIdRequest
+	(*GetTaskByIdResponse)(nil),       // 5: api.GetTaskByIdResponse
+	(*GetAllTasksRequest)(nil),        // 6: api.GetAllTasksRequest
+	(*GetAllTasksResponse)(nil),       // 7: api.GetAllTasksResponse
+	(*PostTaskRequest)(nil),           // 8: api.PostTaskRequest
+	(*PostTaskResponse)(nil),          // 9: api.PostTaskResponse
+	(*PutTaskRequest)(nil),            // 10: api.PutTaskRequest
+	(*PutTaskResponse)(nil),           // 11: api.PutTaskResponse
+	(*DeleteTaskRequest)(nil),         // 12: api.DeleteTaskRequest
+	(*DeleteTaskResponse)(nil),        // 13: api.DeleteTaskResponse
+	(*LeaseTasksRequest)(nil),         // 14: api.LeaseTasksRequest
+	(*LeaseTasksResponse)(nil),        // 15: api.LeaseTasksResponse
+	(*ReportTaskRequest)(nil),         // 16: api.ReportTaskRequest
+	(*ReportTaskResponse)(nil),        // 17: api.ReportTaskResponse
+	(*GetDeadLettersRequest)(nil),     // 18: api.GetDeadLettersRequest
+	(*GetDeadLettersResponse)(nil),    // 19: api.GetDeadLettersResponse
+	(*RequeueDeadLetterRequest)(nil),  // 20: api.RequeueDeadLetterRequest
+	(*RequeueDeadLetterResponse)(nil), // 21: api.RequeueDeadLetterResponse
+	(*WatchTasksRequest)(nil),         // 22: api.WatchTasksRequest
+	(*WatchTasksResponse)(nil),        // 23: api.WatchTasksResponse
+	(*timestamppb.Timestamp)(nil),     // 24: google.protobuf.Timestamp
+	(*ListOptions)(nil),               // 25: api.ListOptions
+	(WatchEventType)(0),               // 26: api.WatchEventType
 }
 var file_task_proto_depIdxs = []int32{
-	0,  // 0: api.Task.status:type_name -> api.TaskStatus
-	1,  // 1: api.GetTaskByIdResponse.task:type_name -> api.Task
-	1,  // 2: api.GetAllTasksResponse.tasks:type_name -> api.Task
-	1,  // 3: api.PostTaskRequest.task:type_name -> api.Task
-	1,  // 4: api.PutTaskRequest.task:type_name -> api.Task
-	2,  // 5: api.TaskService.GetById:input_type -> api.GetTaskByIdRequest
-	4,  // 6: api.TaskService.GetAll:input_type -> api.GetAllTasksRequest
-	6,  // 7: api.TaskService.Post:input_type -> api.PostTaskRequest
-	8,  // 8: api.TaskService.Put:input_type -> api.PutTaskRequest
-	10, // 9: api.TaskService.Delete:input_type -> api.DeleteTaskRequest
-	3,  // 10: api.TaskService.GetById:output_type -> api.GetTaskByIdResponse
-	5,  // 11: api.TaskService.GetAll:output_type -> api.GetAllTasksResponse
-	7,  // 12: api.TaskService.Post:output_type -> api.PostTaskResponse
-	9,  // 13: api.TaskService.Put:output_type -> api.PutTaskResponse
-	11, // 14: api.TaskService.Delete:output_type -> api.DeleteTaskResponse
-	10, // [10:15] is the sub-list for method output_type
-	5,  // [5:10] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	0,  // 0: api.TaskTransition.from:type_name -> api.TaskStatus
+	0,  // 1: api.TaskTransition.to:type_name -> api.TaskStatus
+	24, // 2: api.TaskTransition.at:type_name -> google.protobuf.Timestamp
+	0,  // 3: api.Task.status:type_name -> api.TaskStatus
+	1,  // 4: api.Task.action:type_name -> api.TaskAction
+	24, // 5: api.Task.created_at:type_name -> google.protobuf.Timestamp
+	24, // 6: api.Task.started_at:type_name -> google.protobuf.Timestamp
+	24, // 7: api.Task.finished_at:type_name -> google.protobuf.Timestamp
+	2,  // 8: api.Task.transitions:type_name -> api.TaskTransition
+	3,  // 9: api.GetTaskByIdResponse.task:type_name -> api.Task
+	25, // 10: api.GetAllTasksRequest.options:type_name -> api.ListOptions
+	0,  // 11: api.GetAllTasksRequest.status:type_name -> api.TaskStatus
+	1,  // 12: api.GetAllTasksRequest.action:type_name -> api.TaskAction
+	24, // 13: api.GetAllTasksRequest.created_from:type_name -> google.protobuf.Timestamp
+	24, // 14: api.GetAllTasksRequest.created_to:type_name -> google.protobuf.Timestamp
+	3,  // 15: api.GetAllTasksResponse.tasks:type_name -> api.Task
+	3,  // 16: api.PostTaskRequest.task:type_name -> api.Task
+	3,  // 17: api.PutTaskRequest.task:type_name -> api.Task
+	3,  // 18: api.LeaseTasksResponse.tasks:type_name -> api.Task
+	0,  // 19: api.ReportTaskRequest.status:type_name -> api.TaskStatus
+	3,  // 20: api.GetDeadLettersResponse.tasks:type_name -> api.Task
+	26, // 21: api.WatchTasksResponse.type:type_name -> api.WatchEventType
+	3,  // 22: api.WatchTasksResponse.task:type_name -> api.Task
+	4,  // 23: api.TaskService.GetById:input_type -> api.GetTaskByIdRequest
+	6,  // 24: api.TaskService.GetAll:input_type -> api.GetAllTasksRequest
+	8,  // 25: api.TaskService.Post:input_type -> api.PostTaskRequest
+	10, // 26: api.TaskService.Put:input_type -> api.PutTaskRequest
+	12, // 27: api.TaskService.Delete:input_type -> api.DeleteTaskRequest
+	14, // 28: api.TaskService.Lease:input_type -> api.LeaseTasksRequest
+	16, // 29: api.TaskService.Report:input_type -> api.ReportTaskRequest
+	18, // 30: api.TaskService.GetDeadLetters:input_type -> api.GetDeadLettersRequest
+	20, // 31: api.TaskService.RequeueDeadLetter:input_type -> api.RequeueDeadLetterRequest
+	22, // 32: api.TaskService.Watch:input_type -> api.WatchTasksRequest
+	5,  // 33: api.TaskService.GetById:output_type -> api.GetTaskByIdResponse
+	7,  // 34: api.TaskService.GetAll:output_type -> api.GetAllTasksResponse
+	9,  // 35: api.TaskService.Post:output_type -> api.PostTaskResponse
+	11, // 36: api.TaskService.Put:output_type -> api.PutTaskResponse
+	13, // 37: api.TaskService.Delete:output_type -> api.DeleteTaskResponse
+	15, // 38: api.TaskService.Lease:output_type -> api.LeaseTasksResponse
+	17, // 39: api.TaskService.Report:output_type -> api.ReportTaskResponse
+	19, // 40: api.TaskService.GetDeadLetters:output_type -> api.GetDeadLettersResponse
+	21, // 41: api.TaskService.RequeueDeadLetter:output_type -> api.RequeueDeadLetterResponse
+	23, // 42: api.TaskService.Watch:output_type -> api.WatchTasksResponse
+	33, // [33:43] is the sub-list for method output_type
+	23, // [23:33] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_task_proto_init() }
@@ -649,13 +1587,15 @@ func file_task_proto_init() {
 	if File_task_proto != nil {
 		return
 	}
+	file_watch_proto_init()
+	file_list_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_task_proto_rawDesc), len(file_task_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   11,
+			NumEnums:      2,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,11 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TaskService_GetById_FullMethodName = "/api.TaskService/GetById"
-	TaskService_GetAll_FullMethodName  = "/api.TaskService/GetAll"
-	TaskService_Post_FullMethodName    = "/api.TaskService/Post"
-	TaskService_Put_FullMethodName     = "/api.TaskService/Put"
-	TaskService_Delete_FullMethodName  = "/api.TaskService/Delete"
+	TaskService_GetById_FullMethodName           = "/api.TaskService/GetById"
+	TaskService_GetAll_FullMethodName            = "/api.TaskService/GetAll"
+	TaskService_Post_FullMethodName              = "/api.TaskService/Post"
+	TaskService_Put_FullMethodName               = "/api.TaskService/Put"
+	TaskService_Delete_FullMethodName            = "/api.TaskService/Delete"
+	TaskService_Lease_FullMethodName             = "/api.TaskService/Lease"
+	TaskService_Report_FullMethodName            = "/api.TaskService/Report"
+	TaskService_GetDeadLetters_FullMethodName    = "/api.TaskService/GetDeadLetters"
+	TaskService_RequeueDeadLetter_FullMethodName = "/api.TaskService/RequeueDeadLetter"
+	TaskService_Watch_FullMethodName             = "/api.TaskService/Watch"
 )
 
 // TaskServiceClient is the client API for TaskService service.
@@ -35,6 +40,11 @@ type TaskServiceClient interface {
 	Post(ctx context.Context, in *PostTaskRequest, opts ...grpc.CallOption) (*PostTaskResponse, error)
 	Put(ctx context.Context, in *PutTaskRequest, opts ...grpc.CallOption) (*PutTaskResponse, error)
 	Delete(ctx context.Context, in *DeleteTaskRequest, opts ...grpc.CallOption) (*DeleteTaskResponse, error)
+	Lease(ctx context.Context, in *LeaseTasksRequest, opts ...grpc.CallOption) (*LeaseTasksResponse, error)
+	Report(ctx context.Context, in *ReportTaskRequest, opts ...grpc.CallOption) (*ReportTaskResponse, error)
+	GetDeadLetters(ctx context.Context, in *GetDeadLettersRequest, opts ...grpc.CallOption) (*GetDeadLettersResponse, error)
+	RequeueDeadLetter(ctx context.Context, in *RequeueDeadLetterRequest, opts ...grpc.CallOption) (*RequeueDeadLetterResponse, error)
+	Watch(ctx context.Context, in *WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchTasksResponse], error)
 }
 
 type taskServiceClient struct {
@@ -95,6 +105,65 @@ func (c *taskServiceClient) Delete(ctx context.Context, in *DeleteTaskRequest, o
 	return out, nil
 }
 
+func (c *taskServiceClient) Lease(ctx context.Context, in *LeaseTasksRequest, opts ...grpc.CallOption) (*LeaseTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaseTasksResponse)
+	err := c.cc.Invoke(ctx, TaskService_Lease_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) Report(ctx context.Context, in *ReportTaskRequest, opts ...grpc.CallOption) (*ReportTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportTaskResponse)
+	err := c.cc.Invoke(ctx, TaskService_Report_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetDeadLetters(ctx context.Context, in *GetDeadLettersRequest, opts ...grpc.CallOption) (*GetDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeadLettersResponse)
+	err := c.cc.Invoke(ctx, TaskService_GetDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) RequeueDeadLetter(ctx context.Context, in *RequeueDeadLetterRequest, opts ...grpc.CallOption) (*RequeueDeadLetterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequeueDeadLetterResponse)
+	err := c.cc.Invoke(ctx, TaskService_RequeueDeadLetter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) Watch(ctx context.Context, in *WatchTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchTasksResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TaskService_ServiceDesc.Streams[0], TaskService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTasksRequest, WatchTasksResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TaskService_WatchClient = grpc.ServerStreamingClient[WatchTasksResponse]
+
 // TaskServiceServer is the server API for TaskService service.
 // All implementations must embed UnimplementedTaskServiceServer
 // for forward compatibility.
@@ -104,6 +173,11 @@ type TaskServiceServer interface {
 	Post(context.Context, *PostTaskRequest) (*PostTaskResponse, error)
 	Put(context.Context, *PutTaskRequest) (*PutTaskResponse, error)
 	Delete(context.Context, *DeleteTaskRequest) (*DeleteTaskResponse, error)
+	Lease(context.Context, *LeaseTasksRequest) (*LeaseTasksResponse, error)
+	Report(context.Context, *ReportTaskRequest) (*ReportTaskResponse, error)
+	GetDeadLetters(context.Context, *GetDeadLettersRequest) (*GetDeadLettersResponse, error)
+	RequeueDeadLetter(context.Context, *RequeueDeadLetterRequest) (*RequeueDeadLetterResponse, error)
+	Watch(*WatchTasksRequest, grpc.ServerStreamingServer[WatchTasksResponse]) error
 	mustEmbedUnimplementedTaskServiceServer()
 }
 
@@ -129,6 +203,21 @@ func (UnimplementedTaskServiceServer) Put(context.Context, *PutTaskRequest) (*Pu
 func (UnimplementedTaskServiceServer) Delete(context.Context, *DeleteTaskRequest) (*DeleteTaskResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedTaskServiceServer) Lease(context.Context, *LeaseTasksRequest) (*LeaseTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lease not implemented")
+}
+func (UnimplementedTaskServiceServer) Report(context.Context, *ReportTaskRequest) (*ReportTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Report not implemented")
+}
+func (UnimplementedTaskServiceServer) GetDeadLetters(context.Context, *GetDeadLettersRequest) (*GetDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeadLetters not implemented")
+}
+func (UnimplementedTaskServiceServer) RequeueDeadLetter(context.Context, *RequeueDeadLetterRequest) (*RequeueDeadLetterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequeueDeadLetter not implemented")
+}
+func (UnimplementedTaskServiceServer) Watch(*WatchTasksRequest, grpc.ServerStreamingServer[WatchTasksResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedTaskServiceServer) mustEmbedUnimplementedTaskServiceServer() {}
 func (UnimplementedTaskServiceServer) testEmbeddedByValue()                     {}
 
@@ -240,6 +329,89 @@ func _TaskService_Delete_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TaskService_Lease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).Lease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_Lease_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).Lease(ctx, req.(*LeaseTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_Report_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).Report(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_Report_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).Report(ctx, req.(*ReportTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_GetDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetDeadLetters(ctx, req.(*GetDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_RequeueDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequeueDeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).RequeueDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_RequeueDeadLetter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).RequeueDeadLetter(ctx, req.(*RequeueDeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTasksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskServiceServer).Watch(m, &grpc.GenericServerStream[WatchTasksRequest, WatchTasksResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TaskService_WatchServer = grpc.ServerStreamingServer[WatchTasksResponse]
+
 // TaskService_ServiceDesc is the grpc.ServiceDesc for TaskService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,7 +439,29 @@ var TaskService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _TaskService_Delete_Handler,
 		},
+		{
+			MethodName: "Lease",
+			Handler:    _TaskService_Lease_Handler,
+		},
+		{
+			MethodName: "Report",
+			Handler:    _TaskService_Report_Handler,
+		},
+		{
+			MethodName: "GetDeadLetters",
+			Handler:    _TaskService_GetDeadLetters_Handler,
+		},
+		{
+			MethodName: "RequeueDeadLetter",
+			Handler:    _TaskService_RequeueDeadLetter_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _TaskService_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "task.proto",
 }
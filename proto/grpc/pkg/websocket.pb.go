@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: websocket.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FrameType int32
+
+const (
+	FrameType_FRAME_TYPE_UNSPECIFIED FrameType = 0
+	FrameType_FRAME_TYPE_OPEN        FrameType = 1
+	FrameType_FRAME_TYPE_MESSAGE     FrameType = 2
+	FrameType_FRAME_TYPE_HALF_CLOSE  FrameType = 3
+	FrameType_FRAME_TYPE_END         FrameType = 4
+	FrameType_FRAME_TYPE_CANCEL      FrameType = 5
+)
+
+// Enum value maps for FrameType.
+var (
+	FrameType_name = map[int32]string{
+		0: "FRAME_TYPE_UNSPECIFIED",
+		1: "FRAME_TYPE_OPEN",
+		2: "FRAME_TYPE_MESSAGE",
+		3: "FRAME_TYPE_HALF_CLOSE",
+		4: "FRAME_TYPE_END",
+		5: "FRAME_TYPE_CANCEL",
+	}
+	FrameType_value = map[string]int32{
+		"FRAME_TYPE_UNSPECIFIED": 0,
+		"FRAME_TYPE_OPEN":        1,
+		"FRAME_TYPE_MESSAGE":     2,
+		"FRAME_TYPE_HALF_CLOSE":  3,
+		"FRAME_TYPE_END":         4,
+		"FRAME_TYPE_CANCEL":      5,
+	}
+)
+
+func (x FrameType) Enum() *FrameType {
+	p := new(FrameType)
+	*p = x
+	return p
+}
+
+func (x FrameType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FrameType) Descriptor() protoreflect.EnumDescriptor {
+	return file_websocket_proto_enumTypes[0].Descriptor()
+}
+
+func (FrameType) Type() protoreflect.EnumType {
+	return &file_websocket_proto_enumTypes[0]
+}
+
+func (x FrameType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FrameType.Descriptor instead.
+func (FrameType) EnumDescriptor() ([]byte, []int) {
+	return file_websocket_proto_rawDescGZIP(), []int{0}
+}
+
+type Frame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CallId        uint64                 `protobuf:"varint,1,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"`
+	Type          FrameType              `protobuf:"varint,2,opt,name=type,proto3,enum=api.FrameType" json:"type,omitempty"`
+	Method        string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Payload       []byte                 `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
+	StatusCode    int32                  `protobuf:"varint,6,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	StatusMessage string                 `protobuf:"bytes,7,opt,name=status_message,json=statusMessage,proto3" json:"status_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	mi := &file_websocket_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_websocket_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Frame) GetCallId() uint64 {
+	if x != nil {
+		return x.CallId
+	}
+	return 0
+}
+
+func (x *Frame) GetType() FrameType {
+	if x != nil {
+		return x.Type
+	}
+	return FrameType_FRAME_TYPE_UNSPECIFIED
+}
+
+func (x *Frame) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Frame) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Frame) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Frame) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *Frame) GetStatusMessage() string {
+	if x != nil {
+		return x.StatusMessage
+	}
+	return ""
+}
+
+var File_websocket_proto protoreflect.FileDescriptor
+
+const file_websocket_proto_rawDesc = "" +
+	"\n" +
+	"\x0fwebsocket.proto\x12\x03api\"\xb1\x02\n" +
+	"\x05Frame\x12\x17\n" +
+	"\acall_id\x18\x01 \x01(\x04R\x06callId\x12\"\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x0e.api.FrameTypeR\x04type\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x124\n" +
+	"\bmetadata\x18\x04 \x03(\v2\x18.api.Frame.MetadataEntryR\bmetadata\x12\x18\n" +
+	"\apayload\x18\x05 \x01(\fR\apayload\x12\x1f\n" +
+	"\vstatus_code\x18\x06 \x01(\x05R\n" +
+	"statusCode\x12%\n" +
+	"\x0estatus_message\x18\a \x01(\tR\rstatusMessage\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\x9a\x01\n" +
+	"\tFrameType\x12\x1a\n" +
+	"\x16FRAME_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fFRAME_TYPE_OPEN\x10\x01\x12\x16\n" +
+	"\x12FRAME_TYPE_MESSAGE\x10\x02\x12\x19\n" +
+	"\x15FRAME_TYPE_HALF_CLOSE\x10\x03\x12\x12\n" +
+	"\x0eFRAME_TYPE_END\x10\x04\x12\x15\n" +
+	"\x11FRAME_TYPE_CANCEL\x10\x05B/Z-github.com/vv-sam/otus-project/proto/grpc/apib\x06proto3"
+
+var (
+	file_websocket_proto_rawDescOnce sync.Once
+	file_websocket_proto_rawDescData []byte
+)
+
+func file_websocket_proto_rawDescGZIP() []byte {
+	file_websocket_proto_rawDescOnce.Do(func() {
+		file_websocket_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_websocket_proto_rawDesc), len(file_websocket_proto_rawDesc)))
+	})
+	return file_websocket_proto_rawDescData
+}
+
+var file_websocket_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_websocket_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_websocket_proto_goTypes = []any{
+	(FrameType)(0), // 0: api.FrameType
+	(*Frame)(nil),  // 1: api.Frame
+	nil,            // 2: api.Frame.MetadataEntry
+}
+var file_websocket_proto_depIdxs = []int32{
+	0, // 0: api.Frame.type:type_name -> api.FrameType
+	2, // 1: api.Frame.metadata:type_name -> api.Frame.MetadataEntry
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_websocket_proto_init() }
+func file_websocket_proto_init() {
+	if File_websocket_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_websocket_proto_rawDesc), len(file_websocket_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_websocket_proto_goTypes,
+		DependencyIndexes: file_websocket_proto_depIdxs,
+		EnumInfos:         file_websocket_proto_enumTypes,
+		MessageInfos:      file_websocket_proto_msgTypes,
+	}.Build()
+	File_websocket_proto = out.File
+	file_websocket_proto_goTypes = nil
+	file_websocket_proto_depIdxs = nil
+}
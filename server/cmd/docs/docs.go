@@ -17,7 +17,7 @@ const docTemplate = `{
     "paths": {
         "/api/agents": {
             "get": {
-                "description": "Get all agents",
+                "description": "Get a page of agents ordered by id by default. Filters accept comma separated values",
                 "consumes": [
                     "application/json"
                 ],
@@ -27,7 +27,39 @@ const docTemplate = `{
                 "tags": [
                     "agents"
                 ],
-                "summary": "Get all agents",
+                "summary": "Get agents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locations",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, at most 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from the X-Next-Cursor header",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -36,8 +68,18 @@ const docTemplate = `{
                             "items": {
                                 "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_agent.Info"
                             }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page, absent on the last page"
+                            }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -87,6 +129,52 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/agents/enroll": {
+            "post": {
+                "description": "Exchange a join token and a PEM certificate signing request for a client certificate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agents"
+                ],
+                "summary": "Enroll agent",
+                "parameters": [
+                    {
+                        "description": "Join token and CSR",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server_internal_handlers.enrollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server_internal_handlers.enrollResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
         "/api/agents/history": {
             "get": {
                 "description": "Get history",
@@ -117,6 +205,52 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/agents/join-tokens": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a one-time token an agent exchanges for a client certificate. The token is shown only once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agents"
+                ],
+                "summary": "Create join token",
+                "parameters": [
+                    {
+                        "description": "Token lifetime and optional agent id to re-enroll",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/server_internal_handlers.joinTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/server_internal_handlers.joinTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
         "/api/agents/{id}": {
             "get": {
                 "description": "Get agent by id",
@@ -254,6 +388,98 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/agents/{id}/certificates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get client certificates issued to the agent, including revoked ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agents"
+                ],
+                "summary": "Get agent certificates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agent ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_agent.Certificate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/agents/{id}/certificates/{certId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke a client certificate. The agent can't connect with it anymore",
+                "tags": [
+                    "agents"
+                ],
+                "summary": "Revoke agent certificate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agent ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Certificate ID",
+                        "name": "certId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
         "/api/auth/login": {
             "post": {
                 "description": "Login and get a token",
@@ -298,7 +524,7 @@ const docTemplate = `{
         },
         "/api/configurations": {
             "get": {
-                "description": "Get all configurations",
+                "description": "Get a page of configurations ordered by id by default. Each item is shaped according to its type field (factorio or minecraft). Filters accept comma separated values",
                 "consumes": [
                     "application/json"
                 ],
@@ -308,7 +534,39 @@ const docTemplate = `{
                 "tags": [
                     "configurations"
                 ],
-                "summary": "Get all configurations",
+                "summary": "Get configurations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Types",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent IDs",
+                        "name": "agent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, at most 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from the X-Next-Cursor header",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -317,8 +575,18 @@ const docTemplate = `{
                             "items": {
                                 "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.Factorio"
                             }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page, absent on the last page"
+                            }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -331,7 +599,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new configuration",
+                "description": "Create a new configuration. The type field selects the body shape: factorio (configuration.Factorio) or minecraft (configuration.Minecraft)",
                 "consumes": [
                     "application/json"
                 ],
@@ -361,6 +629,10 @@ const docTemplate = `{
                         "description": "Bad Request",
                         "schema": {}
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -398,9 +670,51 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/configurations/placement": {
+            "post": {
+                "description": "Show which agent the scheduler would pick for a configuration without an agent_id, or why each agent was rejected. Nothing is saved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Explain placement",
+                "parameters": [
+                    {
+                        "description": "Configuration",
+                        "name": "configuration",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.Factorio"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_placement.Decision"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
         "/api/configurations/{id}": {
             "get": {
-                "description": "Get configuration by id",
+                "description": "Get configuration by id. The shape of the response depends on its type field (factorio or minecraft)",
                 "consumes": [
                     "application/json"
                 ],
@@ -447,7 +761,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a configuration",
+                "description": "Update a configuration. The type field selects the body shape: factorio (configuration.Factorio) or minecraft (configuration.Minecraft)",
                 "consumes": [
                     "application/json"
                 ],
@@ -488,6 +802,10 @@ const docTemplate = `{
                         "description": "Not Found",
                         "schema": {}
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -539,26 +857,93 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tasks": {
+        "/api/events": {
             "get": {
-                "description": "Get all tasks",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Server-Sent Events stream of domain events: agent status, task transitions, configuration and server changes.\nEvery event is sent with its id, type as the SSE event name and a JSON body. Reconnect with Last-Event-ID to get missed events.\nWith group and consumer the events are shared between consumers of the group and the position is kept on the server",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "tasks"
+                    "events"
                 ],
-                "summary": "Get all tasks",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Task"
+                "summary": "Subscribe to events",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Event types or groups, e.g. agent, task.status_changed",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of the last received event",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consumer group",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consumer name, required with group",
+                        "name": "consumer",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_event.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "410": {
+                        "description": "Events after Last-Event-ID are no longer kept, reconnect without it",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/servers": {
+            "get": {
+                "description": "Get all game servers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "servers"
+                ],
+                "summary": "Get all servers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_server.Server"
                             }
                         }
                     },
@@ -574,7 +959,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new task",
+                "description": "Deploy a configuration to its agent. Only id (the configuration id) and desired_state (running by default) are used",
                 "consumes": [
                     "application/json"
                 ],
@@ -582,17 +967,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "servers"
                 ],
-                "summary": "Create a new task",
+                "summary": "Create a server",
                 "parameters": [
                     {
-                        "description": "Task",
-                        "name": "task",
+                        "description": "Server",
+                        "name": "server",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Task"
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_server.Server"
                         }
                     }
                 ],
@@ -604,6 +989,10 @@ const docTemplate = `{
                         "description": "Bad Request",
                         "schema": {}
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -611,7 +1000,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tasks/history": {
+        "/api/servers/health": {
+            "get": {
+                "description": "Get the latest check results of all game servers deployed to agents",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "servers"
+                ],
+                "summary": "Get health of all servers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_health.Health"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/servers/history": {
             "get": {
                 "description": "Get history",
                 "consumes": [
@@ -621,7 +1040,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "servers"
                 ],
                 "summary": "Get history",
                 "responses": {
@@ -641,9 +1060,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tasks/{id}": {
+        "/api/servers/{id}": {
             "get": {
-                "description": "Get task by id",
+                "description": "Get a game server with its desired and observed state. The id is the configuration id",
                 "consumes": [
                     "application/json"
                 ],
@@ -651,13 +1070,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "servers"
                 ],
-                "summary": "Get task by id",
+                "summary": "Get server by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
+                        "description": "Server ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -667,7 +1086,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Task"
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_server.Server"
                         }
                     },
                     "400": {
@@ -690,7 +1109,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a task",
+                "description": "Set desired_state to running or stopped. Other fields are ignored",
                 "consumes": [
                     "application/json"
                 ],
@@ -698,24 +1117,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "servers"
                 ],
-                "summary": "Update a task",
+                "summary": "Change server desired state",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
+                        "description": "Server ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Task",
-                        "name": "task",
+                        "description": "Server",
+                        "name": "server",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Task"
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_server.Server"
                         }
                     }
                 ],
@@ -731,6 +1150,10 @@ const docTemplate = `{
                         "description": "Not Found",
                         "schema": {}
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -743,7 +1166,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a task",
+                "description": "Set desired_state to deleted. The server disappears once its container is removed from the agent",
                 "consumes": [
                     "application/json"
                 ],
@@ -751,21 +1174,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "servers"
                 ],
-                "summary": "Delete a task",
+                "summary": "Delete a server",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
+                        "description": "Server ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "202": {
+                        "description": "Accepted"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -781,75 +1204,709 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_vv-sam_otus-project_server_internal_model_agent.Info": {
-            "type": "object",
-            "properties": {
-                "agent_id": {
-                    "type": "string"
-                },
-                "metrics": {
-                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_metrics.HostMetrics"
-                },
-                "status": {
-                    "type": "integer"
-                },
-                "tasks": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Task"
+        },
+        "/api/servers/{id}/console": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Run a command in the remote console (RCON) of a game server, e.g. \"/whitelist add Steve\". The server id is the id of its configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "servers"
+                ],
+                "summary": "Run console command",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Server ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Command",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server_internal_handlers.consoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server_internal_handlers.consoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {}
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {}
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {}
                     }
                 }
             }
         },
-        "github_com_vv-sam_otus-project_server_internal_model_configuration.Factorio": {
-            "type": "object",
-            "properties": {
-                "agent_id": {
-                    "description": "ID агента для запуска задачи",
-                    "type": "string"
-                },
-                "id": {
-                    "description": "уникальный ID конфигурации",
-                    "type": "string"
-                },
-                "map_gen_settings": {
-                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.MapGenSettings"
-                },
-                "map_settings": {
-                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.MapSettings"
-                },
-                "port": {
-                    "description": "Порт сервера",
-                    "type": "integer"
-                },
-                "server_settings": {
-                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.ServerSetting"
-                },
-                "type": {
-                    "description": "Тип конфигурации",
-                    "type": "string"
+        "/api/servers/{id}/health": {
+            "get": {
+                "description": "Get the result of the latest player-side check of a game server (0 - unknown, 1 - healthy, 2 - unhealthy). The server id is the id of its configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "servers"
+                ],
+                "summary": "Get server health",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Server ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_health.Health"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
                 }
             }
         },
-        "github_com_vv-sam_otus-project_server_internal_model_configuration.MapGenSettings": {
-            "type": "object",
-            "properties": {
-                "autoplace_controls": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.resource"
+        "/api/servers/{id}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                },
-                "cliff_settings": {
-                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.cliff"
-                },
-                "height": {
-                    "type": "integer"
-                },
-                "peaceful_mode": {
+                ],
+                "description": "Get output of a game server. The server id is the id of its configuration.\nWith Accept: text/event-stream lines are sent as SSE \"log\" events with a JSON body, otherwise as chunked plain text \"\u003ctime\u003e \u003ctext\u003e\" lines",
+                "produces": [
+                    "text/plain",
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "servers"
+                ],
+                "summary": "Get server logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Server ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time or a duration back from now, e.g. 10m",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of last lines, all by default",
+                        "name": "tail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Keep streaming new lines",
+                        "name": "follow",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server_internal_handlers.logLine"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/tasks": {
+            "get": {
+                "description": "Get a page of tasks, newest first by default. Filters accept comma separated values",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Types",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actions",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent IDs",
+                        "name": "agent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration IDs",
+                        "name": "configuration_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after, RFC 3339",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before, RFC 3339",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, at most 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from the X-Next-Cursor header",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Task"
+                            }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page, absent on the last page"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Create a new task",
+                "parameters": [
+                    {
+                        "description": "Task",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Task"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/tasks/history": {
+            "get": {
+                "description": "Get history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get history",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/tasks/{id}": {
+            "get": {
+                "description": "Get task by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a task. The status can only be changed along allowed transitions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Task",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Task"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Delete a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_vv-sam_otus-project_server_internal_model_agent.Capacity": {
+            "type": "object",
+            "properties": {
+                "cpu_cores": {
+                    "type": "number"
+                },
+                "disk_bytes": {
+                    "type": "integer"
+                },
+                "max_servers": {
+                    "type": "integer"
+                },
+                "memory_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_agent.Certificate": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "revoked_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_agent.Config": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_agent.Capacity"
+                },
+                "data_dir": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "location": {
+                    "description": "Произвольное расположение хоста, например регион или датацентр",
+                    "type": "string"
+                },
+                "port_range": {
+                    "description": "Порты, которые агент может отдавать игровым серверам",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_agent.PortRange"
+                        }
+                    ]
+                },
+                "runtime": {
+                    "type": "string"
+                },
+                "transport": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_agent.Info": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string"
+                },
+                "config": {
+                    "description": "Конфигурация, переданная агентом при последней регистрации",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_agent.Config"
+                        }
+                    ]
+                },
+                "last_seen": {
+                    "description": "Время последнего heartbeat от агента",
+                    "type": "string"
+                },
+                "metrics": {
+                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_metrics.HostMetrics"
+                },
+                "servers": {
+                    "description": "Потребление ресурсов игровыми серверами по последнему heartbeat",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_metrics.ServerUsage"
+                    }
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Task"
+                    }
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_agent.PortRange": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_configuration.Factorio": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "description": "ID агента для запуска задачи",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "уникальный ID конфигурации",
+                    "type": "string"
+                },
+                "map_gen_settings": {
+                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.MapGenSettings"
+                },
+                "map_settings": {
+                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.MapSettings"
+                },
+                "placement": {
+                    "description": "Требования к агенту. Если agent_id не задан, агента выбирает планировщик",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.Placement"
+                        }
+                    ]
+                },
+                "port": {
+                    "description": "Порт сервера",
+                    "type": "integer"
+                },
+                "rcon_port": {
+                    "type": "integer"
+                },
+                "resources": {
+                    "description": "Ограничения ресурсов, которые агент применяет к контейнеру сервера",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.Resources"
+                        }
+                    ]
+                },
+                "server_settings": {
+                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.ServerSetting"
+                },
+                "type": {
+                    "description": "Тип конфигурации",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_configuration.MapGenSettings": {
+            "type": "object",
+            "properties": {
+                "autoplace_controls": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.resource"
+                    }
+                },
+                "cliff_settings": {
+                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.cliff"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "peaceful_mode": {
                     "type": "boolean"
                 },
                 "seed": {
@@ -883,6 +1940,47 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_vv-sam_otus-project_server_internal_model_configuration.Placement": {
+            "type": "object",
+            "properties": {
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "location": {
+                    "type": "string"
+                },
+                "min_cpu_cores": {
+                    "type": "number"
+                },
+                "min_memory_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_configuration.Resources": {
+            "type": "object",
+            "properties": {
+                "cpu_cores": {
+                    "description": "Жёсткая квота CPU в ядрах, например 1.5",
+                    "type": "number"
+                },
+                "cpu_shares": {
+                    "description": "Относительный вес при конкуренции за CPU, по умолчанию у docker 1024",
+                    "type": "integer"
+                },
+                "disk_bytes": {
+                    "description": "Квота записываемого слоя контейнера",
+                    "type": "integer"
+                },
+                "memory_bytes": {
+                    "description": "При превышении сервер убивается OOM killer",
+                    "type": "integer"
+                }
+            }
+        },
         "github_com_vv-sam_otus-project_server_internal_model_configuration.ServerSetting": {
             "type": "object",
             "properties": {
@@ -958,11 +2056,11 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
-                "token": {
-                    "type": "string"
-                },
                 "username": {
                     "type": "string"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_configuration.visibility"
                 }
             }
         },
@@ -994,193 +2092,600 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_vv-sam_otus-project_server_internal_model_configuration.evolution": {
+        "github_com_vv-sam_otus-project_server_internal_model_configuration.evolution": {
+            "type": "object",
+            "properties": {
+                "destroy_factor": {
+                    "type": "number"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "pollution_factor": {
+                    "type": "number"
+                },
+                "time_factor": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_configuration.expansion": {
+            "type": "object",
+            "properties": {
+                "building_coefficient": {
+                    "type": "number"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "enemy_building_influence_radius": {
+                    "type": "integer"
+                },
+                "friendly_base_influence_radius": {
+                    "type": "integer"
+                },
+                "max_colliding_tiles_coefficient": {
+                    "type": "number"
+                },
+                "max_expansion_cooldown": {
+                    "type": "integer"
+                },
+                "max_expansion_distance": {
+                    "type": "integer"
+                },
+                "min_expansion_cooldown": {
+                    "type": "integer"
+                },
+                "neighbouring_base_chunk_coefficient": {
+                    "type": "number"
+                },
+                "neighbouring_chunk_coefficient": {
+                    "type": "number"
+                },
+                "other_base_coefficient": {
+                    "type": "number"
+                },
+                "settler_group_max_size": {
+                    "type": "integer"
+                },
+                "settler_group_min_size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_configuration.pollution": {
+            "type": "object",
+            "properties": {
+                "ageing": {
+                    "type": "integer"
+                },
+                "diffusion_ratio": {
+                    "type": "number"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "enemy_attack_pollution_consumption_modifier": {
+                    "type": "integer"
+                },
+                "expected_max_per_chunk": {
+                    "type": "integer"
+                },
+                "max_pollution_to_restore_trees": {
+                    "type": "integer"
+                },
+                "min_pollution_to_damage_trees": {
+                    "type": "integer"
+                },
+                "min_to_diffuse": {
+                    "type": "integer"
+                },
+                "min_to_show_per_chunk": {
+                    "type": "integer"
+                },
+                "pollution_per_tree_damage": {
+                    "type": "integer"
+                },
+                "pollution_restored_per_tree_damage": {
+                    "type": "integer"
+                },
+                "pollution_with_max_forest_damage": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_configuration.resource": {
+            "type": "object",
+            "properties": {
+                "frequency": {
+                    "type": "number"
+                },
+                "richness": {
+                    "type": "number"
+                },
+                "size": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_configuration.unitGroup": {
+            "type": "object",
+            "properties": {
+                "max_gathering_unit_groups": {
+                    "type": "integer"
+                },
+                "max_group_gathering_time": {
+                    "type": "integer"
+                },
+                "max_group_member_fallback_factor": {
+                    "type": "integer"
+                },
+                "max_group_radius": {
+                    "type": "number"
+                },
+                "max_group_slowdown_factor": {
+                    "type": "number"
+                },
+                "max_member_slowdown_when_ahead": {
+                    "type": "number"
+                },
+                "max_member_speedup_when_behind": {
+                    "type": "number"
+                },
+                "max_unit_group_size": {
+                    "type": "integer"
+                },
+                "max_wait_time_for_late_members": {
+                    "type": "integer"
+                },
+                "member_disown_distance": {
+                    "type": "integer"
+                },
+                "min_group_gathering_time": {
+                    "type": "integer"
+                },
+                "min_group_radius": {
+                    "type": "number"
+                },
+                "tick_tolerance_when_member_arrives": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_configuration.visibility": {
+            "type": "object",
+            "properties": {
+                "lan": {
+                    "type": "boolean"
+                },
+                "public": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_event.Event": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string"
+                },
+                "configuration_id": {
+                    "type": "string"
+                },
+                "from": {
+                    "description": "Предыдущее и новое состояние для событий о переходах",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "Позиция события в журнале, по ней подписчик продолжает чтение.\nНазначается при публикации",
+                    "type": "string"
+                },
+                "server_id": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_health.Health": {
             "type": "object",
             "properties": {
-                "destroy_factor": {
-                    "type": "number"
+                "changed_at": {
+                    "type": "string"
                 },
-                "enabled": {
-                    "type": "boolean"
+                "checked_at": {
+                    "type": "string"
                 },
-                "pollution_factor": {
-                    "type": "number"
+                "error": {
+                    "type": "string"
                 },
-                "time_factor": {
-                    "type": "number"
+                "failures": {
+                    "description": "Неудачные проверки подряд",
+                    "type": "integer"
+                },
+                "probe": {
+                    "description": "Последняя успешная проверка",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_health.Probe"
+                        }
+                    ]
+                },
+                "server_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
                 }
             }
         },
-        "github_com_vv-sam_otus-project_server_internal_model_configuration.expansion": {
+        "github_com_vv-sam_otus-project_server_internal_model_health.Probe": {
             "type": "object",
             "properties": {
-                "building_coefficient": {
-                    "type": "number"
+                "latency_ms": {
+                    "type": "integer"
                 },
-                "enabled": {
-                    "type": "boolean"
+                "motd": {
+                    "type": "string"
                 },
-                "enemy_building_influence_radius": {
+                "players": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "players_max": {
                     "type": "integer"
                 },
-                "friendly_base_influence_radius": {
+                "players_online": {
                     "type": "integer"
                 },
-                "max_colliding_tiles_coefficient": {
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_metrics.HostMetrics": {
+            "type": "object",
+            "properties": {
+                "cpu_usage": {
                     "type": "number"
                 },
-                "max_expansion_cooldown": {
-                    "type": "integer"
-                },
-                "max_expansion_distance": {
+                "disk_available": {
+                    "description": "Место на диске в каталоге с данными агента",
                     "type": "integer"
                 },
-                "min_expansion_cooldown": {
+                "disk_total": {
                     "type": "integer"
                 },
-                "neighbouring_base_chunk_coefficient": {
+                "load_avg_1": {
+                    "description": "Средняя нагрузка за 1, 5 и 15 минут",
                     "type": "number"
                 },
-                "neighbouring_chunk_coefficient": {
+                "load_avg_15": {
                     "type": "number"
                 },
-                "other_base_coefficient": {
+                "load_avg_5": {
                     "type": "number"
                 },
-                "settler_group_max_size": {
+                "net_rx_bytes": {
+                    "description": "Счётчики сетевых интерфейсов (без loopback) с момента загрузки хоста",
                     "type": "integer"
                 },
-                "settler_group_min_size": {
+                "net_tx_bytes": {
+                    "type": "integer"
+                },
+                "ram_available": {
+                    "type": "integer"
+                },
+                "ram_total": {
                     "type": "integer"
                 }
             }
         },
-        "github_com_vv-sam_otus-project_server_internal_model_configuration.pollution": {
+        "github_com_vv-sam_otus-project_server_internal_model_metrics.ServerUsage": {
             "type": "object",
             "properties": {
-                "ageing": {
-                    "type": "integer"
-                },
-                "diffusion_ratio": {
+                "cpu_usage": {
+                    "description": "Загрузка CPU в процентах от одного ядра",
                     "type": "number"
                 },
-                "enabled": {
-                    "type": "boolean"
-                },
-                "enemy_attack_pollution_consumption_modifier": {
+                "disk_usage": {
                     "type": "integer"
                 },
-                "expected_max_per_chunk": {
+                "memory_limit": {
                     "type": "integer"
                 },
-                "max_pollution_to_restore_trees": {
+                "memory_usage": {
                     "type": "integer"
                 },
-                "min_pollution_to_damage_trees": {
+                "net_rx_bytes": {
                     "type": "integer"
                 },
-                "min_to_diffuse": {
+                "net_tx_bytes": {
                     "type": "integer"
                 },
-                "min_to_show_per_chunk": {
-                    "type": "integer"
+                "oom_killed_at": {
+                    "description": "Когда сервер последний раз был убит OOM killer",
+                    "type": "string"
                 },
-                "pollution_per_tree_damage": {
-                    "type": "integer"
+                "revision": {
+                    "description": "Ревизия развёрнутой конфигурации",
+                    "type": "string"
                 },
-                "pollution_restored_per_tree_damage": {
-                    "type": "integer"
+                "running": {
+                    "type": "boolean"
                 },
-                "pollution_with_max_forest_damage": {
-                    "type": "integer"
+                "server_id": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_vv-sam_otus-project_server_internal_model_configuration.resource": {
+        "github_com_vv-sam_otus-project_server_internal_model_placement.Decision": {
             "type": "object",
             "properties": {
-                "frequency": {
-                    "type": "number"
+                "agent_id": {
+                    "type": "string"
                 },
-                "richness": {
-                    "type": "number"
+                "reason": {
+                    "type": "string"
                 },
-                "size": {
-                    "type": "number"
+                "rejected": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_placement.Rejection"
+                    }
                 }
             }
         },
-        "github_com_vv-sam_otus-project_server_internal_model_configuration.unitGroup": {
+        "github_com_vv-sam_otus-project_server_internal_model_placement.Rejection": {
             "type": "object",
             "properties": {
-                "max_gathering_unit_groups": {
-                    "type": "integer"
+                "agent_id": {
+                    "type": "string"
                 },
-                "max_group_gathering_time": {
-                    "type": "integer"
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_server.Server": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "description": "Агент, на котором сервер развёрнут или будет развёрнут",
+                    "type": "string"
                 },
-                "max_group_member_fallback_factor": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "max_group_radius": {
-                    "type": "number"
+                "desired_revision": {
+                    "type": "string"
                 },
-                "max_group_slowdown_factor": {
-                    "type": "number"
+                "desired_state": {
+                    "description": "running, stopped или deleted",
+                    "type": "string"
                 },
-                "max_member_slowdown_when_ahead": {
-                    "type": "number"
+                "error": {
+                    "description": "Ошибка последней неудачной задачи",
+                    "type": "string"
                 },
-                "max_member_speedup_when_behind": {
-                    "type": "number"
+                "id": {
+                    "description": "Совпадает с id конфигурации: у конфигурации не больше одного сервера",
+                    "type": "string"
                 },
-                "max_unit_group_size": {
-                    "type": "integer"
+                "observed_at": {
+                    "description": "Время последнего отчёта агента, по которому определено состояние",
+                    "type": "string"
                 },
-                "max_wait_time_for_late_members": {
-                    "type": "integer"
+                "observed_revision": {
+                    "description": "Ревизия развёрнутой конфигурации по отчёту агента и ревизия,\nкоторую требует текущая конфигурация",
+                    "type": "string"
                 },
-                "member_disown_distance": {
+                "observed_state": {
+                    "description": "unknown, absent, running или stopped",
+                    "type": "string"
+                },
+                "task_id": {
+                    "description": "Последняя задача, созданная для согласования",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Тип игры из конфигурации, по нему агент выбирает обработчик задач",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vv-sam_otus-project_server_internal_model_task.Task": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Действие над игровым сервером: deploy, start, stop, restart, backup, delete",
+                    "type": "string"
+                },
+                "agent_id": {
+                    "description": "ID агента, который должен выполнить задачу. Пустой - любой агент",
+                    "type": "string"
+                },
+                "attempts": {
+                    "description": "Сколько раз задача выдавалась агентам",
                     "type": "integer"
                 },
-                "min_group_gathering_time": {
+                "configuration_id": {
+                    "description": "ID конфигурации, к которой относится задача",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Время создания, начала и окончания выполнения",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Текст ошибки, если задача завершилась неудачно",
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID задачи",
+                    "type": "string"
+                },
+                "lease_expires_at": {
+                    "type": "string"
+                },
+                "leased_by": {
+                    "description": "Агент, взявший задачу в работу, и время окончания аренды.\nПока аренда не истекла, задача не выдаётся другим агентам",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Произвольные параметры задачи в JSON",
+                    "type": "object"
+                },
+                "priority": {
+                    "description": "Приоритет: -1 низкий, 0 обычный, 1 высокий",
                     "type": "integer"
                 },
-                "min_group_radius": {
-                    "type": "number"
+                "result": {
+                    "description": "Результат выполнения в JSON, присылается агентом",
+                    "type": "object"
                 },
-                "tick_tolerance_when_member_arrives": {
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Статус задачи",
                     "type": "integer"
+                },
+                "transitions": {
+                    "description": "История смены статусов",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_task.Transition"
+                    }
+                },
+                "type": {
+                    "description": "Тип задачи",
+                    "type": "string"
                 }
             }
         },
-        "github_com_vv-sam_otus-project_server_internal_model_metrics.HostMetrics": {
+        "github_com_vv-sam_otus-project_server_internal_model_task.Transition": {
             "type": "object",
             "properties": {
-                "cpu_usage": {
-                    "type": "number"
+                "actor": {
+                    "description": "Кто изменил статус: имя пользователя, agent:\u003cid\u003e или system",
+                    "type": "string"
                 },
-                "ram_available": {
+                "at": {
+                    "description": "Время перехода",
+                    "type": "string"
+                },
+                "from": {
                     "type": "integer"
                 },
-                "ram_total": {
+                "to": {
                     "type": "integer"
                 }
             }
         },
-        "github_com_vv-sam_otus-project_server_internal_model_task.Task": {
+        "server_internal_handlers.consoleRequest": {
             "type": "object",
             "properties": {
-                "id": {
-                    "description": "ID задачи",
+                "command": {
+                    "type": "string"
+                }
+            }
+        },
+        "server_internal_handlers.consoleResponse": {
+            "type": "object",
+            "properties": {
+                "output": {
+                    "type": "string"
+                }
+            }
+        },
+        "server_internal_handlers.enrollRequest": {
+            "type": "object",
+            "properties": {
+                "csr": {
+                    "description": "PEM",
                     "type": "string"
                 },
-                "status": {
-                    "description": "Статус задачи",
+                "join_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "server_internal_handlers.enrollResponse": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string"
+                },
+                "ca_certificate": {
+                    "description": "PEM",
+                    "type": "string"
+                },
+                "certificate": {
+                    "description": "PEM",
+                    "type": "string"
+                },
+                "info": {
+                    "$ref": "#/definitions/github_com_vv-sam_otus-project_server_internal_model_agent.Certificate"
+                }
+            }
+        },
+        "server_internal_handlers.joinTokenRequest": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string"
+                },
+                "ttl_seconds": {
                     "type": "integer"
+                }
+            }
+        },
+        "server_internal_handlers.joinTokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
                 },
-                "type": {
-                    "description": "Тип задачи",
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "server_internal_handlers.logLine": {
+            "type": "object",
+            "properties": {
+                "text": {
+                    "type": "string"
+                },
+                "time": {
                     "type": "string"
                 }
             }
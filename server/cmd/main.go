@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 
 	"github.com/redis/go-redis/v9"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
@@ -19,12 +20,14 @@ import (
 	"github.com/vv-sam/otus-project/server/internal/middleware"
 	"github.com/vv-sam/otus-project/server/internal/model/agent"
 	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/server"
 	"github.com/vv-sam/otus-project/server/internal/model/task"
 	"github.com/vv-sam/otus-project/server/internal/repository"
 	"github.com/vv-sam/otus-project/server/internal/services"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -38,6 +41,12 @@ import (
 func main() {
 	user := flag.String("user", "admin", "admin username")
 	pass := flag.String("password", "1234", "admin password")
+	maxAttempts := flag.Int("task-max-attempts", services.DefaultMaxAttempts, "attempts before a task is moved to dead letters")
+	caCert := flag.String("ca-cert", "ca.crt", "CA certificate for agent client certificates, created if missing")
+	caKey := flag.String("ca-key", "ca.key", "CA private key, created if missing")
+	grpcTls := flag.Bool("grpc-tls", false, "serve gRPC over TLS and accept agent client certificates")
+	grpcTlsHosts := flag.String("grpc-tls-hosts", "localhost,127.0.0.1", "comma-separated names and addresses for the gRPC server certificate")
+	agentCertTtl := flag.Duration("agent-cert-ttl", services.DefaultAgentCertTTL, "lifetime of agent client certificates")
 	flag.Parse()
 
 	if *pass == "" {
@@ -75,19 +84,23 @@ func main() {
 	am := middleware.NewAuthMiddleware(as)
 
 	// ar := repository.NewJsonRepository[*agent.Info]("D:\\otus-data", "agents")
-	// cr := repository.NewJsonRepository[*configuration.Factorio]("D:\\otus-data", "configurations")
+	// cr := repository.NewJsonRepository[*configuration.Envelope]("D:\\otus-data", "configurations")
 	//tr := repository.NewJsonRepository[*task.Task]("D:\\otus-data", "tasks")
 
 	ar, err := repository.NewNosqlRepository[*agent.Info](rc, mc, repository.NosqlRepositoryOptions{
 		RedisKey:        "agents",
 		MongoDatabase:   "otus",
 		MongoCollection: "agents",
+		IdPath:          repository.AgentSchema.IdPath,
 	})
 	if err != nil {
 		log.Fatalf("failed to create agent repository: %v", err)
 	}
+	if err := ar.EnsureIndexes(ctx, repository.AgentSchema); err != nil {
+		log.Printf("failed to create agent indexes: %v", err)
+	}
 
-	cr, err := repository.NewNosqlRepository[*configuration.Factorio](rc, mc, repository.NosqlRepositoryOptions{
+	cr, err := repository.NewNosqlRepository[*configuration.Envelope](rc, mc, repository.NosqlRepositoryOptions{
 		RedisKey:        "configurations",
 		MongoDatabase:   "otus",
 		MongoCollection: "configurations",
@@ -95,6 +108,26 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to create configuration repository: %v", err)
 	}
+	if err := cr.EnsureIndexes(ctx, repository.ConfigurationSchema); err != nil {
+		log.Printf("failed to create configuration indexes: %v", err)
+	}
+
+	ports, err := repository.NewRedisPorts(rc, "ports")
+	if err != nil {
+		log.Fatalf("failed to create port registry: %v", err)
+	}
+
+	el, err := repository.NewRedisEvents(rc, "events")
+	if err != nil {
+		log.Fatalf("failed to create event log: %v", err)
+	}
+	events := services.NewEvents(el)
+	agents := services.NewAgents(ar, events)
+
+	sessions := services.NewAgentSessions()
+	scheduler := services.NewScheduler(ar, cr)
+	configs := services.NewConfigurations(cr, ar, ports, scheduler, sessions, events)
+	usage := services.NewUsageMonitor(cr, events)
 
 	tr, err := repository.NewNosqlRepository[*task.Task](rc, mc, repository.NosqlRepositoryOptions{
 		RedisKey:        "tasks",
@@ -104,13 +137,94 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to create task repository: %v", err)
 	}
+	if err := tr.EnsureIndexes(ctx, repository.TaskSchema); err != nil {
+		log.Printf("failed to create task indexes: %v", err)
+	}
+
+	tb, err := repository.NewRedisTaskQueue(rc, "tasks:queue")
+	if err != nil {
+		log.Fatalf("failed to create task queue: %v", err)
+	}
+
+	tq := services.NewTaskQueue(tr, tb, *maxAttempts, events)
+
+	sr, err := repository.NewNosqlRepository[*server.Server](rc, mc, repository.NosqlRepositoryOptions{
+		RedisKey:        "servers",
+		MongoDatabase:   "otus",
+		MongoCollection: "servers",
+	})
+	if err != nil {
+		log.Fatalf("failed to create server repository: %v", err)
+	}
+
+	servers := services.NewServers(sr, configs, events)
+
+	ca, err := services.LoadOrCreateCA(*caCert, *caKey)
+	if err != nil {
+		log.Fatalf("failed to load ca: %v", err)
+	}
+
+	jt, err := repository.NewRedisJoinTokens(rc, "agents:join-tokens")
+	if err != nil {
+		log.Fatalf("failed to create join token repository: %v", err)
+	}
+
+	acr, err := repository.NewNosqlRepository[*agent.Certificate](rc, mc, repository.NosqlRepositoryOptions{
+		RedisKey:        "agent_certificates",
+		MongoDatabase:   "otus",
+		MongoCollection: "agent_certificates",
+	})
+	if err != nil {
+		log.Fatalf("failed to create agent certificate repository: %v", err)
+	}
+
+	enrollment := services.NewEnrollment(ca, jt, acr, *agentCertTtl)
+
+	sl := services.NewServerLogs(sessions, cr)
+	console := services.NewConsole(sessions, cr)
+	health := services.NewServerHealth(sessions, cr)
+
+	grpc_services.SetTokenValidator(as)
+	grpc_services.SetCertificateVerifier(enrollment)
+
+	creds := insecure.NewCredentials()
+	if *grpcTls {
+		tlsConfig, err := ca.ServerTLSConfig(strings.Split(*grpcTlsHosts, ","))
+		if err != nil {
+			log.Fatalf("failed to configure grpc tls: %v", err)
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
 
-	go serveGrpc(as, ar, cr, tr)
+	gs := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.UnaryInterceptor(grpc_services.AuthInterceptor),
+		grpc.StreamInterceptor(grpc_services.AuthStreamInterceptor),
+	)
+	registerGrpcServices(gs, as, agents, configs, tq, sessions, enrollment, sl, console, health, scheduler, usage, servers, events)
+
+	// Те же сервисы для агентов, которые могут подключиться только по websocket
+	ws := grpc_services.NewWebsocketServer(grpc_services.AuthInterceptor, grpc_services.AuthStreamInterceptor)
+	registerGrpcServices(ws, as, agents, configs, tq, sessions, enrollment, sl, console, health, scheduler, usage, servers, events)
 
-	ah := handlers.NewAgents(ar, &services.Validator{})
-	ch := handlers.NewConfiguration(cr, &services.Validator{})
-	th := handlers.NewTasks(tr, &services.Validator{})
+	go serveGrpc(gs)
+	go services.NewAgentMonitor(ar, events).Run(ctx)
+	go events.Run(ctx)
+	go tq.Run(ctx)
+	go health.Run(ctx)
+	go services.NewReconciler(servers, cr, ar, tq).Run(ctx)
+
+	ah := handlers.NewAgents(agents, &services.Validator{})
+	ch := handlers.NewConfiguration(configs, &services.Validator{})
+	th := handlers.NewTasks(tq, &services.Validator{})
 	au := handlers.NewAuth(as)
+	eh := handlers.NewEnrollment(enrollment)
+	lh := handlers.NewLogs(sl)
+	coh := handlers.NewConsole(console)
+	hh := handlers.NewHealth(health)
+	sh := handlers.NewScheduler(scheduler, &services.Validator{})
+	svh := handlers.NewServers(servers, &services.Validator{})
+	evh := handlers.NewEvents(events)
 
 	mux := http.NewServeMux()
 
@@ -126,6 +240,11 @@ func main() {
 	mux.Handle("PUT /api/agents/{id}", am.Authenticate(ah.Put))
 	mux.Handle("DELETE /api/agents/{id}", am.Authenticate(ah.Delete))
 	mux.HandleFunc("GET /api/agents/history", ah.GetHistory)
+	mux.Handle("GET /api/agents/ws", am.Authenticate(ws.ServeHTTP))
+	mux.Handle("POST /api/agents/join-tokens", am.Authenticate(eh.CreateJoinToken))
+	mux.HandleFunc("POST /api/agents/enroll", eh.Enroll)
+	mux.Handle("GET /api/agents/{id}/certificates", am.Authenticate(eh.GetCertificates))
+	mux.Handle("DELETE /api/agents/{id}/certificates/{certId}", am.Authenticate(eh.Revoke))
 
 	mux.HandleFunc("GET /api/configurations", ch.GetAll)
 	mux.HandleFunc("GET /api/configurations/{id}", ch.GetById)
@@ -133,6 +252,7 @@ func main() {
 	mux.Handle("PUT /api/configurations/{id}", am.Authenticate(ch.Put))
 	mux.Handle("DELETE /api/configurations/{id}", am.Authenticate(ch.Delete))
 	mux.HandleFunc("GET /api/configurations/history", ch.GetHistory)
+	mux.HandleFunc("POST /api/configurations/placement", sh.Place)
 
 	mux.HandleFunc("GET /api/tasks", th.GetAll)
 	mux.HandleFunc("GET /api/tasks/{id}", th.GetById)
@@ -141,25 +261,42 @@ func main() {
 	mux.Handle("DELETE /api/tasks/{id}", am.Authenticate(th.Delete))
 	mux.HandleFunc("GET /api/tasks/history", th.GetHistory)
 
+	mux.HandleFunc("GET /api/servers", svh.GetAll)
+	mux.HandleFunc("GET /api/servers/{id}", svh.GetById)
+	mux.Handle("POST /api/servers", am.Authenticate(svh.Post))
+	mux.Handle("PUT /api/servers/{id}", am.Authenticate(svh.Put))
+	mux.Handle("DELETE /api/servers/{id}", am.Authenticate(svh.Delete))
+	mux.HandleFunc("GET /api/servers/history", svh.GetHistory)
+	mux.Handle("GET /api/servers/{id}/logs", am.Authenticate(lh.Get))
+	mux.Handle("POST /api/servers/{id}/console", am.Authenticate(coh.Post))
+	mux.HandleFunc("GET /api/servers/health", hh.GetAll)
+	mux.HandleFunc("GET /api/servers/{id}/health", hh.GetById)
+
+	mux.Handle("GET /api/events", am.Authenticate(evh.Get))
+
 	http.ListenAndServe(":8080", mux)
 }
 
-func serveGrpc(as *services.Users, ar *repository.NosqlRepository[*agent.Info], cr *repository.NosqlRepository[*configuration.Factorio], tr *repository.NosqlRepository[*task.Task]) {
+func registerGrpcServices(r grpc.ServiceRegistrar, as *services.Users, agents *services.Agents, configs *services.Configurations, tq *services.TaskQueue, sessions *services.AgentSessions, enrollment *services.Enrollment, sl *services.ServerLogs, console *services.Console, health *services.ServerHealth, scheduler *services.Scheduler, usage *services.UsageMonitor, servers *services.Servers, events *services.Events) {
+	api.RegisterAuthServiceServer(r, grpc_services.NewAuthService(as))
+	api.RegisterAgentServiceServer(r, grpc_services.NewAgentService(agents, &services.Validator{}, usage, events))
+	api.RegisterConfigurationServiceServer(r, grpc_services.NewConfigurationService(configs, &services.Validator{}, events))
+	api.RegisterTaskServiceServer(r, grpc_services.NewTaskService(tq, tq, &services.Validator{}, events))
+	api.RegisterAgentControlServer(r, grpc_services.NewControlService(sessions))
+	api.RegisterEnrollmentServiceServer(r, grpc_services.NewEnrollmentService(enrollment))
+	api.RegisterLogServiceServer(r, grpc_services.NewLogService(sl))
+	api.RegisterConsoleServiceServer(r, grpc_services.NewConsoleService(console))
+	api.RegisterServerHealthServiceServer(r, grpc_services.NewServerHealthService(health))
+	api.RegisterSchedulerServiceServer(r, grpc_services.NewSchedulerService(scheduler, &services.Validator{}))
+	api.RegisterServerServiceServer(r, grpc_services.NewServerService(servers, &services.Validator{}))
+}
+
+func serveGrpc(s *grpc.Server) {
 	lis, err := net.Listen("tcp", ":8081")
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer(
-		grpc.Creds(insecure.NewCredentials()),
-		grpc.UnaryInterceptor(grpc_services.AuthInterceptor),
-	)
-
-	api.RegisterAuthServiceServer(s, grpc_services.NewAuthService(as))
-	api.RegisterAgentServiceServer(s, grpc_services.NewAgentService(ar, &services.Validator{}))
-	api.RegisterConfigurationServiceServer(s, grpc_services.NewConfigurationService(cr, &services.Validator{}))
-	api.RegisterTaskServiceServer(s, grpc_services.NewTaskService(tr, &services.Validator{}))
-
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
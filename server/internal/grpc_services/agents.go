@@ -3,36 +3,59 @@ package grpc_services
 import (
 	"context"
 	"errors"
+	"log"
+	"math"
+	"time"
 
 	"github.com/google/uuid"
 	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
 	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
 	"github.com/vv-sam/otus-project/server/internal/model/metrics"
 	"github.com/vv-sam/otus-project/server/internal/model/task"
 	"github.com/vv-sam/otus-project/server/internal/repository"
 	"github.com/vv-sam/otus-project/server/internal/services"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type agentRepository interface {
 	Get(id uuid.UUID) (*agent.Info, error)
 	GetAll() ([]*agent.Info, error)
+	List(q repository.ListQuery) (*repository.Page[*agent.Info], error)
 	Add(agent *agent.Info) error
 	Update(id uuid.UUID, agent *agent.Info) error
 	Delete(id uuid.UUID) error
 }
 
+type usageObserver interface {
+	Observe(agentId uuid.UUID, prev, cur []metrics.ServerUsage)
+}
+
+type eventPublisher interface {
+	Publish(e *event.Event) error
+}
+
+type agentEvents interface {
+	eventPublisher
+	eventWatcher
+}
+
 type AgentService struct {
 	api.UnimplementedAgentServiceServer
 	agentRepository agentRepository
 	validator       *services.Validator
+	usage           usageObserver
+	events          agentEvents
 }
 
-func NewAgentService(agentRepository agentRepository, validator *services.Validator) *AgentService {
+func NewAgentService(agentRepository agentRepository, validator *services.Validator, usage usageObserver, events agentEvents) *AgentService {
 	return &AgentService{
 		agentRepository: agentRepository,
 		validator:       validator,
+		usage:           usage,
+		events:          events,
 	}
 }
 
@@ -60,17 +83,28 @@ func (s *AgentService) GetById(ctx context.Context, req *api.GetAgentByIdRequest
 }
 
 func (s *AgentService) GetAll(ctx context.Context, req *api.GetAllAgentsRequest) (*api.GetAllAgentsResponse, error) {
-	agents, err := s.agentRepository.GetAll()
+	for _, st := range req.Status {
+		if st == api.AgentStatus_AGENT_STATUS_UNSPECIFIED {
+			return nil, status.Error(codes.InvalidArgument, "status filter must be specified")
+		}
+	}
+
+	q := listQuery(req.Options,
+		repository.In("status", filterValues(req.Status, func(st api.AgentStatus) any { return convertProtoToAgentStatus(st) })...),
+		repository.In("location", filterValues(req.Location, func(l string) any { return l })...),
+	)
+
+	page, err := s.agentRepository.List(q)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get agents: %v", err)
+		return nil, convertListError("agents", err)
 	}
 
-	protoAgents := make([]*api.AgentInfo, len(agents))
-	for i, agent := range agents {
+	protoAgents := make([]*api.AgentInfo, len(page.Items))
+	for i, agent := range page.Items {
 		protoAgents[i] = convertAgentToProto(agent)
 	}
 
-	return &api.GetAllAgentsResponse{Agents: protoAgents}, nil
+	return &api.GetAllAgentsResponse{Agents: protoAgents, NextCursor: page.NextCursor}, nil
 }
 
 func (s *AgentService) Post(ctx context.Context, req *api.PostAgentRequest) (*api.PostAgentResponse, error) {
@@ -150,6 +184,148 @@ func (s *AgentService) Delete(ctx context.Context, req *api.DeleteAgentRequest)
 	return &api.DeleteAgentResponse{}, nil
 }
 
+func (s *AgentService) Register(ctx context.Context, req *api.RegisterAgentRequest) (*api.RegisterAgentResponse, error) {
+	agentUUID := uuid.New()
+	if id, ok := agentFromContext(ctx); ok {
+		// id агента с сертификатом определяется сертификатом
+		agentUUID = id
+	}
+	if req.AgentId != "" {
+		var err error
+		agentUUID, err = uuid.Parse(req.AgentId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to parse agent id: %v", err)
+		}
+	}
+	if err := authorizeAgent(ctx, agentUUID); err != nil {
+		return nil, err
+	}
+
+	agentInfo, err := s.agentRepository.Get(agentUUID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, status.Errorf(codes.Internal, "failed to get agent: %v", err)
+	}
+
+	exists := err == nil && agentInfo != nil
+	if !exists {
+		agentInfo = &agent.Info{AgentId: agentUUID}
+	}
+
+	prevStatus := agentInfo.Status
+	agentInfo.Status = agent.STATUS_ONLINE
+	agentInfo.LastSeen = time.Now()
+	if req.Metrics != nil {
+		agentInfo.Metrics = *convertProtoToMetrics(req.Metrics)
+	}
+	if req.Config != nil {
+		if req.Config.GetPortRange().GetMax() > math.MaxUint16 {
+			return nil, status.Error(codes.InvalidArgument, "invalid agent config: port out of range")
+		}
+		agentInfo.Config = convertProtoToAgentConfig(req.Config)
+		if err := agentInfo.Config.Validate(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid agent config: %v", err)
+		}
+	}
+
+	if exists {
+		err = s.agentRepository.Update(agentUUID, agentInfo)
+	} else {
+		err = s.agentRepository.Add(agentInfo)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to register agent: %v", err)
+	}
+	s.statusChanged(agentInfo, prevStatus)
+
+	return &api.RegisterAgentResponse{
+		AgentId:                  agentUUID.String(),
+		HeartbeatIntervalSeconds: uint32(services.HeartbeatInterval / time.Second),
+	}, nil
+}
+
+func (s *AgentService) Heartbeat(ctx context.Context, req *api.HeartbeatRequest) (*api.HeartbeatResponse, error) {
+	if req.AgentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent id is required")
+	}
+
+	agentUUID, err := uuid.Parse(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse agent id: %v", err)
+	}
+	if err := authorizeAgent(ctx, agentUUID); err != nil {
+		return nil, err
+	}
+
+	agentInfo, err := s.agentRepository.Get(agentUUID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && agentInfo == nil) {
+		return nil, status.Error(codes.NotFound, "agent is not registered")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get agent: %v", err)
+	}
+
+	prevStatus := agentInfo.Status
+	agentInfo.LastSeen = time.Now()
+	agentInfo.Status = convertProtoToAgentStatus(req.Status)
+	if agentInfo.Status == agent.STATUS_UNKNOWN {
+		agentInfo.Status = agent.STATUS_ONLINE
+	}
+	if req.Metrics != nil {
+		agentInfo.Metrics = *convertProtoToMetrics(req.Metrics)
+	}
+
+	prev := agentInfo.Servers
+	servers, err := convertProtoToServerUsages(req.Servers)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to convert servers: %v", err)
+	}
+	agentInfo.Servers = servers
+
+	if err := s.agentRepository.Update(agentUUID, agentInfo); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update agent: %v", err)
+	}
+
+	s.statusChanged(agentInfo, prevStatus)
+	s.usage.Observe(agentUUID, prev, servers)
+	return &api.HeartbeatResponse{}, nil
+}
+
+// Снимок агентов и изменения: добавление, смена статуса, удаление.
+// Метрики из heartbeat изменениями не считаются
+func (s *AgentService) Watch(req *api.WatchAgentsRequest, stream api.AgentService_WatchServer) error {
+	return watch(stream.Context(), s.events, req.Revision, watchSource[*agent.Info]{
+		group: "agent",
+		id:    func(e *event.Event) uuid.UUID { return e.AgentId },
+		idOf:  func(a *agent.Info) uuid.UUID { return a.AgentId },
+		get:   s.agentRepository.Get,
+		all:   s.agentRepository.GetAll,
+		send: func(t api.WatchEventType, revision string, id uuid.UUID, a *agent.Info) error {
+			resp := &api.WatchAgentsResponse{Type: t, Revision: revision}
+			if id != uuid.Nil {
+				resp.Id = id.String()
+			}
+			if a != nil {
+				resp.Agent = convertAgentToProto(a)
+			}
+			return stream.Send(resp)
+		},
+	})
+}
+
+func (s *AgentService) statusChanged(agentInfo *agent.Info, prev int16) {
+	if agentInfo.Status == prev {
+		return
+	}
+
+	e := event.AgentStatus(agentInfo.AgentId, agentInfo.Status)
+	if e == nil {
+		return
+	}
+	if err := s.events.Publish(e); err != nil {
+		log.Printf("agents: failed to publish %s: %v", e.Type, err)
+	}
+}
+
 // Helper functions for conversion between internal models and protobuf messages
 
 func convertAgentToProto(agentInfo *agent.Info) *api.AgentInfo {
@@ -158,12 +334,19 @@ func convertAgentToProto(agentInfo *agent.Info) *api.AgentInfo {
 		protoTasks[i] = convertTaskToProto(&task)
 	}
 
-	return &api.AgentInfo{
+	protoAgent := &api.AgentInfo{
 		AgentId:      agentInfo.AgentId.String(),
-		Status:       int32(agentInfo.Status),
+		Status:       convertAgentStatusToProto(agentInfo.Status),
 		CurrentTasks: protoTasks,
 		Metrics:      convertMetricsToProto(&agentInfo.Metrics),
+		Config:       convertAgentConfigToProto(&agentInfo.Config),
+		Servers:      convertServerUsagesToProto(agentInfo.Servers),
 	}
+	if !agentInfo.LastSeen.IsZero() {
+		protoAgent.LastSeen = timestamppb.New(agentInfo.LastSeen)
+	}
+
+	return protoAgent
 }
 
 func convertProtoToAgent(protoAgent *api.AgentInfo) (*agent.Info, error) {
@@ -186,20 +369,133 @@ func convertProtoToAgent(protoAgent *api.AgentInfo) (*agent.Info, error) {
 		hostMetrics = *convertProtoToMetrics(protoAgent.Metrics)
 	}
 
-	return &agent.Info{
+	servers, err := convertProtoToServerUsages(protoAgent.Servers)
+	if err != nil {
+		return nil, err
+	}
+
+	agentInfo := &agent.Info{
 		AgentId:      agentUUID,
-		Status:       int16(protoAgent.Status),
+		Status:       convertProtoToAgentStatus(protoAgent.Status),
 		CurrentTasks: tasks,
 		Metrics:      hostMetrics,
-	}, nil
+		Servers:      servers,
+	}
+	if protoAgent.LastSeen != nil {
+		agentInfo.LastSeen = protoAgent.LastSeen.AsTime()
+	}
+	if protoAgent.Config != nil {
+		agentInfo.Config = convertProtoToAgentConfig(protoAgent.Config)
+	}
+
+	return agentInfo, nil
+}
+
+func convertAgentConfigToProto(c *agent.Config) *api.AgentConfig {
+	return &api.AgentConfig{
+		Location: c.Location,
+		Labels:   c.Labels,
+		PortRange: &api.PortRange{
+			Min: uint32(c.PortRange.Min),
+			Max: uint32(c.PortRange.Max),
+		},
+		Capacity: &api.AgentCapacity{
+			CpuCores:    c.Capacity.CpuCores,
+			MemoryBytes: c.Capacity.MemoryBytes,
+			DiskBytes:   c.Capacity.DiskBytes,
+			MaxServers:  c.Capacity.MaxServers,
+		},
+		Transport: c.Transport,
+		DataDir:   c.DataDir,
+		Runtime:   c.Runtime,
+	}
+}
+
+func convertProtoToAgentConfig(c *api.AgentConfig) agent.Config {
+	cfg := agent.Config{
+		Location:  c.Location,
+		Labels:    c.Labels,
+		Transport: c.Transport,
+		DataDir:   c.DataDir,
+		Runtime:   c.Runtime,
+	}
+	if c.PortRange != nil {
+		cfg.PortRange = agent.PortRange{Min: uint16(c.PortRange.Min), Max: uint16(c.PortRange.Max)}
+	}
+	if c.Capacity != nil {
+		cfg.Capacity = agent.Capacity{
+			CpuCores:    c.Capacity.CpuCores,
+			MemoryBytes: c.Capacity.MemoryBytes,
+			DiskBytes:   c.Capacity.DiskBytes,
+			MaxServers:  c.Capacity.MaxServers,
+		}
+	}
+	return cfg
+}
+
+func convertAgentStatusToProto(status int16) api.AgentStatus {
+	switch status {
+	case agent.STATUS_ONLINE:
+		return api.AgentStatus_AGENT_STATUS_ONLINE
+	case agent.STATUS_DEGRADED:
+		return api.AgentStatus_AGENT_STATUS_DEGRADED
+	case agent.STATUS_OFFLINE:
+		return api.AgentStatus_AGENT_STATUS_OFFLINE
+	default:
+		return api.AgentStatus_AGENT_STATUS_UNSPECIFIED
+	}
+}
+
+func convertProtoToAgentStatus(status api.AgentStatus) int16 {
+	switch status {
+	case api.AgentStatus_AGENT_STATUS_ONLINE:
+		return agent.STATUS_ONLINE
+	case api.AgentStatus_AGENT_STATUS_DEGRADED:
+		return agent.STATUS_DEGRADED
+	case api.AgentStatus_AGENT_STATUS_OFFLINE:
+		return agent.STATUS_OFFLINE
+	default:
+		return agent.STATUS_UNKNOWN
+	}
 }
 
 func convertTaskToProto(taskInfo *task.Task) *api.Task {
-	return &api.Task{
-		Id:     taskInfo.Id.String(),
-		Status: convertTaskStatusToProto(taskInfo.Status),
-		Type:   taskInfo.Type,
+	protoTask := &api.Task{
+		Id:       taskInfo.Id.String(),
+		Status:   convertTaskStatusToProto(taskInfo.Status),
+		Type:     taskInfo.Type,
+		Action:   convertTaskActionToProto(taskInfo.Action),
+		Payload:  taskInfo.Payload,
+		Result:   taskInfo.Result,
+		Attempts: uint32(taskInfo.Attempts),
+		Error:    taskInfo.Error,
+		Priority: int32(taskInfo.Priority),
+	}
+	if taskInfo.AgentId != uuid.Nil {
+		protoTask.AgentId = taskInfo.AgentId.String()
 	}
+	if taskInfo.ConfigurationId != uuid.Nil {
+		protoTask.ConfigurationId = taskInfo.ConfigurationId.String()
+	}
+	if !taskInfo.CreatedAt.IsZero() {
+		protoTask.CreatedAt = timestamppb.New(taskInfo.CreatedAt)
+	}
+	if !taskInfo.StartedAt.IsZero() {
+		protoTask.StartedAt = timestamppb.New(taskInfo.StartedAt)
+	}
+	if !taskInfo.FinishedAt.IsZero() {
+		protoTask.FinishedAt = timestamppb.New(taskInfo.FinishedAt)
+	}
+	for _, tr := range taskInfo.Transitions {
+		protoTask.Transitions = append(protoTask.Transitions, &api.TaskTransition{
+			From:  convertTaskStatusToProto(tr.From),
+			To:    convertTaskStatusToProto(tr.To),
+			At:    timestamppb.New(tr.At),
+			Actor: tr.Actor,
+		})
+	}
+
+	return protoTask
 }
 
 func convertProtoToTask(protoTask *api.Task) (*task.Task, error) {
@@ -208,11 +504,92 @@ func convertProtoToTask(protoTask *api.Task) (*task.Task, error) {
 		return nil, err
 	}
 
-	return &task.Task{
-		Id:     taskUUID,
-		Status: convertProtoToTaskStatus(protoTask.Status),
-		Type:   protoTask.Type,
-	}, nil
+	var agentUUID uuid.UUID
+	if protoTask.AgentId != "" {
+		agentUUID, err = uuid.Parse(protoTask.AgentId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var configurationUUID uuid.UUID
+	if protoTask.ConfigurationId != "" {
+		configurationUUID, err = uuid.Parse(protoTask.ConfigurationId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	taskInfo := &task.Task{
+		Id:              taskUUID,
+		Status:          convertProtoToTaskStatus(protoTask.Status),
+		Type:            protoTask.Type,
+		Action:          convertProtoToTaskAction(protoTask.Action),
+		AgentId:         agentUUID,
+		ConfigurationId: configurationUUID,
+		Payload:         protoTask.Payload,
+		Result:          protoTask.Result,
+		Attempts:        int(protoTask.Attempts),
+		Error:           protoTask.Error,
+		Priority:        int16(protoTask.Priority),
+	}
+	if protoTask.CreatedAt != nil {
+		taskInfo.CreatedAt = protoTask.CreatedAt.AsTime()
+	}
+	if protoTask.StartedAt != nil {
+		taskInfo.StartedAt = protoTask.StartedAt.AsTime()
+	}
+	if protoTask.FinishedAt != nil {
+		taskInfo.FinishedAt = protoTask.FinishedAt.AsTime()
+	}
+	for _, tr := range protoTask.Transitions {
+		taskInfo.Transitions = append(taskInfo.Transitions, task.Transition{
+			From:  convertProtoToTaskStatus(tr.From),
+			To:    convertProtoToTaskStatus(tr.To),
+			At:    tr.At.AsTime(),
+			Actor: tr.Actor,
+		})
+	}
+
+	return taskInfo, nil
+}
+
+func convertTaskActionToProto(action string) api.TaskAction {
+	switch action {
+	case task.ACTION_DEPLOY:
+		return api.TaskAction_TASK_ACTION_DEPLOY
+	case task.ACTION_START:
+		return api.TaskAction_TASK_ACTION_START
+	case task.ACTION_STOP:
+		return api.TaskAction_TASK_ACTION_STOP
+	case task.ACTION_RESTART:
+		return api.TaskAction_TASK_ACTION_RESTART
+	case task.ACTION_BACKUP:
+		return api.TaskAction_TASK_ACTION_BACKUP
+	case task.ACTION_DELETE:
+		return api.TaskAction_TASK_ACTION_DELETE
+	default:
+		return api.TaskAction_TASK_ACTION_UNSPECIFIED
+	}
+}
+
+func convertProtoToTaskAction(action api.TaskAction) string {
+	switch action {
+	case api.TaskAction_TASK_ACTION_DEPLOY:
+		return task.ACTION_DEPLOY
+	case api.TaskAction_TASK_ACTION_START:
+		return task.ACTION_START
+	case api.TaskAction_TASK_ACTION_STOP:
+		return task.ACTION_STOP
+	case api.TaskAction_TASK_ACTION_RESTART:
+		return task.ACTION_RESTART
+	case api.TaskAction_TASK_ACTION_BACKUP:
+		return task.ACTION_BACKUP
+	case api.TaskAction_TASK_ACTION_DELETE:
+		return task.ACTION_DELETE
+	default:
+		return ""
+	}
 }
 
 func convertTaskStatusToProto(status int16) api.TaskStatus {
@@ -225,6 +602,12 @@ func convertTaskStatusToProto(status int16) api.TaskStatus {
 		return api.TaskStatus_TASK_STATUS_OK
 	case task.STATUS_DELETED:
 		return api.TaskStatus_TASK_STATUS_DELETED
+	case task.STATUS_FAILED:
+		return api.TaskStatus_TASK_STATUS_FAILED
+	case task.STATUS_CANCELLED:
+		return api.TaskStatus_TASK_STATUS_CANCELLED
+	case task.STATUS_TIMED_OUT:
+		return api.TaskStatus_TASK_STATUS_TIMED_OUT
 	default:
 		return api.TaskStatus_TASK_STATUS_UNSPECIFIED
 	}
@@ -240,6 +623,12 @@ func convertProtoToTaskStatus(status api.TaskStatus) int16 {
 		return task.STATUS_OK
 	case api.TaskStatus_TASK_STATUS_DELETED:
 		return task.STATUS_DELETED
+	case api.TaskStatus_TASK_STATUS_FAILED:
+		return task.STATUS_FAILED
+	case api.TaskStatus_TASK_STATUS_CANCELLED:
+		return task.STATUS_CANCELLED
+	case api.TaskStatus_TASK_STATUS_TIMED_OUT:
+		return task.STATUS_TIMED_OUT
 	default:
 		return task.STATUS_QUEUED
 	}
@@ -247,16 +636,77 @@ func convertProtoToTaskStatus(status api.TaskStatus) int16 {
 
 func convertMetricsToProto(hostMetrics *metrics.HostMetrics) *api.HostMetrics {
 	return &api.HostMetrics{
-		CpuUsage:     hostMetrics.CpuUsage,
-		RamAvailable: hostMetrics.RamAvailable,
-		RamTotal:     hostMetrics.RamTotal,
+		CpuUsage:      hostMetrics.CpuUsage,
+		RamAvailable:  hostMetrics.RamAvailable,
+		RamTotal:      hostMetrics.RamTotal,
+		LoadAvg_1:     hostMetrics.LoadAvg1,
+		LoadAvg_5:     hostMetrics.LoadAvg5,
+		LoadAvg_15:    hostMetrics.LoadAvg15,
+		DiskTotal:     hostMetrics.DiskTotal,
+		DiskAvailable: hostMetrics.DiskAvailable,
+		NetRxBytes:    hostMetrics.NetRxBytes,
+		NetTxBytes:    hostMetrics.NetTxBytes,
 	}
 }
 
 func convertProtoToMetrics(protoMetrics *api.HostMetrics) *metrics.HostMetrics {
 	return &metrics.HostMetrics{
-		CpuUsage:     protoMetrics.CpuUsage,
-		RamAvailable: protoMetrics.RamAvailable,
-		RamTotal:     protoMetrics.RamTotal,
+		CpuUsage:      protoMetrics.CpuUsage,
+		RamAvailable:  protoMetrics.RamAvailable,
+		RamTotal:      protoMetrics.RamTotal,
+		LoadAvg1:      protoMetrics.LoadAvg_1,
+		LoadAvg5:      protoMetrics.LoadAvg_5,
+		LoadAvg15:     protoMetrics.LoadAvg_15,
+		DiskTotal:     protoMetrics.DiskTotal,
+		DiskAvailable: protoMetrics.DiskAvailable,
+		NetRxBytes:    protoMetrics.NetRxBytes,
+		NetTxBytes:    protoMetrics.NetTxBytes,
+	}
+}
+
+func convertServerUsagesToProto(servers []metrics.ServerUsage) []*api.ServerUsage {
+	res := make([]*api.ServerUsage, len(servers))
+	for i, u := range servers {
+		res[i] = &api.ServerUsage{
+			ConfigurationId: u.ServerId.String(),
+			Running:         u.Running,
+			CpuUsage:        u.CpuUsage,
+			MemoryUsage:     u.MemoryUsage,
+			MemoryLimit:     u.MemoryLimit,
+			DiskUsage:       u.DiskUsage,
+			NetRxBytes:      u.NetRxBytes,
+			NetTxBytes:      u.NetTxBytes,
+			Revision:        u.Revision,
+		}
+		if !u.OOMKilledAt.IsZero() {
+			res[i].OomKilledAt = timestamppb.New(u.OOMKilledAt)
+		}
+	}
+	return res
+}
+
+func convertProtoToServerUsages(servers []*api.ServerUsage) ([]metrics.ServerUsage, error) {
+	res := make([]metrics.ServerUsage, len(servers))
+	for i, u := range servers {
+		serverUUID, err := uuid.Parse(u.ConfigurationId)
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = metrics.ServerUsage{
+			ServerId:    serverUUID,
+			Running:     u.Running,
+			CpuUsage:    u.CpuUsage,
+			MemoryUsage: u.MemoryUsage,
+			MemoryLimit: u.MemoryLimit,
+			DiskUsage:   u.DiskUsage,
+			NetRxBytes:  u.NetRxBytes,
+			NetTxBytes:  u.NetTxBytes,
+			Revision:    u.Revision,
+		}
+		if u.OomKilledAt != nil {
+			res[i].OOMKilledAt = u.OomKilledAt.AsTime()
+		}
 	}
+	return res, nil
 }
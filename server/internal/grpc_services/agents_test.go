@@ -0,0 +1,154 @@
+package grpc_services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/model/metrics"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/repository/mongotest"
+	"github.com/vv-sam/otus-project/server/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type memAgents struct {
+	agents map[uuid.UUID]*agent.Info
+}
+
+func (r *memAgents) Get(id uuid.UUID) (*agent.Info, error) {
+	if a, ok := r.agents[id]; ok {
+		return a, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *memAgents) GetAll() ([]*agent.Info, error) { return nil, nil }
+
+func (r *memAgents) List(q repository.ListQuery) (*repository.Page[*agent.Info], error) {
+	return &repository.Page[*agent.Info]{}, nil
+}
+
+func (r *memAgents) Add(a *agent.Info) error {
+	r.agents[a.AgentId] = a
+	return nil
+}
+
+func (r *memAgents) Update(id uuid.UUID, a *agent.Info) error {
+	r.agents[id] = a
+	return nil
+}
+
+func (r *memAgents) Delete(id uuid.UUID) error {
+	delete(r.agents, id)
+	return nil
+}
+
+type nopEvents struct{}
+
+func (nopEvents) Publish(e *event.Event) error                      { return nil }
+func (nopEvents) Revision(ctx context.Context) (string, error)      { return "", nil }
+func (nopEvents) CheckKept(ctx context.Context, after string) error { return nil }
+func (nopEvents) Subscribe(ctx context.Context, q services.EventsQuery, fn func(*event.Event) error) error {
+	return nil
+}
+
+type nopUsage struct{}
+
+func (nopUsage) Observe(agentId uuid.UUID, prev, cur []metrics.ServerUsage) {}
+
+func TestRegisterValidatesPortRange(t *testing.T) {
+	cases := []struct {
+		name     string
+		min, max uint32
+		code     codes.Code
+	}{
+		{"valid", 27000, 27999, codes.OK},
+		{"single port", 27000, 27000, codes.OK},
+		{"zero min", 0, 27999, codes.InvalidArgument},
+		{"empty", 0, 0, codes.OK},
+		{"min above max", 28000, 27000, codes.InvalidArgument},
+		{"above uint16", 27000, 70000, codes.InvalidArgument},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &memAgents{agents: map[uuid.UUID]*agent.Info{}}
+			s := NewAgentService(repo, &services.Validator{}, nil, nopEvents{})
+
+			id := uuid.New()
+			_, err := s.Register(context.Background(), &api.RegisterAgentRequest{
+				AgentId: id.String(),
+				Config:  &api.AgentConfig{PortRange: &api.PortRange{Min: tc.min, Max: tc.max}},
+			})
+			if code := status.Code(err); code != tc.code {
+				t.Fatalf("Register: %v, want %v", err, tc.code)
+			}
+
+			_, saved := repo.agents[id]
+			if saved != (tc.code == codes.OK) {
+				t.Errorf("agent saved = %v", saved)
+			}
+		})
+	}
+}
+
+// Агент регистрируется, присылает heartbeat и регистрируется повторно
+// с новой конфигурацией. В Mongo агент хранится одним документом
+func TestRegisterAndHeartbeatWithMongo(t *testing.T) {
+	ms := mongotest.NewServer()
+	rc := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	repo, err := repository.NewNosqlRepository[*agent.Info](rc, ms.Client(t), repository.NosqlRepositoryOptions{
+		RedisKey:        "agents",
+		MongoDatabase:   "otus",
+		MongoCollection: "agents",
+		IdPath:          repository.AgentSchema.IdPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agents := services.NewAgents(repo, nopEvents{})
+	s := NewAgentService(agents, &services.Validator{}, nopUsage{}, nopEvents{})
+	ctx := context.Background()
+
+	config := &api.AgentConfig{Location: "eu", PortRange: &api.PortRange{Min: 27000, Max: 27999}}
+	resp, err := s.Register(ctx, &api.RegisterAgentRequest{Config: config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.MustParse(resp.AgentId)
+
+	_, err = s.Heartbeat(ctx, &api.HeartbeatRequest{AgentId: resp.AgentId, Status: api.AgentStatus_AGENT_STATUS_DEGRADED})
+	if err != nil {
+		t.Fatalf("Heartbeat: %v", err)
+	}
+	if a, err := repo.Get(id); err != nil || a.Status != agent.STATUS_DEGRADED {
+		t.Fatalf("after heartbeat: %+v, %v", a, err)
+	}
+
+	config.Location = "us"
+	if _, err := s.Register(ctx, &api.RegisterAgentRequest{AgentId: resp.AgentId, Config: config}); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := repo.Get(id)
+	if err != nil || a.Status != agent.STATUS_ONLINE || a.Config.Location != "us" {
+		t.Fatalf("after second registration: %+v, %v", a, err)
+	}
+	if n := len(ms.Documents("otus", "agents")); n != 1 {
+		t.Errorf("%d agent documents, want 1", n)
+	}
+
+	_, err = s.Heartbeat(ctx, &api.HeartbeatRequest{AgentId: uuid.NewString()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("heartbeat of an unknown agent: %v, want NotFound", err)
+	}
+}
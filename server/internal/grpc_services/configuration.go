@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
 	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
 	"github.com/vv-sam/otus-project/server/internal/repository"
 	"github.com/vv-sam/otus-project/server/internal/services"
 	"google.golang.org/grpc/codes"
@@ -14,10 +15,11 @@ import (
 )
 
 type configurationRepository interface {
-	Get(id uuid.UUID) (*configuration.Factorio, error)
-	GetAll() ([]*configuration.Factorio, error)
-	Add(configuration *configuration.Factorio) error
-	Update(id uuid.UUID, configuration *configuration.Factorio) error
+	Get(id uuid.UUID) (*configuration.Envelope, error)
+	GetAll() ([]*configuration.Envelope, error)
+	List(q repository.ListQuery) (*repository.Page[*configuration.Envelope], error)
+	Add(configuration *configuration.Envelope) error
+	Update(id uuid.UUID, configuration *configuration.Envelope) error
 	Delete(id uuid.UUID) error
 }
 
@@ -25,12 +27,14 @@ type ConfigurationService struct {
 	api.UnimplementedConfigurationServiceServer
 	configurationRepository configurationRepository
 	validator               *services.Validator
+	events                  eventWatcher
 }
 
-func NewConfigurationService(configurationRepository configurationRepository, validator *services.Validator) *ConfigurationService {
+func NewConfigurationService(configurationRepository configurationRepository, validator *services.Validator, events eventWatcher) *ConfigurationService {
 	return &ConfigurationService{
 		configurationRepository: configurationRepository,
 		validator:               validator,
+		events:                  events,
 	}
 }
 
@@ -58,17 +62,31 @@ func (s *ConfigurationService) GetById(ctx context.Context, req *api.GetConfigBy
 }
 
 func (s *ConfigurationService) GetAll(ctx context.Context, req *api.GetAllConfigurationsRequest) (*api.GetAllConfigurationsResponse, error) {
-	configurations, err := s.configurationRepository.GetAll()
+	var types []any
+	for _, t := range req.Type {
+		configType, ok := convertProtoToConfigurationType(t)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown configuration type %v", t)
+		}
+		types = append(types, configType)
+	}
+
+	q := listQuery(req.Options,
+		repository.In("type", types...),
+		repository.In("agent_id", filterValues(req.AgentId, func(id string) any { return id })...),
+	)
+
+	page, err := s.configurationRepository.List(q)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get configurations: %v", err)
+		return nil, convertListError("configurations", err)
 	}
 
-	protoConfigurations := make([]*api.FactorioConfig, len(configurations))
-	for i, config := range configurations {
+	protoConfigurations := make([]*api.Configuration, len(page.Items))
+	for i, config := range page.Items {
 		protoConfigurations[i] = convertConfigurationToProto(config)
 	}
 
-	return &api.GetAllConfigurationsResponse{Configurations: protoConfigurations}, nil
+	return &api.GetAllConfigurationsResponse{Configurations: protoConfigurations, NextCursor: page.NextCursor}, nil
 }
 
 func (s *ConfigurationService) Post(ctx context.Context, req *api.PostConfigurationRequest) (*api.PostConfigurationResponse, error) {
@@ -86,6 +104,9 @@ func (s *ConfigurationService) Post(ctx context.Context, req *api.PostConfigurat
 	}
 
 	if err := s.configurationRepository.Add(configInfo); err != nil {
+		if st, ok := convertPlacementError(err); ok {
+			return nil, st
+		}
 		return nil, status.Errorf(codes.Internal, "failed to add configuration: %v", err)
 	}
 
@@ -112,7 +133,7 @@ func (s *ConfigurationService) Put(ctx context.Context, req *api.PutConfiguratio
 	}
 
 	// Set the configuration ID from the request
-	configInfo.Id = configUUID
+	configInfo.GetBase().Id = configUUID
 
 	if !s.validator.IsValid(configInfo) {
 		return nil, status.Error(codes.InvalidArgument, "invalid configuration")
@@ -122,6 +143,9 @@ func (s *ConfigurationService) Put(ctx context.Context, req *api.PutConfiguratio
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, status.Error(codes.NotFound, "configuration not found")
 		}
+		if st, ok := convertPlacementError(err); ok {
+			return nil, st
+		}
 		return nil, status.Errorf(codes.Internal, "failed to update configuration: %v", err)
 	}
 
@@ -148,37 +172,474 @@ func (s *ConfigurationService) Delete(ctx context.Context, req *api.DeleteConfig
 	return &api.DeleteConfigurationResponse{}, nil
 }
 
-func convertConfigurationToProto(config *configuration.Factorio) *api.FactorioConfig {
-	return &api.FactorioConfig{
-		Base: &api.BaseConfig{
-			Id:      config.Id.String(),
-			AgentId: config.AgentId.String(),
-			Port:    uint32(config.Port),
-			Type:    api.ConfigurationType_CONFIGURATION_TYPE_FACTORIO,
+// Снимок конфигураций и изменения: создание, изменение, удаление
+func (s *ConfigurationService) Watch(req *api.WatchConfigurationsRequest, stream api.ConfigurationService_WatchServer) error {
+	return watch(stream.Context(), s.events, req.Revision, watchSource[*configuration.Envelope]{
+		group: "configuration",
+		id:    func(e *event.Event) uuid.UUID { return e.ConfigurationId },
+		idOf:  func(c *configuration.Envelope) uuid.UUID { return c.GetId() },
+		get:   s.configurationRepository.Get,
+		all:   s.configurationRepository.GetAll,
+		send: func(t api.WatchEventType, revision string, id uuid.UUID, c *configuration.Envelope) error {
+			resp := &api.WatchConfigurationsResponse{Type: t, Revision: revision}
+			if id != uuid.Nil {
+				resp.Id = id.String()
+			}
+			if c != nil {
+				resp.Configuration = convertConfigurationToProto(c)
+			}
+			return stream.Send(resp)
 		},
+	})
+}
+
+// Ошибки выбора агента и резервирования портов на нём
+func convertPlacementError(err error) (error, bool) {
+	switch {
+	case errors.Is(err, services.ErrNoSuitableAgent):
+		return status.Error(codes.FailedPrecondition, err.Error()), true
+	case errors.Is(err, services.ErrUnknownAgent), errors.Is(err, services.ErrPortOutOfRange), errors.Is(err, services.ErrDuplicatePort):
+		return status.Error(codes.InvalidArgument, err.Error()), true
+	case errors.Is(err, services.ErrPortConflict):
+		return status.Error(codes.AlreadyExists, err.Error()), true
+	case errors.Is(err, services.ErrPortsExhausted), errors.Is(err, services.ErrExceedsCapacity):
+		return status.Error(codes.ResourceExhausted, err.Error()), true
+	default:
+		return nil, false
 	}
 }
 
-func convertProtoToConfiguration(protoConfig *api.FactorioConfig) (*configuration.Factorio, error) {
-	if protoConfig.Base == nil {
-		return nil, errors.New("base configuration is required")
+func convertConfigurationToProto(config *configuration.Envelope) *api.Configuration {
+	switch c := config.Configuration.(type) {
+	case *configuration.Factorio:
+		return &api.Configuration{Config: &api.Configuration_Factorio{Factorio: convertFactorioToProto(c)}}
+	case *configuration.Minecraft:
+		return &api.Configuration{Config: &api.Configuration_Minecraft{Minecraft: convertMinecraftToProto(c)}}
+	default:
+		return &api.Configuration{}
 	}
+}
 
-	configUUID, err := uuid.Parse(protoConfig.Base.Id)
+func convertProtoToConfiguration(protoConfig *api.Configuration) (*configuration.Envelope, error) {
+	var (
+		config configuration.Configuration
+		err    error
+	)
+
+	switch c := protoConfig.Config.(type) {
+	case *api.Configuration_Factorio:
+		config, err = convertProtoToFactorio(c.Factorio)
+	case *api.Configuration_Minecraft:
+		config, err = convertProtoToMinecraft(c.Minecraft)
+	default:
+		return nil, errors.New("configuration type is required")
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	agentUUID, err := uuid.Parse(protoConfig.Base.AgentId)
+	return &configuration.Envelope{Configuration: config}, nil
+}
+
+func convertProtoToConfigurationType(t api.ConfigurationType) (string, bool) {
+	switch t {
+	case api.ConfigurationType_CONFIGURATION_TYPE_FACTORIO:
+		return configuration.CONFIGURATION_TYPE_FACTORIO, true
+	case api.ConfigurationType_CONFIGURATION_TYPE_MINECRAFT:
+		return configuration.CONFIGURATION_TYPE_MINECRAFT, true
+	default:
+		return "", false
+	}
+}
+
+func convertBaseToProto(base *configuration.BaseConfig, configType api.ConfigurationType) *api.BaseConfig {
+	return &api.BaseConfig{
+		Id:        base.Id.String(),
+		AgentId:   base.AgentId.String(),
+		Port:      uint32(base.Port),
+		Type:      configType,
+		Placement: convertPlacementToProto(base.Placement),
+		Resources: convertResourcesToProto(base.Resources),
+	}
+}
+
+func convertProtoToBase(protoBase *api.BaseConfig, base *configuration.BaseConfig) error {
+	if protoBase == nil {
+		return errors.New("base configuration is required")
+	}
+
+	configUUID, err := uuid.Parse(protoBase.Id)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	// Без агента конфигурацию размещает планировщик
+	agentUUID := uuid.Nil
+	if protoBase.AgentId != "" {
+		agentUUID, err = uuid.Parse(protoBase.AgentId)
+		if err != nil {
+			return err
+		}
+	}
+
+	base.Id = configUUID
+	base.AgentId = agentUUID
+	base.Port = uint16(protoBase.Port)
+	base.Placement = convertProtoToPlacement(protoBase.Placement)
+	base.Resources = convertProtoToResources(protoBase.Resources)
+
+	return nil
+}
+
+func convertPlacementToProto(p *configuration.Placement) *api.Placement {
+	if p == nil {
+		return nil
+	}
+
+	return &api.Placement{
+		Location:       p.Location,
+		Labels:         p.Labels,
+		MinMemoryBytes: p.MinMemoryBytes,
+		MinCpuCores:    p.MinCpuCores,
+	}
+}
+
+func convertProtoToPlacement(p *api.Placement) *configuration.Placement {
+	if p == nil {
+		return nil
+	}
+
+	return &configuration.Placement{
+		Location:       p.Location,
+		Labels:         p.Labels,
+		MinMemoryBytes: p.MinMemoryBytes,
+		MinCpuCores:    p.MinCpuCores,
+	}
+}
+
+func convertResourcesToProto(r *configuration.Resources) *api.Resources {
+	if r == nil {
+		return nil
+	}
+
+	return &api.Resources{
+		CpuCores:    r.CpuCores,
+		CpuShares:   r.CpuShares,
+		MemoryBytes: r.MemoryBytes,
+		DiskBytes:   r.DiskBytes,
+	}
+}
+
+func convertProtoToResources(r *api.Resources) *configuration.Resources {
+	if r == nil {
+		return nil
 	}
 
-	config := &configuration.Factorio{}
-	config.Id = configUUID
-	config.AgentId = agentUUID
-	config.Port = uint16(protoConfig.Base.Port)
+	return &configuration.Resources{
+		CpuCores:    r.CpuCores,
+		CpuShares:   r.CpuShares,
+		MemoryBytes: r.MemoryBytes,
+		DiskBytes:   r.DiskBytes,
+	}
+}
+
+func convertFactorioToProto(config *configuration.Factorio) *api.FactorioConfig {
+	server := config.Server
+	m := config.Map
+	mapGen := config.MapGen
+
+	autoplace := make(map[string]*api.FactorioResourceSettings, len(mapGen.AutoplaceControls))
+	for name, r := range mapGen.AutoplaceControls {
+		autoplace[name] = &api.FactorioResourceSettings{
+			Frequency: r.Frequency,
+			Size:      r.Size,
+			Richness:  r.Richness,
+		}
+	}
+
+	return &api.FactorioConfig{
+		Base: convertBaseToProto(&config.BaseConfig, api.ConfigurationType_CONFIGURATION_TYPE_FACTORIO),
+		Server: &api.FactorioServerSettings{
+			Name:                                 server.Name,
+			Description:                          server.Description,
+			Tags:                                 server.Tags,
+			MaxPlayers:                           int32(server.MaxPlayers),
+			Username:                             server.Username,
+			RequireUserVerification:              server.RequireUserVerification,
+			MaxUploadInKilobytesPerSecond:        int32(server.MaxUploadInKilobytesPerSecond),
+			MaxUploadSlots:                       int32(server.MaxUploadSlots),
+			MinimumLatencyInTicks:                int32(server.MinimumLatencyInTicks),
+			MaxHeartbeatsPerSecond:               int32(server.MaxHeartbeatsPerSecond),
+			IgnorePlayerLimitForReturningPlayers: server.IgnorePlayerLimitForReturningPlayers,
+			AllowCommands:                        server.AllowCommands,
+			AutosaveInterval:                     int32(server.AutosaveInterval),
+			AutosaveSlots:                        int32(server.AutosaveSlots),
+			AfkAutokickInterval:                  int32(server.AfkAutokickInterval),
+			AutoPause:                            server.AutoPause,
+			AutoPauseWhenPlayersConnect:          server.AutoPauseWhenPlayersConnect,
+			OnlyAdminsCanPauseTheGame:            server.OnlyAdminsCanPauseTheGame,
+			AutosaveOnlyOnServer:                 server.AutosaveOnlyOnServer,
+			NonBlockingSaving:                    server.NonBlockingSaving,
+			MinimumSegmentSize:                   int32(server.MinimumSegmentSize),
+			MinimumSegmentSizePeerCount:          int32(server.MinimumSegmentSizePeerCount),
+			MaximumSegmentSize:                   int32(server.MaximumSegmentSize),
+			MaximumSegmentSizePeerCount:          int32(server.MaximumSegmentSizePeerCount),
+			Visibility: &api.FactorioVisibility{
+				Public: server.Visibility.Public,
+				Lan:    server.Visibility.Lan,
+			},
+		},
+		Map: &api.FactorioMapSettings{
+			DifficultySettings: &api.FactorioDifficultySettings{
+				TechnologyPriceMultiplier: m.DifficultySettings.TechnologyPriceMultiplier,
+				SpoilTimeModifier:         m.DifficultySettings.SpoilTimeModifier,
+			},
+			Pollution: &api.FactorioPollutionSettings{
+				Enabled:                                 m.Pollution.Enabled,
+				DiffusionRatio:                          m.Pollution.DiffusionRatio,
+				MinToDiffuse:                            int32(m.Pollution.MinToDiffuse),
+				Ageing:                                  int32(m.Pollution.Ageing),
+				ExpectedMaxPerChunk:                     int32(m.Pollution.ExpectedMaxPerChunk),
+				MinToShowPerChunk:                       int32(m.Pollution.MinToShowPerChunk),
+				MinPollutionToDamageTrees:               int32(m.Pollution.MinPollutionToDamageTrees),
+				PollutionWithMaxForestDamage:            int32(m.Pollution.PollutionWithMaxForestDamage),
+				PollutionPerTreeDamage:                  int32(m.Pollution.PollutionPerTreeDamage),
+				PollutionRestoredPerTreeDamage:          int32(m.Pollution.PollutionRestoredPerTreeDamage),
+				MaxPollutionToRestoreTrees:              int32(m.Pollution.MaxPollutionToRestoreTrees),
+				EnemyAttackPollutionConsumptionModifier: int32(m.Pollution.EnemyAttackPollutionConsumptionModifier),
+			},
+			EnemyEvolution: &api.FactorioEnemyEvolution{
+				Enabled:         m.EnemyEvolution.Enabled,
+				TimeFactor:      m.EnemyEvolution.TimeFactor,
+				DestroyFactor:   m.EnemyEvolution.DestroyFactor,
+				PollutionFactor: m.EnemyEvolution.PollutionFactor,
+			},
+			EnemyExpansion: &api.FactorioEnemyExpansion{
+				Enabled:                          m.EnemyExpansion.Enabled,
+				MaxExpansionDistance:             int32(m.EnemyExpansion.MaxExpansionDistance),
+				FriendlyBaseInfluenceRadius:      int32(m.EnemyExpansion.FriendlyBaseInfluenceRadius),
+				EnemyBuildingInfluenceRadius:     int32(m.EnemyExpansion.EnemyBuildingInfluenceRadius),
+				BuildingCoefficient:              m.EnemyExpansion.BuildingCoefficient,
+				OtherBaseCoefficient:             m.EnemyExpansion.OtherBaseCoefficient,
+				NeighbouringChunkCoefficient:     m.EnemyExpansion.NeighbouringChunkCoefficient,
+				NeighbouringBaseChunkCoefficient: m.EnemyExpansion.NeighbouringBaseChunkCoefficient,
+				MaxCollidingTilesCoefficient:     m.EnemyExpansion.MaxCollidingTilesCoefficient,
+				SettlerGroupMinSize:              int32(m.EnemyExpansion.SettlerGroupMinSize),
+				SettlerGroupMaxSize:              int32(m.EnemyExpansion.SettlerGroupMaxSize),
+				MinExpansionCooldown:             int32(m.EnemyExpansion.MinExpansionCooldown),
+				MaxExpansionCooldown:             int32(m.EnemyExpansion.MaxExpansionCooldown),
+			},
+			UnitGroup: &api.FactorioUnitGroup{
+				MinGroupGatheringTime:          int32(m.UnitGroup.MinGroupGatheringTime),
+				MaxGroupGatheringTime:          int32(m.UnitGroup.MaxGroupGatheringTime),
+				MaxWaitTimeForLateMembers:      int32(m.UnitGroup.MaxWaitTimeForLateMembers),
+				MaxGroupRadius:                 m.UnitGroup.MaxGroupRadius,
+				MinGroupRadius:                 m.UnitGroup.MinGroupRadius,
+				MaxMemberSpeedupWhenBehind:     m.UnitGroup.MaxMemberSpeedupWhenBehind,
+				MaxMemberSlowdownWhenAhead:     m.UnitGroup.MaxMemberSlowdownWhenAhead,
+				MaxGroupSlowdownFactor:         m.UnitGroup.MaxGroupSlowdownFactor,
+				MaxGroupMemberFallbackFactor:   int32(m.UnitGroup.MaxGroupMemberFallbackFactor),
+				MemberDisownDistance:           int32(m.UnitGroup.MemberDisownDistance),
+				TickToleranceWhenMemberArrives: int32(m.UnitGroup.TickToleranceWhenMemberArrives),
+				MaxGatheringUnitGroups:         int32(m.UnitGroup.MaxGatheringUnitGroups),
+				MaxUnitGroupSize:               int32(m.UnitGroup.MaxUnitGroupSize),
+			},
+		},
+		MapGen: &api.FactorioMapGenSettings{
+			Width:             int32(mapGen.Width),
+			Height:            int32(mapGen.Height),
+			StartingArea:      int32(mapGen.StartingArea),
+			PeacefulMode:      mapGen.PeacefulMode,
+			AutoplaceControls: autoplace,
+			CliffSettings: &api.FactorioCliffSettings{
+				Name:                   mapGen.CliffSettings.Name,
+				CliffElevation_0:       int32(mapGen.CliffSettings.CliffElevation0),
+				CliffElevationInterval: int32(mapGen.CliffSettings.CliffElevationInterval),
+				Richness:               int32(mapGen.CliffSettings.Richness),
+			},
+			Seed: int32(mapGen.Seed),
+		},
+		RconPort: uint32(config.RconPort),
+	}
+}
+
+func convertProtoToFactorio(protoConfig *api.FactorioConfig) (*configuration.Factorio, error) {
+	config := &configuration.Factorio{RconPort: uint16(protoConfig.RconPort)}
+	if err := convertProtoToBase(protoConfig.Base, &config.BaseConfig); err != nil {
+		return nil, err
+	}
 	config.Type = configuration.CONFIGURATION_TYPE_FACTORIO
 
+	if server := protoConfig.Server; server != nil {
+		s := &config.Server
+		s.Name = server.Name
+		s.Description = server.Description
+		s.Tags = server.Tags
+		s.MaxPlayers = int(server.MaxPlayers)
+		s.Username = server.Username
+		s.Token = server.Token
+		s.RequireUserVerification = server.RequireUserVerification
+		s.MaxUploadInKilobytesPerSecond = int(server.MaxUploadInKilobytesPerSecond)
+		s.MaxUploadSlots = int(server.MaxUploadSlots)
+		s.MinimumLatencyInTicks = int(server.MinimumLatencyInTicks)
+		s.MaxHeartbeatsPerSecond = int(server.MaxHeartbeatsPerSecond)
+		s.IgnorePlayerLimitForReturningPlayers = server.IgnorePlayerLimitForReturningPlayers
+		s.AllowCommands = server.AllowCommands
+		s.AutosaveInterval = int(server.AutosaveInterval)
+		s.AutosaveSlots = int(server.AutosaveSlots)
+		s.AfkAutokickInterval = int(server.AfkAutokickInterval)
+		s.AutoPause = server.AutoPause
+		s.AutoPauseWhenPlayersConnect = server.AutoPauseWhenPlayersConnect
+		s.OnlyAdminsCanPauseTheGame = server.OnlyAdminsCanPauseTheGame
+		s.AutosaveOnlyOnServer = server.AutosaveOnlyOnServer
+		s.NonBlockingSaving = server.NonBlockingSaving
+		s.MinimumSegmentSize = int(server.MinimumSegmentSize)
+		s.MinimumSegmentSizePeerCount = int(server.MinimumSegmentSizePeerCount)
+		s.MaximumSegmentSize = int(server.MaximumSegmentSize)
+		s.MaximumSegmentSizePeerCount = int(server.MaximumSegmentSizePeerCount)
+		s.Password = server.Password
+		s.GamePassword = server.GamePassword
+		s.Visibility.Public = server.Visibility.GetPublic()
+		s.Visibility.Lan = server.Visibility.GetLan()
+	}
+
+	if m := protoConfig.Map; m != nil {
+		c := &config.Map
+		c.DifficultySettings.TechnologyPriceMultiplier = m.DifficultySettings.GetTechnologyPriceMultiplier()
+		c.DifficultySettings.SpoilTimeModifier = m.DifficultySettings.GetSpoilTimeModifier()
+
+		if p := m.Pollution; p != nil {
+			c.Pollution.Enabled = p.Enabled
+			c.Pollution.DiffusionRatio = p.DiffusionRatio
+			c.Pollution.MinToDiffuse = int(p.MinToDiffuse)
+			c.Pollution.Ageing = int(p.Ageing)
+			c.Pollution.ExpectedMaxPerChunk = int(p.ExpectedMaxPerChunk)
+			c.Pollution.MinToShowPerChunk = int(p.MinToShowPerChunk)
+			c.Pollution.MinPollutionToDamageTrees = int(p.MinPollutionToDamageTrees)
+			c.Pollution.PollutionWithMaxForestDamage = int(p.PollutionWithMaxForestDamage)
+			c.Pollution.PollutionPerTreeDamage = int(p.PollutionPerTreeDamage)
+			c.Pollution.PollutionRestoredPerTreeDamage = int(p.PollutionRestoredPerTreeDamage)
+			c.Pollution.MaxPollutionToRestoreTrees = int(p.MaxPollutionToRestoreTrees)
+			c.Pollution.EnemyAttackPollutionConsumptionModifier = int(p.EnemyAttackPollutionConsumptionModifier)
+		}
+
+		if e := m.EnemyEvolution; e != nil {
+			c.EnemyEvolution.Enabled = e.Enabled
+			c.EnemyEvolution.TimeFactor = e.TimeFactor
+			c.EnemyEvolution.DestroyFactor = e.DestroyFactor
+			c.EnemyEvolution.PollutionFactor = e.PollutionFactor
+		}
+
+		if e := m.EnemyExpansion; e != nil {
+			c.EnemyExpansion.Enabled = e.Enabled
+			c.EnemyExpansion.MaxExpansionDistance = int(e.MaxExpansionDistance)
+			c.EnemyExpansion.FriendlyBaseInfluenceRadius = int(e.FriendlyBaseInfluenceRadius)
+			c.EnemyExpansion.EnemyBuildingInfluenceRadius = int(e.EnemyBuildingInfluenceRadius)
+			c.EnemyExpansion.BuildingCoefficient = e.BuildingCoefficient
+			c.EnemyExpansion.OtherBaseCoefficient = e.OtherBaseCoefficient
+			c.EnemyExpansion.NeighbouringChunkCoefficient = e.NeighbouringChunkCoefficient
+			c.EnemyExpansion.NeighbouringBaseChunkCoefficient = e.NeighbouringBaseChunkCoefficient
+			c.EnemyExpansion.MaxCollidingTilesCoefficient = e.MaxCollidingTilesCoefficient
+			c.EnemyExpansion.SettlerGroupMinSize = int(e.SettlerGroupMinSize)
+			c.EnemyExpansion.SettlerGroupMaxSize = int(e.SettlerGroupMaxSize)
+			c.EnemyExpansion.MinExpansionCooldown = int(e.MinExpansionCooldown)
+			c.EnemyExpansion.MaxExpansionCooldown = int(e.MaxExpansionCooldown)
+		}
+
+		if u := m.UnitGroup; u != nil {
+			c.UnitGroup.MinGroupGatheringTime = int(u.MinGroupGatheringTime)
+			c.UnitGroup.MaxGroupGatheringTime = int(u.MaxGroupGatheringTime)
+			c.UnitGroup.MaxWaitTimeForLateMembers = int(u.MaxWaitTimeForLateMembers)
+			c.UnitGroup.MaxGroupRadius = u.MaxGroupRadius
+			c.UnitGroup.MinGroupRadius = u.MinGroupRadius
+			c.UnitGroup.MaxMemberSpeedupWhenBehind = u.MaxMemberSpeedupWhenBehind
+			c.UnitGroup.MaxMemberSlowdownWhenAhead = u.MaxMemberSlowdownWhenAhead
+			c.UnitGroup.MaxGroupSlowdownFactor = u.MaxGroupSlowdownFactor
+			c.UnitGroup.MaxGroupMemberFallbackFactor = int(u.MaxGroupMemberFallbackFactor)
+			c.UnitGroup.MemberDisownDistance = int(u.MemberDisownDistance)
+			c.UnitGroup.TickToleranceWhenMemberArrives = int(u.TickToleranceWhenMemberArrives)
+			c.UnitGroup.MaxGatheringUnitGroups = int(u.MaxGatheringUnitGroups)
+			c.UnitGroup.MaxUnitGroupSize = int(u.MaxUnitGroupSize)
+		}
+	}
+
+	if g := protoConfig.MapGen; g != nil {
+		c := &config.MapGen
+		c.Width = int(g.Width)
+		c.Height = int(g.Height)
+		c.StartingArea = int(g.StartingArea)
+		c.PeacefulMode = g.PeacefulMode
+		c.Seed = int(g.Seed)
+		c.CliffSettings.Name = g.CliffSettings.GetName()
+		c.CliffSettings.CliffElevation0 = int(g.CliffSettings.GetCliffElevation_0())
+		c.CliffSettings.CliffElevationInterval = int(g.CliffSettings.GetCliffElevationInterval())
+		c.CliffSettings.Richness = int(g.CliffSettings.GetRichness())
+
+		for name, r := range g.AutoplaceControls {
+			c.SetAutoplaceControl(name, r.GetFrequency(), r.GetSize(), r.GetRichness())
+		}
+	}
+
+	return config, nil
+}
+
+func convertMinecraftToProto(config *configuration.Minecraft) *api.MinecraftConfig {
+	return &api.MinecraftConfig{
+		Base:         convertBaseToProto(&config.BaseConfig, api.ConfigurationType_CONFIGURATION_TYPE_MINECRAFT),
+		Seed:         config.Seed,
+		RconPort:     uint32(config.RconPort),
+		Gamemode:     convertGamemodeToProto(config.Gamemode),
+		ServerName:   config.ServerName,
+		MaxPlayers:   uint32(config.MaxPlayers),
+		ViewDistance: int32(config.ViewDistance),
+		Properties:   config.Properties,
+	}
+}
+
+func convertProtoToMinecraft(protoConfig *api.MinecraftConfig) (*configuration.Minecraft, error) {
+	config := &configuration.Minecraft{
+		Seed:         protoConfig.Seed,
+		RconPort:     uint16(protoConfig.RconPort),
+		Gamemode:     convertProtoToGamemode(protoConfig.Gamemode),
+		ServerName:   protoConfig.ServerName,
+		MaxPlayers:   uint(protoConfig.MaxPlayers),
+		ViewDistance: int(protoConfig.ViewDistance),
+		Properties:   protoConfig.Properties,
+	}
+	if err := convertProtoToBase(protoConfig.Base, &config.BaseConfig); err != nil {
+		return nil, err
+	}
+	config.Type = configuration.CONFIGURATION_TYPE_MINECRAFT
+
 	return config, nil
 }
+
+func convertGamemodeToProto(gamemode string) api.MinecraftGamemode {
+	switch gamemode {
+	case configuration.GAMEMODE_SURVIVAL:
+		return api.MinecraftGamemode_MINECRAFT_GAMEMODE_SURVIVAL
+	case configuration.GAMEMODE_CREATIVE:
+		return api.MinecraftGamemode_MINECRAFT_GAMEMODE_CREATIVE
+	case configuration.GAMEMODE_ADVENTURE:
+		return api.MinecraftGamemode_MINECRAFT_GAMEMODE_ADVENTURE
+	case configuration.GAMEMODE_SPECTATOR:
+		return api.MinecraftGamemode_MINECRAFT_GAMEMODE_SPECTATOR
+	default:
+		return api.MinecraftGamemode_MINECRAFT_GAMEMODE_UNSPECIFIED
+	}
+}
+
+func convertProtoToGamemode(gamemode api.MinecraftGamemode) string {
+	switch gamemode {
+	case api.MinecraftGamemode_MINECRAFT_GAMEMODE_SURVIVAL:
+		return configuration.GAMEMODE_SURVIVAL
+	case api.MinecraftGamemode_MINECRAFT_GAMEMODE_CREATIVE:
+		return configuration.GAMEMODE_CREATIVE
+	case api.MinecraftGamemode_MINECRAFT_GAMEMODE_ADVENTURE:
+		return configuration.GAMEMODE_ADVENTURE
+	case api.MinecraftGamemode_MINECRAFT_GAMEMODE_SPECTATOR:
+		return configuration.GAMEMODE_SPECTATOR
+	default:
+		return ""
+	}
+}
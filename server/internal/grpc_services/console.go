@@ -0,0 +1,50 @@
+package grpc_services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ConsoleService struct {
+	api.UnimplementedConsoleServiceServer
+	console *services.Console
+}
+
+func NewConsoleService(console *services.Console) *ConsoleService {
+	return &ConsoleService{console: console}
+}
+
+// Выполняет команду в консоли игрового сервера. id сервера - id его конфигурации
+func (s *ConsoleService) RunCommand(ctx context.Context, req *api.RunCommandRequest) (*api.RunCommandResponse, error) {
+	serverUUID, err := uuid.Parse(req.ConfigurationId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse configuration id: %v", err)
+	}
+
+	if req.Command == "" {
+		return nil, status.Error(codes.InvalidArgument, "command is required")
+	}
+
+	output, err := s.console.Run(ctx, userFromContext(ctx), serverUUID, req.Command)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "configuration not found")
+		case errors.Is(err, services.ErrConsoleUnsupported):
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		case errors.Is(err, services.ErrServerNotAssigned), errors.Is(err, services.ErrConsoleNotConfigured), errors.Is(err, services.ErrCommandFailed):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			return nil, convertSessionError(err)
+		}
+	}
+
+	return &api.RunCommandResponse{Output: output}, nil
+}
@@ -0,0 +1,82 @@
+package grpc_services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ControlService struct {
+	api.UnimplementedAgentControlServer
+	sessions *services.AgentSessions
+}
+
+func NewControlService(sessions *services.AgentSessions) *ControlService {
+	return &ControlService{sessions: sessions}
+}
+
+// Первое сообщение агента должно представить его, дальше поток обслуживается реестром сессий
+func (s *ControlService) Connect(stream api.AgentControl_ConnectServer) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	hello := msg.GetHello()
+	if hello == nil {
+		return status.Error(codes.InvalidArgument, "first message must be hello")
+	}
+
+	agentUUID, err := uuid.Parse(hello.AgentId)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to parse agent id: %v", err)
+	}
+	if err := authorizeAgent(stream.Context(), agentUUID); err != nil {
+		return err
+	}
+
+	session := s.sessions.Attach(agentUUID, stream)
+	if err := s.sessions.Serve(stream.Context(), session); err != nil && stream.Context().Err() == nil {
+		return status.Error(codes.Aborted, err.Error())
+	}
+
+	return nil
+}
+
+// Выполняет команду на подключённом агенте и возвращает её результат
+func (s *ControlService) Execute(ctx context.Context, req *api.ExecuteCommandRequest) (*api.ExecuteCommandResponse, error) {
+	agentUUID, err := uuid.Parse(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse agent id: %v", err)
+	}
+
+	if req.Command == nil || req.Command.Type == api.CommandType_COMMAND_TYPE_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "command is required")
+	}
+
+	res, err := s.sessions.Send(ctx, agentUUID, req.Command, time.Duration(req.TimeoutSeconds)*time.Second)
+	if err != nil {
+		return nil, convertSessionError(err)
+	}
+
+	return &api.ExecuteCommandResponse{Result: res}, nil
+}
+
+func convertSessionError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrAgentNotConnected):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, services.ErrCommandTimeout):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, services.ErrSessionClosed):
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "failed to execute command: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package grpc_services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type EnrollmentService struct {
+	api.UnimplementedEnrollmentServiceServer
+	enrollment *services.Enrollment
+}
+
+func NewEnrollmentService(enrollment *services.Enrollment) *EnrollmentService {
+	return &EnrollmentService{enrollment: enrollment}
+}
+
+func (s *EnrollmentService) CreateJoinToken(ctx context.Context, req *api.CreateJoinTokenRequest) (*api.CreateJoinTokenResponse, error) {
+	var agentUUID uuid.UUID
+	if req.AgentId != "" {
+		var err error
+		agentUUID, err = uuid.Parse(req.AgentId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to parse agent id: %v", err)
+		}
+	}
+
+	token, t, err := s.enrollment.CreateJoinToken(userFromContext(ctx), agentUUID, time.Duration(req.TtlSeconds)*time.Second)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create join token: %v", err)
+	}
+
+	return &api.CreateJoinTokenResponse{Token: token, ExpiresAt: timestamppb.New(t.ExpiresAt)}, nil
+}
+
+// Обмен join-токена на клиентский сертификат. Не требует авторизации: её заменяет сам токен
+func (s *EnrollmentService) Enroll(ctx context.Context, req *api.EnrollRequest) (*api.EnrollResponse, error) {
+	if req.JoinToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "join token is required")
+	}
+
+	c, certPEM, err := s.enrollment.Enroll(req.JoinToken, req.Csr)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCSR):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, services.ErrInvalidJoinToken):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		default:
+			return nil, status.Errorf(codes.Internal, "failed to enroll agent: %v", err)
+		}
+	}
+
+	return &api.EnrollResponse{
+		AgentId:       c.AgentId.String(),
+		Certificate:   certPEM,
+		CaCertificate: s.enrollment.CACertificate(),
+		Info:          convertCertificateToProto(c),
+	}, nil
+}
+
+func (s *EnrollmentService) GetCertificates(ctx context.Context, req *api.GetCertificatesRequest) (*api.GetCertificatesResponse, error) {
+	agentUUID, err := uuid.Parse(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse agent id: %v", err)
+	}
+
+	certs, err := s.enrollment.Certificates(agentUUID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get certificates: %v", err)
+	}
+
+	protoCerts := make([]*api.AgentCertificate, len(certs))
+	for i, c := range certs {
+		protoCerts[i] = convertCertificateToProto(c)
+	}
+
+	return &api.GetCertificatesResponse{Certificates: protoCerts}, nil
+}
+
+func (s *EnrollmentService) RevokeCertificate(ctx context.Context, req *api.RevokeCertificateRequest) (*api.RevokeCertificateResponse, error) {
+	agentUUID, err := uuid.Parse(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse agent id: %v", err)
+	}
+
+	certUUID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse id: %v", err)
+	}
+
+	if err := s.enrollment.Revoke(agentUUID, certUUID, userFromContext(ctx)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "certificate not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to revoke certificate: %v", err)
+	}
+
+	return &api.RevokeCertificateResponse{}, nil
+}
+
+func convertCertificateToProto(c *agent.Certificate) *api.AgentCertificate {
+	protoCert := &api.AgentCertificate{
+		Id:        c.Id.String(),
+		AgentId:   c.AgentId.String(),
+		IssuedAt:  timestamppb.New(c.IssuedAt),
+		ExpiresAt: timestamppb.New(c.ExpiresAt),
+		RevokedBy: c.RevokedBy,
+	}
+	if c.IsRevoked() {
+		protoCert.RevokedAt = timestamppb.New(c.RevokedAt)
+	}
+
+	return protoCert
+}
@@ -0,0 +1,89 @@
+package grpc_services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/health"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ServerHealthService struct {
+	api.UnimplementedServerHealthServiceServer
+	h *services.ServerHealth
+}
+
+func NewServerHealthService(h *services.ServerHealth) *ServerHealthService {
+	return &ServerHealthService{h: h}
+}
+
+func (s *ServerHealthService) GetServerHealth(ctx context.Context, req *api.GetServerHealthRequest) (*api.GetServerHealthResponse, error) {
+	serverUUID, err := uuid.Parse(req.ConfigurationId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse configuration id: %v", err)
+	}
+
+	h, err := s.h.Get(serverUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "configuration not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get server health: %v", err)
+	}
+
+	return &api.GetServerHealthResponse{Health: convertHealthToProto(h)}, nil
+}
+
+func (s *ServerHealthService) GetAllServerHealth(ctx context.Context, req *api.GetAllServerHealthRequest) (*api.GetAllServerHealthResponse, error) {
+	all := s.h.GetAll()
+
+	res := make([]*api.ServerHealth, len(all))
+	for i, h := range all {
+		res[i] = convertHealthToProto(h)
+	}
+
+	return &api.GetAllServerHealthResponse{Health: res}, nil
+}
+
+func convertHealthToProto(h *health.Health) *api.ServerHealth {
+	res := &api.ServerHealth{
+		ConfigurationId: h.ServerId.String(),
+		Status:          convertHealthStatusToProto(h.Status),
+		Failures:        h.Failures,
+		Error:           h.Error,
+	}
+	if !h.CheckedAt.IsZero() {
+		res.CheckedAt = timestamppb.New(h.CheckedAt)
+	}
+	if !h.ChangedAt.IsZero() {
+		res.ChangedAt = timestamppb.New(h.ChangedAt)
+	}
+	if h.Probe != nil {
+		res.Result = &api.ProbeResult{
+			LatencyMs:     h.Probe.LatencyMs,
+			Version:       h.Probe.Version,
+			Motd:          h.Probe.Motd,
+			PlayersOnline: h.Probe.PlayersOnline,
+			PlayersMax:    h.Probe.PlayersMax,
+			Players:       h.Probe.Players,
+		}
+	}
+	return res
+}
+
+func convertHealthStatusToProto(s int16) api.HealthStatus {
+	switch s {
+	case health.STATUS_HEALTHY:
+		return api.HealthStatus_HEALTH_STATUS_HEALTHY
+	case health.STATUS_UNHEALTHY:
+		return api.HealthStatus_HEALTH_STATUS_UNHEALTHY
+	default:
+		return api.HealthStatus_HEALTH_STATUS_UNKNOWN
+	}
+}
@@ -0,0 +1,53 @@
+package grpc_services
+
+import (
+	"errors"
+	"strings"
+
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Запрос страницы по параметрам списка. Условия без значений пропускаются
+func listQuery(opts *api.ListOptions, conditions ...repository.Condition) repository.ListQuery {
+	q := repository.ListQuery{}
+	for _, c := range conditions {
+		if len(c.Values) > 0 && c.Values[0] != nil {
+			q.Conditions = append(q.Conditions, c)
+		}
+	}
+
+	if opts != nil {
+		q.Limit = int(opts.Limit)
+		q.Cursor = opts.Cursor
+		q.Sort, q.Desc = strings.CutPrefix(opts.Sort, "-")
+	}
+	return q
+}
+
+// Значения фильтра, для пустого фильтра - nil
+func filterValues[T any](items []T, convert func(item T) any) []any {
+	var values []any
+	for _, item := range items {
+		values = append(values, convert(item))
+	}
+	return values
+}
+
+// Граница диапазона времени, nil если не задана
+func timeValue(t *timestamppb.Timestamp) any {
+	if t == nil {
+		return nil
+	}
+	return t.AsTime()
+}
+
+func convertListError(entity string, err error) error {
+	if errors.Is(err, repository.ErrInvalidQuery) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Errorf(codes.Internal, "failed to get %s: %v", entity, err)
+}
@@ -0,0 +1,55 @@
+package grpc_services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type LogService struct {
+	api.UnimplementedLogServiceServer
+	logs *services.ServerLogs
+}
+
+func NewLogService(logs *services.ServerLogs) *LogService {
+	return &LogService{logs: logs}
+}
+
+// Вывод игрового сервера. id сервера - id его конфигурации
+func (s *LogService) StreamLogs(req *api.LogsRequest, stream api.LogService_StreamLogsServer) error {
+	serverUUID, err := uuid.Parse(req.ConfigurationId)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to parse configuration id: %v", err)
+	}
+
+	q := services.LogsQuery{Tail: req.Tail, Follow: req.Follow}
+	if req.Since != nil {
+		q.Since = req.Since.AsTime()
+	}
+
+	err = s.logs.Stream(stream.Context(), serverUUID, q, stream.Send)
+	if err == nil || stream.Context().Err() != nil {
+		return nil
+	}
+	return convertLogsError(err)
+}
+
+func convertLogsError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return status.Error(codes.NotFound, "configuration not found")
+	case errors.Is(err, services.ErrServerNotAssigned):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, services.ErrStreamOverflow):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, services.ErrAgentNotConnected), errors.Is(err, services.ErrSessionClosed):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "failed to stream logs: %v", err)
+	}
+}
@@ -2,27 +2,93 @@ package grpc_services
 
 import (
 	"context"
+	"crypto/x509"
 	"strings"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 type tokenValidator interface {
-	ValidateToken(token string) bool
+	ValidateToken(token string) (string, error)
 }
 
-var tokenValidatorInstance tokenValidator
+type certificateVerifier interface {
+	Verify(cert *x509.Certificate) (uuid.UUID, error)
+}
+
+type userKey struct{}
+type agentKey struct{}
+
+var (
+	tokenValidatorInstance      tokenValidator
+	certificateVerifierInstance certificateVerifier
+)
+
+// Методы, доступные без авторизации: вход, выпуск сертификата агента и чтение,
+// как в REST API. Все остальные методы меняют состояние или отдают секреты
+// и требуют токена или сертификата агента
+var publicMethods = []string{"/Login", "/Enroll", "/GetById", "/GetAll", "/GetServerHealth", "/GetAllServerHealth", "/Place"}
+
+// Методы, доступные агенту, вошедшему по клиентскому сертификату
+var agentMethods = []string{"/Register", "/Heartbeat", "/Lease", "/Report", "/Connect", "/GetById", "/GetAll", "/Watch"}
 
 func SetTokenValidator(validator tokenValidator) {
 	tokenValidatorInstance = validator
 }
 
+func SetCertificateVerifier(verifier certificateVerifier) {
+	certificateVerifierInstance = verifier
+}
+
 func AuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-	if !strings.HasSuffix(info.FullMethod, "/Put") && !strings.HasSuffix(info.FullMethod, "/Post") {
-		return handler(ctx, req)
+	ctx, err := authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func AuthStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// Поток с контекстом, в который добавлен пользователь
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func authenticate(ctx context.Context, method string) (context.Context, error) {
+	agentId, ok, err := authenticateCertificate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if !hasSuffix(method, agentMethods) {
+			return nil, status.Error(codes.PermissionDenied, "method is not available to agents")
+		}
+		ctx = context.WithValue(ctx, userKey{}, "agent:"+agentId.String())
+		return context.WithValue(ctx, agentKey{}, agentId), nil
+	}
+
+	if hasSuffix(method, publicMethods) {
+		return ctx, nil
 	}
 
 	md, ok := metadata.FromIncomingContext(ctx)
@@ -45,9 +111,65 @@ func AuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, h
 		return nil, status.Error(codes.Unauthenticated, "missing token")
 	}
 
-	if tokenValidatorInstance != nil && !tokenValidatorInstance.ValidateToken(token) {
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	if tokenValidatorInstance != nil {
+		user, err := tokenValidatorInstance.ValidateToken(token)
+		if err != nil || user == "" {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		ctx = context.WithValue(ctx, userKey{}, user)
 	}
 
-	return handler(ctx, req)
+	return ctx, nil
+}
+
+// Агент, вошедший по клиентскому сертификату. ok == false, если сертификата нет
+func authenticateCertificate(ctx context.Context) (uuid.UUID, bool, error) {
+	if certificateVerifierInstance == nil {
+		return uuid.Nil, false, nil
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return uuid.Nil, false, nil
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return uuid.Nil, false, nil
+	}
+
+	agentId, err := certificateVerifierInstance.Verify(info.State.VerifiedChains[0][0])
+	if err != nil {
+		return uuid.Nil, false, status.Errorf(codes.Unauthenticated, "invalid client certificate: %v", err)
+	}
+	return agentId, true, nil
+}
+
+// Имя пользователя, вызвавшего метод
+func userFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(userKey{}).(string)
+	return user
+}
+
+// Агент, вошедший по клиентскому сертификату
+func agentFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(agentKey{}).(uuid.UUID)
+	return id, ok
+}
+
+// Агент с сертификатом может действовать только от своего имени
+func authorizeAgent(ctx context.Context, agentId uuid.UUID) error {
+	if id, ok := agentFromContext(ctx); ok && id != agentId {
+		return status.Error(codes.PermissionDenied, "client certificate is issued for another agent")
+	}
+	return nil
+}
+
+func hasSuffix(method string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(method, suffix) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,64 @@
+package grpc_services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/placement"
+	"github.com/vv-sam/otus-project/server/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type placer interface {
+	Place(conf configuration.Configuration) (*placement.Decision, error)
+}
+
+type SchedulerService struct {
+	api.UnimplementedSchedulerServiceServer
+	scheduler placer
+	validator *services.Validator
+}
+
+func NewSchedulerService(scheduler placer, validator *services.Validator) *SchedulerService {
+	return &SchedulerService{scheduler: scheduler, validator: validator}
+}
+
+// Объясняет, куда была бы размещена конфигурация. Отсутствие подходящего
+// агента - не ошибка, причины отказов возвращаются в решении
+func (s *SchedulerService) Place(ctx context.Context, req *api.PlaceRequest) (*api.PlaceResponse, error) {
+	if req.Configuration == nil {
+		return nil, status.Error(codes.InvalidArgument, "configuration is required")
+	}
+
+	configInfo, err := convertProtoToConfiguration(req.Configuration)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to convert configuration: %v", err)
+	}
+
+	if !s.validator.IsValid(configInfo) {
+		return nil, status.Error(codes.InvalidArgument, "invalid configuration")
+	}
+
+	decision, err := s.scheduler.Place(configInfo.Configuration)
+	if err != nil && !errors.Is(err, services.ErrNoSuitableAgent) {
+		return nil, status.Errorf(codes.Internal, "failed to place configuration: %v", err)
+	}
+
+	return &api.PlaceResponse{Decision: convertDecisionToProto(decision)}, nil
+}
+
+func convertDecisionToProto(d *placement.Decision) *api.PlacementDecision {
+	res := &api.PlacementDecision{Reason: d.Reason}
+	if d.AgentId != uuid.Nil {
+		res.AgentId = d.AgentId.String()
+	}
+
+	for _, r := range d.Rejected {
+		res.Rejected = append(res.Rejected, &api.PlacementRejection{AgentId: r.AgentId.String(), Reasons: r.Reasons})
+	}
+	return res
+}
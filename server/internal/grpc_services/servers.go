@@ -0,0 +1,248 @@
+package grpc_services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/server"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type serverRepository interface {
+	Get(id uuid.UUID) (*server.Server, error)
+	GetAll() ([]*server.Server, error)
+	Add(server *server.Server) error
+	Update(id uuid.UUID, server *server.Server) error
+	Delete(id uuid.UUID) error
+}
+
+type ServerService struct {
+	api.UnimplementedServerServiceServer
+	serverRepository serverRepository
+	validator        *services.Validator
+}
+
+func NewServerService(serverRepository serverRepository, validator *services.Validator) *ServerService {
+	return &ServerService{
+		serverRepository: serverRepository,
+		validator:        validator,
+	}
+}
+
+func (s *ServerService) GetById(ctx context.Context, req *api.GetServerByIdRequest) (*api.GetServerByIdResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	serverUUID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse id: %v", err)
+	}
+
+	srv, err := s.serverRepository.Get(serverUUID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, status.Errorf(codes.Internal, "failed to get server: %v", err)
+	}
+
+	if srv == nil {
+		return nil, status.Error(codes.NotFound, "server not found")
+	}
+
+	return &api.GetServerByIdResponse{Server: convertServerToProto(srv)}, nil
+}
+
+func (s *ServerService) GetAll(ctx context.Context, req *api.GetAllServersRequest) (*api.GetAllServersResponse, error) {
+	servers, err := s.serverRepository.GetAll()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get servers: %v", err)
+	}
+
+	protoServers := make([]*api.Server, len(servers))
+	for i, srv := range servers {
+		protoServers[i] = convertServerToProto(srv)
+	}
+
+	return &api.GetAllServersResponse{Servers: protoServers}, nil
+}
+
+func (s *ServerService) Post(ctx context.Context, req *api.PostServerRequest) (*api.PostServerResponse, error) {
+	if req.Server == nil {
+		return nil, status.Error(codes.InvalidArgument, "server is required")
+	}
+
+	srv, err := convertProtoToServer(req.Server)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to convert server: %v", err)
+	}
+
+	if srv.DesiredState == "" {
+		srv.DesiredState = server.DESIRED_RUNNING
+	}
+
+	if !s.validator.IsValid(srv) {
+		return nil, status.Error(codes.InvalidArgument, "invalid server")
+	}
+
+	if err := s.serverRepository.Add(srv); err != nil {
+		if st, ok := convertServerError(err); ok {
+			return nil, st
+		}
+		return nil, status.Errorf(codes.Internal, "failed to add server: %v", err)
+	}
+
+	return &api.PostServerResponse{}, nil
+}
+
+func (s *ServerService) Put(ctx context.Context, req *api.PutServerRequest) (*api.PutServerResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if req.Server == nil {
+		return nil, status.Error(codes.InvalidArgument, "server is required")
+	}
+
+	serverUUID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse id: %v", err)
+	}
+
+	req.Server.Id = req.Id
+	srv, err := convertProtoToServer(req.Server)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to convert server: %v", err)
+	}
+
+	if !s.validator.IsValid(srv) {
+		return nil, status.Error(codes.InvalidArgument, "invalid server")
+	}
+
+	if err := s.serverRepository.Update(serverUUID, srv); err != nil {
+		if st, ok := convertServerError(err); ok {
+			return nil, st
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update server: %v", err)
+	}
+
+	return &api.PutServerResponse{}, nil
+}
+
+func (s *ServerService) Delete(ctx context.Context, req *api.DeleteServerRequest) (*api.DeleteServerResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	serverUUID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse id: %v", err)
+	}
+
+	if err := s.serverRepository.Delete(serverUUID); err != nil {
+		if st, ok := convertServerError(err); ok {
+			return nil, st
+		}
+		return nil, status.Errorf(codes.Internal, "failed to delete server: %v", err)
+	}
+
+	return &api.DeleteServerResponse{}, nil
+}
+
+func convertServerError(err error) (error, bool) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return status.Error(codes.NotFound, "server not found"), true
+	case errors.Is(err, services.ErrUnknownConfiguration):
+		return status.Error(codes.InvalidArgument, err.Error()), true
+	case errors.Is(err, services.ErrServerExists):
+		return status.Error(codes.AlreadyExists, err.Error()), true
+	case errors.Is(err, services.ErrServerDeleting):
+		return status.Error(codes.FailedPrecondition, err.Error()), true
+	default:
+		return nil, false
+	}
+}
+
+func convertServerToProto(srv *server.Server) *api.Server {
+	res := &api.Server{
+		Id:               srv.Id.String(),
+		DesiredState:     convertDesiredStateToProto(srv.DesiredState),
+		ObservedState:    convertServerStateToProto(srv.ObservedState),
+		ObservedRevision: srv.ObservedRevision,
+		DesiredRevision:  srv.DesiredRevision,
+		Error:            srv.Error,
+	}
+	if srv.AgentId != uuid.Nil {
+		res.AgentId = srv.AgentId.String()
+	}
+	if srv.TaskId != uuid.Nil {
+		res.TaskId = srv.TaskId.String()
+	}
+	if !srv.ObservedAt.IsZero() {
+		res.ObservedAt = timestamppb.New(srv.ObservedAt)
+	}
+	if !srv.CreatedAt.IsZero() {
+		res.CreatedAt = timestamppb.New(srv.CreatedAt)
+	}
+	if !srv.UpdatedAt.IsZero() {
+		res.UpdatedAt = timestamppb.New(srv.UpdatedAt)
+	}
+	return res
+}
+
+// Из запроса берутся только id и желаемое состояние, остальное ведёт сервер
+func convertProtoToServer(protoServer *api.Server) (*server.Server, error) {
+	serverUUID, err := uuid.Parse(protoServer.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &server.Server{
+		Id:           serverUUID,
+		DesiredState: convertProtoToDesiredState(protoServer.DesiredState),
+	}, nil
+}
+
+func convertDesiredStateToProto(state string) api.DesiredState {
+	switch state {
+	case server.DESIRED_RUNNING:
+		return api.DesiredState_DESIRED_STATE_RUNNING
+	case server.DESIRED_STOPPED:
+		return api.DesiredState_DESIRED_STATE_STOPPED
+	case server.DESIRED_DELETED:
+		return api.DesiredState_DESIRED_STATE_DELETED
+	default:
+		return api.DesiredState_DESIRED_STATE_UNSPECIFIED
+	}
+}
+
+func convertProtoToDesiredState(state api.DesiredState) string {
+	switch state {
+	case api.DesiredState_DESIRED_STATE_RUNNING:
+		return server.DESIRED_RUNNING
+	case api.DesiredState_DESIRED_STATE_STOPPED:
+		return server.DESIRED_STOPPED
+	case api.DesiredState_DESIRED_STATE_DELETED:
+		return server.DESIRED_DELETED
+	default:
+		return ""
+	}
+}
+
+func convertServerStateToProto(state string) api.ServerState {
+	switch state {
+	case server.STATE_ABSENT:
+		return api.ServerState_SERVER_STATE_ABSENT
+	case server.STATE_RUNNING:
+		return api.ServerState_SERVER_STATE_RUNNING
+	case server.STATE_STOPPED:
+		return api.ServerState_SERVER_STATE_STOPPED
+	default:
+		return api.ServerState_SERVER_STATE_UNKNOWN
+	}
+}
@@ -2,10 +2,14 @@ package grpc_services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"log"
+	"time"
 
 	"github.com/google/uuid"
 	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
 	"github.com/vv-sam/otus-project/server/internal/model/task"
 	"github.com/vv-sam/otus-project/server/internal/repository"
 	"github.com/vv-sam/otus-project/server/internal/services"
@@ -16,21 +20,33 @@ import (
 type tasksRepository interface {
 	Get(id uuid.UUID) (*task.Task, error)
 	GetAll() ([]*task.Task, error)
+	List(q repository.ListQuery) (*repository.Page[*task.Task], error)
 	Add(task *task.Task) error
-	Update(id uuid.UUID, task *task.Task) error
+	Put(id uuid.UUID, update *task.Task, actor string) error
 	Delete(id uuid.UUID) error
 }
 
+type taskQueue interface {
+	Lease(agentId uuid.UUID, types []string, max int, timeout time.Duration) ([]*task.Task, error)
+	Report(id, agentId uuid.UUID, status int16, result json.RawMessage, taskErr string, timeout time.Duration) error
+	DeadLetters() ([]*task.Task, error)
+	RequeueDeadLetter(id uuid.UUID, actor string) error
+}
+
 type TaskService struct {
 	api.UnimplementedTaskServiceServer
 	tasksRepository tasksRepository
+	taskQueue       taskQueue
 	validator       *services.Validator
+	events          eventWatcher
 }
 
-func NewTaskService(tasksRepository tasksRepository, validator *services.Validator) *TaskService {
+func NewTaskService(tasksRepository tasksRepository, taskQueue taskQueue, validator *services.Validator, events eventWatcher) *TaskService {
 	return &TaskService{
 		tasksRepository: tasksRepository,
+		taskQueue:       taskQueue,
 		validator:       validator,
+		events:          events,
 	}
 }
 
@@ -58,17 +74,39 @@ func (s *TaskService) GetById(ctx context.Context, req *api.GetTaskByIdRequest)
 }
 
 func (s *TaskService) GetAll(ctx context.Context, req *api.GetAllTasksRequest) (*api.GetAllTasksResponse, error) {
-	tasks, err := s.tasksRepository.GetAll()
+	for _, st := range req.Status {
+		if st == api.TaskStatus_TASK_STATUS_UNSPECIFIED {
+			return nil, status.Error(codes.InvalidArgument, "status filter must be specified")
+		}
+	}
+	for _, a := range req.Action {
+		if a == api.TaskAction_TASK_ACTION_UNSPECIFIED {
+			return nil, status.Error(codes.InvalidArgument, "action filter must be specified")
+		}
+	}
+
+	str := func(s string) any { return s }
+	q := listQuery(req.Options,
+		repository.In("status", filterValues(req.Status, func(st api.TaskStatus) any { return convertProtoToTaskStatus(st) })...),
+		repository.In("type", filterValues(req.Type, str)...),
+		repository.In("action", filterValues(req.Action, func(a api.TaskAction) any { return convertProtoToTaskAction(a) })...),
+		repository.In("agent_id", filterValues(req.AgentId, str)...),
+		repository.In("configuration_id", filterValues(req.ConfigurationId, str)...),
+		repository.Gte("created_at", timeValue(req.CreatedFrom)),
+		repository.Lt("created_at", timeValue(req.CreatedTo)),
+	)
+
+	page, err := s.tasksRepository.List(q)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get tasks: %v", err)
+		return nil, convertListError("tasks", err)
 	}
 
-	protoTasks := make([]*api.Task, len(tasks))
-	for i, task := range tasks {
+	protoTasks := make([]*api.Task, len(page.Items))
+	for i, task := range page.Items {
 		protoTasks[i] = convertTaskToProto(task)
 	}
 
-	return &api.GetAllTasksResponse{Tasks: protoTasks}, nil
+	return &api.GetAllTasksResponse{Tasks: protoTasks, NextCursor: page.NextCursor}, nil
 }
 
 func (s *TaskService) Post(ctx context.Context, req *api.PostTaskRequest) (*api.PostTaskResponse, error) {
@@ -81,6 +119,10 @@ func (s *TaskService) Post(ctx context.Context, req *api.PostTaskRequest) (*api.
 		return nil, status.Errorf(codes.InvalidArgument, "failed to convert task: %v", err)
 	}
 
+	if taskInfo.CreatedAt.IsZero() {
+		taskInfo.CreatedAt = time.Now()
+	}
+
 	if !s.validator.IsValid(taskInfo) {
 		return nil, status.Error(codes.InvalidArgument, "invalid task")
 	}
@@ -118,10 +160,13 @@ func (s *TaskService) Put(ctx context.Context, req *api.PutTaskRequest) (*api.Pu
 		return nil, status.Error(codes.InvalidArgument, "invalid task")
 	}
 
-	if err := s.tasksRepository.Update(taskUUID, taskInfo); err != nil {
+	if err := s.tasksRepository.Put(taskUUID, taskInfo, userFromContext(ctx)); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, status.Error(codes.NotFound, "task not found")
 		}
+		if errors.Is(err, task.ErrInvalidTransition) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, status.Errorf(codes.Internal, "failed to update task: %v", err)
 	}
 
@@ -147,3 +192,126 @@ func (s *TaskService) Delete(ctx context.Context, req *api.DeleteTaskRequest) (*
 
 	return &api.DeleteTaskResponse{}, nil
 }
+
+// Снимок задач и изменения: создание, изменение, переходы между статусами, удаление
+func (s *TaskService) Watch(req *api.WatchTasksRequest, stream api.TaskService_WatchServer) error {
+	return watch(stream.Context(), s.events, req.Revision, watchSource[*task.Task]{
+		group: "task",
+		id:    func(e *event.Event) uuid.UUID { return e.TaskId },
+		idOf:  func(t *task.Task) uuid.UUID { return t.Id },
+		get:   s.tasksRepository.Get,
+		all:   s.tasksRepository.GetAll,
+		send: func(t api.WatchEventType, revision string, id uuid.UUID, tk *task.Task) error {
+			resp := &api.WatchTasksResponse{Type: t, Revision: revision}
+			if id != uuid.Nil {
+				resp.Id = id.String()
+			}
+			if tk != nil {
+				resp.Task = convertTaskToProto(tk)
+			}
+			return stream.Send(resp)
+		},
+	})
+}
+
+func (s *TaskService) Lease(ctx context.Context, req *api.LeaseTasksRequest) (*api.LeaseTasksResponse, error) {
+	agentUUID, err := uuid.Parse(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse agent id: %v", err)
+	}
+	if err := authorizeAgent(ctx, agentUUID); err != nil {
+		return nil, err
+	}
+
+	maxTasks := int(req.MaxTasks)
+	if maxTasks == 0 {
+		maxTasks = 1
+	}
+
+	tasks, err := s.taskQueue.Lease(agentUUID, req.Types, maxTasks, time.Duration(req.VisibilityTimeoutSeconds)*time.Second)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to lease tasks: %v", err)
+	}
+
+	protoTasks := make([]*api.Task, len(tasks))
+	for i, task := range tasks {
+		protoTasks[i] = convertTaskToProto(task)
+	}
+
+	return &api.LeaseTasksResponse{Tasks: protoTasks}, nil
+}
+
+func (s *TaskService) Report(ctx context.Context, req *api.ReportTaskRequest) (*api.ReportTaskResponse, error) {
+	taskUUID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse id: %v", err)
+	}
+
+	agentUUID, err := uuid.Parse(req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse agent id: %v", err)
+	}
+	if err := authorizeAgent(ctx, agentUUID); err != nil {
+		return nil, err
+	}
+
+	switch req.Status {
+	case api.TaskStatus_TASK_STATUS_IN_PROGRESS, api.TaskStatus_TASK_STATUS_OK, api.TaskStatus_TASK_STATUS_FAILED, api.TaskStatus_TASK_STATUS_TIMED_OUT:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "status %v can't be reported by agent", req.Status)
+	}
+
+	if len(req.Result) > 0 && !json.Valid(req.Result) {
+		return nil, status.Error(codes.InvalidArgument, "result must be valid json")
+	}
+
+	err = s.taskQueue.Report(taskUUID, agentUUID, convertProtoToTaskStatus(req.Status), req.Result, req.Error, time.Duration(req.VisibilityTimeoutSeconds)*time.Second)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "task not found")
+		}
+		if errors.Is(err, services.ErrLeaseNotHeld) || errors.Is(err, task.ErrInvalidTransition) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to report task: %v", err)
+	}
+
+	if req.Status == api.TaskStatus_TASK_STATUS_FAILED || req.Status == api.TaskStatus_TASK_STATUS_TIMED_OUT {
+		log.Printf("task %s failed on agent %s: %s", req.Id, req.AgentId, req.Error)
+	}
+
+	return &api.ReportTaskResponse{}, nil
+}
+
+func (s *TaskService) GetDeadLetters(ctx context.Context, req *api.GetDeadLettersRequest) (*api.GetDeadLettersResponse, error) {
+	tasks, err := s.taskQueue.DeadLetters()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get dead letters: %v", err)
+	}
+
+	protoTasks := make([]*api.Task, len(tasks))
+	for i, task := range tasks {
+		protoTasks[i] = convertTaskToProto(task)
+	}
+
+	return &api.GetDeadLettersResponse{Tasks: protoTasks}, nil
+}
+
+func (s *TaskService) RequeueDeadLetter(ctx context.Context, req *api.RequeueDeadLetterRequest) (*api.RequeueDeadLetterResponse, error) {
+	taskUUID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse id: %v", err)
+	}
+
+	if err := s.taskQueue.RequeueDeadLetter(taskUUID, userFromContext(ctx)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "task not found")
+		}
+		if errors.Is(err, services.ErrNotInDeadLetters) || errors.Is(err, task.ErrInvalidTransition) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to requeue task: %v", err)
+	}
+
+	return &api.RequeueDeadLetterResponse{}, nil
+}
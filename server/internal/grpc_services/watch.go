@@ -0,0 +1,111 @@
+package grpc_services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type eventWatcher interface {
+	Revision(ctx context.Context) (string, error)
+	CheckKept(ctx context.Context, after string) error
+	Subscribe(ctx context.Context, q services.EventsQuery, fn func(*event.Event) error) error
+}
+
+// Объекты одного вида, за которыми следит Watch
+type watchSource[T comparable] struct {
+	group string                         // группа типов событий об объектах
+	id    func(e *event.Event) uuid.UUID // id объекта, к которому относится событие
+	idOf  func(item T) uuid.UUID
+	get   func(id uuid.UUID) (T, error)
+	all   func() ([]T, error)
+
+	// Для удаления и отметки synced item - нулевое значение, для synced и id пустой
+	send func(t api.WatchEventType, revision string, id uuid.UUID, item T) error
+}
+
+// Без ревизии отправляет снимок всех объектов с текущей ревизией и отметку
+// synced, с ревизией - только изменения после неё. Изменение несёт текущее
+// состояние объекта, а не разницу, поэтому повтор изменения безопасен
+func watch[T comparable](ctx context.Context, events eventWatcher, revision string, src watchSource[T]) error {
+	var zero T
+
+	if revision == "" {
+		rev, err := events.Revision(ctx)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get revision: %v", err)
+		}
+
+		items, err := src.all()
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get snapshot: %v", err)
+		}
+		for _, item := range items {
+			if err := src.send(api.WatchEventType_WATCH_EVENT_TYPE_ADDED, rev, src.idOf(item), item); err != nil {
+				return err
+			}
+		}
+
+		if err := src.send(api.WatchEventType_WATCH_EVENT_TYPE_SYNCED, rev, uuid.Nil, zero); err != nil {
+			return err
+		}
+		revision = rev
+	} else if err := events.CheckKept(ctx, revision); err != nil {
+		return convertWatchError(err)
+	}
+
+	q := services.EventsQuery{Types: []string{src.group}, After: revision}
+	err := events.Subscribe(ctx, q, func(e *event.Event) error {
+		if e == nil {
+			return nil
+		}
+
+		id := src.id(e)
+		if id == uuid.Nil {
+			return nil
+		}
+
+		item, err := src.get(id)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return status.Errorf(codes.Internal, "failed to get %s %s: %v", src.group, id, err)
+		}
+
+		t := api.WatchEventType_WATCH_EVENT_TYPE_MODIFIED
+		switch {
+		case item == zero:
+			t = api.WatchEventType_WATCH_EVENT_TYPE_DELETED
+		case strings.HasSuffix(e.Type, ".created"):
+			t = api.WatchEventType_WATCH_EVENT_TYPE_ADDED
+		}
+		return src.send(t, e.Seq, id, item)
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return convertWatchError(err)
+}
+
+func convertWatchError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, services.ErrInvalidEventSeq):
+		return status.Errorf(codes.InvalidArgument, "invalid revision: %v", err)
+	case errors.Is(err, services.ErrEventsExpired):
+		return status.Error(codes.OutOfRange, "revision is too old, watch again without revision")
+	case errors.Is(err, services.ErrSubscriberTooSlow):
+		return status.Error(codes.ResourceExhausted, "consumer is too slow, resume from the last received revision")
+	default:
+		return status.Errorf(codes.Internal, "failed to watch: %v", err)
+	}
+}
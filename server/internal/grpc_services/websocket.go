@@ -0,0 +1,329 @@
+package grpc_services
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Сколько сообщений вызова буферизуется, пока обработчик их не прочитал.
+// Вызов, который не успевает читать, завершается с ResourceExhausted, чтобы
+// не останавливать чтение остальных вызовов подключения
+const wsCallBuffer = 16
+
+// Наибольший размер кадра, как у grpc.Server по умолчанию. Подключение
+// с кадром большего размера закрывается
+const wsMaxFrameSize = 4 << 20
+
+// Сервер gRPC-сервисов поверх websocket для агентов, которым доступен только исходящий HTTP(S).
+// Каждый websocket-кадр - сериализованный api.Frame; вызовы мультиплексируются по call id.
+// Сервисы регистрируются так же, как в grpc.Server, и проходят через те же перехватчики
+type WebsocketServer struct {
+	unary  grpc.UnaryServerInterceptor
+	stream grpc.StreamServerInterceptor
+
+	upgrader websocket.Upgrader
+
+	m        sync.RWMutex
+	services map[string]*wsService
+}
+
+type wsService struct {
+	impl    any
+	methods map[string]*grpc.MethodDesc
+	streams map[string]*grpc.StreamDesc
+}
+
+func NewWebsocketServer(unary grpc.UnaryServerInterceptor, stream grpc.StreamServerInterceptor) *WebsocketServer {
+	return &WebsocketServer{
+		unary:    unary,
+		stream:   stream,
+		services: make(map[string]*wsService),
+	}
+}
+
+func (s *WebsocketServer) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	svc := &wsService{
+		impl:    impl,
+		methods: make(map[string]*grpc.MethodDesc),
+		streams: make(map[string]*grpc.StreamDesc),
+	}
+	for i := range desc.Methods {
+		svc.methods[desc.Methods[i].MethodName] = &desc.Methods[i]
+	}
+	for i := range desc.Streams {
+		svc.streams[desc.Streams[i].StreamName] = &desc.Streams[i]
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.services[desc.ServiceName] = svc
+}
+
+func (s *WebsocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade уже ответил клиенту ошибкой
+		log.Printf("websocket: failed to upgrade: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	ws.SetReadLimit(wsMaxFrameSize)
+	c := &wsServerConn{server: s, ws: ws, ctx: ctx, calls: make(map[uint64]*wsServerCall)}
+	if err := c.serve(); err != nil && !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		log.Printf("websocket: connection closed: %v", err)
+	}
+}
+
+func (s *WebsocketServer) lookup(fullMethod string) (*wsService, string, bool) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	i := strings.LastIndex(name, "/")
+	if i < 0 {
+		return nil, "", false
+	}
+
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	svc, ok := s.services[name[:i]]
+	return svc, name[i+1:], ok
+}
+
+// Одно websocket-подключение агента
+type wsServerConn struct {
+	server *WebsocketServer
+	ws     *websocket.Conn
+	ctx    context.Context
+
+	writeM sync.Mutex
+
+	m     sync.Mutex
+	calls map[uint64]*wsServerCall
+}
+
+type wsServerCall struct {
+	id     uint64
+	in     chan []byte
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	// Клиент больше не отправит сообщений. Меняется только в цикле чтения подключения
+	halfClosed bool
+}
+
+func (c *wsServerConn) serve() error {
+	defer func() {
+		c.m.Lock()
+		for _, call := range c.calls {
+			call.cancel(nil)
+		}
+		c.m.Unlock()
+		c.ws.Close()
+	}()
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var f api.Frame
+		if err := proto.Unmarshal(data, &f); err != nil {
+			return err
+		}
+
+		switch f.Type {
+		case api.FrameType_FRAME_TYPE_OPEN:
+			c.open(&f)
+		case api.FrameType_FRAME_TYPE_MESSAGE:
+			if call := c.call(f.CallId); call != nil && !call.halfClosed && call.ctx.Err() == nil {
+				select {
+				case call.in <- f.Payload:
+				default:
+					call.cancel(status.Errorf(codes.ResourceExhausted, "more than %d unread messages in call %d", wsCallBuffer, call.id))
+				}
+			}
+		case api.FrameType_FRAME_TYPE_HALF_CLOSE:
+			if call := c.call(f.CallId); call != nil && !call.halfClosed {
+				call.halfClosed = true
+				close(call.in)
+			}
+		case api.FrameType_FRAME_TYPE_CANCEL:
+			if call := c.call(f.CallId); call != nil {
+				call.cancel(nil)
+			}
+		}
+	}
+}
+
+func (c *wsServerConn) open(f *api.Frame) {
+	svc, name, ok := c.server.lookup(f.Method)
+	if !ok {
+		c.end(f.CallId, status.Errorf(codes.Unimplemented, "unknown service for method %s", f.Method))
+		return
+	}
+
+	ctx, cancel := context.WithCancelCause(metadata.NewIncomingContext(c.ctx, metadata.New(f.Metadata)))
+	call := &wsServerCall{id: f.CallId, in: make(chan []byte, wsCallBuffer), ctx: ctx, cancel: cancel}
+
+	// Повторный OPEN не должен подменять идущий вызов
+	c.m.Lock()
+	if _, ok := c.calls[f.CallId]; ok {
+		c.m.Unlock()
+		cancel(nil)
+		c.end(f.CallId, status.Errorf(codes.InvalidArgument, "call %d is already open", f.CallId))
+		return
+	}
+	c.calls[f.CallId] = call
+	c.m.Unlock()
+
+	go func() {
+		defer func() {
+			cancel(nil)
+			c.m.Lock()
+			delete(c.calls, call.id)
+			c.m.Unlock()
+		}()
+
+		var err error
+		if md, ok := svc.methods[name]; ok {
+			err = c.serveUnary(ctx, call, svc.impl, md)
+		} else if sd, ok := svc.streams[name]; ok {
+			err = c.serveStream(ctx, call, svc.impl, sd, f.Method)
+		} else {
+			err = status.Errorf(codes.Unimplemented, "unknown method %s", f.Method)
+		}
+
+		// Сообщение переполненного вызова потеряно, поэтому он завершается ошибкой,
+		// даже если обработчик успел закончить без неё
+		if cause := context.Cause(ctx); status.Code(cause) == codes.ResourceExhausted {
+			err = cause
+		}
+		c.end(call.id, err)
+	}()
+}
+
+func (c *wsServerConn) serveUnary(ctx context.Context, call *wsServerCall, impl any, md *grpc.MethodDesc) error {
+	var payload []byte
+	select {
+	case p, ok := <-call.in:
+		if !ok {
+			return status.Error(codes.InvalidArgument, "request message is missing")
+		}
+		payload = p
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	dec := func(v any) error {
+		return proto.Unmarshal(payload, v.(proto.Message))
+	}
+
+	resp, err := md.Handler(impl, ctx, dec, c.server.unary)
+	if err != nil {
+		return err
+	}
+
+	return c.send(call.id, resp.(proto.Message))
+}
+
+func (c *wsServerConn) serveStream(ctx context.Context, call *wsServerCall, impl any, sd *grpc.StreamDesc, method string) error {
+	ss := &wsServerStream{ctx: ctx, conn: c, call: call}
+	if c.server.stream == nil {
+		return sd.Handler(impl, ss)
+	}
+
+	info := &grpc.StreamServerInfo{FullMethod: method, IsClientStream: sd.ClientStreams, IsServerStream: sd.ServerStreams}
+	return c.server.stream(impl, ss, info, sd.Handler)
+}
+
+func (c *wsServerConn) call(id uint64) *wsServerCall {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.calls[id]
+}
+
+func (c *wsServerConn) send(callId uint64, m proto.Message) error {
+	payload, err := proto.Marshal(m)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal message: %v", err)
+	}
+
+	return c.write(&api.Frame{CallId: callId, Type: api.FrameType_FRAME_TYPE_MESSAGE, Payload: payload})
+}
+
+// Завершает вызов со статусом err
+func (c *wsServerConn) end(callId uint64, err error) {
+	st := status.Convert(err)
+	f := &api.Frame{
+		CallId:        callId,
+		Type:          api.FrameType_FRAME_TYPE_END,
+		StatusCode:    int32(st.Code()),
+		StatusMessage: st.Message(),
+	}
+	if err := c.write(f); err != nil {
+		log.Printf("websocket: failed to end call %d: %v", callId, err)
+	}
+}
+
+func (c *wsServerConn) write(f *api.Frame) error {
+	data, err := proto.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	c.writeM.Lock()
+	defer c.writeM.Unlock()
+
+	return c.ws.WriteMessage(websocket.BinaryMessage, data)
+}
+
+// Потоковый вызов поверх websocket со стороны сервера
+type wsServerStream struct {
+	ctx  context.Context
+	conn *wsServerConn
+	call *wsServerCall
+}
+
+func (s *wsServerStream) SetHeader(metadata.MD) error  { return nil }
+func (s *wsServerStream) SendHeader(metadata.MD) error { return nil }
+func (s *wsServerStream) SetTrailer(metadata.MD)       {}
+
+func (s *wsServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *wsServerStream) SendMsg(m any) error {
+	if err := s.ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return s.conn.send(s.call.id, m.(proto.Message))
+}
+
+func (s *wsServerStream) RecvMsg(m any) error {
+	select {
+	case payload, ok := <-s.call.in:
+		if !ok {
+			return io.EOF
+		}
+		return proto.Unmarshal(payload, m.(proto.Message))
+	case <-s.ctx.Done():
+		return status.FromContextError(s.ctx.Err()).Err()
+	}
+}
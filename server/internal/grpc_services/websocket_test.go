@@ -0,0 +1,183 @@
+package grpc_services
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Сервис с эхо-методом и потоком, который ничего не читает, пока не отменён
+var testWsService = grpc.ServiceDesc{
+	ServiceName: "test.Service",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Echo",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := &wrapperspb.StringValue{}
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			return in, nil
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Stuck",
+		ClientStreams: true,
+		Handler: func(srv any, stream grpc.ServerStream) error {
+			<-stream.Context().Done()
+			return stream.Context().Err()
+		},
+	}},
+}
+
+func dialTestWs(t *testing.T) *websocket.Conn {
+	t.Helper()
+
+	s := NewWebsocketServer(nil, nil)
+	s.RegisterService(&testWsService, struct{}{})
+	hs := httptest.NewServer(s)
+	t.Cleanup(hs.Close)
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(hs.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func writeFrame(t *testing.T, ws *websocket.Conn, f *api.Frame) {
+	t.Helper()
+
+	data, err := proto.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.WriteMessage(websocket.BinaryMessage, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Читает кадры, пока не завершатся все вызовы calls. Возвращает кадры END по call id
+func readEnds(t *testing.T, ws *websocket.Conn, calls ...uint64) map[uint64]*api.Frame {
+	t.Helper()
+
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	ends := map[uint64]*api.Frame{}
+	for len(ends) < len(calls) {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			t.Fatalf("read: %v (ended %d of %d calls)", err, len(ends), len(calls))
+		}
+
+		f := &api.Frame{}
+		if err := proto.Unmarshal(data, f); err != nil {
+			t.Fatal(err)
+		}
+		if f.Type == api.FrameType_FRAME_TYPE_END {
+			ends[f.CallId] = f
+		}
+	}
+	return ends
+}
+
+func TestWebsocketUnary(t *testing.T) {
+	ws := dialTestWs(t)
+
+	payload, _ := proto.Marshal(wrapperspb.String("hello"))
+	writeFrame(t, ws, &api.Frame{CallId: 1, Type: api.FrameType_FRAME_TYPE_OPEN, Method: "/test.Service/Echo"})
+	writeFrame(t, ws, &api.Frame{CallId: 1, Type: api.FrameType_FRAME_TYPE_MESSAGE, Payload: payload})
+
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &api.Frame{}
+	proto.Unmarshal(data, f)
+
+	resp := &wrapperspb.StringValue{}
+	if err := proto.Unmarshal(f.Payload, resp); err != nil || resp.Value != "hello" {
+		t.Fatalf("unexpected response %v: %v", f, err)
+	}
+
+	if end := readEnds(t, ws, 1)[1]; codes.Code(end.StatusCode) != codes.OK {
+		t.Errorf("call ended with %v: %s", codes.Code(end.StatusCode), end.StatusMessage)
+	}
+}
+
+// Вызов, который не читает сообщения, завершается с ResourceExhausted,
+// а остальные вызовы подключения продолжают работать
+func TestWebsocketSlowCallDoesNotBlockConnection(t *testing.T) {
+	ws := dialTestWs(t)
+
+	writeFrame(t, ws, &api.Frame{CallId: 1, Type: api.FrameType_FRAME_TYPE_OPEN, Method: "/test.Service/Stuck"})
+	for range wsCallBuffer + 5 {
+		writeFrame(t, ws, &api.Frame{CallId: 1, Type: api.FrameType_FRAME_TYPE_MESSAGE, Payload: []byte{}})
+	}
+
+	payload, _ := proto.Marshal(wrapperspb.String("still here"))
+	writeFrame(t, ws, &api.Frame{CallId: 2, Type: api.FrameType_FRAME_TYPE_OPEN, Method: "/test.Service/Echo"})
+	writeFrame(t, ws, &api.Frame{CallId: 2, Type: api.FrameType_FRAME_TYPE_MESSAGE, Payload: payload})
+
+	ends := readEnds(t, ws, 1, 2)
+	if code := codes.Code(ends[1].StatusCode); code != codes.ResourceExhausted {
+		t.Errorf("stuck call ended with %v: %s, want ResourceExhausted", code, ends[1].StatusMessage)
+	}
+	if code := codes.Code(ends[2].StatusCode); code != codes.OK {
+		t.Errorf("echo call ended with %v: %s", code, ends[2].StatusMessage)
+	}
+}
+
+func TestWebsocketUnknownMethod(t *testing.T) {
+	ws := dialTestWs(t)
+
+	writeFrame(t, ws, &api.Frame{CallId: 7, Type: api.FrameType_FRAME_TYPE_OPEN, Method: "/test.Service/Missing"})
+	writeFrame(t, ws, &api.Frame{CallId: 8, Type: api.FrameType_FRAME_TYPE_OPEN, Method: "/other.Service/Echo"})
+
+	for id, end := range readEnds(t, ws, 7, 8) {
+		if code := codes.Code(end.StatusCode); code != codes.Unimplemented {
+			t.Errorf("call %d ended with %v, want Unimplemented", id, code)
+		}
+	}
+}
+
+// Повторный OPEN с тем же call id отклоняется, а идущий вызов продолжает работать
+func TestWebsocketDuplicateCallId(t *testing.T) {
+	ws := dialTestWs(t)
+
+	writeFrame(t, ws, &api.Frame{CallId: 1, Type: api.FrameType_FRAME_TYPE_OPEN, Method: "/test.Service/Echo"})
+	writeFrame(t, ws, &api.Frame{CallId: 1, Type: api.FrameType_FRAME_TYPE_OPEN, Method: "/test.Service/Stuck"})
+
+	if end := readEnds(t, ws, 1)[1]; codes.Code(end.StatusCode) != codes.InvalidArgument {
+		t.Fatalf("duplicate call ended with %v: %s, want InvalidArgument", codes.Code(end.StatusCode), end.StatusMessage)
+	}
+
+	payload, _ := proto.Marshal(wrapperspb.String("hello"))
+	writeFrame(t, ws, &api.Frame{CallId: 1, Type: api.FrameType_FRAME_TYPE_MESSAGE, Payload: payload})
+	if end := readEnds(t, ws, 1)[1]; codes.Code(end.StatusCode) != codes.OK {
+		t.Errorf("first call ended with %v: %s", codes.Code(end.StatusCode), end.StatusMessage)
+	}
+}
+
+func TestWebsocketFrameTooLarge(t *testing.T) {
+	ws := dialTestWs(t)
+
+	if err := ws.WriteMessage(websocket.BinaryMessage, make([]byte, wsMaxFrameSize+1)); err != nil {
+		t.Fatal(err)
+	}
+
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := ws.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Errorf("got %v, want close with message too big", err)
+	}
+}
@@ -15,7 +15,7 @@ import (
 
 type agentRepository interface {
 	Get(id uuid.UUID) (*agent.Info, error)
-	GetAll() ([]*agent.Info, error)
+	List(q repository.ListQuery) (*repository.Page[*agent.Info], error)
 	Add(agent *agent.Info) error
 	Update(id uuid.UUID, agent *agent.Info) error
 	Delete(id uuid.UUID) error
@@ -75,29 +75,28 @@ func (a *Agents) GetById(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
-// @Summary Get all agents
-// @Description Get all agents
+// @Summary Get agents
+// @Description Get a page of agents ordered by id by default. Filters accept comma separated values
 // @Tags agents
 // @Accept json
 // @Produce json
+// @Param status query string false "Statuses"
+// @Param location query string false "Locations"
+// @Param sort query string false "Sort field, prefixed with - for descending order"
+// @Param limit query int false "Page size, 50 by default, at most 500"
+// @Param cursor query string false "Cursor of the next page from the X-Next-Cursor header"
 // @Success 200 {array} agent.Info
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Failure 400 {object} error
 // @Failure 500 {object} error
 // @Router /api/agents [get]
 func (a *Agents) GetAll(w http.ResponseWriter, r *http.Request) {
-	agents, err := a.r.GetAll()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	q, err := parseListQuery(r, []string{"status", "location"}, nil)
+	var page *repository.Page[*agent.Info]
+	if err == nil {
+		page, err = a.r.List(q)
 	}
-
-	data, err := json.Marshal(agents)
-	if err != nil {
-		http.Error(w, fmt.Errorf("failed to marshal agents: %w", err).Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(data)
+	writePage(w, page, err, "agents")
 }
 
 // @Summary Create a new agent
@@ -14,12 +14,12 @@ import (
 )
 
 type configurationRepository interface {
-	Get(id uuid.UUID) (*configuration.Factorio, error)
-	GetAll() ([]*configuration.Factorio, error)
-	Add(configuration *configuration.Factorio) error
-	Update(id uuid.UUID, configuration *configuration.Factorio) error
+	Get(id uuid.UUID) (*configuration.Envelope, error)
+	List(q repository.ListQuery) (*repository.Page[*configuration.Envelope], error)
+	Add(configuration *configuration.Envelope) error
+	Update(id uuid.UUID, configuration *configuration.Envelope) error
 	Delete(id uuid.UUID) error
-	GetHistory() ([]history.Log[*configuration.Factorio], error)
+	GetHistory() ([]history.Log[*configuration.Envelope], error)
 }
 
 type Configuration struct {
@@ -32,7 +32,7 @@ func NewConfiguration(r configurationRepository, v *services.Validator) *Configu
 }
 
 // @Summary Get configuration by id
-// @Description Get configuration by id
+// @Description Get configuration by id. The shape of the response depends on its type field (factorio or minecraft)
 // @Tags configurations
 // @Accept json
 // @Produce json
@@ -57,6 +57,11 @@ func (c *Configuration) GetById(w http.ResponseWriter, r *http.Request) {
 
 	configuration, err := c.r.Get(uuid)
 	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			http.Error(w, "configuration not found", http.StatusNotFound)
+			return
+		}
+
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -76,33 +81,32 @@ func (c *Configuration) GetById(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
-// @Summary Get all configurations
-// @Description Get all configurations
+// @Summary Get configurations
+// @Description Get a page of configurations ordered by id by default. Each item is shaped according to its type field (factorio or minecraft). Filters accept comma separated values
 // @Tags configurations
 // @Accept json
 // @Produce json
+// @Param type query string false "Types"
+// @Param agent_id query string false "Agent IDs"
+// @Param sort query string false "Sort field, prefixed with - for descending order"
+// @Param limit query int false "Page size, 50 by default, at most 500"
+// @Param cursor query string false "Cursor of the next page from the X-Next-Cursor header"
 // @Success 200 {array} configuration.Factorio
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Failure 400 {object} error
 // @Failure 500 {object} error
 // @Router /api/configurations [get]
 func (c *Configuration) GetAll(w http.ResponseWriter, r *http.Request) {
-	configuration, err := c.r.GetAll()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	data, err := json.Marshal(configuration)
-	if err != nil {
-		http.Error(w, fmt.Errorf("failed to marshal configuration: %w", err).Error(), http.StatusInternalServerError)
-		return
+	q, err := parseListQuery(r, []string{"type", "agent_id"}, nil)
+	var page *repository.Page[*configuration.Envelope]
+	if err == nil {
+		page, err = c.r.List(q)
 	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(data)
+	writePage(w, page, err, "configurations")
 }
 
 // @Summary Create a new configuration
-// @Description Create a new configuration
+// @Description Create a new configuration. The type field selects the body shape: factorio (configuration.Factorio) or minecraft (configuration.Minecraft)
 // @Tags configurations
 // @Accept json
 // @Produce json
@@ -110,10 +114,11 @@ func (c *Configuration) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Success 201
 // @Failure 400 {object} error
+// @Failure 409 {object} error
 // @Failure 500 {object} error
 // @Router /api/configurations [post]
 func (c *Configuration) Post(w http.ResponseWriter, r *http.Request) {
-	var configuration configuration.Factorio
+	var configuration configuration.Envelope
 	if err := json.NewDecoder(r.Body).Decode(&configuration); err != nil {
 		http.Error(w, fmt.Errorf("failed to unmarshal configuration: %w", err).Error(), http.StatusBadRequest)
 		return
@@ -125,6 +130,11 @@ func (c *Configuration) Post(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := c.r.Add(&configuration); err != nil {
+		if code, ok := placementErrorStatus(err); ok {
+			http.Error(w, err.Error(), code)
+			return
+		}
+
 		http.Error(w, fmt.Errorf("failed to add configuration: %w", err).Error(), http.StatusInternalServerError)
 		return
 	}
@@ -133,7 +143,7 @@ func (c *Configuration) Post(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Update a configuration
-// @Description Update a configuration
+// @Description Update a configuration. The type field selects the body shape: factorio (configuration.Factorio) or minecraft (configuration.Minecraft)
 // @Tags configurations
 // @Accept json
 // @Produce json
@@ -143,6 +153,7 @@ func (c *Configuration) Post(w http.ResponseWriter, r *http.Request) {
 // @Success 200
 // @Failure 400 {object} error
 // @Failure 404 {object} error
+// @Failure 409 {object} error
 // @Failure 500 {object} error
 // @Router /api/configurations/{id} [put]
 func (c *Configuration) Put(w http.ResponseWriter, r *http.Request) {
@@ -158,12 +169,14 @@ func (c *Configuration) Put(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var configuration configuration.Factorio
+	var configuration configuration.Envelope
 	if err := json.NewDecoder(r.Body).Decode(&configuration); err != nil {
 		http.Error(w, fmt.Errorf("failed to unmarshal configuration: %w", err).Error(), http.StatusBadRequest)
 		return
 	}
 
+	configuration.GetBase().Id = uuid
+
 	if !c.v.IsValid(&configuration) {
 		http.Error(w, "invalid configuration", http.StatusBadRequest)
 		return
@@ -175,6 +188,11 @@ func (c *Configuration) Put(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if code, ok := placementErrorStatus(err); ok {
+			http.Error(w, err.Error(), code)
+			return
+		}
+
 		http.Error(w, fmt.Errorf("failed to update configuration: %w", err).Error(), http.StatusInternalServerError)
 		return
 	}
@@ -244,3 +262,17 @@ func (c *Configuration) GetHistory(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
 }
+
+// Ошибки выбора агента и резервирования портов на нём
+func placementErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, services.ErrNoSuitableAgent):
+		return http.StatusConflict, true
+	case errors.Is(err, services.ErrUnknownAgent), errors.Is(err, services.ErrPortOutOfRange), errors.Is(err, services.ErrDuplicatePort):
+		return http.StatusBadRequest, true
+	case errors.Is(err, services.ErrPortConflict), errors.Is(err, services.ErrPortsExhausted), errors.Is(err, services.ErrExceedsCapacity):
+		return http.StatusConflict, true
+	default:
+		return 0, false
+	}
+}
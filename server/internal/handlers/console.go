@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/middleware"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/services"
+)
+
+type consoleService interface {
+	Run(ctx context.Context, actor string, serverId uuid.UUID, command string) (string, error)
+}
+
+type consoleRequest struct {
+	Command string `json:"command"`
+}
+
+type consoleResponse struct {
+	Output string `json:"output"`
+}
+
+type Console struct {
+	c consoleService
+}
+
+func NewConsole(c consoleService) *Console {
+	return &Console{c: c}
+}
+
+// @Summary Run console command
+// @Description Run a command in the remote console (RCON) of a game server, e.g. "/whitelist add Steve". The server id is the id of its configuration
+// @Tags servers
+// @Accept json
+// @Produce json
+// @Param id path string true "Server ID"
+// @Param request body consoleRequest true "Command"
+// @Security BearerAuth
+// @Success 200 {object} consoleResponse
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 502 {object} error
+// @Failure 503 {object} error
+// @Failure 504 {object} error
+// @Failure 500 {object} error
+// @Router /api/servers/{id}/console [post]
+func (c *Console) Post(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to parse id: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req consoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Errorf("failed to unmarshal request: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(req.Command) == "" {
+		http.Error(w, "command is required", http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.c.Run(r.Context(), middleware.User(r.Context()), id, req.Command)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			http.Error(w, "server not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrConsoleUnsupported), errors.Is(err, services.ErrServerNotAssigned), errors.Is(err, services.ErrConsoleNotConfigured):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, services.ErrCommandFailed):
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		case errors.Is(err, services.ErrAgentNotConnected), errors.Is(err, services.ErrSessionClosed):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		case errors.Is(err, services.ErrCommandTimeout):
+			http.Error(w, err.Error(), http.StatusGatewayTimeout)
+		default:
+			http.Error(w, fmt.Errorf("failed to run command: %w", err).Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	data, err := json.Marshal(consoleResponse{Output: output})
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal response: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
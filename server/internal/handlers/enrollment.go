@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/middleware"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/services"
+)
+
+type enrollmentService interface {
+	CreateJoinToken(actor string, agentId uuid.UUID, ttl time.Duration) (string, *agent.JoinToken, error)
+	Enroll(token string, csrPEM []byte) (*agent.Certificate, []byte, error)
+	CACertificate() []byte
+	Certificates(agentId uuid.UUID) ([]*agent.Certificate, error)
+	Revoke(agentId, id uuid.UUID, actor string) error
+}
+
+type joinTokenRequest struct {
+	TtlSeconds uint32    `json:"ttl_seconds,omitempty"`
+	AgentId    uuid.UUID `json:"agent_id,omitempty"`
+}
+
+type joinTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type enrollRequest struct {
+	JoinToken string `json:"join_token"`
+	Csr       string `json:"csr"` // PEM
+}
+
+type enrollResponse struct {
+	AgentId       uuid.UUID          `json:"agent_id"`
+	Certificate   string             `json:"certificate"`    // PEM
+	CaCertificate string             `json:"ca_certificate"` // PEM
+	Info          *agent.Certificate `json:"info"`
+}
+
+type Enrollment struct {
+	e enrollmentService
+}
+
+func NewEnrollment(e enrollmentService) *Enrollment {
+	return &Enrollment{e: e}
+}
+
+// @Summary Create join token
+// @Description Create a one-time token an agent exchanges for a client certificate. The token is shown only once
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Param request body joinTokenRequest false "Token lifetime and optional agent id to re-enroll"
+// @Security BearerAuth
+// @Success 201 {object} joinTokenResponse
+// @Failure 400 {object} error
+// @Failure 500 {object} error
+// @Router /api/agents/join-tokens [post]
+func (e *Enrollment) CreateJoinToken(w http.ResponseWriter, r *http.Request) {
+	var req joinTokenRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Errorf("failed to unmarshal request: %w", err).Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	token, t, err := e.e.CreateJoinToken(middleware.User(r.Context()), req.AgentId, time.Duration(req.TtlSeconds)*time.Second)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to create join token: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(joinTokenResponse{Token: token, ExpiresAt: t.ExpiresAt})
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal response: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+// @Summary Enroll agent
+// @Description Exchange a join token and a PEM certificate signing request for a client certificate
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Param request body enrollRequest true "Join token and CSR"
+// @Success 200 {object} enrollResponse
+// @Failure 400 {object} error
+// @Failure 403 {object} error
+// @Failure 500 {object} error
+// @Router /api/agents/enroll [post]
+func (e *Enrollment) Enroll(w http.ResponseWriter, r *http.Request) {
+	var req enrollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Errorf("failed to unmarshal request: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.JoinToken == "" {
+		http.Error(w, "join_token is required", http.StatusBadRequest)
+		return
+	}
+
+	c, certPEM, err := e.e.Enroll(req.JoinToken, []byte(req.Csr))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCSR):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrInvalidJoinToken):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, fmt.Errorf("failed to enroll agent: %w", err).Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	data, err := json.Marshal(enrollResponse{
+		AgentId:       c.AgentId,
+		Certificate:   string(certPEM),
+		CaCertificate: string(e.e.CACertificate()),
+		Info:          c,
+	})
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal response: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// @Summary Get agent certificates
+// @Description Get client certificates issued to the agent, including revoked ones
+// @Tags agents
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Security BearerAuth
+// @Success 200 {array} agent.Certificate
+// @Failure 400 {object} error
+// @Failure 500 {object} error
+// @Router /api/agents/{id}/certificates [get]
+func (e *Enrollment) GetCertificates(w http.ResponseWriter, r *http.Request) {
+	agentId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to parse id: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	certs, err := e.e.Certificates(agentId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(certs)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal certificates: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// @Summary Revoke agent certificate
+// @Description Revoke a client certificate. The agent can't connect with it anymore
+// @Tags agents
+// @Param id path string true "Agent ID"
+// @Param certId path string true "Certificate ID"
+// @Security BearerAuth
+// @Success 204
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /api/agents/{id}/certificates/{certId} [delete]
+func (e *Enrollment) Revoke(w http.ResponseWriter, r *http.Request) {
+	agentId, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to parse id: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	certId, err := uuid.Parse(r.PathValue("certId"))
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to parse certificate id: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := e.e.Revoke(agentId, certId, middleware.User(r.Context())); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			http.Error(w, "certificate not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Errorf("failed to revoke certificate: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/services"
+)
+
+type eventSubscriber interface {
+	CheckKept(ctx context.Context, after string) error
+	Subscribe(ctx context.Context, q services.EventsQuery, fn func(*event.Event) error) error
+}
+
+type Events struct {
+	e eventSubscriber
+}
+
+func NewEvents(e eventSubscriber) *Events {
+	return &Events{e: e}
+}
+
+// @Summary Subscribe to events
+// @Description Server-Sent Events stream of domain events: agent status, task transitions, configuration and server changes.
+// @Description Every event is sent with its id, type as the SSE event name and a JSON body. Reconnect with Last-Event-ID to get missed events.
+// @Description With group and consumer the events are shared between consumers of the group and the position is kept on the server
+// @Tags events
+// @Produce text/event-stream
+// @Param type query []string false "Event types or groups, e.g. agent, task.status_changed" collectionFormat(csv)
+// @Param Last-Event-ID header string false "Id of the last received event"
+// @Param group query string false "Consumer group"
+// @Param consumer query string false "Consumer name, required with group"
+// @Security BearerAuth
+// @Success 200 {object} event.Event
+// @Failure 400 {object} error
+// @Failure 410 {object} error "Events after Last-Event-ID are no longer kept, reconnect without it"
+// @Failure 500 {object} error
+// @Router /api/events [get]
+func (h *Events) Get(w http.ResponseWriter, r *http.Request) {
+	q := parseEventsQuery(r)
+	if err := q.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Пока статус не отправлен, об устаревшей позиции можно сообщить кодом ответа
+	if q.After != "" && q.Group == "" {
+		err := h.e.CheckKept(r.Context(), q.After)
+		if errors.Is(err, services.ErrEventsExpired) {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		}
+		if err != nil {
+			http.Error(w, fmt.Errorf("failed to check event id: %w", err).Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	err := h.e.Subscribe(r.Context(), q, func(e *event.Event) error {
+		if err := writeEvent(w, e); err != nil {
+			return err
+		}
+		return rc.Flush()
+	})
+	if err != nil && r.Context().Err() == nil {
+		// Статус уже отправлен, остаётся сообщить об ошибке в самом потоке
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+	}
+}
+
+func parseEventsQuery(r *http.Request) services.EventsQuery {
+	values := r.URL.Query()
+
+	q := services.EventsQuery{
+		After:    r.Header.Get("Last-Event-ID"),
+		Group:    values.Get("group"),
+		Consumer: values.Get("consumer"),
+	}
+	for _, v := range values["type"] {
+		for t := range strings.SplitSeq(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				q.Types = append(q.Types, t)
+			}
+		}
+	}
+	return q
+}
+
+// Без события отправляется комментарий, чтобы прокси не закрыли соединение
+func writeEvent(w http.ResponseWriter, e *event.Event) error {
+	if e == nil {
+		_, err := fmt.Fprint(w, ": keepalive\n\n")
+		return err
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.Seq, e.Type, data)
+	return err
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/health"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+type healthService interface {
+	Get(serverId uuid.UUID) (*health.Health, error)
+	GetAll() []*health.Health
+}
+
+type Health struct {
+	h healthService
+}
+
+func NewHealth(h healthService) *Health {
+	return &Health{h: h}
+}
+
+// @Summary Get server health
+// @Description Get the result of the latest player-side check of a game server (0 - unknown, 1 - healthy, 2 - unhealthy). The server id is the id of its configuration
+// @Tags servers
+// @Accept json
+// @Produce json
+// @Param id path string true "Server ID"
+// @Success 200 {object} health.Health
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /api/servers/{id}/health [get]
+func (h *Health) GetById(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to parse id: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.h.Get(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			http.Error(w, "server not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal health: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// @Summary Get health of all servers
+// @Description Get the latest check results of all game servers deployed to agents
+// @Tags servers
+// @Accept json
+// @Produce json
+// @Success 200 {array} health.Health
+// @Failure 500 {object} error
+// @Router /api/servers/health [get]
+func (h *Health) GetAll(w http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(h.h.GetAll())
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal health: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+// Заголовок с курсором следующей страницы списка, на последней странице его нет
+const NEXT_CURSOR_HEADER = "X-Next-Cursor"
+
+// Разбирает параметры списка: фильтры по полям filters (несколько значений через
+// запятую), диапазоны времени по полям ranges (<поле без _at>_from и _to),
+// sort (-поле для убывания), limit и cursor
+func parseListQuery(r *http.Request, filters []string, ranges []string) (repository.ListQuery, error) {
+	params := r.URL.Query()
+	q := repository.ListQuery{Cursor: params.Get("cursor")}
+
+	for _, name := range filters {
+		if v := params.Get(name); v != "" {
+			var values []any
+			for _, s := range strings.Split(v, ",") {
+				values = append(values, s)
+			}
+			q.Conditions = append(q.Conditions, repository.In(name, values...))
+		}
+	}
+
+	for _, name := range ranges {
+		prefix := strings.TrimSuffix(name, "_at")
+		if v := params.Get(prefix + "_from"); v != "" {
+			q.Conditions = append(q.Conditions, repository.Gte(name, v))
+		}
+		if v := params.Get(prefix + "_to"); v != "" {
+			q.Conditions = append(q.Conditions, repository.Lt(name, v))
+		}
+	}
+
+	q.Sort, q.Desc = strings.CutPrefix(params.Get("sort"), "-")
+	if q.Sort == "" {
+		q.Desc = false
+	}
+
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return q, fmt.Errorf("%w: limit must be a number", repository.ErrInvalidQuery)
+		}
+		q.Limit = limit
+	}
+
+	return q, nil
+}
+
+// Пишет страницу списка массивом, курсор следующей страницы - в заголовке
+func writePage[T any](w http.ResponseWriter, page *repository.Page[T], err error, entity string) {
+	if errors.Is(err, repository.ErrInvalidQuery) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(page.Items)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal %s: %w", entity, err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if page.NextCursor != "" {
+		w.Header().Set(NEXT_CURSOR_HEADER, page.NextCursor)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
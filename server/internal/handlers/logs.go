@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/services"
+)
+
+type logsService interface {
+	Stream(ctx context.Context, serverId uuid.UUID, q services.LogsQuery, fn func(*api.LogChunk) error) error
+}
+
+type logLine struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+type Logs struct {
+	l logsService
+}
+
+func NewLogs(l logsService) *Logs {
+	return &Logs{l: l}
+}
+
+// @Summary Get server logs
+// @Description Get output of a game server. The server id is the id of its configuration.
+// @Description With Accept: text/event-stream lines are sent as SSE "log" events with a JSON body, otherwise as chunked plain text "<time> <text>" lines
+// @Tags servers
+// @Produce plain
+// @Produce text/event-stream
+// @Param id path string true "Server ID"
+// @Param since query string false "RFC 3339 time or a duration back from now, e.g. 10m"
+// @Param tail query int false "Number of last lines, all by default"
+// @Param follow query bool false "Keep streaming new lines"
+// @Security BearerAuth
+// @Success 200 {object} logLine
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 503 {object} error
+// @Failure 500 {object} error
+// @Router /api/servers/{id}/logs [get]
+func (l *Logs) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to parse id: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseLogsQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sse := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	rc := http.NewResponseController(w)
+
+	// Заголовки отправляются с первой порцией: до неё ошибку ещё можно вернуть статусом
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+
+		if sse {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+		} else {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	err = l.l.Stream(r.Context(), id, q, func(chunk *api.LogChunk) error {
+		start()
+
+		for _, line := range chunk.Lines {
+			if err := writeLogLine(w, sse, line); err != nil {
+				return err
+			}
+		}
+		return rc.Flush()
+	})
+	if r.Context().Err() != nil {
+		return
+	}
+
+	if err == nil {
+		start()
+		return
+	}
+
+	if started {
+		// Статус уже отправлен, остаётся сообщить об ошибке в самом потоке
+		if sse {
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+		}
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		http.Error(w, "server not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrServerNotAssigned):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrAgentNotConnected), errors.Is(err, services.ErrSessionClosed):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, fmt.Errorf("failed to get logs: %w", err).Error(), http.StatusInternalServerError)
+	}
+}
+
+func parseLogsQuery(r *http.Request) (services.LogsQuery, error) {
+	var q services.LogsQuery
+	values := r.URL.Query()
+
+	if since := values.Get("since"); since != "" {
+		if d, err := time.ParseDuration(since); err == nil {
+			q.Since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339Nano, since); err == nil {
+			q.Since = t
+		} else {
+			return q, fmt.Errorf("since must be an RFC 3339 time or a duration, got %q", since)
+		}
+	}
+
+	if tail := values.Get("tail"); tail != "" {
+		n, err := strconv.ParseUint(tail, 10, 32)
+		if err != nil {
+			return q, fmt.Errorf("failed to parse tail: %w", err)
+		}
+		q.Tail = uint32(n)
+	}
+
+	if follow := values.Get("follow"); follow != "" {
+		v, err := strconv.ParseBool(follow)
+		if err != nil {
+			return q, fmt.Errorf("failed to parse follow: %w", err)
+		}
+		q.Follow = v
+	}
+
+	return q, nil
+}
+
+func writeLogLine(w http.ResponseWriter, sse bool, line *api.LogLine) error {
+	t := line.Time.AsTime()
+	if !sse {
+		_, err := fmt.Fprintf(w, "%s %s\n", t.Format(time.RFC3339Nano), line.Text)
+		return err
+	}
+
+	data, err := json.Marshal(logLine{Time: t, Text: line.Text})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: log\ndata: %s\n\n", data)
+	return err
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/placement"
+	"github.com/vv-sam/otus-project/server/internal/services"
+)
+
+type placer interface {
+	Place(conf configuration.Configuration) (*placement.Decision, error)
+}
+
+type Scheduler struct {
+	s placer
+	v *services.Validator
+}
+
+func NewScheduler(s placer, v *services.Validator) *Scheduler {
+	return &Scheduler{s: s, v: v}
+}
+
+// @Summary Explain placement
+// @Description Show which agent the scheduler would pick for a configuration without an agent_id, or why each agent was rejected. Nothing is saved
+// @Tags configurations
+// @Accept json
+// @Produce json
+// @Param configuration body configuration.Factorio true "Configuration"
+// @Success 200 {object} placement.Decision
+// @Failure 400 {object} error
+// @Failure 500 {object} error
+// @Router /api/configurations/placement [post]
+func (s *Scheduler) Place(w http.ResponseWriter, r *http.Request) {
+	var configuration configuration.Envelope
+	if err := json.NewDecoder(r.Body).Decode(&configuration); err != nil {
+		http.Error(w, fmt.Errorf("failed to unmarshal configuration: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !s.v.IsValid(&configuration) {
+		http.Error(w, "invalid configuration", http.StatusBadRequest)
+		return
+	}
+
+	decision, err := s.s.Place(configuration.Configuration)
+	if err != nil && !errors.Is(err, services.ErrNoSuitableAgent) {
+		http.Error(w, fmt.Errorf("failed to place configuration: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(decision)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal decision: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
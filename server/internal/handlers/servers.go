@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/history"
+	"github.com/vv-sam/otus-project/server/internal/model/server"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"github.com/vv-sam/otus-project/server/internal/services"
+)
+
+type serverRepository interface {
+	Get(id uuid.UUID) (*server.Server, error)
+	GetAll() ([]*server.Server, error)
+	Add(server *server.Server) error
+	Update(id uuid.UUID, server *server.Server) error
+	Delete(id uuid.UUID) error
+	GetHistory() ([]history.Log[*server.Server], error)
+}
+
+type Servers struct {
+	r serverRepository
+	v *services.Validator
+}
+
+func NewServers(r serverRepository, v *services.Validator) *Servers {
+	return &Servers{r: r, v: v}
+}
+
+// @Summary Get server by id
+// @Description Get a game server with its desired and observed state. The id is the configuration id
+// @Tags servers
+// @Accept json
+// @Produce json
+// @Param id path string true "Server ID"
+// @Success 200 {object} server.Server
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /api/servers/{id} [get]
+func (s *Servers) GetById(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	uuid, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to parse id: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	srv, err := s.r.Get(uuid)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if srv == nil {
+		http.Error(w, "server not found", http.StatusNotFound)
+		return
+	}
+
+	data, err := json.Marshal(srv)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal server: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// @Summary Get all servers
+// @Description Get all game servers
+// @Tags servers
+// @Accept json
+// @Produce json
+// @Success 200 {array} server.Server
+// @Failure 500 {object} error
+// @Router /api/servers [get]
+func (s *Servers) GetAll(w http.ResponseWriter, r *http.Request) {
+	servers, err := s.r.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(servers)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal servers: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// @Summary Create a server
+// @Description Deploy a configuration to its agent. Only id (the configuration id) and desired_state (running by default) are used
+// @Tags servers
+// @Accept json
+// @Produce json
+// @Param server body server.Server true "Server"
+// @Security BearerAuth
+// @Success 201
+// @Failure 400 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /api/servers [post]
+func (s *Servers) Post(w http.ResponseWriter, r *http.Request) {
+	var srv server.Server
+	if err := json.NewDecoder(r.Body).Decode(&srv); err != nil {
+		http.Error(w, fmt.Errorf("failed to unmarshal server: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	if srv.DesiredState == "" {
+		srv.DesiredState = server.DESIRED_RUNNING
+	}
+
+	if !s.v.IsValid(srv) {
+		http.Error(w, "invalid server", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.r.Add(&srv); err != nil {
+		if code, ok := serverErrorStatus(err); ok {
+			http.Error(w, err.Error(), code)
+			return
+		}
+
+		http.Error(w, fmt.Errorf("failed to add server: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// @Summary Change server desired state
+// @Description Set desired_state to running or stopped. Other fields are ignored
+// @Tags servers
+// @Accept json
+// @Produce json
+// @Param id path string true "Server ID"
+// @Param server body server.Server true "Server"
+// @Security BearerAuth
+// @Success 200
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /api/servers/{id} [put]
+func (s *Servers) Put(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	uuid, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to parse id: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	var srv server.Server
+	if err := json.NewDecoder(r.Body).Decode(&srv); err != nil {
+		http.Error(w, fmt.Errorf("failed to unmarshal server: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+	srv.Id = uuid
+
+	if !s.v.IsValid(srv) {
+		http.Error(w, "invalid server", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.r.Update(uuid, &srv); err != nil {
+		if code, ok := serverErrorStatus(err); ok {
+			http.Error(w, err.Error(), code)
+			return
+		}
+
+		http.Error(w, fmt.Errorf("failed to update server: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Delete a server
+// @Description Set desired_state to deleted. The server disappears once its container is removed from the agent
+// @Tags servers
+// @Accept json
+// @Produce json
+// @Param id path string true "Server ID"
+// @Security BearerAuth
+// @Success 202
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /api/servers/{id} [delete]
+func (s *Servers) Delete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	uuid, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to parse id: %w", err).Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.r.Delete(uuid); err != nil {
+		if code, ok := serverErrorStatus(err); ok {
+			http.Error(w, err.Error(), code)
+			return
+		}
+
+		http.Error(w, fmt.Errorf("failed to delete server: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// @Summary Get history
+// @Description Get history
+// @Tags servers
+// @Accept json
+// @Produce json
+// @Success 200 {array} object
+// @Failure 500 {object} error
+// @Router /api/servers/history [get]
+func (s *Servers) GetHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := s.r.GetHistory()
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to get history: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		http.Error(w, fmt.Errorf("failed to marshal history: %w", err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func serverErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound, true
+	case errors.Is(err, services.ErrUnknownConfiguration):
+		return http.StatusBadRequest, true
+	case errors.Is(err, services.ErrServerExists), errors.Is(err, services.ErrServerDeleting):
+		return http.StatusConflict, true
+	default:
+		return 0, false
+	}
+}
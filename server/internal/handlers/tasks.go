@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/middleware"
 	"github.com/vv-sam/otus-project/server/internal/model/history"
 	"github.com/vv-sam/otus-project/server/internal/model/task"
 	"github.com/vv-sam/otus-project/server/internal/repository"
@@ -15,9 +17,9 @@ import (
 
 type tasksRepository interface {
 	Get(id uuid.UUID) (*task.Task, error)
-	GetAll() ([]*task.Task, error)
+	List(q repository.ListQuery) (*repository.Page[*task.Task], error)
 	Add(task *task.Task) error
-	Update(id uuid.UUID, task *task.Task) error
+	Put(id uuid.UUID, update *task.Task, actor string) error
 	Delete(id uuid.UUID) error
 	GetHistory() ([]history.Log[*task.Task], error)
 }
@@ -76,29 +78,35 @@ func (t *Tasks) GetById(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
-// @Summary Get all tasks
-// @Description Get all tasks
+// @Summary Get tasks
+// @Description Get a page of tasks, newest first by default. Filters accept comma separated values
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param status query string false "Statuses"
+// @Param type query string false "Types"
+// @Param action query string false "Actions"
+// @Param agent_id query string false "Agent IDs"
+// @Param configuration_id query string false "Configuration IDs"
+// @Param created_from query string false "Created at or after, RFC 3339"
+// @Param created_to query string false "Created before, RFC 3339"
+// @Param sort query string false "Sort field, prefixed with - for descending order"
+// @Param limit query int false "Page size, 50 by default, at most 500"
+// @Param cursor query string false "Cursor of the next page from the X-Next-Cursor header"
 // @Success 200 {array} task.Task
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Failure 400 {object} error
 // @Failure 500 {object} error
 // @Router /api/tasks [get]
 func (t *Tasks) GetAll(w http.ResponseWriter, r *http.Request) {
-	tasks, err := t.r.GetAll()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
+	filters := []string{"status", "type", "action", "agent_id", "configuration_id"}
 
-	data, err := json.Marshal(tasks)
-	if err != nil {
-		http.Error(w, fmt.Errorf("failed to marshal tasks: %w", err).Error(), http.StatusInternalServerError)
-		return
+	q, err := parseListQuery(r, filters, []string{"created_at"})
+	var page *repository.Page[*task.Task]
+	if err == nil {
+		page, err = t.r.List(q)
 	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(data)
+	writePage(w, page, err, "tasks")
 }
 
 // @Summary Create a new task
@@ -119,6 +127,10 @@ func (t *Tasks) Post(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+
 	if !t.v.IsValid(&task) {
 		http.Error(w, "invalid task", http.StatusBadRequest)
 		return
@@ -133,7 +145,7 @@ func (t *Tasks) Post(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Update a task
-// @Description Update a task
+// @Description Update a task. The status can only be changed along allowed transitions
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -143,6 +155,7 @@ func (t *Tasks) Post(w http.ResponseWriter, r *http.Request) {
 // @Success 200
 // @Failure 400 {object} error
 // @Failure 404 {object} error
+// @Failure 409 {object} error
 // @Failure 500 {object} error
 // @Router /api/tasks/{id} [put]
 func (t *Tasks) Put(w http.ResponseWriter, r *http.Request) {
@@ -158,23 +171,30 @@ func (t *Tasks) Put(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var task task.Task
-	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+	var update task.Task
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
 		http.Error(w, fmt.Errorf("failed to unmarshal task: %w", err).Error(), http.StatusBadRequest)
 		return
 	}
 
-	if !t.v.IsValid(&task) {
+	update.Id = uuid
+
+	if !t.v.IsValid(&update) {
 		http.Error(w, "invalid task", http.StatusBadRequest)
 		return
 	}
 
-	if err := t.r.Update(uuid, &task); err != nil {
+	if err := t.r.Put(uuid, &update, middleware.User(r.Context())); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			http.Error(w, "task not found", http.StatusNotFound)
 			return
 		}
 
+		if errors.Is(err, task.ErrInvalidTransition) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
 		http.Error(w, fmt.Errorf("failed to update task: %w", err).Error(), http.StatusInternalServerError)
 		return
 	}
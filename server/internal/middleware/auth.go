@@ -32,6 +32,6 @@ func (a *AuthMiddleware) Authenticate(next http.HandlerFunc) http.Handler {
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(withUser(r.Context(), user)))
 	})
 }
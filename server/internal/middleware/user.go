@@ -0,0 +1,15 @@
+package middleware
+
+import "context"
+
+type userKey struct{}
+
+// Имя пользователя, прошедшего аутентификацию
+func User(ctx context.Context) string {
+	user, _ := ctx.Value(userKey{}).(string)
+	return user
+}
+
+func withUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+)
+
+// Конфигурация, с которой запущен агент. Агент сообщает её при регистрации,
+// по ней сервер решает, какие игровые сервера можно разместить на хосте
+type Config struct {
+	// Произвольное расположение хоста, например регион или датацентр
+	Location string            `json:"location,omitempty" bson:"location,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
+
+	// Порты, которые агент может отдавать игровым серверам
+	PortRange PortRange `json:"port_range" bson:"port_range"`
+	Capacity  Capacity  `json:"capacity" bson:"capacity"`
+
+	Transport string `json:"transport,omitempty" bson:"transport,omitempty"`
+	DataDir   string `json:"data_dir,omitempty" bson:"data_dir,omitempty"`
+	Runtime   string `json:"runtime,omitempty" bson:"runtime,omitempty"`
+}
+
+type PortRange struct {
+	Min uint16 `json:"min" bson:"min"`
+	Max uint16 `json:"max" bson:"max"`
+}
+
+// Ресурсы хоста, которые агент готов отдать игровым серверам. Нулевое значение - без ограничения
+type Capacity struct {
+	CpuCores    float64 `json:"cpu_cores" bson:"cpu_cores"`
+	MemoryBytes uint64  `json:"memory_bytes" bson:"memory_bytes"`
+	DiskBytes   uint64  `json:"disk_bytes" bson:"disk_bytes"`
+	MaxServers  uint32  `json:"max_servers" bson:"max_servers"`
+}
+
+// Пустой диапазон портов означает, что агент не выбирает порты сам.
+// Порт 0 не может быть отдан серверу, поэтому диапазон начинается с 1
+func (c Config) Validate() error {
+	if c.PortRange.Min == 0 && c.PortRange.Max != 0 {
+		return fmt.Errorf("port_range: min must be greater than 0")
+	}
+
+	if c.PortRange.Min > c.PortRange.Max {
+		return fmt.Errorf("port_range: min %d is greater than max %d", c.PortRange.Min, c.PortRange.Max)
+	}
+
+	if c.Capacity.CpuCores < 0 {
+		return fmt.Errorf("capacity: cpu_cores must not be negative")
+	}
+
+	return nil
+}
+
+// Совпадают ли конфигурации. Пустые и незаданные метки не различаются
+func (c Config) Equal(o Config) bool {
+	if !maps.Equal(c.Labels, o.Labels) {
+		return false
+	}
+
+	c.Labels, o.Labels = nil, nil
+	return reflect.DeepEqual(c, o)
+}
@@ -0,0 +1,20 @@
+package agent
+
+import "testing"
+
+func TestConfigValidatePortRange(t *testing.T) {
+	cases := map[PortRange]bool{
+		{}:                       true,
+		{Min: 1, Max: 1}:         true,
+		{Min: 27000, Max: 27999}: true,
+		{Min: 0, Max: 27999}:     false,
+		{Min: 28000, Max: 27000}: false,
+	}
+
+	for rng, valid := range cases {
+		err := Config{PortRange: rng}.Validate()
+		if (err == nil) != valid {
+			t.Errorf("%+v: Validate() = %v, want valid %v", rng, err, valid)
+		}
+	}
+}
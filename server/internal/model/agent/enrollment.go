@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Одноразовый токен, по которому агент получает клиентский сертификат.
+// Сам токен не хранится, только его хеш
+type JoinToken struct {
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Если задан, агент получит сертификат для этого id, иначе сервер выдаст новый id
+	AgentId uuid.UUID `json:"agent_id,omitempty"`
+}
+
+// Выданный агенту клиентский сертификат. Id совпадает с серийным номером сертификата
+type Certificate struct {
+	Id        uuid.UUID `json:"id" bson:"id"`
+	AgentId   uuid.UUID `json:"agent_id" bson:"agent_id"`
+	IssuedAt  time.Time `json:"issued_at" bson:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+
+	RevokedAt time.Time `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	RevokedBy string    `json:"revoked_by,omitempty" bson:"revoked_by,omitempty"`
+}
+
+func (c *Certificate) GetId() uuid.UUID {
+	return c.Id
+}
+
+func (c *Certificate) IsRevoked() bool {
+	return !c.RevokedAt.IsZero()
+}
+
+// Сертификат можно использовать для входа: он не отозван и не истёк
+func (c *Certificate) IsValid(now time.Time) bool {
+	return !c.IsRevoked() && now.Before(c.ExpiresAt)
+}
@@ -2,17 +2,48 @@ package agent
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/vv-sam/otus-project/server/internal/model/metrics"
 	"github.com/vv-sam/otus-project/server/internal/model/task"
 )
 
+const (
+	STATUS_UNKNOWN  = 0
+	STATUS_ONLINE   = 1
+	STATUS_DEGRADED = 2
+	STATUS_OFFLINE  = 3
+)
+
+var statusNames = map[int16]string{
+	STATUS_UNKNOWN:  "unknown",
+	STATUS_ONLINE:   "online",
+	STATUS_DEGRADED: "degraded",
+	STATUS_OFFLINE:  "offline",
+}
+
+func StatusName(status int16) string {
+	if name, ok := statusNames[status]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", status)
+}
+
 type Info struct {
 	AgentId      uuid.UUID           `json:"agent_id" bson:"agent_id"`
 	Status       int16               `json:"status" bson:"status"`
 	CurrentTasks []task.Task         `json:"tasks" bson:"tasks"`
 	Metrics      metrics.HostMetrics `json:"metrics" bson:"metrics"`
+
+	// Время последнего heartbeat от агента
+	LastSeen time.Time `json:"last_seen" bson:"last_seen"`
+
+	// Конфигурация, переданная агентом при последней регистрации
+	Config Config `json:"config" bson:"config"`
+
+	// Потребление ресурсов игровыми серверами по последнему heartbeat
+	Servers []metrics.ServerUsage `json:"servers,omitempty" bson:"servers,omitempty"`
 }
 
 // Вернём строку с id агента и id статуса
@@ -29,5 +60,28 @@ func (i Info) Validate() error {
 		return fmt.Errorf("agent_id is required")
 	}
 
-	return nil
+	return i.Config.Validate()
+}
+
+// Агент принимает новые задачи только в статусе online
+func (i Info) IsAvailable() bool {
+	return i.Status == STATUS_ONLINE
+}
+
+// Статус агента с учётом времени, прошедшего с последнего heartbeat.
+// Агенты, которые ни разу не присылали heartbeat, остаются в текущем статусе.
+func (i Info) StatusAt(now time.Time, degradedAfter, offlineAfter time.Duration) int16 {
+	if i.LastSeen.IsZero() {
+		return i.Status
+	}
+
+	elapsed := now.Sub(i.LastSeen)
+	switch {
+	case elapsed >= offlineAfter:
+		return STATUS_OFFLINE
+	case elapsed >= degradedAfter && i.Status == STATUS_ONLINE:
+		return STATUS_DEGRADED
+	default:
+		return i.Status
+	}
 }
@@ -7,13 +7,19 @@ import (
 )
 
 // Базовые опции, присущие каждой возможной конфигурации
-type baseConfig struct {
+type BaseConfig struct {
 	// уникальный ID конфигурации
 	Id uuid.UUID `json:"id" bson:"id"`
 
 	// ID агента для запуска задачи
 	AgentId uuid.UUID `json:"agent_id" bson:"agent_id"`
 
+	// Требования к агенту. Если agent_id не задан, агента выбирает планировщик
+	Placement *Placement `json:"placement,omitempty" bson:"placement,omitempty"`
+
+	// Ограничения ресурсов, которые агент применяет к контейнеру сервера
+	Resources *Resources `json:"resources,omitempty" bson:"resources,omitempty"`
+
 	// Порт сервера
 	Port uint16 `json:"port" bson:"port"`
 
@@ -21,11 +27,15 @@ type baseConfig struct {
 	Type string `json:"type" bson:"type"`
 }
 
-func (c *baseConfig) GetId() uuid.UUID {
+func (c *BaseConfig) GetId() uuid.UUID {
 	return c.Id
 }
 
-func (c *baseConfig) Validate() error {
+func (c *BaseConfig) GetBase() *BaseConfig {
+	return c
+}
+
+func (c *BaseConfig) Validate() error {
 	if c.Id == uuid.Nil {
 		return fmt.Errorf("id is required")
 	}
@@ -34,5 +44,87 @@ func (c *baseConfig) Validate() error {
 		return fmt.Errorf("type is required")
 	}
 
+	if c.Placement != nil {
+		if err := c.Placement.Validate(); err != nil {
+			return fmt.Errorf("placement: %w", err)
+		}
+	}
+
+	if c.Resources != nil {
+		if err := c.Resources.Validate(); err != nil {
+			return fmt.Errorf("resources: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Память, ядра и диск, которые сервер занимает на агенте: ограничения,
+// а если их нет - минимальные требования размещения
+func (c *BaseConfig) Reserved() (memoryBytes uint64, cpuCores float64, diskBytes uint64) {
+	if p := c.Placement; p != nil {
+		memoryBytes, cpuCores = p.MinMemoryBytes, p.MinCpuCores
+	}
+
+	if r := c.Resources; r != nil {
+		memoryBytes = max(memoryBytes, r.MemoryBytes)
+		cpuCores = max(cpuCores, r.CpuCores)
+		diskBytes = r.DiskBytes
+	}
+	return memoryBytes, cpuCores, diskBytes
+}
+
+// Ограничения, по которым выбирается агент для сервера. Запрошенные
+// память и CPU считаются занятыми на агенте, пока на нём стоит сервер
+type Placement struct {
+	Location string            `json:"location,omitempty" bson:"location,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
+
+	MinMemoryBytes uint64  `json:"min_memory_bytes,omitempty" bson:"min_memory_bytes,omitempty"`
+	MinCpuCores    float64 `json:"min_cpu_cores,omitempty" bson:"min_cpu_cores,omitempty"`
+}
+
+func (p *Placement) Validate() error {
+	if p.MinCpuCores < 0 {
+		return fmt.Errorf("min_cpu_cores must not be negative")
+	}
+
+	return nil
+}
+
+const (
+	// Меньше docker не позволяет
+	MIN_MEMORY_BYTES = 6 << 20
+	MIN_CPU_SHARES   = 2
+)
+
+// Ограничения ресурсов сервера. Нулевое значение - без ограничения
+type Resources struct {
+	// Жёсткая квота CPU в ядрах, например 1.5
+	CpuCores float64 `json:"cpu_cores,omitempty" bson:"cpu_cores,omitempty"`
+
+	// Относительный вес при конкуренции за CPU, по умолчанию у docker 1024
+	CpuShares uint32 `json:"cpu_shares,omitempty" bson:"cpu_shares,omitempty"`
+
+	// При превышении сервер убивается OOM killer
+	MemoryBytes uint64 `json:"memory_bytes,omitempty" bson:"memory_bytes,omitempty"`
+
+	// Квота записываемого слоя контейнера
+	DiskBytes uint64 `json:"disk_bytes,omitempty" bson:"disk_bytes,omitempty"`
+}
+
+func (r *Resources) Validate() error {
+	if r.CpuCores < 0 {
+		return fmt.Errorf("cpu_cores must not be negative")
+	}
+
+	if r.CpuShares != 0 && r.CpuShares < MIN_CPU_SHARES {
+		return fmt.Errorf("cpu_shares must be at least %d", MIN_CPU_SHARES)
+	}
+
+	if r.MemoryBytes != 0 && r.MemoryBytes < MIN_MEMORY_BYTES {
+		return fmt.Errorf("memory_bytes must be at least %d", MIN_MEMORY_BYTES)
+	}
+
 	return nil
 }
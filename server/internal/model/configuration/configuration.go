@@ -0,0 +1,123 @@
+package configuration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Общий интерфейс конфигураций всех поддерживаемых игр
+type Configuration interface {
+	GetId() uuid.UUID
+	GetBase() *BaseConfig
+	Validate() error
+
+	// Порт, к которому подключаются игроки, с учётом значения по умолчанию
+	GamePort() uint16
+
+	// Порты, которые сервер занимает на хосте агента
+	Ports() []PortSlot
+
+	// Параметры запуска сервера на агенте
+	Deployment() (*Deployment, error)
+}
+
+// Поле конфигурации с портом. Нулевой порт назначает сервер
+type PortSlot struct {
+	Name    string // имя поля в API, например rcon_port
+	Port    *uint16
+	Default uint16 // порт игры по умолчанию, если агент не задал диапазон
+}
+
+// Конфигурация игры с удалённой консолью RCON
+type RconEnabled interface {
+	// Порт и пароль RCON. Пароль генерируется при сохранении конфигурации
+	Rcon() (uint16, string)
+}
+
+// Создаёт пустую конфигурацию нужного типа
+func New(configType string) (Configuration, error) {
+	switch configType {
+	case CONFIGURATION_TYPE_FACTORIO:
+		c := &Factorio{}
+		c.Type = configType
+		return c, nil
+	case CONFIGURATION_TYPE_MINECRAFT:
+		c := &Minecraft{}
+		c.Type = configType
+		return c, nil
+	default:
+		return nil, fmt.Errorf("unknown configuration type %q", configType)
+	}
+}
+
+// Конфигурация любого типа. При разборе JSON и BSON конкретный тип
+// выбирается по полю type, поэтому конфигурации разных игр хранятся в одной коллекции
+type Envelope struct {
+	Configuration
+}
+
+func (e *Envelope) GetId() uuid.UUID {
+	if e.Configuration == nil {
+		return uuid.Nil
+	}
+	return e.Configuration.GetId()
+}
+
+func (e *Envelope) Validate() error {
+	if e.Configuration == nil {
+		return errors.New("configuration is empty")
+	}
+	return e.Configuration.Validate()
+}
+
+func (e Envelope) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Configuration)
+}
+
+func (e *Envelope) UnmarshalJSON(data []byte) error {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+
+	c, err := New(header.Type)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return err
+	}
+
+	e.Configuration = c
+	return nil
+}
+
+func (e Envelope) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(e.Configuration)
+}
+
+func (e *Envelope) UnmarshalBSON(data []byte) error {
+	t, ok := bson.Raw(data).Lookup("type").StringValueOK()
+	if !ok {
+		return errors.New("configuration type is missing")
+	}
+
+	c, err := New(t)
+	if err != nil {
+		return err
+	}
+
+	if err := bson.Unmarshal(data, c); err != nil {
+		return err
+	}
+
+	e.Configuration = c
+	return nil
+}
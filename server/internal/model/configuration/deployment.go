@@ -0,0 +1,166 @@
+package configuration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+)
+
+// Образы контейнеров игровых серверов
+const (
+	MINECRAFT_IMAGE = "itzg/minecraft-server"
+	FACTORIO_IMAGE  = "factoriotools/factorio"
+)
+
+const (
+	PROTOCOL_TCP = "tcp"
+	PROTOCOL_UDP = "udp"
+)
+
+// Всё, что нужно агенту, чтобы запустить сервер: образ, окружение, порты
+// и файлы настроек в каталоге данных сервера
+type Deployment struct {
+	Image string            `json:"image"`
+	Env   map[string]string `json:"env,omitempty"`
+	Ports []DeploymentPort  `json:"ports"`
+
+	// Файлы по путям относительно каталога данных сервера
+	Files map[string][]byte `json:"files,omitempty"`
+
+	// Куда каталог данных монтируется внутри контейнера
+	DataPath string `json:"data_path"`
+}
+
+// Порт публикуется на хосте под тем же номером, что и внутри контейнера
+type DeploymentPort struct {
+	Port     uint16 `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+// Хеш всего, что требует пересоздания контейнера. Лимиты ресурсов
+// применяются к запущенному контейнеру и в ревизию не входят
+func (d *Deployment) Revision() (string, error) {
+	// Ключи карт encoding/json сортирует, поэтому хеш стабилен
+	data, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func (c *Minecraft) Deployment() (*Deployment, error) {
+	props, err := c.RenderProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Deployment{
+		Image: MINECRAFT_IMAGE,
+		Env: map[string]string{
+			"EULA": "TRUE",
+			// server.properties генерирует сервис, образ не должен его переписывать
+			"SKIP_SERVER_PROPERTIES": "true",
+		},
+		Ports: []DeploymentPort{
+			{Port: c.serverPort(), Protocol: PROTOCOL_TCP},
+			{Port: c.rconPort(), Protocol: PROTOCOL_TCP},
+		},
+		Files:    map[string][]byte{MINECRAFT_PROPERTIES_FILE: props},
+		DataPath: "/data",
+	}, nil
+}
+
+func (s *Factorio) Deployment() (*Deployment, error) {
+	files, err := s.RenderFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	// Образ читает настройки и пароль RCON из каталога config
+	res := make(map[string][]byte, len(files)+1)
+	for name, data := range files {
+		res[path.Join("config", name)] = data
+	}
+	res["config/rconpw"] = []byte(s.RconPassword)
+
+	port, rconPort := s.GamePort(), s.rconPort()
+	return &Deployment{
+		Image: FACTORIO_IMAGE,
+		Env: map[string]string{
+			"PORT":      strconv.Itoa(int(port)),
+			"RCON_PORT": strconv.Itoa(int(rconPort)),
+		},
+		Ports: []DeploymentPort{
+			{Port: port, Protocol: PROTOCOL_UDP},
+			{Port: rconPort, Protocol: PROTOCOL_TCP},
+		},
+		Files:    res,
+		DataPath: "/factorio",
+	}, nil
+}
+
+// Переносит в conf пароль RCON из old, если он не задан: через API пароль
+// не передаётся, а после смены пароля перестанут работать консоль и проверки.
+// Если пароля нет и в old, генерирует новый, чтобы ревизия не менялась
+// между развёртываниями. Незаданные токен и пароли Factorio тоже берутся из old
+func KeepSecrets(conf, old Configuration) error {
+	if f, ok := conf.(*Factorio); ok {
+		if prev, ok := old.(*Factorio); ok {
+			if f.Server.Token == "" {
+				f.Server.Token = prev.Server.Token
+			}
+			if f.Server.Password == "" {
+				f.Server.Password = prev.Server.Password
+			}
+			if f.Server.GamePassword == "" {
+				f.Server.GamePassword = prev.Server.GamePassword
+			}
+		}
+	}
+
+	p := rconPassword(conf)
+	if p == nil || *p != "" {
+		return nil
+	}
+
+	if old != nil {
+		if prev := rconPassword(old); prev != nil && *prev != "" {
+			*p = *prev
+			return nil
+		}
+	}
+
+	pass, err := generateRconPassword()
+	if err != nil {
+		return fmt.Errorf("failed to generate rcon password: %w", err)
+	}
+	*p = pass
+	return nil
+}
+
+// Пароль RCON ещё не сгенерирован
+func MissingSecrets(c Configuration) bool {
+	p := rconPassword(c)
+	return p != nil && *p == ""
+}
+
+func rconPassword(c Configuration) *string {
+	switch c := c.(type) {
+	case *Minecraft:
+		return &c.RconPassword
+	case *Factorio:
+		return &c.RconPassword
+	default:
+		return nil
+	}
+}
+
+func (s *Factorio) rconPort() uint16 {
+	port, _ := s.Rcon()
+	return port
+}
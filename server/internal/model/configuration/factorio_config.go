@@ -1,7 +1,12 @@
 package configuration
 
+import "encoding/json"
+
 const (
 	CONFIGURATION_TYPE_FACTORIO = "factorio"
+
+	FACTORIO_DEFAULT_PORT      = 34197
+	FACTORIO_DEFAULT_RCON_PORT = 27015
 )
 
 // Конфигурация для запуска сервера Factorio
@@ -10,47 +15,98 @@ type Factorio struct {
 	Map    MapSettings    `json:"map_settings" bson:"map_settings"`
 	MapGen MapGenSettings `json:"map_gen_settings" bson:"map_gen_settings"`
 
-	baseConfig
+	RconPort uint16 `json:"rcon_port" bson:"rcon_port"`
+
+	// Пароль RCON передаётся серверу файлом config/rconpw, генерируется
+	// при сохранении конфигурации и не отдаётся через API
+	RconPassword string `json:"-" bson:"rcon_password,omitempty"`
+
+	BaseConfig `bson:",inline"`
+}
+
+// Игровой UDP-порт
+func (s *Factorio) GamePort() uint16 {
+	if s.Port == 0 {
+		return FACTORIO_DEFAULT_PORT
+	}
+	return s.Port
+}
+
+func (s *Factorio) Ports() []PortSlot {
+	return []PortSlot{
+		{Name: "port", Port: &s.Port, Default: FACTORIO_DEFAULT_PORT},
+		{Name: "rcon_port", Port: &s.RconPort, Default: FACTORIO_DEFAULT_RCON_PORT},
+	}
+}
+
+func (s *Factorio) Rcon() (uint16, string) {
+	if s.RconPort == 0 {
+		return FACTORIO_DEFAULT_RCON_PORT, s.RconPassword
+	}
+	return s.RconPort, s.RconPassword
 }
 
 type ServerSetting struct {
-	Name                                 string   `json:"name" bson:"name"`
-	Description                          string   `json:"description" bson:"description"`
-	Tags                                 []string `json:"tags" bson:"tags"`
-	MaxPlayers                           int      `json:"max_players" bson:"max_players"`
-	Username                             string   `json:"username" bson:"username"`
-	password                             string
-	Token                                string `json:"token" bson:"token"`
-	gamePassword                         string
-	RequireUserVerification              bool   `json:"require_user_verification" bson:"require_user_verification"`
-	MaxUploadInKilobytesPerSecond        int    `json:"max_upload_in_kilobytes_per_second" bson:"max_upload_in_kilobytes_per_second"`
-	MaxUploadSlots                       int    `json:"max_upload_slots" bson:"max_upload_slots"`
-	MinimumLatencyInTicks                int    `json:"minimum_latency_in_ticks" bson:"minimum_latency_in_ticks"`
-	MaxHeartbeatsPerSecond               int    `json:"max_heartbeats_per_second" bson:"max_heartbeats_per_second"`
-	IgnorePlayerLimitForReturningPlayers bool   `json:"ignore_player_limit_for_returning_players" bson:"ignore_player_limit_for_returning_players"`
-	AllowCommands                        string `json:"allow_commands" bson:"allow_commands"`
-	AutosaveInterval                     int    `json:"autosave_interval" bson:"autosave_interval"`
-	AutosaveSlots                        int    `json:"autosave_slots" bson:"autosave_slots"`
-	AfkAutokickInterval                  int    `json:"afk_autokick_interval" bson:"afk_autokick_interval"`
-	AutoPause                            bool   `json:"auto_pause" bson:"auto_pause"`
-	AutoPauseWhenPlayersConnect          bool   `json:"auto_pause_when_players_connect" bson:"auto_pause_when_players_connect"`
-	OnlyAdminsCanPauseTheGame            bool   `json:"only_admins_can_pause_the_game" bson:"only_admins_can_pause_the_game"`
-	AutosaveOnlyOnServer                 bool   `json:"autosave_only_on_server" bson:"autosave_only_on_server"`
-	NonBlockingSaving                    bool   `json:"non_blocking_saving" bson:"non_blocking_saving"`
-	MinimumSegmentSize                   int    `json:"minimum_segment_size" bson:"minimum_segment_size"`
-	MinimumSegmentSizePeerCount          int    `json:"minimum_segment_size_peer_count" bson:"minimum_segment_size_peer_count"`
-	MaximumSegmentSize                   int    `json:"maximum_segment_size" bson:"maximum_segment_size"`
-	MaximumSegmentSizePeerCount          int    `json:"maximum_segment_size_peer_count" bson:"maximum_segment_size_peer_count"`
-}
-
-func (s *Factorio) SetPassword(pass string) {
-	s.Server.password = pass
-}
-
-func (s *Factorio) SetGamePassword(pass string) {
-	s.Server.gamePassword = pass
+	Name                                 string     `json:"name" bson:"name"`
+	Description                          string     `json:"description" bson:"description"`
+	Tags                                 []string   `json:"tags" bson:"tags"`
+	MaxPlayers                           int        `json:"max_players" bson:"max_players"`
+	Visibility                           visibility `json:"visibility" bson:"visibility"`
+	Username                             string     `json:"username" bson:"username"`
+	RequireUserVerification              bool       `json:"require_user_verification" bson:"require_user_verification"`
+	MaxUploadInKilobytesPerSecond        int        `json:"max_upload_in_kilobytes_per_second" bson:"max_upload_in_kilobytes_per_second"`
+	MaxUploadSlots                       int        `json:"max_upload_slots" bson:"max_upload_slots"`
+	MinimumLatencyInTicks                int        `json:"minimum_latency_in_ticks" bson:"minimum_latency_in_ticks"`
+	MaxHeartbeatsPerSecond               int        `json:"max_heartbeats_per_second" bson:"max_heartbeats_per_second"`
+	IgnorePlayerLimitForReturningPlayers bool       `json:"ignore_player_limit_for_returning_players" bson:"ignore_player_limit_for_returning_players"`
+	AllowCommands                        string     `json:"allow_commands" bson:"allow_commands"`
+	AutosaveInterval                     int        `json:"autosave_interval" bson:"autosave_interval"`
+	AutosaveSlots                        int        `json:"autosave_slots" bson:"autosave_slots"`
+	AfkAutokickInterval                  int        `json:"afk_autokick_interval" bson:"afk_autokick_interval"`
+	AutoPause                            bool       `json:"auto_pause" bson:"auto_pause"`
+	AutoPauseWhenPlayersConnect          bool       `json:"auto_pause_when_players_connect" bson:"auto_pause_when_players_connect"`
+	OnlyAdminsCanPauseTheGame            bool       `json:"only_admins_can_pause_the_game" bson:"only_admins_can_pause_the_game"`
+	AutosaveOnlyOnServer                 bool       `json:"autosave_only_on_server" bson:"autosave_only_on_server"`
+	NonBlockingSaving                    bool       `json:"non_blocking_saving" bson:"non_blocking_saving"`
+	MinimumSegmentSize                   int        `json:"minimum_segment_size" bson:"minimum_segment_size"`
+	MinimumSegmentSizePeerCount          int        `json:"minimum_segment_size_peer_count" bson:"minimum_segment_size_peer_count"`
+	MaximumSegmentSize                   int        `json:"maximum_segment_size" bson:"maximum_segment_size"`
+	MaximumSegmentSizePeerCount          int        `json:"maximum_segment_size_peer_count" bson:"maximum_segment_size_peer_count"`
+
+	// Токен и пароль учётной записи factorio.com и пароль игры принимаются через API,
+	// но не отдаются. Пустое значение при обновлении оставляет сохранённое
+	Token        string `json:"-" bson:"token"`
+	Password     string `json:"-" bson:"password,omitempty"`
+	GamePassword string `json:"-" bson:"game_password,omitempty"`
 }
 
+// Разбирает настройки вместе с секретами, которые не попадают в ответы API
+func (s *ServerSetting) UnmarshalJSON(data []byte) error {
+	type plain ServerSetting
+	var v struct {
+		plain
+		Token        string `json:"token"`
+		Password     string `json:"password"`
+		GamePassword string `json:"game_password"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*s = ServerSetting(v.plain)
+	s.Token = v.Token
+	s.Password = v.Password
+	s.GamePassword = v.GamePassword
+	return nil
+}
+
+type visibility struct {
+	Public bool `json:"public" bson:"public"`
+	Lan    bool `json:"lan" bson:"lan"`
+}
+
+// Настройки карты. Нулевые числа и незаданные флаги enabled при развёртывании
+// заменяются значениями из upstream-примера map-settings.json
 type MapSettings struct {
 	DifficultySettings difficulty `json:"difficulty_settings" bson:"difficulty_settings"`
 	Pollution          pollution  `json:"pollution" bson:"pollution"`
@@ -65,7 +121,7 @@ type difficulty struct {
 }
 
 type pollution struct {
-	Enabled                                 bool    `json:"enabled" bson:"enabled"`
+	Enabled                                 *bool   `json:"enabled,omitempty" bson:"enabled,omitempty"`
 	DiffusionRatio                          float64 `json:"diffusion_ratio" bson:"diffusion_ratio"`
 	MinToDiffuse                            int     `json:"min_to_diffuse" bson:"min_to_diffuse"`
 	Ageing                                  int     `json:"ageing" bson:"ageing"`
@@ -80,14 +136,14 @@ type pollution struct {
 }
 
 type evolution struct {
-	Enabled         bool    `json:"enabled" bson:"enabled"`
+	Enabled         *bool   `json:"enabled,omitempty" bson:"enabled,omitempty"`
 	TimeFactor      float64 `json:"time_factor" bson:"time_factor"`
 	DestroyFactor   float64 `json:"destroy_factor" bson:"destroy_factor"`
 	PollutionFactor float64 `json:"pollution_factor" bson:"pollution_factor"`
 }
 
 type expansion struct {
-	Enabled                          bool    `json:"enabled" bson:"enabled"`
+	Enabled                          *bool   `json:"enabled,omitempty" bson:"enabled,omitempty"`
 	MaxExpansionDistance             int     `json:"max_expansion_distance" bson:"max_expansion_distance"`
 	FriendlyBaseInfluenceRadius      int     `json:"friendly_base_influence_radius" bson:"friendly_base_influence_radius"`
 	EnemyBuildingInfluenceRadius     int     `json:"enemy_building_influence_radius" bson:"enemy_building_influence_radius"`
@@ -128,6 +184,19 @@ type MapGenSettings struct {
 	Seed              int                 `json:"seed" bson:"seed"`
 }
 
+// Задаёт настройки генерации ресурса name
+func (s *MapGenSettings) SetAutoplaceControl(name string, frequency, size, richness float32) {
+	if s.AutoplaceControls == nil {
+		s.AutoplaceControls = make(map[string]resource)
+	}
+
+	s.AutoplaceControls[name] = resource{
+		Frequency: frequency,
+		Size:      size,
+		Richness:  richness,
+	}
+}
+
 type resource struct {
 	Frequency float32 `json:"frequency" bson:"frequency"`
 	Size      float32 `json:"size" bson:"size"`
@@ -0,0 +1,367 @@
+package configuration
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Имена файлов, которые читает headless-сервер Factorio
+const (
+	FACTORIO_SERVER_SETTINGS_FILE  = "server-settings.json"
+	FACTORIO_MAP_SETTINGS_FILE     = "map-settings.json"
+	FACTORIO_MAP_GEN_SETTINGS_FILE = "map-gen-settings.json"
+)
+
+// Upstream-пример map-settings.json без комментариев. В нём есть разделы,
+// которых нет в MapSettings (steering, path_finder...), без них сервер
+// получил бы неполные настройки
+//
+//go:embed defaults/factorio-map-settings.json
+var factorioDefaultMapSettings []byte
+
+// server-settings.json в формате и порядке ключей upstream-примера.
+// В отличие от ServerSetting, пароли в нём не скрыты
+type serverSettingsFile struct {
+	Name                                 string     `json:"name"`
+	Description                          string     `json:"description"`
+	Tags                                 []string   `json:"tags"`
+	MaxPlayers                           int        `json:"max_players"`
+	Visibility                           visibility `json:"visibility"`
+	Username                             string     `json:"username"`
+	Password                             string     `json:"password"`
+	Token                                string     `json:"token"`
+	GamePassword                         string     `json:"game_password"`
+	RequireUserVerification              bool       `json:"require_user_verification"`
+	MaxUploadInKilobytesPerSecond        int        `json:"max_upload_in_kilobytes_per_second"`
+	MaxUploadSlots                       int        `json:"max_upload_slots"`
+	MinimumLatencyInTicks                int        `json:"minimum_latency_in_ticks"`
+	MaxHeartbeatsPerSecond               int        `json:"max_heartbeats_per_second"`
+	IgnorePlayerLimitForReturningPlayers bool       `json:"ignore_player_limit_for_returning_players"`
+	AllowCommands                        string     `json:"allow_commands"`
+	AutosaveInterval                     int        `json:"autosave_interval"`
+	AutosaveSlots                        int        `json:"autosave_slots"`
+	AfkAutokickInterval                  int        `json:"afk_autokick_interval"`
+	AutoPause                            bool       `json:"auto_pause"`
+	AutoPauseWhenPlayersConnect          bool       `json:"auto_pause_when_players_connect"`
+	OnlyAdminsCanPauseTheGame            bool       `json:"only_admins_can_pause_the_game"`
+	AutosaveOnlyOnServer                 bool       `json:"autosave_only_on_server"`
+	NonBlockingSaving                    bool       `json:"non_blocking_saving"`
+	MinimumSegmentSize                   int        `json:"minimum_segment_size"`
+	MinimumSegmentSizePeerCount          int        `json:"minimum_segment_size_peer_count"`
+	MaximumSegmentSize                   int        `json:"maximum_segment_size"`
+	MaximumSegmentSizePeerCount          int        `json:"maximum_segment_size_peer_count"`
+}
+
+func (s *Factorio) RenderServerSettings() ([]byte, error) {
+	tags := s.Server.Tags
+	if tags == nil {
+		// Factorio ожидает массив, а не null
+		tags = []string{}
+	}
+
+	return renderJson(serverSettingsFile{
+		Name:                                 s.Server.Name,
+		Description:                          s.Server.Description,
+		Tags:                                 tags,
+		MaxPlayers:                           s.Server.MaxPlayers,
+		Visibility:                           s.Server.Visibility,
+		Username:                             s.Server.Username,
+		Password:                             s.Server.Password,
+		Token:                                s.Server.Token,
+		GamePassword:                         s.Server.GamePassword,
+		RequireUserVerification:              s.Server.RequireUserVerification,
+		MaxUploadInKilobytesPerSecond:        s.Server.MaxUploadInKilobytesPerSecond,
+		MaxUploadSlots:                       s.Server.MaxUploadSlots,
+		MinimumLatencyInTicks:                s.Server.MinimumLatencyInTicks,
+		MaxHeartbeatsPerSecond:               s.Server.MaxHeartbeatsPerSecond,
+		IgnorePlayerLimitForReturningPlayers: s.Server.IgnorePlayerLimitForReturningPlayers,
+		AllowCommands:                        s.Server.AllowCommands,
+		AutosaveInterval:                     s.Server.AutosaveInterval,
+		AutosaveSlots:                        s.Server.AutosaveSlots,
+		AfkAutokickInterval:                  s.Server.AfkAutokickInterval,
+		AutoPause:                            s.Server.AutoPause,
+		AutoPauseWhenPlayersConnect:          s.Server.AutoPauseWhenPlayersConnect,
+		OnlyAdminsCanPauseTheGame:            s.Server.OnlyAdminsCanPauseTheGame,
+		AutosaveOnlyOnServer:                 s.Server.AutosaveOnlyOnServer,
+		NonBlockingSaving:                    s.Server.NonBlockingSaving,
+		MinimumSegmentSize:                   s.Server.MinimumSegmentSize,
+		MinimumSegmentSizePeerCount:          s.Server.MinimumSegmentSizePeerCount,
+		MaximumSegmentSize:                   s.Server.MaximumSegmentSize,
+		MaximumSegmentSizePeerCount:          s.Server.MaximumSegmentSizePeerCount,
+	})
+}
+
+// map-settings.json: upstream-пример, поверх него поля MapSettings
+func (s *Factorio) RenderMapSettings() ([]byte, error) {
+	defaults, err := parseJsonObject(factorioDefaultMapSettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse default map settings: %w", err)
+	}
+
+	data, err := json.Marshal(s.Map)
+	if err != nil {
+		return nil, err
+	}
+	configured, err := parseJsonObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderJson(defaults.overlay(configured))
+}
+
+func (s *Factorio) RenderMapGenSettings() ([]byte, error) {
+	mapGen := s.MapGen
+	if mapGen.AutoplaceControls == nil {
+		mapGen.AutoplaceControls = map[string]resource{}
+	}
+
+	return renderJson(mapGen)
+}
+
+// Содержимое всех файлов настроек по их именам. Пароль RCON генерируется, если ещё не задан
+func (s *Factorio) RenderFiles() (map[string][]byte, error) {
+	if s.RconPassword == "" {
+		pass, err := generateRconPassword()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate rcon password: %w", err)
+		}
+		s.RconPassword = pass
+	}
+
+	files := make(map[string][]byte, 3)
+	renderers := map[string]func() ([]byte, error){
+		FACTORIO_SERVER_SETTINGS_FILE:  s.RenderServerSettings,
+		FACTORIO_MAP_SETTINGS_FILE:     s.RenderMapSettings,
+		FACTORIO_MAP_GEN_SETTINGS_FILE: s.RenderMapGenSettings,
+	}
+
+	for name, render := range renderers {
+		data, err := render()
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		files[name] = data
+	}
+
+	return files, nil
+}
+
+func (s *Factorio) WriteFiles(dir string) error {
+	files, err := s.RenderFiles()
+	if err != nil {
+		return err
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Заполняет настройки из содержимого файлов. Базовые поля (id, агент, порт) не затрагиваются
+func (s *Factorio) ParseFiles(serverSettings, mapSettings, mapGenSettings []byte) error {
+	var server serverSettingsFile
+	if err := json.Unmarshal(serverSettings, &server); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", FACTORIO_SERVER_SETTINGS_FILE, err)
+	}
+
+	var m MapSettings
+	if err := json.Unmarshal(mapSettings, &m); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", FACTORIO_MAP_SETTINGS_FILE, err)
+	}
+
+	var mapGen MapGenSettings
+	if err := json.Unmarshal(mapGenSettings, &mapGen); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", FACTORIO_MAP_GEN_SETTINGS_FILE, err)
+	}
+
+	s.Server = ServerSetting{
+		Name:                                 server.Name,
+		Description:                          server.Description,
+		Tags:                                 server.Tags,
+		MaxPlayers:                           server.MaxPlayers,
+		Visibility:                           server.Visibility,
+		Username:                             server.Username,
+		Password:                             server.Password,
+		Token:                                server.Token,
+		GamePassword:                         server.GamePassword,
+		RequireUserVerification:              server.RequireUserVerification,
+		MaxUploadInKilobytesPerSecond:        server.MaxUploadInKilobytesPerSecond,
+		MaxUploadSlots:                       server.MaxUploadSlots,
+		MinimumLatencyInTicks:                server.MinimumLatencyInTicks,
+		MaxHeartbeatsPerSecond:               server.MaxHeartbeatsPerSecond,
+		IgnorePlayerLimitForReturningPlayers: server.IgnorePlayerLimitForReturningPlayers,
+		AllowCommands:                        server.AllowCommands,
+		AutosaveInterval:                     server.AutosaveInterval,
+		AutosaveSlots:                        server.AutosaveSlots,
+		AfkAutokickInterval:                  server.AfkAutokickInterval,
+		AutoPause:                            server.AutoPause,
+		AutoPauseWhenPlayersConnect:          server.AutoPauseWhenPlayersConnect,
+		OnlyAdminsCanPauseTheGame:            server.OnlyAdminsCanPauseTheGame,
+		AutosaveOnlyOnServer:                 server.AutosaveOnlyOnServer,
+		NonBlockingSaving:                    server.NonBlockingSaving,
+		MinimumSegmentSize:                   server.MinimumSegmentSize,
+		MinimumSegmentSizePeerCount:          server.MinimumSegmentSizePeerCount,
+		MaximumSegmentSize:                   server.MaximumSegmentSize,
+		MaximumSegmentSizePeerCount:          server.MaximumSegmentSizePeerCount,
+	}
+	s.Map = m
+	s.MapGen = mapGen
+
+	return nil
+}
+
+func (s *Factorio) ReadFiles(dir string) error {
+	var contents [3][]byte
+	for i, name := range []string{FACTORIO_SERVER_SETTINGS_FILE, FACTORIO_MAP_SETTINGS_FILE, FACTORIO_MAP_GEN_SETTINGS_FILE} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		contents[i] = data
+	}
+
+	return s.ParseFiles(contents[0], contents[1], contents[2])
+}
+
+// JSON с отступом в два пробела и переводом строки в конце, как в примерах Factorio.
+// HTML-символы не экранируются, чтобы описание сервера оставалось читаемым
+func renderJson(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// JSON-объект с порядком ключей как в исходном документе
+type jsonObject []jsonMember
+
+type jsonMember struct {
+	Key   string
+	Value any // jsonObject, []any, json.Number, string, bool или nil
+}
+
+func parseJsonObject(data []byte) (jsonObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	v, err := decodeJsonValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	o, ok := v.(jsonObject)
+	if !ok {
+		return nil, errors.New("json object expected")
+	}
+	return o, nil
+}
+
+func decodeJsonValue(dec *json.Decoder) (any, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case json.Delim('{'):
+		o := jsonObject{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJsonValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, jsonMember{Key: key.(string), Value: value})
+		}
+		_, err := dec.Token()
+		return o, err
+	case json.Delim('['):
+		a := []any{}
+		for dec.More() {
+			value, err := decodeJsonValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, value)
+		}
+		_, err := dec.Token()
+		return a, err
+	default:
+		return t, nil
+	}
+}
+
+// Переносит значения из src, вложенные объекты объединяются. Нулевые числа
+// и null не переносятся: для них остаётся значение из o
+func (o jsonObject) overlay(src jsonObject) jsonObject {
+	for _, m := range src {
+		i := slices.IndexFunc(o, func(d jsonMember) bool { return d.Key == m.Key })
+		if i < 0 {
+			o = append(o, m)
+			continue
+		}
+
+		dst, dstObject := o[i].Value.(jsonObject)
+		value, srcObject := m.Value.(jsonObject)
+		switch {
+		case dstObject && srcObject:
+			o[i].Value = dst.overlay(value)
+		case !isZeroJson(m.Value):
+			o[i].Value = m.Value
+		}
+	}
+	return o
+}
+
+func isZeroJson(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case json.Number:
+		f, err := v.Float64()
+		return err == nil && f == 0
+	default:
+		return false
+	}
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(m.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,310 @@
+package configuration
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+const factorioGoldenDir = "testdata/factorio"
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func goldenFactorio() *Factorio {
+	c := &Factorio{
+		Server: ServerSetting{
+			Name:                          "Name of the game as it will appear in the game listing",
+			Description:                   "Description of the game <that> will appear & in the listing",
+			Tags:                          []string{"game", "tags"},
+			MaxPlayers:                    0,
+			Visibility:                    visibility{Public: true, Lan: true},
+			Username:                      "factorio-user",
+			Token:                         "0123456789abcdef",
+			RequireUserVerification:       true,
+			MaxUploadInKilobytesPerSecond: 0,
+			MaxUploadSlots:                5,
+			MinimumLatencyInTicks:         0,
+			MaxHeartbeatsPerSecond:        60,
+			AllowCommands:                 "admins-only",
+			AutosaveInterval:              10,
+			AutosaveSlots:                 5,
+			AutoPause:                     true,
+			AutoPauseWhenPlayersConnect:   false,
+			OnlyAdminsCanPauseTheGame:     true,
+			AutosaveOnlyOnServer:          true,
+			MinimumSegmentSize:            25,
+			MinimumSegmentSizePeerCount:   20,
+			MaximumSegmentSize:            100,
+			MaximumSegmentSizePeerCount:   10,
+			Password:                      "account-secret",
+			GamePassword:                  "letmein",
+		},
+		Map: MapSettings{
+			DifficultySettings: difficulty{TechnologyPriceMultiplier: 2, SpoilTimeModifier: 1},
+			Pollution: pollution{
+				Enabled:                                 boolPtr(false),
+				DiffusionRatio:                          0.02,
+				MinToDiffuse:                            15,
+				Ageing:                                  1,
+				ExpectedMaxPerChunk:                     150,
+				MinToShowPerChunk:                       50,
+				MinPollutionToDamageTrees:               60,
+				PollutionWithMaxForestDamage:            150,
+				PollutionPerTreeDamage:                  50,
+				PollutionRestoredPerTreeDamage:          10,
+				MaxPollutionToRestoreTrees:              20,
+				EnemyAttackPollutionConsumptionModifier: 1,
+			},
+			EnemyEvolution: evolution{Enabled: boolPtr(true), TimeFactor: 0.000004, DestroyFactor: 0.002, PollutionFactor: 0.0000009},
+			EnemyExpansion: expansion{
+				Enabled:                          boolPtr(true),
+				MaxExpansionDistance:             7,
+				FriendlyBaseInfluenceRadius:      2,
+				EnemyBuildingInfluenceRadius:     2,
+				BuildingCoefficient:              0.1,
+				OtherBaseCoefficient:             2,
+				NeighbouringChunkCoefficient:     0.5,
+				NeighbouringBaseChunkCoefficient: 0.4,
+				MaxCollidingTilesCoefficient:     0.9,
+				SettlerGroupMinSize:              5,
+				SettlerGroupMaxSize:              20,
+				MinExpansionCooldown:             14400,
+				MaxExpansionCooldown:             216000,
+			},
+			UnitGroup: unitGroup{
+				MinGroupGatheringTime:          3600,
+				MaxGroupGatheringTime:          36000,
+				MaxWaitTimeForLateMembers:      7200,
+				MaxGroupRadius:                 30,
+				MinGroupRadius:                 5,
+				MaxMemberSpeedupWhenBehind:     1.4,
+				MaxMemberSlowdownWhenAhead:     0.6,
+				MaxGroupSlowdownFactor:         0.3,
+				MaxGroupMemberFallbackFactor:   3,
+				MemberDisownDistance:           10,
+				TickToleranceWhenMemberArrives: 60,
+				MaxGatheringUnitGroups:         30,
+				MaxUnitGroupSize:               200,
+			},
+		},
+		MapGen: MapGenSettings{
+			Width:         0,
+			Height:        0,
+			StartingArea:  1,
+			PeacefulMode:  false,
+			CliffSettings: cliff{Name: "cliff", CliffElevation0: 10, CliffElevationInterval: 40, Richness: 1},
+			Seed:          123456,
+		},
+	}
+	c.Type = CONFIGURATION_TYPE_FACTORIO
+	c.MapGen.SetAutoplaceControl("coal", 1, 1, 1)
+	c.MapGen.SetAutoplaceControl("iron-ore", 1.5, 0.5, 2)
+	return c
+}
+
+func TestFactorioRenderGolden(t *testing.T) {
+	files, err := goldenFactorio().RenderFiles()
+	if err != nil {
+		t.Fatalf("RenderFiles: %v", err)
+	}
+
+	for name, got := range files {
+		checkGolden(t, filepath.Join(factorioGoldenDir, name), got)
+	}
+}
+
+// Без заданных полей map-settings.json совпадает с upstream-примером
+func TestFactorioRenderEmptyMapSettingsGolden(t *testing.T) {
+	got, err := (&Factorio{}).RenderMapSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "testdata/factorio-empty/map-settings.json", got)
+}
+
+// Заданные поля меняют только себя, остальное берётся из upstream-примера
+func TestFactorioRenderPartialMapSettings(t *testing.T) {
+	c := &Factorio{}
+	c.Map.Pollution.Enabled = boolPtr(false)
+	c.Map.EnemyExpansion.MaxExpansionDistance = 3
+
+	got, err := c.RenderMapSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m struct {
+		Pollution      map[string]any `json:"pollution"`
+		EnemyExpansion map[string]any `json:"enemy_expansion"`
+		PathFinder     map[string]any `json:"path_finder"`
+	}
+	if err := json.Unmarshal(got, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Pollution["enabled"] != false || m.Pollution["min_to_diffuse"] != 15.0 {
+		t.Errorf("pollution: %v", m.Pollution)
+	}
+	if m.EnemyExpansion["enabled"] != true || m.EnemyExpansion["max_expansion_distance"] != 3.0 {
+		t.Errorf("enemy expansion: %v", m.EnemyExpansion)
+	}
+	if m.PathFinder["use_path_cache"] != true {
+		t.Errorf("path finder: %v", m.PathFinder)
+	}
+}
+
+func checkGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s differs from golden file:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestFactorioParseGoldenRoundTrip(t *testing.T) {
+	parsed := &Factorio{}
+	if err := parsed.ReadFiles(factorioGoldenDir); err != nil {
+		t.Fatalf("ReadFiles: %v", err)
+	}
+
+	want := goldenFactorio()
+	if !reflect.DeepEqual(parsed.Server, want.Server) {
+		t.Errorf("server settings:\ngot  %+v\nwant %+v", parsed.Server, want.Server)
+	}
+	if !reflect.DeepEqual(parsed.Map, want.Map) {
+		t.Errorf("map settings:\ngot  %+v\nwant %+v", parsed.Map, want.Map)
+	}
+	if !reflect.DeepEqual(parsed.MapGen, want.MapGen) {
+		t.Errorf("map gen settings:\ngot  %+v\nwant %+v", parsed.MapGen, want.MapGen)
+	}
+
+	// Повторная запись разобранных файлов даёт те же байты
+	files, err := parsed.RenderFiles()
+	if err != nil {
+		t.Fatalf("RenderFiles: %v", err)
+	}
+	for name, got := range files {
+		want, err := os.ReadFile(filepath.Join(factorioGoldenDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s changed after round trip", name)
+		}
+	}
+}
+
+func TestFactorioWriteReadFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := goldenFactorio().WriteFiles(dir); err != nil {
+		t.Fatalf("WriteFiles: %v", err)
+	}
+
+	read := &Factorio{}
+	if err := read.ReadFiles(dir); err != nil {
+		t.Fatalf("ReadFiles: %v", err)
+	}
+	if read.Server.Token != "0123456789abcdef" || read.Server.Password != "account-secret" || read.Server.GamePassword != "letmein" {
+		t.Errorf("secrets were not written: %q, %q, %q", read.Server.Token, read.Server.Password, read.Server.GamePassword)
+	}
+}
+
+func TestFactorioRenderEmptyCollections(t *testing.T) {
+	c := &Factorio{}
+
+	server, err := c.RenderServerSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(server), `"tags": []`) {
+		t.Errorf("tags are not rendered as an empty array:\n%s", server)
+	}
+
+	mapGen, err := c.RenderMapGenSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(mapGen), `"autoplace_controls": {}`) {
+		t.Errorf("autoplace_controls are not rendered as an empty object:\n%s", mapGen)
+	}
+}
+
+func TestFactorioParseInvalidFiles(t *testing.T) {
+	valid := []byte("{}")
+	cases := map[string][3][]byte{
+		FACTORIO_SERVER_SETTINGS_FILE:  {[]byte("{"), valid, valid},
+		FACTORIO_MAP_SETTINGS_FILE:     {valid, []byte("[]"), valid},
+		FACTORIO_MAP_GEN_SETTINGS_FILE: {valid, valid, []byte("nope")},
+	}
+
+	for name, files := range cases {
+		err := (&Factorio{}).ParseFiles(files[0], files[1], files[2])
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: expected parse error naming the file, got %v", name, err)
+		}
+	}
+}
+
+func TestServerSettingSecretsAreWriteOnly(t *testing.T) {
+	var s ServerSetting
+	if err := json.Unmarshal([]byte(`{"name":"n","token":"t","password":"p","game_password":"g"}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "n" || s.Token != "t" || s.Password != "p" || s.GamePassword != "g" {
+		t.Fatalf("unexpected settings: %+v", s)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{`"t"`, `"p"`, `"g"`} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("secret %s leaked into API output: %s", secret, data)
+		}
+	}
+}
+
+func TestKeepSecretsKeepsFactorioSecrets(t *testing.T) {
+	old := goldenFactorio()
+
+	conf := goldenFactorio()
+	conf.Server.Token = ""
+	conf.Server.Password = ""
+	conf.Server.GamePassword = "changed"
+	if err := KeepSecrets(conf, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.Server.Token != "0123456789abcdef" {
+		t.Errorf("empty token was not kept: %q", conf.Server.Token)
+	}
+
+	if conf.Server.Password != "account-secret" {
+		t.Errorf("empty password was not kept: %q", conf.Server.Password)
+	}
+	if conf.Server.GamePassword != "changed" {
+		t.Errorf("new game password was overwritten: %q", conf.Server.GamePassword)
+	}
+}
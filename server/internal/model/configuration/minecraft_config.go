@@ -12,16 +12,52 @@ const (
 )
 
 type Minecraft struct {
-	Seed         string `json:"seed"`
-	RconPort     uint16 `json:"rcon_port"`
-	Gamemode     string `json:"gamemode"`
-	ServerName   string `json:"server_name"`
-	MaxPlayers   uint   `json:"max_players"`
-	ViewDistance int    `json:"view_distance"`
-
-	baseConfig
+	Seed         string `json:"seed" bson:"seed"`
+	RconPort     uint16 `json:"rcon_port" bson:"rcon_port"`
+	Gamemode     string `json:"gamemode" bson:"gamemode"`
+	ServerName   string `json:"server_name" bson:"server_name"`
+	MaxPlayers   uint   `json:"max_players" bson:"max_players"`
+	ViewDistance int    `json:"view_distance" bson:"view_distance"`
+
+	// Пароль RCON генерируется при сохранении конфигурации и не отдаётся через API.
+	// Пустой пароль не перезаписывает сохранённый при обновлении конфигурации
+	RconPassword string `json:"-" bson:"rcon_password,omitempty"`
+
+	// Остальные ключи server.properties, которыми не управляет сервис
+	Properties map[string]string `json:"properties,omitempty" bson:"properties,omitempty"`
+
+	BaseConfig `bson:",inline"`
 }
 
 func (c Minecraft) String() string {
 	return fmt.Sprintf("%q, %d", c.ServerName, c.MaxPlayers)
 }
+
+func (c *Minecraft) GamePort() uint16 {
+	return c.serverPort()
+}
+
+func (c *Minecraft) Ports() []PortSlot {
+	return []PortSlot{
+		{Name: "port", Port: &c.Port, Default: MINECRAFT_DEFAULT_PORT},
+		{Name: "rcon_port", Port: &c.RconPort, Default: MINECRAFT_DEFAULT_RCON_PORT},
+	}
+}
+
+func (c *Minecraft) Rcon() (uint16, string) {
+	return c.rconPort(), c.RconPassword
+}
+
+func (c *Minecraft) Validate() error {
+	if err := c.BaseConfig.Validate(); err != nil {
+		return err
+	}
+
+	switch c.Gamemode {
+	case "", GAMEMODE_SURVIVAL, GAMEMODE_CREATIVE, GAMEMODE_ADVENTURE, GAMEMODE_SPECTATOR:
+	default:
+		return fmt.Errorf("unknown gamemode %q", c.Gamemode)
+	}
+
+	return nil
+}
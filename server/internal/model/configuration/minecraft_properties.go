@@ -0,0 +1,397 @@
+package configuration
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	MINECRAFT_PROPERTIES_FILE = "server.properties"
+
+	MINECRAFT_DEFAULT_PORT      = 25565
+	MINECRAFT_DEFAULT_RCON_PORT = 25575
+)
+
+// Ключи server.properties, значения которых берутся из полей конфигурации
+const (
+	propLevelSeed    = "level-seed"
+	propRconPort     = "rcon.port"
+	propRconPassword = "rcon.password"
+	propEnableRcon   = "enable-rcon"
+	propGamemode     = "gamemode"
+	propMotd         = "motd"
+	propMaxPlayers   = "max-players"
+	propViewDistance = "view-distance"
+	propServerPort   = "server-port"
+)
+
+// Значения по умолчанию vanilla-сервера для ключей, не заданных пользователем
+var minecraftDefaultProperties = map[string]string{
+	"allow-flight":                      "false",
+	"allow-nether":                      "true",
+	"broadcast-console-to-ops":          "true",
+	"broadcast-rcon-to-ops":             "true",
+	"difficulty":                        "easy",
+	"enable-command-block":              "false",
+	"enable-jmx-monitoring":             "false",
+	"enable-query":                      "false",
+	"enable-status":                     "true",
+	"enforce-secure-profile":            "true",
+	"enforce-whitelist":                 "false",
+	"entity-broadcast-range-percentage": "100",
+	"force-gamemode":                    "false",
+	"function-permission-level":         "2",
+	"generate-structures":               "true",
+	"generator-settings":                "{}",
+	"hardcore":                          "false",
+	"hide-online-players":               "false",
+	"initial-disabled-packs":            "",
+	"initial-enabled-packs":             "vanilla",
+	"level-name":                        "world",
+	"level-type":                        "minecraft:normal",
+	"log-ips":                           "true",
+	"max-chained-neighbor-updates":      "1000000",
+	"max-tick-time":                     "60000",
+	"max-world-size":                    "29999984",
+	"network-compression-threshold":     "256",
+	"online-mode":                       "true",
+	"op-permission-level":               "4",
+	"player-idle-timeout":               "0",
+	"prevent-proxy-connections":         "false",
+	"pvp":                               "true",
+	"query.port":                        "25565",
+	"rate-limit":                        "0",
+	"require-resource-pack":             "false",
+	"resource-pack":                     "",
+	"resource-pack-prompt":              "",
+	"resource-pack-sha1":                "",
+	"server-ip":                         "",
+	"simulation-distance":               "10",
+	"spawn-monsters":                    "true",
+	"spawn-protection":                  "16",
+	"sync-chunk-writes":                 "true",
+	"text-filtering-config":             "",
+	"use-native-transport":              "true",
+	"white-list":                        "false",
+}
+
+// Генерирует полный server.properties: значения по умолчанию, поверх них
+// пользовательские ключи из Properties, поверх них поля конфигурации.
+// RCON включается всегда, пароль генерируется, если ещё не задан
+func (c *Minecraft) RenderProperties() ([]byte, error) {
+	if c.RconPassword == "" {
+		pass, err := generateRconPassword()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate rcon password: %w", err)
+		}
+		c.RconPassword = pass
+	}
+
+	props := maps.Clone(minecraftDefaultProperties)
+	maps.Copy(props, c.Properties)
+
+	props[propEnableRcon] = "true"
+	props[propRconPassword] = c.RconPassword
+	props[propRconPort] = strconv.Itoa(int(c.rconPort()))
+	props[propLevelSeed] = c.Seed
+	props[propGamemode] = c.gamemode()
+	props[propMotd] = c.ServerName
+	props[propMaxPlayers] = strconv.FormatUint(uint64(c.maxPlayers()), 10)
+	props[propViewDistance] = strconv.Itoa(c.viewDistance())
+	props[propServerPort] = strconv.Itoa(int(c.serverPort()))
+
+	var buf bytes.Buffer
+	buf.WriteString("#Minecraft server properties\n")
+	for _, k := range slices.Sorted(maps.Keys(props)) {
+		buf.WriteString(escapeProperty(k, true))
+		buf.WriteByte('=')
+		buf.WriteString(escapeProperty(props[k], false))
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c *Minecraft) WriteProperties(dir string) error {
+	data, err := c.RenderProperties()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, MINECRAFT_PROPERTIES_FILE), data, 0600)
+}
+
+// Заполняет поля конфигурации из server.properties. Ключи, которыми сервис не управляет,
+// сохраняются в Properties. Базовые поля, кроме порта, не затрагиваются
+func (c *Minecraft) ParseProperties(data []byte) error {
+	props, err := parseProperties(data)
+	if err != nil {
+		return err
+	}
+
+	c.Properties = make(map[string]string)
+	for k, v := range props {
+		switch k {
+		case propLevelSeed:
+			c.Seed = v
+		case propMotd:
+			c.ServerName = v
+		case propGamemode:
+			c.Gamemode = parseGamemode(v)
+		case propRconPassword:
+			c.RconPassword = v
+		case propEnableRcon:
+			// RCON включён всегда
+		case propRconPort, propServerPort:
+			port, err := strconv.ParseUint(v, 10, 16)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", k, v, err)
+			}
+			if k == propRconPort {
+				c.RconPort = uint16(port)
+			} else {
+				c.Port = uint16(port)
+			}
+		case propMaxPlayers:
+			n, err := strconv.ParseUint(v, 10, 0)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", k, v, err)
+			}
+			c.MaxPlayers = uint(n)
+		case propViewDistance:
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", k, v, err)
+			}
+			c.ViewDistance = n
+		default:
+			c.Properties[k] = v
+		}
+	}
+
+	return nil
+}
+
+func (c *Minecraft) ReadProperties(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, MINECRAFT_PROPERTIES_FILE))
+	if err != nil {
+		return err
+	}
+
+	return c.ParseProperties(data)
+}
+
+func (c *Minecraft) rconPort() uint16 {
+	if c.RconPort == 0 {
+		return MINECRAFT_DEFAULT_RCON_PORT
+	}
+	return c.RconPort
+}
+
+func (c *Minecraft) serverPort() uint16 {
+	if c.Port == 0 {
+		return MINECRAFT_DEFAULT_PORT
+	}
+	return c.Port
+}
+
+func (c *Minecraft) gamemode() string {
+	if c.Gamemode == "" {
+		return GAMEMODE_SURVIVAL
+	}
+	return c.Gamemode
+}
+
+func (c *Minecraft) maxPlayers() uint {
+	if c.MaxPlayers == 0 {
+		return 20
+	}
+	return c.MaxPlayers
+}
+
+func (c *Minecraft) viewDistance() int {
+	if c.ViewDistance == 0 {
+		return 10
+	}
+	return c.ViewDistance
+}
+
+// Старые версии сервера хранят режим игры числом
+func parseGamemode(v string) string {
+	switch v {
+	case "0":
+		return GAMEMODE_SURVIVAL
+	case "1":
+		return GAMEMODE_CREATIVE
+	case "2":
+		return GAMEMODE_ADVENTURE
+	case "3":
+		return GAMEMODE_SPECTATOR
+	default:
+		return v
+	}
+}
+
+func generateRconPassword() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Разбор формата java.util.Properties: комментарии (# и !), разделители =, : и пробел,
+// продолжение строки обратным слешем и escape-последовательности
+func parseProperties(data []byte) (map[string]string, error) {
+	props := make(map[string]string)
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	var logical strings.Builder
+	for s.Scan() {
+		line := strings.TrimLeft(s.Text(), " \t\f")
+		if logical.Len() == 0 && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		// Нечётное количество слешей в конце - строка продолжается на следующей
+		trailing := len(line) - len(strings.TrimRight(line, "\\"))
+		if trailing%2 == 1 {
+			logical.WriteString(line[:len(line)-1])
+			continue
+		}
+
+		logical.WriteString(line)
+		k, v := splitProperty(logical.String())
+		props[unescapeProperty(k)] = unescapeProperty(v)
+		logical.Reset()
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if logical.Len() > 0 {
+		k, v := splitProperty(logical.String())
+		props[unescapeProperty(k)] = unescapeProperty(v)
+	}
+
+	return props, nil
+}
+
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':':
+			return line[:i], strings.TrimLeft(line[i+1:], " \t\f")
+		case ' ', '\t', '\f':
+			rest := strings.TrimLeft(line[i:], " \t\f")
+			if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+				rest = rest[1:]
+			}
+			return line[:i], strings.TrimLeft(rest, " \t\f")
+		}
+	}
+
+	return line, ""
+}
+
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+
+	var (
+		b     strings.Builder
+		units []uint16
+	)
+	flush := func() {
+		if len(units) > 0 {
+			b.WriteString(string(utf16.Decode(units)))
+			units = units[:0]
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			flush()
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n':
+			flush()
+			b.WriteByte('\n')
+		case 'r':
+			flush()
+			b.WriteByte('\r')
+		case 't':
+			flush()
+			b.WriteByte('\t')
+		case 'f':
+			flush()
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 < len(s) {
+				if u, err := strconv.ParseUint(s[i+1:i+5], 16, 16); err == nil {
+					units = append(units, uint16(u))
+					i += 4
+					continue
+				}
+			}
+			flush()
+			b.WriteByte('u')
+		default:
+			flush()
+			b.WriteByte(s[i])
+		}
+	}
+	flush()
+
+	return b.String()
+}
+
+// Экранирование как в java.util.Properties.store: в ключах экранируются все пробелы,
+// в значениях - только ведущие, символы вне ASCII записываются как \uXXXX
+func escapeProperty(s string, isKey bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == ' ' && (isKey || i == 0):
+			b.WriteString("\\ ")
+		case r == '\\':
+			b.WriteString("\\\\")
+		case r == '\n':
+			b.WriteString("\\n")
+		case r == '\r':
+			b.WriteString("\\r")
+		case r == '\t':
+			b.WriteString("\\t")
+		case r == '\f':
+			b.WriteString("\\f")
+		case r == '=' || r == ':' || r == '#' || r == '!':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, u := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, "\\u%04X", u)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,241 @@
+package configuration
+
+import (
+	"maps"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMinecraftPropertiesRoundTrip(t *testing.T) {
+	c := &Minecraft{
+		Seed:         "-4172144997902289642",
+		RconPort:     25576,
+		Gamemode:     GAMEMODE_CREATIVE,
+		ServerName:   "  Привет, мир 🎮 #1: a=b!",
+		MaxPlayers:   8,
+		ViewDistance: 12,
+		RconPassword: "secret",
+		Properties: map[string]string{
+			"difficulty":            "hard",
+			"key with spaces":       "value",
+			"odd=key:#!":            "odd=value:#!",
+			"resource-pack":         `https://example.com/pack.zip?a=1&b=\x`,
+			"text-filtering-config": "line one\nline two\ttabbed\r\f",
+			"initial-enabled-packs": " leading space",
+		},
+	}
+	c.Port = 25566
+
+	data, err := c.RenderProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "#Minecraft server properties\n") {
+		t.Errorf("missing header:\n%s", data)
+	}
+
+	parsed := &Minecraft{}
+	if err := parsed.ParseProperties(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Seed != c.Seed || parsed.RconPort != c.RconPort || parsed.Gamemode != c.Gamemode ||
+		parsed.ServerName != c.ServerName || parsed.MaxPlayers != c.MaxPlayers || parsed.ViewDistance != c.ViewDistance ||
+		parsed.RconPassword != c.RconPassword || parsed.Port != c.Port {
+		t.Errorf("fields changed after round trip:\ngot  %+v\nwant %+v", parsed, c)
+	}
+
+	want := maps.Clone(minecraftDefaultProperties)
+	maps.Copy(want, c.Properties)
+	if !reflect.DeepEqual(parsed.Properties, want) {
+		for k, v := range want {
+			if parsed.Properties[k] != v {
+				t.Errorf("property %q = %q, want %q", k, parsed.Properties[k], v)
+			}
+		}
+		for k := range parsed.Properties {
+			if _, ok := want[k]; !ok {
+				t.Errorf("unexpected property %q", k)
+			}
+		}
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want map[string]string
+	}{
+		{
+			name: "comments",
+			data: "# comment\n! comment\n   # indented comment\n\n\t\na=1\n",
+			want: map[string]string{"a": "1"},
+		},
+		{
+			name: "separators",
+			data: "a=1\nb:2\nc 3\nd  =  4\ne\t:5\nf\ng=\n  h = 8 \n",
+			want: map[string]string{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5", "f": "", "g": "", "h": "8 "},
+		},
+		{
+			name: "escaped key",
+			data: `key\ with\ spaces\=and\:colon = value` + "\n" + `\#not-comment=1`,
+			want: map[string]string{"key with spaces=and:colon": "value", "#not-comment": "1"},
+		},
+		{
+			name: "escaped value",
+			data: `a=\ \ two spaces, tab\t, newline\n, back\\slash, \q` + "\n",
+			want: map[string]string{"a": "  two spaces, tab\t, newline\n, back\\slash, q"},
+		},
+		{
+			name: "unicode",
+			data: `motd=\u041f\u0440\u0438\u0432\u0435\u0442 \u00e9`,
+			want: map[string]string{"motd": "Привет é"},
+		},
+		{
+			name: "surrogate pair",
+			data: `icon=\uD83C\uDFAE and \ud83d\ude00`,
+			want: map[string]string{"icon": "🎮 and 😀"},
+		},
+		{
+			name: "lone surrogate",
+			data: `a=\uD83Cx`,
+			want: map[string]string{"a": "\uFFFDx"},
+		},
+		{
+			name: "invalid unicode escape",
+			data: `a=\u12 \uZZZZ`,
+			want: map[string]string{"a": "u12 uZZZZ"},
+		},
+		{
+			name: "raw utf-8",
+			data: "motd=Привет\n",
+			want: map[string]string{"motd": "Привет"},
+		},
+		{
+			name: "line continuation",
+			data: "long=one, \\\n    two, \\\n\tthree\nnext=1\n",
+			want: map[string]string{"long": "one, two, three", "next": "1"},
+		},
+		{
+			name: "continued key",
+			data: "lo\\\n  ng=value\n",
+			want: map[string]string{"long": "value"},
+		},
+		{
+			name: "continuation is not a comment",
+			data: "a=1\\\n# not a comment\n",
+			want: map[string]string{"a": "1# not a comment"},
+		},
+		{
+			name: "escaped trailing backslash",
+			data: "path=C:\\\\\nnext=1\n",
+			want: map[string]string{"path": `C:\`, "next": "1"},
+		},
+		{
+			name: "continuation at end of file",
+			data: "a=b\\",
+			want: map[string]string{"a": "b"},
+		},
+		{
+			name: "crlf",
+			data: "a=1\r\nb=2\r\n",
+			want: map[string]string{"a": "1", "b": "2"},
+		},
+		{
+			name: "last wins",
+			data: "a=1\na=2\n",
+			want: map[string]string{"a": "2"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseProperties([]byte(tc.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitProperty(t *testing.T) {
+	cases := []struct {
+		line, key, value string
+	}{
+		{"a=b", "a", "b"},
+		{"a:b", "a", "b"},
+		{"a b", "a", "b"},
+		{"a = b", "a", "b"},
+		{"a  :  b = c", "a", "b = c"},
+		{"a==b", "a", "=b"},
+		{"a", "a", ""},
+		{"a=", "a", ""},
+		{`a\=b=c`, `a\=b`, "c"},
+		{`a\ b c`, `a\ b`, "c"},
+		{`a\\=b`, `a\\`, "b"},
+	}
+
+	for _, tc := range cases {
+		key, value := splitProperty(tc.line)
+		if key != tc.key || value != tc.value {
+			t.Errorf("splitProperty(%q) = %q, %q, want %q, %q", tc.line, key, value, tc.key, tc.value)
+		}
+	}
+}
+
+func TestUnescapeProperty(t *testing.T) {
+	cases := map[string]string{
+		"plain":          "plain",
+		`a\ b`:           "a b",
+		`\t\n\r\f`:       "\t\n\r\f",
+		`\\`:             `\`,
+		`trailing\`:      `trailing\`,
+		`\u0041\u0042`:   "AB",
+		`\uD83C\uDFAE!`:  "🎮!",
+		`\uD83C\n\uDFAE`: "\uFFFD\n\uFFFD",
+		`\u004`:          "u004",
+		`\x\=\:\#\!`:     "x=:#!",
+	}
+
+	for in, want := range cases {
+		if got := unescapeProperty(in); got != want {
+			t.Errorf("unescapeProperty(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEscapeProperty(t *testing.T) {
+	cases := []struct {
+		in    string
+		isKey bool
+		want  string
+	}{
+		{"a b c", true, `a\ b\ c`},
+		{" a b", false, `\ a b`},
+		{"  two", false, `\  two`},
+		{"a=b:c#d!e", true, `a\=b\:c\#d\!e`},
+		{"a=b:c#d!e", false, `a\=b\:c\#d\!e`},
+		{`back\slash`, false, `back\\slash`},
+		{"\t\n\r\f", false, `\t\n\r\f`},
+		{"\x01\x7f", false, `\u0001\u007F`},
+		{"Привет", false, `\u041F\u0440\u0438\u0432\u0435\u0442`},
+		{"🎮", false, `\uD83C\uDFAE`},
+	}
+
+	for _, tc := range cases {
+		got := escapeProperty(tc.in, tc.isKey)
+		if got != tc.want {
+			t.Errorf("escapeProperty(%q, %v) = %q, want %q", tc.in, tc.isKey, got, tc.want)
+		}
+
+		// Экранированная строка разбирается обратно в исходную
+		if back := unescapeProperty(got); back != tc.in {
+			t.Errorf("unescapeProperty(%q) = %q, want %q", got, back, tc.in)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package event
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+)
+
+// Типы событий
+const (
+	AGENT_CREATED  = "agent.created"  // агент добавлен
+	AGENT_UPDATED  = "agent.updated"  // изменилась конфигурация агента: метки, расположение, ресурсы
+	AGENT_ONLINE   = "agent.online"   // агент зарегистрировался или снова присылает heartbeat
+	AGENT_DEGRADED = "agent.degraded" // агент пропустил несколько heartbeat
+	AGENT_OFFLINE  = "agent.offline"  // агент давно не выходил на связь
+	AGENT_DELETED  = "agent.deleted"  // агент удалён
+
+	TASK_CREATED        = "task.created"        // задача создана
+	TASK_UPDATED        = "task.updated"        // задача изменена через API без смены статуса
+	TASK_STATUS_CHANGED = "task.status_changed" // задача перешла в другой статус
+	TASK_DELETED        = "task.deleted"        // задача удалена
+
+	CONFIGURATION_CREATED = "configuration.created" // конфигурация создана
+	CONFIGURATION_UPDATED = "configuration.updated" // конфигурация изменена
+	CONFIGURATION_DELETED = "configuration.deleted" // конфигурация удалена
+
+	SERVER_CREATED       = "server.created"       // сервер создан
+	SERVER_UPDATED       = "server.updated"       // изменено желаемое состояние сервера
+	SERVER_STATE_CHANGED = "server.state_changed" // изменилось фактическое состояние сервера
+	SERVER_DELETED       = "server.deleted"       // сервер удалён с агента и забыт
+	SERVER_OOM_KILLED    = "server.oom_killed"    // сервер превысил лимит памяти и был убит
+	SERVER_DISK_FULL     = "server.disk_full"     // сервер исчерпал квоту диска
+)
+
+var Types = []string{
+	AGENT_CREATED, AGENT_UPDATED, AGENT_ONLINE, AGENT_DEGRADED, AGENT_OFFLINE, AGENT_DELETED,
+	TASK_CREATED, TASK_UPDATED, TASK_STATUS_CHANGED, TASK_DELETED,
+	CONFIGURATION_CREATED, CONFIGURATION_UPDATED, CONFIGURATION_DELETED,
+	SERVER_CREATED, SERVER_UPDATED, SERVER_STATE_CHANGED, SERVER_DELETED, SERVER_OOM_KILLED, SERVER_DISK_FULL,
+}
+
+var agentStatusTypes = map[int16]string{
+	agent.STATUS_ONLINE:   AGENT_ONLINE,
+	agent.STATUS_DEGRADED: AGENT_DEGRADED,
+	agent.STATUS_OFFLINE:  AGENT_OFFLINE,
+}
+
+// Событие предметной области
+type Event struct {
+	Id uuid.UUID `json:"id"`
+
+	// Позиция события в журнале, по ней подписчик продолжает чтение.
+	// Назначается при публикации
+	Seq string `json:"seq,omitempty"`
+
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+
+	AgentId         uuid.UUID `json:"agent_id,omitzero"`
+	ServerId        uuid.UUID `json:"server_id,omitzero"`
+	TaskId          uuid.UUID `json:"task_id,omitzero"`
+	ConfigurationId uuid.UUID `json:"configuration_id,omitzero"`
+
+	// Предыдущее и новое состояние для событий о переходах
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+
+	Message string `json:"message,omitempty"`
+}
+
+func New(eventType string, message string) *Event {
+	return &Event{Id: uuid.New(), Type: eventType, Time: time.Now(), Message: message}
+}
+
+// Событие о новом статусе агента, nil для статуса без события
+func AgentStatus(agentId uuid.UUID, status int16) *Event {
+	eventType, ok := agentStatusTypes[status]
+	if !ok {
+		return nil
+	}
+
+	e := New(eventType, "")
+	e.AgentId = agentId
+	return e
+}
+
+// Фильтр подходит к типу целиком или к группе типов: agent подходит к agent.online
+func Matches(filter, eventType string) bool {
+	return filter == eventType || strings.HasPrefix(eventType, filter+".")
+}
+
+// Известен ли тип или группа типов
+func IsKnown(filter string) bool {
+	return slices.ContainsFunc(Types, func(t string) bool {
+		return Matches(filter, t)
+	})
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	STATUS_UNKNOWN   = 0
+	STATUS_HEALTHY   = 1
+	STATUS_UNHEALTHY = 2
+)
+
+// Результат успешной проверки игрового сервера
+type Probe struct {
+	LatencyMs     uint32   `json:"latency_ms"`
+	Version       string   `json:"version,omitempty"`
+	Motd          string   `json:"motd,omitempty"`
+	PlayersOnline uint32   `json:"players_online"`
+	PlayersMax    uint32   `json:"players_max,omitempty"`
+	Players       []string `json:"players,omitempty"`
+}
+
+// Доступность игрового сервера для игроков
+type Health struct {
+	ServerId  uuid.UUID `json:"server_id"`
+	Status    int16     `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+	ChangedAt time.Time `json:"changed_at"`
+
+	// Неудачные проверки подряд
+	Failures uint32 `json:"failures"`
+	Error    string `json:"error,omitempty"`
+
+	// Последняя успешная проверка
+	Probe *Probe `json:"probe,omitempty"`
+}
+
+// Учитывает результат проверки. Сервер становится unhealthy только после
+// threshold неудач подряд, чтобы одна потерянная проверка не дёргала статус
+func (h *Health) Record(now time.Time, probe *Probe, err error, threshold uint32) {
+	h.CheckedAt = now
+
+	status := h.Status
+	if err == nil {
+		h.Failures = 0
+		h.Error = ""
+		h.Probe = probe
+		status = STATUS_HEALTHY
+	} else {
+		h.Failures++
+		h.Error = err.Error()
+		if h.Failures >= threshold || h.Status == STATUS_UNKNOWN {
+			status = STATUS_UNHEALTHY
+		}
+	}
+
+	h.setStatus(now, status)
+}
+
+// Сервер нельзя проверить, например агент не подключён. Это ничего не говорит
+// о самом сервере, поэтому счётчик неудач не растёт
+func (h *Health) Unknown(now time.Time, reason error) {
+	h.CheckedAt = now
+	h.Error = reason.Error()
+	h.setStatus(now, STATUS_UNKNOWN)
+}
+
+func (h *Health) setStatus(now time.Time, status int16) {
+	if h.Status != status {
+		h.Status = status
+		h.ChangedAt = now
+	}
+}
@@ -1,13 +1,51 @@
 package metrics
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type HostMetrics struct {
 	CpuUsage     float32 `json:"cpu_usage" bson:"cpu_usage"`
 	RamAvailable uint64  `json:"ram_available" bson:"ram_available"`
 	RamTotal     uint64  `json:"ram_total" bson:"ram_total"`
+
+	// Средняя нагрузка за 1, 5 и 15 минут
+	LoadAvg1  float32 `json:"load_avg_1" bson:"load_avg_1"`
+	LoadAvg5  float32 `json:"load_avg_5" bson:"load_avg_5"`
+	LoadAvg15 float32 `json:"load_avg_15" bson:"load_avg_15"`
+
+	// Место на диске в каталоге с данными агента
+	DiskAvailable uint64 `json:"disk_available" bson:"disk_available"`
+	DiskTotal     uint64 `json:"disk_total" bson:"disk_total"`
+
+	// Счётчики сетевых интерфейсов (без loopback) с момента загрузки хоста
+	NetRxBytes uint64 `json:"net_rx_bytes" bson:"net_rx_bytes"`
+	NetTxBytes uint64 `json:"net_tx_bytes" bson:"net_tx_bytes"`
 }
 
 func (m HostMetrics) String() string {
 	return fmt.Sprintf("%f, %d / %d", m.CpuUsage, m.RamAvailable, m.RamTotal)
 }
+
+// Потребление ресурсов игровым сервером по данным среды запуска агента
+type ServerUsage struct {
+	ServerId uuid.UUID `json:"server_id" bson:"server_id"`
+	Running  bool      `json:"running" bson:"running"`
+
+	// Загрузка CPU в процентах от одного ядра
+	CpuUsage    float32 `json:"cpu_usage" bson:"cpu_usage"`
+	MemoryUsage uint64  `json:"memory_usage" bson:"memory_usage"`
+	MemoryLimit uint64  `json:"memory_limit" bson:"memory_limit"`
+	DiskUsage   uint64  `json:"disk_usage" bson:"disk_usage"`
+	NetRxBytes  uint64  `json:"net_rx_bytes" bson:"net_rx_bytes"`
+	NetTxBytes  uint64  `json:"net_tx_bytes" bson:"net_tx_bytes"`
+
+	// Когда сервер последний раз был убит OOM killer
+	OOMKilledAt time.Time `json:"oom_killed_at,omitempty" bson:"oom_killed_at,omitempty"`
+
+	// Ревизия развёрнутой конфигурации
+	Revision string `json:"revision,omitempty" bson:"revision,omitempty"`
+}
@@ -0,0 +1,38 @@
+package placement
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Почему агент не подошёл для сервера
+type Rejection struct {
+	AgentId uuid.UUID `json:"agent_id"`
+	Reasons []string  `json:"reasons"`
+}
+
+// Решение планировщика. Если подходящих агентов нет, AgentId пуст,
+// а Rejected объясняет, чем не подошёл каждый агент
+type Decision struct {
+	AgentId  uuid.UUID   `json:"agent_id"`
+	Reason   string      `json:"reason,omitempty"`
+	Rejected []Rejection `json:"rejected,omitempty"`
+}
+
+func (d Decision) String() string {
+	if d.AgentId != uuid.Nil {
+		return fmt.Sprintf("agent %s: %s", d.AgentId, d.Reason)
+	}
+
+	if len(d.Rejected) == 0 {
+		return "no agents"
+	}
+
+	parts := make([]string, len(d.Rejected))
+	for i, r := range d.Rejected {
+		parts[i] = fmt.Sprintf("agent %s: %s", r.AgentId, strings.Join(r.Reasons, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Желаемое состояние сервера, задаётся пользователем
+const (
+	DESIRED_RUNNING = "running"
+	DESIRED_STOPPED = "stopped"
+	DESIRED_DELETED = "deleted"
+)
+
+// Состояние сервера по последнему отчёту агента
+const (
+	STATE_UNKNOWN = "unknown" // агент не на связи
+	STATE_ABSENT  = "absent"  // контейнера на агенте нет
+	STATE_RUNNING = "running"
+	STATE_STOPPED = "stopped"
+)
+
+var desiredStates = []string{DESIRED_RUNNING, DESIRED_STOPPED, DESIRED_DELETED}
+
+// Игровой сервер: конфигурация, развёрнутая на агенте. Пользователь задаёт
+// желаемое состояние, агент сообщает фактическое, а цикл согласования
+// создаёт задачи, пока они не совпадут
+type Server struct {
+	// Совпадает с id конфигурации: у конфигурации не больше одного сервера
+	Id uuid.UUID `json:"id" bson:"id"`
+
+	// Агент, на котором сервер развёрнут или будет развёрнут
+	AgentId uuid.UUID `json:"agent_id" bson:"agent_id"`
+
+	// Тип игры из конфигурации, по нему агент выбирает обработчик задач
+	Type string `json:"type" bson:"type"`
+
+	// running, stopped или deleted
+	DesiredState string `json:"desired_state" bson:"desired_state"`
+
+	// unknown, absent, running или stopped
+	ObservedState string `json:"observed_state" bson:"observed_state"`
+
+	// Ревизия развёрнутой конфигурации по отчёту агента и ревизия,
+	// которую требует текущая конфигурация
+	ObservedRevision string `json:"observed_revision,omitempty" bson:"observed_revision,omitempty"`
+	DesiredRevision  string `json:"desired_revision,omitempty" bson:"desired_revision,omitempty"`
+
+	// Время последнего отчёта агента, по которому определено состояние
+	ObservedAt time.Time `json:"observed_at" bson:"observed_at"`
+
+	// Последняя задача, созданная для согласования
+	TaskId uuid.UUID `json:"task_id" bson:"task_id"`
+
+	// Ошибка последней неудачной задачи
+	Error string `json:"error,omitempty" bson:"error,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+func (s Server) String() string {
+	return fmt.Sprintf("%q, %s -> %s", s.Id, s.ObservedState, s.DesiredState)
+}
+
+func (s Server) GetId() uuid.UUID {
+	return s.Id
+}
+
+func (s Server) Validate() error {
+	if s.Id == uuid.Nil {
+		return fmt.Errorf("id is required")
+	}
+
+	if !slices.Contains(desiredStates, s.DesiredState) {
+		return fmt.Errorf("unknown desired state %q", s.DesiredState)
+	}
+
+	return nil
+}
+
+// Фактическое состояние совпадает с желаемым
+func (s Server) IsConverged() bool {
+	switch s.DesiredState {
+	case DESIRED_RUNNING:
+		return s.ObservedState == STATE_RUNNING && s.ObservedRevision == s.DesiredRevision
+	case DESIRED_STOPPED:
+		return s.ObservedState == STATE_STOPPED || s.ObservedState == STATE_ABSENT
+	case DESIRED_DELETED:
+		return s.ObservedState == STATE_ABSENT
+	default:
+		return false
+	}
+}
@@ -0,0 +1,114 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// Допустимые переходы между статусами. Удалённая задача - конечное состояние
+var transitions = map[int16][]int16{
+	STATUS_QUEUED:      {STATUS_IN_PROGRESS, STATUS_CANCELLED, STATUS_DELETED, STATUS_TIMED_OUT},
+	STATUS_IN_PROGRESS: {STATUS_QUEUED, STATUS_OK, STATUS_FAILED, STATUS_CANCELLED, STATUS_TIMED_OUT},
+	STATUS_OK:          {STATUS_DELETED},
+	STATUS_FAILED:      {STATUS_QUEUED, STATUS_DELETED},
+	STATUS_CANCELLED:   {STATUS_DELETED},
+	STATUS_TIMED_OUT:   {STATUS_QUEUED, STATUS_DELETED},
+}
+
+var statusNames = map[int16]string{
+	STATUS_QUEUED:      "queued",
+	STATUS_IN_PROGRESS: "in_progress",
+	STATUS_OK:          "ok",
+	STATUS_DELETED:     "deleted",
+	STATUS_FAILED:      "failed",
+	STATUS_CANCELLED:   "cancelled",
+	STATUS_TIMED_OUT:   "timed_out",
+}
+
+// Запись о смене статуса задачи
+type Transition struct {
+	From int16 `json:"from" bson:"from"`
+	To   int16 `json:"to" bson:"to"`
+
+	// Время перехода
+	At time.Time `json:"at" bson:"at"`
+
+	// Кто изменил статус: имя пользователя, agent:<id> или system
+	Actor string `json:"actor" bson:"actor"`
+}
+
+func StatusName(status int16) string {
+	if name, ok := statusNames[status]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", status)
+}
+
+// Можно ли перевести задачу из статуса from в статус to
+func CanTransition(from, to int16) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// Задача в конечном статусе и больше не будет выполняться
+func IsFinal(status int16) bool {
+	switch status {
+	case STATUS_OK, STATUS_DELETED, STATUS_FAILED, STATUS_CANCELLED, STATUS_TIMED_OUT:
+		return true
+	default:
+		return false
+	}
+}
+
+// Переводит задачу в статус to, записывает переход в историю и проставляет
+// время начала и окончания выполнения. Переход в текущий статус ничего не меняет
+func (t *Task) Transition(to int16, actor string, now time.Time) error {
+	if t.Status == to {
+		return nil
+	}
+
+	if !CanTransition(t.Status, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, StatusName(t.Status), StatusName(to))
+	}
+
+	t.Transitions = append(t.Transitions, Transition{From: t.Status, To: to, At: now, Actor: actor})
+	t.Status = to
+
+	switch {
+	case to == STATUS_QUEUED:
+		t.StartedAt = time.Time{}
+		t.FinishedAt = time.Time{}
+	case to == STATUS_IN_PROGRESS:
+		if t.StartedAt.IsZero() {
+			t.StartedAt = now
+		}
+	case IsFinal(to):
+		if t.FinishedAt.IsZero() {
+			t.FinishedAt = now
+		}
+	}
+
+	return nil
+}
+
+// Переносит в задачу служебные поля текущей версии (историю, аренду, время,
+// присланные агентом результат и ошибку) и переводит её из текущего статуса в запрошенный
+func (t *Task) MergeFrom(current *Task, actor string, now time.Time) error {
+	to := t.Status
+
+	t.Status = current.Status
+	t.Transitions = current.Transitions
+	t.LeasedBy = current.LeasedBy
+	t.LeaseExpiresAt = current.LeaseExpiresAt
+	t.Attempts = current.Attempts
+	t.CreatedAt = current.CreatedAt
+	t.StartedAt = current.StartedAt
+	t.FinishedAt = current.FinishedAt
+	t.Result = current.Result
+	t.Error = current.Error
+
+	return t.Transition(to, actor, now)
+}
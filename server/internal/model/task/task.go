@@ -1,7 +1,10 @@
 package task
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,8 +14,29 @@ const (
 	STATUS_IN_PROGRESS = 1
 	STATUS_OK          = 2
 	STATUS_DELETED     = 3
+	STATUS_FAILED      = 4
+	STATUS_CANCELLED   = 5
+	STATUS_TIMED_OUT   = 6
 )
 
+// Приоритет выдачи задач агентам
+const (
+	PRIORITY_LOW    = -1
+	PRIORITY_NORMAL = 0
+	PRIORITY_HIGH   = 1
+)
+
+const (
+	ACTION_DEPLOY  = "deploy"
+	ACTION_START   = "start"
+	ACTION_STOP    = "stop"
+	ACTION_RESTART = "restart"
+	ACTION_BACKUP  = "backup"
+	ACTION_DELETE  = "delete"
+)
+
+var actions = []string{ACTION_DEPLOY, ACTION_START, ACTION_STOP, ACTION_RESTART, ACTION_BACKUP, ACTION_DELETE}
+
 type Task struct {
 	// ID задачи
 	Id uuid.UUID `json:"id" bson:"id"`
@@ -22,10 +46,47 @@ type Task struct {
 
 	// Тип задачи
 	Type string `json:"type" bson:"type"`
+
+	// Приоритет: -1 низкий, 0 обычный, 1 высокий
+	Priority int16 `json:"priority" bson:"priority"`
+
+	// Действие над игровым сервером: deploy, start, stop, restart, backup, delete
+	Action string `json:"action" bson:"action"`
+
+	// ID агента, который должен выполнить задачу. Пустой - любой агент
+	AgentId uuid.UUID `json:"agent_id" bson:"agent_id"`
+
+	// ID конфигурации, к которой относится задача
+	ConfigurationId uuid.UUID `json:"configuration_id" bson:"configuration_id"`
+
+	// Произвольные параметры задачи в JSON
+	Payload json.RawMessage `json:"payload,omitempty" bson:"payload,omitempty" swaggertype:"object"`
+
+	// Результат выполнения в JSON, присылается агентом
+	Result json.RawMessage `json:"result,omitempty" bson:"result,omitempty" swaggertype:"object"`
+
+	// Время создания, начала и окончания выполнения
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	StartedAt  time.Time `json:"started_at" bson:"started_at"`
+	FinishedAt time.Time `json:"finished_at" bson:"finished_at"`
+
+	// Сколько раз задача выдавалась агентам
+	Attempts int `json:"attempts" bson:"attempts"`
+
+	// Текст ошибки, если задача завершилась неудачно
+	Error string `json:"error,omitempty" bson:"error,omitempty"`
+
+	// История смены статусов
+	Transitions []Transition `json:"transitions,omitempty" bson:"transitions,omitempty"`
+
+	// Агент, взявший задачу в работу, и время окончания аренды.
+	// Пока аренда не истекла, задача не выдаётся другим агентам
+	LeasedBy       uuid.UUID `json:"leased_by" bson:"leased_by"`
+	LeaseExpiresAt time.Time `json:"lease_expires_at" bson:"lease_expires_at"`
 }
 
 func (t Task) String() string {
-	return fmt.Sprintf("%q, %d, %q", t.Id, t.Status, t.Type)
+	return fmt.Sprintf("%q, %d, %q, %q", t.Id, t.Status, t.Type, t.Action)
 }
 
 func (t Task) GetId() uuid.UUID {
@@ -41,5 +102,40 @@ func (t Task) Validate() error {
 		return fmt.Errorf("type is required")
 	}
 
+	if t.Priority < PRIORITY_LOW || t.Priority > PRIORITY_HIGH {
+		return fmt.Errorf("priority must be between %d and %d", PRIORITY_LOW, PRIORITY_HIGH)
+	}
+
+	if _, ok := statusNames[t.Status]; !ok {
+		return fmt.Errorf("unknown status %d", t.Status)
+	}
+
+	if t.Action != "" && !slices.Contains(actions, t.Action) {
+		return fmt.Errorf("unknown action %q", t.Action)
+	}
+
+	if len(t.Payload) > 0 && !json.Valid(t.Payload) {
+		return fmt.Errorf("payload must be valid json")
+	}
+
 	return nil
 }
+
+// Задача ещё не завершена
+func (t Task) IsActive() bool {
+	return t.Status == STATUS_QUEUED || t.Status == STATUS_IN_PROGRESS
+}
+
+// Задача арендована агентом и аренда ещё не истекла
+func (t Task) IsLeased(now time.Time) bool {
+	return t.LeasedBy != uuid.Nil && now.Before(t.LeaseExpiresAt)
+}
+
+// Задача может быть выдана агенту agentId
+func (t Task) IsAvailableFor(agentId uuid.UUID, now time.Time) bool {
+	if t.Status != STATUS_QUEUED || t.IsLeased(now) {
+		return false
+	}
+
+	return t.AgentId == uuid.Nil || t.AgentId == agentId
+}
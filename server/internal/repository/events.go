@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+)
+
+// Примерно столько последних событий хранится в потоке
+const maxEvents = 10000
+
+// Журнал событий в потоке Redis. Позиция события - его id в потоке,
+// группы потребителей Redis помнят, что уже обработал каждый потребитель
+type RedisEvents struct {
+	rc  *redis.Client
+	key string
+}
+
+func NewRedisEvents(rc *redis.Client, key string) (*RedisEvents, error) {
+	if key == "" {
+		return nil, fmt.Errorf("redis key is required")
+	}
+
+	return &RedisEvents{rc: rc, key: key}, nil
+}
+
+// Добавляет событие в поток и записывает в него назначенную позицию
+func (r *RedisEvents) Publish(e *event.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	seq, err := r.rc.XAdd(context.Background(), &redis.XAddArgs{
+		Stream: r.key,
+		MaxLen: maxEvents,
+		Approx: true,
+		Values: map[string]any{"type": e.Type, "data": data},
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	e.Seq = seq
+	return nil
+}
+
+// Позиция последнего события, "0-0" для пустого журнала
+func (r *RedisEvents) Last(ctx context.Context) (string, error) {
+	msgs, err := r.rc.XRevRangeN(ctx, r.key, "+", "-", 1).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(msgs) == 0 {
+		return "0-0", nil
+	}
+	return msgs[0].ID, nil
+}
+
+// Позиция самого старого хранимого события, если из журнала уже вытеснялись
+// события. Пустая, если журнал хранит всё опубликованное
+func (r *RedisEvents) Oldest(ctx context.Context) (string, error) {
+	info, err := r.rc.XInfoStream(ctx, r.key).Result()
+	if err != nil && strings.Contains(err.Error(), "no such key") {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if info.EntriesAdded <= info.Length {
+		return "", nil
+	}
+	return info.FirstEntry.ID, nil
+}
+
+// До count событий после позиции after без ожидания новых
+func (r *RedisEvents) Range(ctx context.Context, after string, count int64) ([]*event.Event, error) {
+	msgs, err := r.rc.XRangeN(ctx, r.key, "("+after, "+", count).Result()
+	if err != nil {
+		return nil, err
+	}
+	return decodeEvents(msgs)
+}
+
+// До count событий после позиции after. Если их нет, ждёт новые не дольше block
+func (r *RedisEvents) Read(ctx context.Context, after string, count int64, block time.Duration) ([]*event.Event, error) {
+	streams, err := r.rc.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{r.key, after},
+		Count:   count,
+		Block:   block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeStreams(streams)
+}
+
+// Создаёт группу потребителей, которая начнёт с новых событий. Существующая группа не меняется
+func (r *RedisEvents) CreateGroup(ctx context.Context, group string) error {
+	err := r.rc.XGroupCreateMkStream(ctx, r.key, group, "$").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// Читает события группы. С pending возвращает события, выданные потребителю,
+// но не подтверждённые им, иначе - ещё никому не выданные, ожидая их не дольше block.
+// У событий, вытесненных из журнала до подтверждения, заполнена только позиция
+func (r *RedisEvents) ReadGroup(ctx context.Context, group, consumer string, pending bool, count int64, block time.Duration) ([]*event.Event, error) {
+	args := &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{r.key, ">"},
+		Count:    count,
+		Block:    block,
+	}
+	if pending {
+		args.Streams[1] = "0"
+		args.Block = -1
+	}
+
+	streams, err := r.rc.XReadGroup(ctx, args).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeStreams(streams)
+}
+
+// Подтверждает обработку событий потребителем группы
+func (r *RedisEvents) Ack(ctx context.Context, group string, seqs ...string) error {
+	return r.rc.XAck(ctx, r.key, group, seqs...).Err()
+}
+
+func decodeStreams(streams []redis.XStream) ([]*event.Event, error) {
+	var events []*event.Event
+	for _, s := range streams {
+		decoded, err := decodeEvents(s.Messages)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, decoded...)
+	}
+	return events, nil
+}
+
+func decodeEvents(msgs []redis.XMessage) ([]*event.Event, error) {
+	events := make([]*event.Event, 0, len(msgs))
+	for _, msg := range msgs {
+		e := &event.Event{}
+		if data, ok := msg.Values["data"].(string); ok {
+			if err := json.Unmarshal([]byte(data), e); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal event %s: %w", msg.ID, err)
+			}
+		}
+
+		e.Seq = msg.ID
+		events = append(events, e)
+	}
+	return events, nil
+}
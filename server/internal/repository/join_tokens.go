@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+)
+
+// Хранилище join-токенов агентов. Токен живёт в Redis до истечения срока
+// и удаляется при первом использовании
+type RedisJoinTokens struct {
+	rc     *redis.Client
+	prefix string
+}
+
+func NewRedisJoinTokens(rc *redis.Client, prefix string) (*RedisJoinTokens, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("redis prefix is required")
+	}
+
+	return &RedisJoinTokens{rc: rc, prefix: prefix}, nil
+}
+
+func (r *RedisJoinTokens) Add(hash string, t *agent.JoinToken) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to marshal join token: %w", err)
+	}
+
+	ttl := time.Until(t.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("join token is already expired")
+	}
+
+	return r.rc.Set(context.Background(), r.key(hash), data, ttl).Err()
+}
+
+// Забирает токен. Повторный вызов с тем же хешем вернёт ErrNotFound
+func (r *RedisJoinTokens) Take(hash string) (*agent.JoinToken, error) {
+	data, err := r.rc.GetDel(context.Background(), r.key(hash)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	var t agent.JoinToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal join token: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *RedisJoinTokens) key(hash string) string {
+	return r.prefix + ":" + hash
+}
@@ -58,6 +58,19 @@ func (r *JsonRepository[T]) GetAll() ([]T, error) {
 	return res, nil
 }
 
+// Страница объектов по запросу с той же семантикой, что и у NosqlRepository
+func (r *JsonRepository[T]) List(s *Schema[T], q ListQuery) (*Page[T], error) {
+	p, err := s.plan(q)
+	if err != nil {
+		return nil, err
+	}
+
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	return p.apply(r.items)
+}
+
 func (r *JsonRepository[T]) Add(item T) error {
 	r.m.Lock()
 	defer r.m.Unlock()
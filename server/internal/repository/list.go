@@ -0,0 +1,473 @@
+package repository
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	DEFAULT_LIST_LIMIT = 50
+	MAX_LIST_LIMIT     = 500
+)
+
+var ErrInvalidQuery = errors.New("invalid list query")
+
+// Тип значения поля, по нему разбираются значения фильтров и курсора
+type FieldKind int
+
+const (
+	FIELD_STRING FieldKind = iota
+	FIELD_INT
+	FIELD_TIME
+	FIELD_UUID
+)
+
+// Операция фильтра
+type Op string
+
+const (
+	OP_IN  Op = "in"  // значение поля совпадает с одним из значений
+	OP_GTE Op = "gte" // значение поля не меньше значения
+	OP_LT  Op = "lt"  // значение поля меньше значения
+)
+
+// Условие на поле. Значения - значения нужного полю типа или строки,
+// которые разбираются по типу поля
+type Condition struct {
+	Field  string
+	Op     Op
+	Values []any
+}
+
+func In(field string, values ...any) Condition {
+	return Condition{Field: field, Op: OP_IN, Values: values}
+}
+
+func Gte(field string, value any) Condition {
+	return Condition{Field: field, Op: OP_GTE, Values: []any{value}}
+}
+
+func Lt(field string, value any) Condition {
+	return Condition{Field: field, Op: OP_LT, Values: []any{value}}
+}
+
+// Запрос страницы списка. Условия объединяются через И. Следующая страница
+// запрашивается с курсором из предыдущей и с той же сортировкой
+type ListQuery struct {
+	Conditions []Condition
+	Sort       string // поле сортировки, пустое - сортировка схемы по умолчанию
+	Desc       bool
+	Limit      int // 0 - DEFAULT_LIST_LIMIT, больше MAX_LIST_LIMIT не отдаётся
+	Cursor     string
+}
+
+// Страница списка. Пустой курсор - страница последняя
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// Поле, по которому можно фильтровать список
+type Field[T any] struct {
+	Name string // имя в API
+	Path string // путь в документе Mongo
+	Kind FieldKind
+
+	// Значение поля объекта: string, int64, time.Time или uuid.UUID по Kind
+	Value func(item T) any
+
+	// Поле есть во всех документах, поэтому по нему можно сортировать
+	Sortable bool
+}
+
+// Описание списка: поля для фильтров и сортировки, сортировка по умолчанию
+// и индексы Mongo под частые запросы. При равных значениях поля сортировки
+// объекты упорядочиваются по id, поэтому порядок страниц устойчив
+type Schema[T uniqueObject] struct {
+	IdPath      string
+	Fields      []Field[T]
+	DefaultSort string
+	DefaultDesc bool
+
+	// Наборы полей для составных индексов, id добавляется в конец каждого
+	Indexes [][]string
+}
+
+func (s *Schema[T]) field(name string) (*Field[T], bool) {
+	for i := range s.Fields {
+		if s.Fields[i].Name == name {
+			return &s.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// Имена полей, по которым можно сортировать
+func (s *Schema[T]) SortFields() []string {
+	var names []string
+	for _, f := range s.Fields {
+		if f.Sortable {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+// Разобранный и проверенный запрос
+type listPlan[T uniqueObject] struct {
+	conditions []planCondition[T]
+	sort       *Field[T]
+	desc       bool
+	limit      int
+	after      *listCursor
+}
+
+type planCondition[T uniqueObject] struct {
+	field  *Field[T]
+	op     Op
+	values []any
+}
+
+type listCursor struct {
+	Sort  string          `json:"s"`
+	Desc  bool            `json:"d,omitempty"`
+	Value json.RawMessage `json:"v"`
+	Id    uuid.UUID       `json:"id"`
+
+	value any
+}
+
+func (s *Schema[T]) plan(q ListQuery) (*listPlan[T], error) {
+	p := &listPlan[T]{desc: q.Desc, limit: q.Limit}
+
+	for _, c := range q.Conditions {
+		f, ok := s.field(c.Field)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidQuery, c.Field)
+		}
+
+		switch c.Op {
+		case OP_IN:
+			if len(c.Values) == 0 {
+				return nil, fmt.Errorf("%w: %s: no values", ErrInvalidQuery, c.Field)
+			}
+		case OP_GTE, OP_LT:
+			if len(c.Values) != 1 {
+				return nil, fmt.Errorf("%w: %s: %s needs one value", ErrInvalidQuery, c.Field, c.Op)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidQuery, c.Op)
+		}
+
+		values := make([]any, len(c.Values))
+		for i, v := range c.Values {
+			nv, err := normalize(f.Kind, v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidQuery, c.Field, err)
+			}
+			values[i] = nv
+		}
+		p.conditions = append(p.conditions, planCondition[T]{field: f, op: c.Op, values: values})
+	}
+
+	sort := q.Sort
+	if sort == "" {
+		sort = s.DefaultSort
+		p.desc = s.DefaultDesc
+	}
+	f, ok := s.field(sort)
+	if !ok || !f.Sortable {
+		return nil, fmt.Errorf("%w: can not sort by %q", ErrInvalidQuery, sort)
+	}
+	p.sort = f
+
+	switch {
+	case p.limit < 0:
+		return nil, fmt.Errorf("%w: limit must not be negative", ErrInvalidQuery)
+	case p.limit == 0:
+		p.limit = DEFAULT_LIST_LIMIT
+	case p.limit > MAX_LIST_LIMIT:
+		p.limit = MAX_LIST_LIMIT
+	}
+
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != f.Name || c.Desc != p.desc {
+			return nil, fmt.Errorf("%w: cursor was issued for another sort", ErrInvalidQuery)
+		}
+		if c.value, err = decodeValue(f.Kind, c.Value); err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+		}
+		p.after = c
+	}
+
+	return p, nil
+}
+
+func (p *listPlan[T]) matches(item T) bool {
+	for _, c := range p.conditions {
+		v := c.field.Value(item)
+
+		var ok bool
+		switch c.op {
+		case OP_IN:
+			ok = slices.ContainsFunc(c.values, func(cv any) bool {
+				return compareValues(c.field.Kind, v, cv) == 0
+			})
+		case OP_GTE:
+			ok = compareValues(c.field.Kind, v, c.values[0]) >= 0
+		case OP_LT:
+			ok = compareValues(c.field.Kind, v, c.values[0]) < 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Порядок объектов в списке с учётом направления
+func (p *listPlan[T]) compare(a, b T) int {
+	res := compareValues(p.sort.Kind, p.sort.Value(a), p.sort.Value(b))
+	if res == 0 {
+		res = compareValues(FIELD_UUID, a.GetId(), b.GetId())
+	}
+	if p.desc {
+		res = -res
+	}
+	return res
+}
+
+func (p *listPlan[T]) afterCursor(item T) bool {
+	res := compareValues(p.sort.Kind, p.sort.Value(item), p.after.value)
+	if res == 0 {
+		res = compareValues(FIELD_UUID, item.GetId(), p.after.Id)
+	}
+	if p.desc {
+		res = -res
+	}
+	return res > 0
+}
+
+// Страница из объектов, уже отфильтрованных и упорядоченных. В items
+// может быть на один объект больше лимита - по нему видно, что есть следующая страница
+func (p *listPlan[T]) page(items []T) (*Page[T], error) {
+	res := &Page[T]{Items: items}
+	if items == nil {
+		res.Items = []T{}
+	}
+	if len(items) <= p.limit {
+		return res, nil
+	}
+
+	res.Items = items[:p.limit]
+	cursor, err := p.cursor(res.Items[p.limit-1])
+	if err != nil {
+		return nil, err
+	}
+	res.NextCursor = cursor
+	return res, nil
+}
+
+// Список в памяти с той же семантикой, что и запрос к Mongo
+func (p *listPlan[T]) apply(items []T) (*Page[T], error) {
+	var res []T
+	for _, item := range items {
+		if p.matches(item) && (p.after == nil || p.afterCursor(item)) {
+			res = append(res, item)
+		}
+	}
+
+	slices.SortFunc(res, p.compare)
+	if len(res) > p.limit+1 {
+		res = res[:p.limit+1]
+	}
+	return p.page(res)
+}
+
+func (p *listPlan[T]) cursor(last T) (string, error) {
+	value, err := json.Marshal(p.sort.Value(last))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+
+	data, err := json.Marshal(listCursor{Sort: p.sort.Name, Desc: p.desc, Value: value, Id: last.GetId()})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(s string) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	c := &listCursor{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	return c, nil
+}
+
+func decodeValue(kind FieldKind, data json.RawMessage) (any, error) {
+	switch kind {
+	case FIELD_STRING:
+		var s string
+		err := json.Unmarshal(data, &s)
+		return s, err
+	case FIELD_INT:
+		var i int64
+		err := json.Unmarshal(data, &i)
+		return i, err
+	case FIELD_TIME:
+		var t time.Time
+		err := json.Unmarshal(data, &t)
+		return t, err
+	case FIELD_UUID:
+		var id uuid.UUID
+		err := json.Unmarshal(data, &id)
+		return id, err
+	}
+	return nil, fmt.Errorf("unknown field kind %d", kind)
+}
+
+// Фильтр Mongo с условиями и продолжением после курсора
+func (p *listPlan[T]) filter(idPath string) bson.M {
+	var and bson.A
+	for _, c := range p.conditions {
+		switch c.op {
+		case OP_IN:
+			and = append(and, bson.M{c.field.Path: bson.M{"$in": c.values}})
+		case OP_GTE:
+			and = append(and, bson.M{c.field.Path: bson.M{"$gte": c.values[0]}})
+		case OP_LT:
+			and = append(and, bson.M{c.field.Path: bson.M{"$lt": c.values[0]}})
+		}
+	}
+
+	if p.after != nil {
+		op := "$gt"
+		if p.desc {
+			op = "$lt"
+		}
+
+		if p.sort.Path == idPath {
+			and = append(and, bson.M{idPath: bson.M{op: p.after.Id}})
+		} else {
+			and = append(and, bson.M{"$or": bson.A{
+				bson.M{p.sort.Path: bson.M{op: p.after.value}},
+				bson.M{p.sort.Path: p.after.value, idPath: bson.M{op: p.after.Id}},
+			}})
+		}
+	}
+
+	if len(and) == 0 {
+		return bson.M{}
+	}
+	return bson.M{"$and": and}
+}
+
+// Фильтр Mongo по id объекта и условиям на его поля
+func (s *Schema[T]) where(id uuid.UUID, conditions []Condition) (bson.M, error) {
+	p, err := s.plan(ListQuery{Conditions: conditions})
+	if err != nil {
+		return nil, err
+	}
+
+	filter := p.filter(s.IdPath)
+	and, _ := filter["$and"].(bson.A)
+	return bson.M{"$and": append(bson.A{bson.M{s.IdPath: id}}, and...)}, nil
+}
+
+func (p *listPlan[T]) mongoSort(idPath string) bson.D {
+	dir := 1
+	if p.desc {
+		dir = -1
+	}
+
+	if p.sort.Path == idPath {
+		return bson.D{{Key: idPath, Value: dir}}
+	}
+	return bson.D{{Key: p.sort.Path, Value: dir}, {Key: idPath, Value: dir}}
+}
+
+// Приводит значение фильтра к типу поля. Строки разбираются: время в RFC 3339
+func normalize(kind FieldKind, v any) (any, error) {
+	switch kind {
+	case FIELD_STRING:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case FIELD_INT:
+		switch i := v.(type) {
+		case int:
+			return int64(i), nil
+		case int16:
+			return int64(i), nil
+		case int32:
+			return int64(i), nil
+		case int64:
+			return i, nil
+		case string:
+			n, err := strconv.ParseInt(i, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a number", i)
+			}
+			return n, nil
+		}
+	case FIELD_TIME:
+		switch t := v.(type) {
+		case time.Time:
+			return t, nil
+		case string:
+			res, err := time.Parse(time.RFC3339Nano, t)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not an RFC 3339 time", t)
+			}
+			return res, nil
+		}
+	case FIELD_UUID:
+		switch id := v.(type) {
+		case uuid.UUID:
+			return id, nil
+		case string:
+			res, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a uuid", id)
+			}
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected value %v", v)
+}
+
+func compareValues(kind FieldKind, a, b any) int {
+	switch kind {
+	case FIELD_STRING:
+		return cmp.Compare(a.(string), b.(string))
+	case FIELD_INT:
+		return cmp.Compare(a.(int64), b.(int64))
+	case FIELD_TIME:
+		return a.(time.Time).Compare(b.(time.Time))
+	case FIELD_UUID:
+		x, y := a.(uuid.UUID), b.(uuid.UUID)
+		return bytes.Compare(x[:], y[:])
+	}
+	return 0
+}
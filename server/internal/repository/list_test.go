@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/task"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var listEpoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Задачи с id по порядку: i-я создана через i минут, у 3 и 4 одинаковое время создания
+func listTasks() []*task.Task {
+	var tasks []*task.Task
+	for i := range 6 {
+		t := &task.Task{Id: uuid.UUID{15: byte(i)}, Type: "minecraft", CreatedAt: listEpoch.Add(time.Duration(i) * time.Minute)}
+		if i%2 == 1 {
+			t.Status = task.STATUS_OK
+			t.Type = "factorio"
+		}
+		tasks = append(tasks, t)
+	}
+	tasks[4].CreatedAt = tasks[3].CreatedAt
+	return tasks
+}
+
+func ids(tasks []*task.Task) []byte {
+	var res []byte
+	for _, t := range tasks {
+		res = append(res, t.Id[15])
+	}
+	return res
+}
+
+func mustPlan(t *testing.T, q ListQuery) *listPlan[*task.Task] {
+	t.Helper()
+
+	p, err := TaskSchema.plan(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// Проходит все страницы списка и возвращает id задач по порядку
+func allPages(t *testing.T, items []*task.Task, q ListQuery) []byte {
+	t.Helper()
+
+	var res []byte
+	for range len(items) + 1 {
+		page, err := mustPlan(t, q).apply(items)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Items) > q.Limit {
+			t.Fatalf("page of %d items, limit %d", len(page.Items), q.Limit)
+		}
+
+		res = append(res, ids(page.Items)...)
+		if page.NextCursor == "" {
+			return res
+		}
+		q.Cursor = page.NextCursor
+	}
+	t.Fatal("pagination does not end")
+	return nil
+}
+
+func TestSchemaWhere(t *testing.T) {
+	id := uuid.New()
+	cutoff := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	filter, err := AgentSchema.where(id, []Condition{Lt("last_seen", cutoff), In("status", int16(1))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := bson.M{"$and": bson.A{
+		bson.M{"agent_id": id},
+		bson.M{"last_seen": bson.M{"$lt": cutoff}},
+		bson.M{"status": bson.M{"$in": []any{int64(1)}}},
+	}}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("filter = %v, want %v", filter, want)
+	}
+
+	// Без условий - только id
+	filter, err = AgentSchema.where(id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (bson.M{"$and": bson.A{bson.M{"agent_id": id}}}); !reflect.DeepEqual(filter, want) {
+		t.Errorf("filter = %v, want %v", filter, want)
+	}
+
+	if _, err := AgentSchema.where(id, []Condition{In("unknown", "x")}); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery for an unknown field, got %v", err)
+	}
+}
+
+func TestListPlanApply(t *testing.T) {
+	tasks := listTasks()
+
+	tests := []struct {
+		name  string
+		query ListQuery
+		want  []byte
+	}{
+		{"default sort", ListQuery{Limit: 10}, []byte{5, 4, 3, 2, 1, 0}},
+		{"ascending", ListQuery{Sort: "created_at", Limit: 10}, []byte{0, 1, 2, 3, 4, 5}},
+		{"by string", ListQuery{Sort: "type", Limit: 10}, []byte{1, 3, 5, 0, 2, 4}},
+		{"by id descending", ListQuery{Sort: "id", Desc: true, Limit: 10}, []byte{5, 4, 3, 2, 1, 0}},
+		{"status", ListQuery{Conditions: []Condition{In("status", "2")}, Limit: 10}, []byte{5, 3, 1}},
+		{"several values", ListQuery{Conditions: []Condition{In("type", "factorio", "minecraft")}, Limit: 10}, []byte{5, 4, 3, 2, 1, 0}},
+		{"created range", ListQuery{
+			Conditions: []Condition{Gte("created_at", listEpoch.Add(time.Minute)), Lt("created_at", listEpoch.Add(3*time.Minute).Format(time.RFC3339))},
+			Limit:      10,
+		}, []byte{2, 1}},
+		{"nothing matches", ListQuery{Conditions: []Condition{In("type", "terraria")}, Limit: 10}, []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := mustPlan(t, tt.query).apply(tasks)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(page.Items); !slices.Equal(got, tt.want) || page.NextCursor != "" {
+				t.Errorf("got %v with cursor %q, want %v on one page", got, page.NextCursor, tt.want)
+			}
+			if page.Items == nil {
+				t.Error("empty page has nil items")
+			}
+		})
+	}
+}
+
+// Страницы не теряют и не повторяют объекты, в том числе с одинаковым значением сортировки
+func TestListPlanApplyPages(t *testing.T) {
+	tasks := listTasks()
+
+	tests := []struct {
+		name  string
+		query ListQuery
+		want  []byte
+	}{
+		{"default sort", ListQuery{Limit: 2}, []byte{5, 4, 3, 2, 1, 0}},
+		{"ascending", ListQuery{Sort: "created_at", Limit: 4}, []byte{0, 1, 2, 3, 4, 5}},
+		{"by status", ListQuery{Sort: "status", Desc: true, Limit: 1}, []byte{5, 3, 1, 4, 2, 0}},
+		{"filtered", ListQuery{Conditions: []Condition{In("type", "minecraft")}, Limit: 2}, []byte{4, 2, 0}},
+		{"exact limit", ListQuery{Limit: 6}, []byte{5, 4, 3, 2, 1, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allPages(t, tasks, tt.query); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListPlanInvalid(t *testing.T) {
+	p := mustPlan(t, ListQuery{Limit: 2})
+	page, err := p.apply(listTasks())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query ListQuery
+	}{
+		{"unknown field", ListQuery{Conditions: []Condition{In("owner", "x")}}},
+		{"no values", ListQuery{Conditions: []Condition{In("status")}}},
+		{"two values", ListQuery{Conditions: []Condition{{Field: "status", Op: OP_GTE, Values: []any{1, 2}}}}},
+		{"unknown operation", ListQuery{Conditions: []Condition{{Field: "status", Op: "ne", Values: []any{1}}}}},
+		{"not a number", ListQuery{Conditions: []Condition{In("status", "ok")}}},
+		{"not a time", ListQuery{Conditions: []Condition{Gte("created_at", "yesterday")}}},
+		{"not a uuid", ListQuery{Conditions: []Condition{In("agent_id", "agent")}}},
+		{"unknown sort", ListQuery{Sort: "owner"}},
+		{"negative limit", ListQuery{Limit: -1}},
+		{"malformed cursor", ListQuery{Cursor: "not a cursor"}},
+		{"cursor of another sort", ListQuery{Sort: "status", Cursor: page.NextCursor}},
+		{"cursor of another direction", ListQuery{Sort: "created_at", Cursor: page.NextCursor}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := TaskSchema.plan(tt.query); !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("got %v, want ErrInvalidQuery", err)
+			}
+		})
+	}
+}
+
+func TestListPlanLimit(t *testing.T) {
+	if p := mustPlan(t, ListQuery{}); p.limit != DEFAULT_LIST_LIMIT {
+		t.Errorf("default limit %d", p.limit)
+	}
+	if p := mustPlan(t, ListQuery{Limit: MAX_LIST_LIMIT + 1}); p.limit != MAX_LIST_LIMIT {
+		t.Errorf("limit %d, want at most %d", p.limit, MAX_LIST_LIMIT)
+	}
+}
+
+func TestListPlanFilter(t *testing.T) {
+	tasks := listTasks()
+	first := func(q ListQuery) string {
+		page, err := mustPlan(t, q).apply(tasks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return page.NextCursor
+	}
+
+	created := listEpoch.Add(3 * time.Minute)
+	id := uuid.UUID{15: 4}
+
+	tests := []struct {
+		name  string
+		query ListQuery
+		want  bson.M
+	}{
+		{"no conditions", ListQuery{}, bson.M{}},
+		{"conditions", ListQuery{Conditions: []Condition{In("status", 0, "2"), Gte("created_at", listEpoch), Lt("priority", 5)}}, bson.M{"$and": bson.A{
+			bson.M{"status": bson.M{"$in": []any{int64(0), int64(2)}}},
+			bson.M{"created_at": bson.M{"$gte": listEpoch}},
+			bson.M{"priority": bson.M{"$lt": int64(5)}},
+		}}},
+		{"descending cursor", ListQuery{Limit: 2, Cursor: first(ListQuery{Limit: 2})}, bson.M{"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"created_at": bson.M{"$lt": created}},
+				bson.M{"created_at": created, "id": bson.M{"$lt": id}},
+			}},
+		}}},
+		{"ascending cursor with condition", ListQuery{
+			Conditions: []Condition{In("type", "minecraft")},
+			Sort:       "type",
+			Limit:      2,
+			Cursor:     first(ListQuery{Sort: "type", Limit: 4}),
+		}, bson.M{"$and": bson.A{
+			bson.M{"type": bson.M{"$in": []any{"minecraft"}}},
+			bson.M{"$or": bson.A{
+				bson.M{"type": bson.M{"$gt": "minecraft"}},
+				bson.M{"type": "minecraft", "id": bson.M{"$gt": uuid.UUID{15: 0}}},
+			}},
+		}}},
+		{"cursor on id", ListQuery{Sort: "id", Limit: 2, Cursor: first(ListQuery{Sort: "id", Limit: 2})}, bson.M{"$and": bson.A{
+			bson.M{"id": bson.M{"$gt": uuid.UUID{15: 1}}},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustPlan(t, tt.query).filter(TaskSchema.IdPath); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListPlanMongoSort(t *testing.T) {
+	if got, want := mustPlan(t, ListQuery{}).mongoSort("id"), (bson.D{{Key: "created_at", Value: -1}, {Key: "id", Value: -1}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("sort = %v, want %v", got, want)
+	}
+	if got, want := mustPlan(t, ListQuery{Sort: "id"}).mongoSort("id"), (bson.D{{Key: "id", Value: 1}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("sort = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,489 @@
+// Mongo в памяти для тестов репозиториев. Клиент драйвера работает с ним
+// через свой протокол, поэтому проверяются настоящие фильтры и обновления,
+// которые строит репозиторий. Поддерживается только то, что использует
+// сервис: insert, find, update с $set, delete, findAndModify и createIndexes,
+// фильтры по равенству, $in, $lt, $lte, $gt, $gte, $and и $or
+package mongotest
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
+)
+
+// Коллекции в памяти. Ключ - "база.коллекция"
+type Server struct {
+	*drivertest.MockDeployment
+
+	m           sync.Mutex
+	collections map[string][]bson.Raw
+}
+
+func NewServer() *Server {
+	return &Server{MockDeployment: drivertest.NewMockDeployment(), collections: make(map[string][]bson.Raw)}
+}
+
+// Клиент, подключённый к серверу. Отключается по завершении теста
+func (s *Server) Client(t testing.TB) *mongo.Client {
+	t.Helper()
+
+	opts := options.Client()
+	opts.Deployment = s
+	c, err := mongo.Connect(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Disconnect(context.Background()) })
+	return c
+}
+
+// Документы коллекции в порядке добавления
+func (s *Server) Documents(database, collection string) []bson.Raw {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return slices.Clone(s.collections[database+"."+collection])
+}
+
+func (s *Server) SelectServer(context.Context, description.ServerSelector) (driver.Server, error) {
+	return s, nil
+}
+
+func (s *Server) Connection(context.Context) (*mnet.Connection, error) {
+	return mnet.NewConnection(&conn{s: s}), nil
+}
+
+// Подключение отвечает на каждую записанную команду при следующем чтении
+type conn struct {
+	s     *Server
+	reply []byte
+}
+
+func (c *conn) Write(ctx context.Context, wm []byte) error {
+	cmd, err := readCommand(wm)
+	if err != nil {
+		return err
+	}
+
+	reply, err := c.s.run(cmd)
+	if err != nil {
+		reply = bson.D{{Key: "ok", Value: 0}, {Key: "errmsg", Value: err.Error()}, {Key: "code", Value: 115}}
+	}
+
+	doc, err := bson.Marshal(reply)
+	if err != nil {
+		return err
+	}
+
+	idx, dst := wiremessage.AppendHeaderStart(nil, wiremessage.NextRequestID(), 0, wiremessage.OpMsg)
+	dst = wiremessage.AppendMsgFlags(dst, 0)
+	dst = wiremessage.AppendMsgSectionType(dst, wiremessage.SingleDocument)
+	dst = append(dst, doc...)
+	c.reply = bsoncore.UpdateLength(dst, idx, int32(len(dst[idx:])))
+	return nil
+}
+
+func (c *conn) Read(ctx context.Context) ([]byte, error) {
+	if c.reply == nil {
+		return nil, fmt.Errorf("no command to reply to")
+	}
+	reply := c.reply
+	c.reply = nil
+	return reply, nil
+}
+
+func (c *conn) Close() error                    { return nil }
+func (c *conn) Description() description.Server { return drivertest.MockDescription }
+func (c *conn) ID() string                      { return "mongotest" }
+func (c *conn) ServerConnectionID() *int64      { return nil }
+func (c *conn) DriverConnectionID() int64       { return 0 }
+func (c *conn) Address() address.Address        { return drivertest.MockDescription.Addr }
+func (c *conn) Stale() bool                     { return false }
+func (c *conn) OIDCTokenGenID() uint64          { return 0 }
+func (c *conn) SetOIDCTokenGenID(uint64)        {}
+
+// Команда из OP_MSG. Документы из последовательностей (documents, updates,
+// deletes) собираются отдельно от тела команды
+type command struct {
+	body bson.Raw
+	seqs map[string][]bson.Raw
+}
+
+func readCommand(wm []byte) (*command, error) {
+	_, _, _, opcode, rem, ok := wiremessage.ReadHeader(wm)
+	if !ok || opcode != wiremessage.OpMsg {
+		return nil, fmt.Errorf("unexpected wire message %v", opcode)
+	}
+	if _, rem, ok = wiremessage.ReadMsgFlags(rem); !ok {
+		return nil, fmt.Errorf("malformed message flags")
+	}
+
+	cmd := &command{seqs: make(map[string][]bson.Raw)}
+	for len(rem) > 0 {
+		var stype wiremessage.SectionType
+		if stype, rem, ok = wiremessage.ReadMsgSectionType(rem); !ok {
+			return nil, fmt.Errorf("malformed section")
+		}
+
+		switch stype {
+		case wiremessage.SingleDocument:
+			var doc bsoncore.Document
+			if doc, rem, ok = wiremessage.ReadMsgSectionSingleDocument(rem); !ok {
+				return nil, fmt.Errorf("malformed command")
+			}
+			cmd.body = bson.Raw(slices.Clone(doc))
+		case wiremessage.DocumentSequence:
+			var id string
+			var docs []bsoncore.Document
+			if id, docs, rem, ok = wiremessage.ReadMsgSectionDocumentSequence(rem); !ok {
+				return nil, fmt.Errorf("malformed document sequence")
+			}
+			for _, d := range docs {
+				cmd.seqs[id] = append(cmd.seqs[id], bson.Raw(slices.Clone(d)))
+			}
+		default:
+			return nil, fmt.Errorf("unexpected section type %v", stype)
+		}
+	}
+	return cmd, nil
+}
+
+// Документы аргумента команды из последовательности или из тела
+func (c *command) docs(name string) []bson.Raw {
+	if docs, ok := c.seqs[name]; ok {
+		return docs
+	}
+
+	values, _ := c.body.Lookup(name).Array().Values()
+	var docs []bson.Raw
+	for _, v := range values {
+		docs = append(docs, v.Document())
+	}
+	return docs
+}
+
+func (s *Server) run(cmd *command) (bson.D, error) {
+	name := cmd.body.Index(0).Key()
+	collection, _ := cmd.body.Index(0).Value().StringValueOK()
+	ns := cmd.body.Lookup("$db").StringValue() + "." + collection
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	ok := bson.E{Key: "ok", Value: 1}
+	switch name {
+	case "insert":
+		docs := cmd.docs("documents")
+		s.collections[ns] = append(s.collections[ns], docs...)
+		return bson.D{{Key: "n", Value: len(docs)}, ok}, nil
+
+	case "find":
+		docs, err := s.find(ns, cmd.body.Lookup("filter"), cmd.body.Lookup("sort"))
+		if err != nil {
+			return nil, err
+		}
+		if limit, ok := cmd.body.Lookup("limit").AsInt64OK(); ok && limit > 0 && int(limit) < len(docs) {
+			docs = docs[:limit]
+		}
+		cursor := bson.D{{Key: "firstBatch", Value: docs}, {Key: "id", Value: int64(0)}, {Key: "ns", Value: ns}}
+		return bson.D{{Key: "cursor", Value: cursor}, ok}, nil
+
+	case "update":
+		matched, modified := 0, 0
+		for _, u := range cmd.docs("updates") {
+			if upsert, _ := u.Lookup("upsert").BooleanOK(); upsert {
+				return nil, fmt.Errorf("upsert is not supported")
+			}
+			multi, _ := u.Lookup("multi").BooleanOK()
+			for i, doc := range s.collections[ns] {
+				match, err := matches(doc, u.Lookup("q").Document())
+				if err != nil {
+					return nil, err
+				}
+				if !match {
+					continue
+				}
+
+				updated, err := apply(doc, u.Lookup("u").Document())
+				if err != nil {
+					return nil, err
+				}
+				matched++
+				if !bytes.Equal(updated, doc) {
+					modified++
+				}
+				s.collections[ns][i] = updated
+				if !multi {
+					break
+				}
+			}
+		}
+		return bson.D{{Key: "n", Value: matched}, {Key: "nModified", Value: modified}, ok}, nil
+
+	case "delete":
+		deleted := 0
+		for _, d := range cmd.docs("deletes") {
+			limit, _ := d.Lookup("limit").AsInt64OK()
+			docs := s.collections[ns][:0]
+			for _, doc := range s.collections[ns] {
+				match, err := matches(doc, d.Lookup("q").Document())
+				if err != nil {
+					return nil, err
+				}
+				if match && (limit == 0 || deleted < int(limit)) {
+					deleted++
+					continue
+				}
+				docs = append(docs, doc)
+			}
+			s.collections[ns] = docs
+		}
+		return bson.D{{Key: "n", Value: deleted}, ok}, nil
+
+	case "findAndModify":
+		remove, _ := cmd.body.Lookup("remove").BooleanOK()
+		upsert, _ := cmd.body.Lookup("upsert").BooleanOK()
+		if remove || upsert {
+			return nil, fmt.Errorf("only findAndModify with an update is supported")
+		}
+		for i, doc := range s.collections[ns] {
+			match, err := matches(doc, cmd.body.Lookup("query").Document())
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				continue
+			}
+
+			updated, err := apply(doc, cmd.body.Lookup("update").Document())
+			if err != nil {
+				return nil, err
+			}
+			s.collections[ns][i] = updated
+
+			value := doc
+			if returnNew, _ := cmd.body.Lookup("new").BooleanOK(); returnNew {
+				value = updated
+			}
+			last := bson.D{{Key: "n", Value: 1}, {Key: "updatedExisting", Value: true}}
+			return bson.D{{Key: "lastErrorObject", Value: last}, {Key: "value", Value: value}, ok}, nil
+		}
+		last := bson.D{{Key: "n", Value: 0}, {Key: "updatedExisting", Value: false}}
+		return bson.D{{Key: "lastErrorObject", Value: last}, {Key: "value", Value: nil}, ok}, nil
+
+	case "createIndexes", "endSessions", "ping":
+		return bson.D{ok}, nil
+	}
+	return nil, fmt.Errorf("command %s is not supported", name)
+}
+
+func (s *Server) find(ns string, filter, sort bson.RawValue) ([]bson.Raw, error) {
+	docs := []bson.Raw{}
+	for _, doc := range s.collections[ns] {
+		match := true
+		if f, ok := filter.DocumentOK(); ok {
+			var err error
+			if match, err = matches(doc, f); err != nil {
+				return nil, err
+			}
+		}
+		if match {
+			docs = append(docs, doc)
+		}
+	}
+
+	spec, ok := sort.DocumentOK()
+	if !ok {
+		return docs, nil
+	}
+	keys, err := spec.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(docs, func(a, b bson.Raw) int {
+		for _, k := range keys {
+			path := strings.Split(k.Key(), ".")
+			res := compareValues(a.Lookup(path...), b.Lookup(path...))
+			if dir, _ := k.Value().AsInt64OK(); dir < 0 {
+				res = -res
+			}
+			if res != 0 {
+				return res
+			}
+		}
+		return 0
+	})
+	return docs, nil
+}
+
+func matches(doc, filter bson.Raw) (bool, error) {
+	elems, err := filter.Elements()
+	if err != nil {
+		return false, err
+	}
+
+	for _, e := range elems {
+		var ok bool
+		switch key := e.Key(); key {
+		case "$and", "$or":
+			values, err := e.Value().Array().Values()
+			if err != nil {
+				return false, err
+			}
+			ok = key == "$and"
+			for _, v := range values {
+				m, err := matches(doc, v.Document())
+				if err != nil {
+					return false, err
+				}
+				if key == "$and" && !m {
+					ok = false
+					break
+				}
+				if key == "$or" && m {
+					ok = true
+					break
+				}
+			}
+		default:
+			if strings.HasPrefix(key, "$") {
+				return false, fmt.Errorf("operator %s is not supported", key)
+			}
+			if ok, err = matchesField(doc.Lookup(strings.Split(key, ".")...), e.Value()); err != nil {
+				return false, err
+			}
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchesField(v, cond bson.RawValue) (bool, error) {
+	ops, isDoc := cond.DocumentOK()
+	if !isDoc || len(ops) <= 5 || !strings.HasPrefix(ops.Index(0).Key(), "$") {
+		return v.Type != 0 && compareValues(v, cond) == 0, nil
+	}
+
+	elems, err := ops.Elements()
+	if err != nil {
+		return false, err
+	}
+	for _, op := range elems {
+		var ok bool
+		switch op.Key() {
+		case "$in":
+			values, err := op.Value().Array().Values()
+			if err != nil {
+				return false, err
+			}
+			ok = slices.ContainsFunc(values, func(iv bson.RawValue) bool {
+				return v.Type != 0 && compareValues(v, iv) == 0
+			})
+		case "$lt":
+			ok = v.Type != 0 && compareValues(v, op.Value()) < 0
+		case "$lte":
+			ok = v.Type != 0 && compareValues(v, op.Value()) <= 0
+		case "$gt":
+			ok = v.Type != 0 && compareValues(v, op.Value()) > 0
+		case "$gte":
+			ok = v.Type != 0 && compareValues(v, op.Value()) >= 0
+		default:
+			return false, fmt.Errorf("operator %s is not supported", op.Key())
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Сравнивает значения одного типа, числа разных типов сравниваются как числа.
+// Отсутствующее значение меньше любого
+func compareValues(a, b bson.RawValue) int {
+	switch {
+	case a.Type == 0 || b.Type == 0:
+		return cmp.Compare(a.Type, b.Type)
+	case a.IsNumber() && b.IsNumber():
+		return cmp.Compare(number(a), number(b))
+	case a.Type != b.Type:
+		return cmp.Compare(a.Type, b.Type)
+	}
+
+	if x, ok := a.StringValueOK(); ok {
+		return cmp.Compare(x, b.StringValue())
+	}
+	if x, ok := a.DateTimeOK(); ok {
+		return cmp.Compare(x, b.DateTime())
+	}
+	return bytes.Compare(a.Value, b.Value)
+}
+
+func number(v bson.RawValue) float64 {
+	if f, ok := v.DoubleOK(); ok {
+		return f
+	}
+	return float64(v.AsInt64())
+}
+
+// Применяет обновление $set или заменяет документ целиком
+func apply(doc, update bson.Raw) (bson.Raw, error) {
+	if len(update) <= 5 || !strings.HasPrefix(update.Index(0).Key(), "$") {
+		return update, nil
+	}
+
+	elems, err := update.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+	d := bson.D{}
+	for _, f := range fields {
+		d = append(d, bson.E{Key: f.Key(), Value: f.Value()})
+	}
+
+	for _, op := range elems {
+		if op.Key() != "$set" {
+			return nil, fmt.Errorf("update operator %s is not supported", op.Key())
+		}
+
+		set, err := op.Value().Document().Elements()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range set {
+			if strings.Contains(s.Key(), ".") {
+				return nil, fmt.Errorf("nested field %s is not supported", s.Key())
+			}
+			i := slices.IndexFunc(d, func(e bson.E) bool { return e.Key == s.Key() })
+			if i < 0 {
+				d = append(d, bson.E{Key: s.Key(), Value: s.Value()})
+			} else {
+				d[i].Value = s.Value()
+			}
+		}
+	}
+	return bson.Marshal(d)
+}
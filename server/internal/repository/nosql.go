@@ -11,6 +11,7 @@ import (
 	"github.com/vv-sam/otus-project/server/internal/model/history"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type NosqlRepository[T uniqueObject] struct {
@@ -21,12 +22,14 @@ type NosqlRepository[T uniqueObject] struct {
 
 	mongoDatabase   string
 	mongoCollection string
+	idPath          string
 }
 
 type NosqlRepositoryOptions struct {
 	RedisKey        string // Ключ для записи логов истории в Redis
 	MongoDatabase   string // Название базы данных в MongoDB
 	MongoCollection string // Название коллекции в MongoDB
+	IdPath          string // Путь к id объекта в документе, по умолчанию id
 }
 
 func NewNosqlRepository[T uniqueObject](rc *redis.Client, mc *mongo.Client, opts NosqlRepositoryOptions) (*NosqlRepository[T], error) {
@@ -42,12 +45,17 @@ func NewNosqlRepository[T uniqueObject](rc *redis.Client, mc *mongo.Client, opts
 		return nil, fmt.Errorf("redis key is required")
 	}
 
+	if opts.IdPath == "" {
+		opts.IdPath = "id"
+	}
+
 	return &NosqlRepository[T]{
 		rc:              rc,
 		mc:              mc,
 		redisKey:        opts.RedisKey,
 		mongoDatabase:   opts.MongoDatabase,
 		mongoCollection: opts.MongoCollection,
+		idPath:          opts.IdPath,
 	}, nil
 }
 
@@ -55,7 +63,7 @@ func (r *NosqlRepository[T]) Get(id uuid.UUID) (T, error) {
 	db := r.mc.Database(r.mongoDatabase)
 	collection := db.Collection(r.mongoCollection)
 
-	res := collection.FindOne(context.Background(), bson.M{"id": id})
+	res := collection.FindOne(context.Background(), bson.M{r.idPath: id})
 	if err := res.Err(); err != nil {
 		var zero T
 		if err == mongo.ErrNoDocuments {
@@ -88,6 +96,55 @@ func (r *NosqlRepository[T]) GetAll() ([]T, error) {
 	return items, nil
 }
 
+// Страница объектов по запросу. Фильтр, сортировка и лимит выполняются в Mongo
+func (r *NosqlRepository[T]) List(s *Schema[T], q ListQuery) (*Page[T], error) {
+	p, err := s.plan(q)
+	if err != nil {
+		return nil, err
+	}
+
+	db := r.mc.Database(r.mongoDatabase)
+	collection := db.Collection(r.mongoCollection)
+
+	opts := options.Find().SetSort(p.mongoSort(s.IdPath)).SetLimit(int64(p.limit + 1))
+	c, err := collection.Find(context.Background(), p.filter(s.IdPath), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []T
+	if err := c.All(context.Background(), &items); err != nil {
+		return nil, err
+	}
+	return p.page(items)
+}
+
+// Создаёт индексы схемы, уже существующие индексы не меняются
+func (r *NosqlRepository[T]) EnsureIndexes(ctx context.Context, s *Schema[T]) error {
+	models := make([]mongo.IndexModel, 0, len(s.Indexes))
+	for _, fields := range s.Indexes {
+		keys := bson.D{}
+		for _, name := range fields {
+			f, ok := s.field(name)
+			if !ok {
+				return fmt.Errorf("unknown index field %q", name)
+			}
+			keys = append(keys, bson.E{Key: f.Path, Value: 1})
+		}
+		keys = append(keys, bson.E{Key: s.IdPath, Value: 1})
+		models = append(models, mongo.IndexModel{Keys: keys})
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	db := r.mc.Database(r.mongoDatabase)
+	collection := db.Collection(r.mongoCollection)
+
+	_, err := collection.Indexes().CreateMany(ctx, models)
+	return err
+}
+
 func (r *NosqlRepository[T]) Add(item T) error {
 	db := r.mc.Database(r.mongoDatabase)
 	collection := db.Collection(r.mongoCollection)
@@ -119,13 +176,13 @@ func (r *NosqlRepository[T]) Update(id uuid.UUID, item T) error {
 	db := r.mc.Database(r.mongoDatabase)
 	collection := db.Collection(r.mongoCollection)
 
-	_, err := collection.UpdateOne(context.Background(), bson.M{"id": id}, bson.M{"$set": item})
+	res, err := collection.UpdateOne(context.Background(), bson.M{r.idPath: id}, bson.M{"$set": item})
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return ErrNotFound
-		}
 		return err
 	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
 
 	logData, err := json.Marshal(history.Log[T]{
 		Time:   time.Now(),
@@ -145,17 +202,66 @@ func (r *NosqlRepository[T]) Update(id uuid.UUID, item T) error {
 	return nil
 }
 
-func (r *NosqlRepository[T]) Delete(id uuid.UUID) error {
+// Меняет одно поле объекта, если объект удовлетворяет условиям. false - объекта
+// нет или условия не выполнились. Остальные поля документа не перезаписываются,
+// поэтому параллельное изменение объекта целиком не теряется
+func (r *NosqlRepository[T]) UpdateFieldIf(s *Schema[T], id uuid.UUID, field string, value any, where ...Condition) (bool, error) {
+	f, ok := s.field(field)
+	if !ok {
+		return false, fmt.Errorf("%w: unknown field %q", ErrInvalidQuery, field)
+	}
+
+	filter, err := s.where(id, where)
+	if err != nil {
+		return false, err
+	}
+
 	db := r.mc.Database(r.mongoDatabase)
 	collection := db.Collection(r.mongoCollection)
 
-	_, err := collection.DeleteOne(context.Background(), bson.M{"id": id})
-	if err != nil {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	res := collection.FindOneAndUpdate(context.Background(), filter, bson.M{"$set": bson.M{f.Path: value}}, opts)
+	if err := res.Err(); err != nil {
 		if err == mongo.ErrNoDocuments {
-			return ErrNotFound
+			return false, nil
 		}
+		return false, err
+	}
+
+	var item T
+	if err := res.Decode(&item); err != nil {
+		return false, err
+	}
+
+	logData, err := json.Marshal(history.Log[T]{
+		Time:   time.Now(),
+		Action: "update",
+		Id:     id,
+		Data:   item,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal log: %w", err)
+	}
+
+	cmd := r.rc.RPush(context.Background(), r.redisKey, logData)
+	if _, err := cmd.Result(); err != nil {
+		return false, fmt.Errorf("failed to push to redis: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *NosqlRepository[T]) Delete(id uuid.UUID) error {
+	db := r.mc.Database(r.mongoDatabase)
+	collection := db.Collection(r.mongoCollection)
+
+	res, err := collection.DeleteOne(context.Background(), bson.M{r.idPath: id})
+	if err != nil {
 		return err
 	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
 
 	logData, err := json.Marshal(history.Log[T]{
 		Time:   time.Now(),
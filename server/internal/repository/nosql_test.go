@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/task"
+	"github.com/vv-sam/otus-project/server/internal/repository/mongotest"
+)
+
+func newTestNosql[T uniqueObject](t *testing.T, ms *mongotest.Server, collection, idPath string) *NosqlRepository[T] {
+	t.Helper()
+
+	rc := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	r, err := NewNosqlRepository[T](rc, ms.Client(t), NosqlRepositoryOptions{
+		RedisKey:        collection,
+		MongoDatabase:   "test",
+		MongoCollection: collection,
+		IdPath:          idPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// Агенты хранят id в agent_id, а не в id
+func TestNosqlRepositoryIdPath(t *testing.T) {
+	ms := mongotest.NewServer()
+	r := newTestNosql[*agent.Info](t, ms, "agents", AgentSchema.IdPath)
+
+	a := &agent.Info{AgentId: uuid.New(), Status: agent.STATUS_ONLINE}
+	other := &agent.Info{AgentId: uuid.New(), Status: agent.STATUS_ONLINE}
+	for _, x := range []*agent.Info{a, other} {
+		if err := r.Add(x); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := r.Get(a.AgentId)
+	if err != nil || got.AgentId != a.AgentId {
+		t.Fatalf("get: %v, %v", got, err)
+	}
+
+	a.Config.Location = "eu"
+	if err := r.Update(a.AgentId, a); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := r.Get(a.AgentId); got.Config.Location != "eu" {
+		t.Errorf("location %q after update", got.Config.Location)
+	}
+	if got, _ := r.Get(other.AgentId); got.Config.Location != "" {
+		t.Errorf("update changed another agent: %+v", got)
+	}
+
+	ok, err := r.UpdateFieldIf(AgentSchema, a.AgentId, "status", agent.STATUS_OFFLINE, In("status", agent.STATUS_ONLINE), Lt("last_seen", time.Now()))
+	if err != nil || !ok {
+		t.Fatalf("conditional update: %v, %v", ok, err)
+	}
+	if got, _ := r.Get(a.AgentId); got.Status != agent.STATUS_OFFLINE || got.Config.Location != "eu" {
+		t.Errorf("after conditional update: %+v", got)
+	}
+	if ok, _ := r.UpdateFieldIf(AgentSchema, a.AgentId, "status", agent.STATUS_DEGRADED, In("status", agent.STATUS_ONLINE)); ok {
+		t.Error("conditional update ignored its condition")
+	}
+
+	if err := r.Delete(a.AgentId); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Get(a.AgentId); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get after delete: %v", err)
+	}
+	if n := len(ms.Documents("test", "agents")); n != 1 {
+		t.Errorf("%d documents left, want 1", n)
+	}
+}
+
+func TestNosqlRepositoryNotFound(t *testing.T) {
+	r := newTestNosql[*task.Task](t, mongotest.NewServer(), "tasks", "")
+
+	id := uuid.New()
+	if _, err := r.Get(id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get: %v", err)
+	}
+	if err := r.Update(id, &task.Task{Id: id}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("update: %v", err)
+	}
+	if err := r.Delete(id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("delete: %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Результат резервирования портов
+const (
+	PORTS_RESERVED  = 1 // порты закреплены за конфигурацией
+	PORTS_CONFLICT  = 2 // один из портов занят другой конфигурацией
+	PORTS_EXHAUSTED = 3 // в диапазоне не хватило свободных портов
+)
+
+// Резервирует за конфигурацией ARGV[1] порты ARGV[5..] и ARGV[4] свободных портов
+// из диапазона ARGV[2]-ARGV[3]. Порты конфигурации, которые больше не нужны, освобождаются.
+// Возвращает {1, назначенные порты...}, {2, порт, владелец} или {3}
+var reservePortsScript = redis.NewScript(`
+local owner = ARGV[1]
+local keep = {}
+for i = 5, #ARGV do
+	local cur = redis.call('HGET', KEYS[1], ARGV[i])
+	if cur and cur ~= owner then
+		return {2, ARGV[i], cur}
+	end
+	keep[ARGV[i]] = true
+end
+
+local assigned = {}
+local need = tonumber(ARGV[4])
+local port = tonumber(ARGV[2])
+local max = tonumber(ARGV[3])
+while #assigned < need and port <= max do
+	local p = tostring(port)
+	if not keep[p] then
+		local cur = redis.call('HGET', KEYS[1], p)
+		if not cur or cur == owner then
+			keep[p] = true
+			table.insert(assigned, p)
+		end
+	end
+	port = port + 1
+end
+if #assigned < need then
+	return {3}
+end
+
+local held = redis.call('HGETALL', KEYS[1])
+for i = 1, #held, 2 do
+	if held[i + 1] == owner and not keep[held[i]] then
+		redis.call('HDEL', KEYS[1], held[i])
+	end
+end
+for p in pairs(keep) do
+	redis.call('HSET', KEYS[1], p, owner)
+end
+
+local res = {1}
+for _, p in ipairs(assigned) do
+	table.insert(res, p)
+end
+return res
+`)
+
+// Освобождает все порты конфигурации ARGV[1]
+var releasePortsScript = redis.NewScript(`
+local held = redis.call('HGETALL', KEYS[1])
+local released = 0
+for i = 1, #held, 2 do
+	if held[i + 1] == ARGV[1] then
+		redis.call('HDEL', KEYS[1], held[i])
+		released = released + 1
+	end
+end
+return released
+`)
+
+// Запрос на резервирование портов агента
+type PortRequest struct {
+	Ports    []uint16 // порты, заданные явно
+	Auto     int      // сколько портов выбрать из диапазона
+	Min, Max uint16   // диапазон для автоматического выбора
+}
+
+// Итог резервирования
+type PortReservation struct {
+	Result   int
+	Assigned []uint16  // порты, выбранные из диапазона, по возрастанию
+	Port     uint16    // занятый порт при PORTS_CONFLICT
+	Owner    uuid.UUID // конфигурация, которой он принадлежит
+}
+
+// Занятые порты агентов в Redis: для каждого агента хеш порт -> id конфигурации.
+// Резервирование выполняется Lua-скриптом, поэтому два запроса не могут
+// одновременно получить один порт
+type RedisPorts struct {
+	rc     *redis.Client
+	prefix string
+}
+
+func NewRedisPorts(rc *redis.Client, prefix string) (*RedisPorts, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("redis prefix is required")
+	}
+
+	return &RedisPorts{rc: rc, prefix: prefix}, nil
+}
+
+// Закрепляет порты за конфигурацией на агенте. Остальные порты этой
+// конфигурации на агенте освобождаются, при неудаче ничего не меняется
+func (r *RedisPorts) Reserve(agentId, configurationId uuid.UUID, req PortRequest) (*PortReservation, error) {
+	if req.Auto > 0 && (req.Min == 0 || req.Min > req.Max) {
+		return nil, fmt.Errorf("invalid port range %d-%d", req.Min, req.Max)
+	}
+
+	args := make([]any, 0, 4+len(req.Ports))
+	args = append(args, configurationId.String(), req.Min, req.Max, req.Auto)
+	for _, p := range req.Ports {
+		args = append(args, p)
+	}
+
+	res, err := reservePortsScript.Run(context.Background(), r.rc, []string{r.key(agentId)}, args...).Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, errors.New("empty response from reserve ports script")
+	}
+
+	result, ok := res[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected reserve ports result %v", res[0])
+	}
+
+	switch result {
+	case PORTS_RESERVED:
+		assigned := make([]uint16, 0, len(res)-1)
+		for _, v := range res[1:] {
+			p, err := parsePort(v)
+			if err != nil {
+				return nil, err
+			}
+			assigned = append(assigned, p)
+		}
+		return &PortReservation{Result: PORTS_RESERVED, Assigned: assigned}, nil
+	case PORTS_CONFLICT:
+		if len(res) != 3 {
+			return nil, fmt.Errorf("unexpected reserve ports response %v", res)
+		}
+		port, err := parsePort(res[1])
+		if err != nil {
+			return nil, err
+		}
+		owner, err := uuid.Parse(fmt.Sprint(res[2]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse port owner: %w", err)
+		}
+		return &PortReservation{Result: PORTS_CONFLICT, Port: port, Owner: owner}, nil
+	case PORTS_EXHAUSTED:
+		return &PortReservation{Result: PORTS_EXHAUSTED}, nil
+	default:
+		return nil, fmt.Errorf("unexpected reserve ports result %d", result)
+	}
+}
+
+// Освобождает все порты конфигурации на агенте
+func (r *RedisPorts) Release(agentId, configurationId uuid.UUID) error {
+	return releasePortsScript.Run(context.Background(), r.rc, []string{r.key(agentId)}, configurationId.String()).Err()
+}
+
+func (r *RedisPorts) key(agentId uuid.UUID) string {
+	return r.prefix + ":" + agentId.String()
+}
+
+func parsePort(v any) (uint16, error) {
+	p, err := strconv.ParseUint(fmt.Sprint(v), 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port %v: %w", v, err)
+	}
+	return uint16(p), nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestPorts(t *testing.T) *RedisPorts {
+	t.Helper()
+
+	rc := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { rc.Close() })
+
+	p, err := NewRedisPorts(rc, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPortsReserve(t *testing.T) {
+	p := newTestPorts(t)
+	agentId, first, second := uuid.New(), uuid.New(), uuid.New()
+
+	res, err := p.Reserve(agentId, first, PortRequest{Ports: []uint16{27001}, Auto: 2, Min: 27000, Max: 27003})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Result != PORTS_RESERVED || !slices.Equal(res.Assigned, []uint16{27000, 27002}) {
+		t.Fatalf("reservation = %+v", res)
+	}
+
+	res, err = p.Reserve(agentId, second, PortRequest{Ports: []uint16{27002}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Result != PORTS_CONFLICT || res.Port != 27002 || res.Owner != first {
+		t.Errorf("reservation = %+v, want conflict with the first configuration", res)
+	}
+
+	res, err = p.Reserve(agentId, second, PortRequest{Auto: 2, Min: 27000, Max: 27003})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Result != PORTS_EXHAUSTED {
+		t.Errorf("reservation = %+v, want exhausted", res)
+	}
+
+	// После освобождения порты снова доступны
+	if err := p.Release(agentId, first); err != nil {
+		t.Fatal(err)
+	}
+	res, err = p.Reserve(agentId, second, PortRequest{Auto: 2, Min: 27000, Max: 27003})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Result != PORTS_RESERVED || !slices.Equal(res.Assigned, []uint16{27000, 27001}) {
+		t.Errorf("reservation = %+v", res)
+	}
+}
+
+func TestPortsReserveRejectsInvalidRange(t *testing.T) {
+	p := newTestPorts(t)
+
+	for _, req := range []PortRequest{
+		{Auto: 1, Min: 0, Max: 10},
+		{Auto: 1, Min: 0, Max: 0},
+		{Auto: 1, Min: 20, Max: 10},
+	} {
+		if res, err := p.Reserve(uuid.New(), uuid.New(), req); err == nil {
+			t.Errorf("%+v: reserved %+v from an invalid range", req, res)
+		}
+	}
+
+	// Явные порты не зависят от диапазона
+	res, err := p.Reserve(uuid.New(), uuid.New(), PortRequest{Ports: []uint16{25565}})
+	if err != nil || res.Result != PORTS_RESERVED {
+		t.Errorf("Reserve = %+v, %v", res, err)
+	}
+}
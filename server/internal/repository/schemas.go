@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/task"
+)
+
+// Списки задач, агентов и конфигураций. Пустой набор полей в Indexes -
+// индекс только по id для сортировки по умолчанию
+var TaskSchema = &Schema[*task.Task]{
+	IdPath: "id",
+	Fields: []Field[*task.Task]{
+		{Name: "id", Path: "id", Kind: FIELD_UUID, Sortable: true, Value: func(t *task.Task) any { return t.Id }},
+		{Name: "status", Path: "status", Kind: FIELD_INT, Sortable: true, Value: func(t *task.Task) any { return int64(t.Status) }},
+		{Name: "type", Path: "type", Kind: FIELD_STRING, Sortable: true, Value: func(t *task.Task) any { return t.Type }},
+		{Name: "action", Path: "action", Kind: FIELD_STRING, Sortable: true, Value: func(t *task.Task) any { return t.Action }},
+		{Name: "priority", Path: "priority", Kind: FIELD_INT, Sortable: true, Value: func(t *task.Task) any { return int64(t.Priority) }},
+		{Name: "agent_id", Path: "agent_id", Kind: FIELD_UUID, Sortable: true, Value: func(t *task.Task) any { return t.AgentId }},
+		{Name: "configuration_id", Path: "configuration_id", Kind: FIELD_UUID, Sortable: true, Value: func(t *task.Task) any { return t.ConfigurationId }},
+		{Name: "created_at", Path: "created_at", Kind: FIELD_TIME, Sortable: true, Value: func(t *task.Task) any { return t.CreatedAt }},
+	},
+	DefaultSort: "created_at",
+	DefaultDesc: true,
+	Indexes: [][]string{
+		{},
+		{"created_at"},
+		{"status", "created_at"},
+		{"agent_id", "created_at"},
+		{"configuration_id", "created_at"},
+	},
+}
+
+var AgentSchema = &Schema[*agent.Info]{
+	IdPath: "agent_id",
+	Fields: []Field[*agent.Info]{
+		{Name: "agent_id", Path: "agent_id", Kind: FIELD_UUID, Sortable: true, Value: func(i *agent.Info) any { return i.AgentId }},
+		{Name: "status", Path: "status", Kind: FIELD_INT, Sortable: true, Value: func(i *agent.Info) any { return int64(i.Status) }},
+		{Name: "last_seen", Path: "last_seen", Kind: FIELD_TIME, Sortable: true, Value: func(i *agent.Info) any { return i.LastSeen }},
+
+		// Пустая локация не сохраняется, поэтому только для фильтра
+		{Name: "location", Path: "config.location", Kind: FIELD_STRING, Value: func(i *agent.Info) any { return i.Config.Location }},
+	},
+	DefaultSort: "agent_id",
+	Indexes: [][]string{
+		{},
+		{"status"},
+		{"location"},
+	},
+}
+
+var ConfigurationSchema = &Schema[*configuration.Envelope]{
+	IdPath: "id",
+	Fields: []Field[*configuration.Envelope]{
+		{Name: "id", Path: "id", Kind: FIELD_UUID, Sortable: true, Value: func(e *configuration.Envelope) any { return e.GetId() }},
+		{Name: "type", Path: "type", Kind: FIELD_STRING, Sortable: true, Value: func(e *configuration.Envelope) any { return e.GetBase().Type }},
+		{Name: "agent_id", Path: "agent_id", Kind: FIELD_UUID, Sortable: true, Value: func(e *configuration.Envelope) any { return e.GetBase().AgentId }},
+	},
+	DefaultSort: "id",
+	Indexes: [][]string{
+		{},
+		{"type"},
+		{"agent_id"},
+	},
+}
@@ -0,0 +1,401 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/vv-sam/otus-project/server/internal/model/task"
+)
+
+// Результат возврата задачи в очередь
+const (
+	REQUEUED      = 1 // задача снова ждёт агента
+	DEAD_LETTERED = 2 // попытки исчерпаны, задача перенесена в dead-letter список
+)
+
+// Очереди агента, в которые попадают задачи без назначенного агента
+const anyAgent = "any"
+
+// Выданная агенту задача
+type QueueLease struct {
+	Id       uuid.UUID
+	Attempts int // номер попытки, начиная с 1
+}
+
+// Скрипт вернул readyChanged: очередь задачи изменилась после того, как её прочитали
+const readyChanged = -1
+
+// Сколько раз повторять скрипт, если очередь задачи меняется между чтением и вызовом
+const readyRetries = 5
+
+// Постановка в очередь. Новой задаче записывается очередь, score и счётчик попыток.
+// Задача из dead-letter списка возвращается в очередь со сброшенным счётчиком
+// попыток, задача в очереди переносится в новую очередь с новым score, если
+// изменились агент, тип или приоритет. У задачи в аренде меняется только
+// очередь, в которую она вернётся после неудачи
+var enqueueScript = redis.NewScript(`
+if (redis.call('HGET', KEYS[2], 'ready') or '') ~= KEYS[6] then
+	return -1
+end
+if KEYS[6] ~= '' then
+	if redis.call('LREM', KEYS[4], 0, ARGV[1]) > 0 then
+		redis.call('HSET', KEYS[5], ARGV[1], 0)
+	elseif redis.call('ZREM', KEYS[6], ARGV[1]) == 0 then
+		redis.call('HSET', KEYS[2], 'ready', KEYS[1], 'score', ARGV[2])
+		redis.call('SADD', KEYS[3], ARGV[3])
+		return 1
+	end
+else
+	redis.call('HSET', KEYS[5], ARGV[1], ARGV[4])
+end
+redis.call('HSET', KEYS[2], 'ready', KEYS[1], 'score', ARGV[2])
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+redis.call('SADD', KEYS[3], ARGV[3])
+return 1
+`)
+
+// Выдаёт до ARGV[3] задач с наименьшим score из переданных очередей.
+// Возвращает пары id, номер попытки
+var leaseScript = redis.NewScript(`
+local leased = {}
+local max = tonumber(ARGV[3])
+while #leased / 2 < max do
+	local bestKey, bestId, bestScore
+	for i = 4, #KEYS do
+		local head = redis.call('ZRANGE', KEYS[i], 0, 0, 'WITHSCORES')
+		if #head > 0 then
+			local score = tonumber(head[2])
+			if bestScore == nil or score < bestScore then
+				bestKey, bestId, bestScore = KEYS[i], head[1], score
+			end
+		end
+	end
+	if bestKey == nil then
+		break
+	end
+	redis.call('ZREM', bestKey, bestId)
+	redis.call('ZADD', KEYS[1], ARGV[2], bestId)
+	redis.call('HSET', KEYS[2], bestId, ARGV[1])
+	local attempts = redis.call('HINCRBY', KEYS[3], bestId, 1)
+	table.insert(leased, bestId)
+	table.insert(leased, attempts)
+end
+return leased
+`)
+
+// Продлевает аренду, если она ещё принадлежит агенту
+var extendScript = redis.NewScript(`
+if redis.call('HGET', KEYS[2], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+local expires = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not expires or tonumber(expires) < tonumber(ARGV[3]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[4], ARGV[1])
+return 1
+`)
+
+// Подтверждает выполнение и убирает задачу из очереди
+var ackScript = redis.NewScript(`
+if redis.call('HGET', KEYS[2], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+local expires = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not expires or tonumber(expires) < tonumber(ARGV[3]) then
+	return 0
+end
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[4], ARGV[1])
+redis.call('DEL', KEYS[3])
+return 1
+`)
+
+// Снимает аренду после неудачи: возвращает задачу в её очередь
+// или переносит в dead-letter список, если попытки исчерпаны
+var nackScript = redis.NewScript(`
+if redis.call('HGET', KEYS[2], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+local expires = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not expires or tonumber(expires) < tonumber(ARGV[3]) then
+	return 0
+end
+if (redis.call('HGET', KEYS[3], 'ready') or '') ~= KEYS[6] then
+	return -1
+end
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+local attempts = tonumber(redis.call('HGET', KEYS[4], ARGV[1]) or '0')
+if attempts >= tonumber(ARGV[4]) or KEYS[6] == '' then
+	redis.call('RPUSH', KEYS[5], ARGV[1])
+	return 2
+end
+redis.call('ZADD', KEYS[6], redis.call('HGET', KEYS[3], 'score'), ARGV[1])
+return 1
+`)
+
+// Возвращает в очередь задачу с истёкшей арендой или переносит её в dead-letter
+// список, если попытки исчерпаны. 0 - аренда успела закончиться или продлиться
+var reapScript = redis.NewScript(`
+local expires = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not expires or tonumber(expires) >= tonumber(ARGV[2]) then
+	return 0
+end
+if (redis.call('HGET', KEYS[4], 'ready') or '') ~= KEYS[6] then
+	return -1
+end
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+local attempts = tonumber(redis.call('HGET', KEYS[5], ARGV[1]) or '0')
+if attempts >= tonumber(ARGV[3]) or KEYS[6] == '' then
+	redis.call('RPUSH', KEYS[3], ARGV[1])
+	return 2
+end
+redis.call('ZADD', KEYS[6], redis.call('HGET', KEYS[4], 'score'), ARGV[1])
+return 1
+`)
+
+// Возвращает задачу из dead-letter списка в её очередь со сброшенным счётчиком попыток
+var requeueScript = redis.NewScript(`
+if (redis.call('HGET', KEYS[2], 'ready') or '') ~= KEYS[4] then
+	return -1
+end
+if KEYS[4] == '' or redis.call('LREM', KEYS[1], 0, ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[3], ARGV[1], 0)
+redis.call('ZADD', KEYS[4], redis.call('HGET', KEYS[2], 'score'), ARGV[1])
+return 1
+`)
+
+// Полностью убирает задачу из очередей, аренды и dead-letter списка
+var removeScript = redis.NewScript(`
+if (redis.call('HGET', KEYS[1], 'ready') or '') ~= KEYS[6] then
+	return -1
+end
+if KEYS[6] ~= '' then
+	redis.call('ZREM', KEYS[6], ARGV[1])
+end
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[3], ARGV[1])
+redis.call('LREM', KEYS[4], 0, ARGV[1])
+redis.call('HDEL', KEYS[5], ARGV[1])
+redis.call('DEL', KEYS[1])
+return 1
+`)
+
+// Очередь задач в Redis. Для каждой пары агент/тип задачи заводится
+// отдельный sorted set, порядок выдачи определяется приоритетом и временем создания.
+// Все изменения состояния выполняются Lua-скриптами и атомарны. Скрипты не
+// собирают имена ключей сами и получают их все через KEYS
+type RedisTaskQueue struct {
+	rc     *redis.Client
+	prefix string
+}
+
+func NewRedisTaskQueue(rc *redis.Client, prefix string) (*RedisTaskQueue, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("redis key prefix is required")
+	}
+
+	return &RedisTaskQueue{rc: rc, prefix: prefix}, nil
+}
+
+// Ставит задачу в очередь её агента и типа
+func (q *RedisTaskQueue) Enqueue(t *task.Task) error {
+	agent := anyAgent
+	if t.AgentId != uuid.Nil {
+		agent = t.AgentId.String()
+	}
+
+	created := t.CreatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	// Чем выше приоритет, тем меньше score. Внутри приоритета - FIFO по времени создания
+	score := int64(task.PRIORITY_HIGH-t.Priority)*1e13 + created.UnixMilli()
+
+	id := t.Id.String()
+	ready := q.readyKey(agent, t.Type)
+	_, err := q.runWithReady(context.Background(), enqueueScript, id, func(cur string) []string {
+		return []string{ready, q.taskKey(id), q.key("types"), q.key("dead"), q.key("attempts"), cur}
+	}, id, score, t.Type, t.Attempts)
+	return err
+}
+
+// Выдаёт агенту до max задач указанных типов (всех известных, если types пуст)
+// в порядке приоритета
+func (q *RedisTaskQueue) Lease(agentId uuid.UUID, types []string, max int, timeout time.Duration) ([]QueueLease, error) {
+	ctx := context.Background()
+
+	if len(types) == 0 {
+		var err error
+		types, err = q.rc.SMembers(ctx, q.key("types")).Result()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	keys := []string{q.key("leased"), q.key("owners"), q.key("attempts")}
+	for _, t := range types {
+		keys = append(keys, q.readyKey(agentId.String(), t), q.readyKey(anyAgent, t))
+	}
+
+	expires := time.Now().Add(timeout).UnixMilli()
+	res, err := leaseScript.Run(ctx, q.rc, keys, agentId.String(), expires, max).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	leased := make([]QueueLease, 0, len(res)/2)
+	for i := 0; i+1 < len(res); i += 2 {
+		id, err := uuid.Parse(fmt.Sprint(res[i]))
+		if err != nil {
+			return nil, err
+		}
+		attempts, _ := res[i+1].(int64)
+		leased = append(leased, QueueLease{Id: id, Attempts: int(attempts)})
+	}
+	return leased, nil
+}
+
+// Продлевает аренду задачи агентом
+func (q *RedisTaskQueue) Extend(id, agentId uuid.UUID, timeout time.Duration) (bool, error) {
+	now := time.Now()
+	keys := []string{q.key("leased"), q.key("owners")}
+	return q.runBool(extendScript, keys, id.String(), agentId.String(), now.UnixMilli(), now.Add(timeout).UnixMilli())
+}
+
+// Подтверждает успешное выполнение задачи
+func (q *RedisTaskQueue) Ack(id, agentId uuid.UUID) (bool, error) {
+	keys := []string{q.key("leased"), q.key("owners"), q.taskKey(id.String()), q.key("attempts")}
+	return q.runBool(ackScript, keys, id.String(), agentId.String(), time.Now().UnixMilli())
+}
+
+// Сообщает о неудаче. Возвращает REQUEUED или DEAD_LETTERED, 0 - если аренда агенту не принадлежит
+func (q *RedisTaskQueue) Nack(id, agentId uuid.UUID, maxAttempts int) (int, error) {
+	taskId := id.String()
+	return q.runWithReady(context.Background(), nackScript, taskId, func(ready string) []string {
+		return []string{q.key("leased"), q.key("owners"), q.taskKey(taskId), q.key("attempts"), q.key("dead"), ready}
+	}, taskId, agentId.String(), time.Now().UnixMilli(), maxAttempts)
+}
+
+// Снимает истёкшие аренды. Для каждой задачи возвращает REQUEUED или DEAD_LETTERED
+func (q *RedisTaskQueue) Reap(maxAttempts int) (map[uuid.UUID]int, error) {
+	ctx := context.Background()
+	now := time.Now().UnixMilli()
+
+	expired, err := q.rc.ZRangeByScore(ctx, q.key("leased"), &redis.ZRangeBy{Min: "-inf", Max: "(" + strconv.FormatInt(now, 10)}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	// Каждая задача снимается своим вызовом скрипта: её очередь известна только из её описания
+	reaped := make(map[uuid.UUID]int, len(expired))
+	for _, taskId := range expired {
+		id, err := uuid.Parse(taskId)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := q.runWithReady(ctx, reapScript, taskId, func(ready string) []string {
+			return []string{q.key("leased"), q.key("owners"), q.key("dead"), q.taskKey(taskId), q.key("attempts"), ready}
+		}, taskId, now, maxAttempts)
+		if err != nil {
+			return nil, err
+		}
+		if res != 0 {
+			reaped[id] = res
+		}
+	}
+	return reaped, nil
+}
+
+// Id задач в dead-letter списке в порядке попадания туда
+func (q *RedisTaskQueue) DeadLetters() ([]uuid.UUID, error) {
+	res, err := q.rc.LRange(context.Background(), q.key("dead"), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(res))
+	for _, r := range res {
+		id, err := uuid.Parse(r)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Возвращает задачу из dead-letter списка в очередь. false - задачи в списке нет
+func (q *RedisTaskQueue) Requeue(id uuid.UUID) (bool, error) {
+	taskId := id.String()
+	res, err := q.runWithReady(context.Background(), requeueScript, taskId, func(ready string) []string {
+		return []string{q.key("dead"), q.taskKey(taskId), q.key("attempts"), ready}
+	}, taskId)
+	return res == 1, err
+}
+
+// Убирает задачу из очереди, например после отмены или удаления
+func (q *RedisTaskQueue) Remove(id uuid.UUID) error {
+	taskId := id.String()
+	_, err := q.runWithReady(context.Background(), removeScript, taskId, func(ready string) []string {
+		return []string{q.taskKey(taskId), q.key("leased"), q.key("owners"), q.key("dead"), q.key("attempts"), ready}
+	}, taskId)
+	return err
+}
+
+// Очередь задачи хранится в её описании, поэтому читается заранее и передаётся
+// скрипту последним ключом (пустая строка - описания нет). Скрипт проверяет,
+// что очередь не изменилась, иначе вызов повторяется
+func (q *RedisTaskQueue) runWithReady(ctx context.Context, s *redis.Script, id string, keys func(ready string) []string, args ...any) (int, error) {
+	for range readyRetries {
+		ready, err := q.rc.HGet(ctx, q.taskKey(id), "ready").Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return 0, err
+		}
+
+		res, err := s.Run(ctx, q.rc, keys(ready), args...).Int()
+		if err != nil {
+			return 0, err
+		}
+		if res != readyChanged {
+			return res, nil
+		}
+	}
+	return 0, fmt.Errorf("queue of task %s changed %d times in a row", id, readyRetries)
+}
+
+func (q *RedisTaskQueue) runBool(s *redis.Script, keys []string, args ...any) (bool, error) {
+	res, err := s.Run(context.Background(), q.rc, keys, args...).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+func (q *RedisTaskQueue) key(name string) string {
+	return q.prefix + ":" + name
+}
+
+func (q *RedisTaskQueue) readyKey(agent, taskType string) string {
+	return q.prefix + ":ready:" + agent + ":" + taskType
+}
+
+func (q *RedisTaskQueue) taskKey(id string) string {
+	return q.prefix + ":task:" + id
+}
@@ -0,0 +1,403 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/vv-sam/otus-project/server/internal/model/task"
+)
+
+func newTestQueue(t *testing.T) (*RedisTaskQueue, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rc.Close() })
+
+	q, err := NewRedisTaskQueue(rc, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q, mr
+}
+
+func newQueuedTask(agentId uuid.UUID, priority int16, created time.Time) *task.Task {
+	return &task.Task{Id: uuid.New(), Type: "minecraft", AgentId: agentId, Priority: priority, CreatedAt: created}
+}
+
+func enqueue(t *testing.T, q *RedisTaskQueue, tasks ...*task.Task) {
+	t.Helper()
+
+	for _, tk := range tasks {
+		if err := q.Enqueue(tk); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+}
+
+func lease(t *testing.T, q *RedisTaskQueue, agentId uuid.UUID, max int, timeout time.Duration) []QueueLease {
+	t.Helper()
+
+	leased, err := q.Lease(agentId, nil, max, timeout)
+	if err != nil {
+		t.Fatalf("Lease: %v", err)
+	}
+	return leased
+}
+
+func leasedIds(leased []QueueLease) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, l := range leased {
+		ids = append(ids, l.Id)
+	}
+	return ids
+}
+
+// Скрипты обращаются к Redis только по ключам из KEYS. redis.Script не отдаёт
+// свой текст, поэтому скрипты берутся из исходника
+func TestTaskQueueScriptsUseDeclaredKeys(t *testing.T) {
+	src, err := os.ReadFile("task_queue.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scripts := regexp.MustCompile("(?s)var (\\w+) = redis\\.NewScript\\(`(.*?)`\\)").FindAllSubmatch(src, -1)
+	if len(scripts) != 8 {
+		t.Fatalf("found %d scripts, want 8", len(scripts))
+	}
+
+	call := regexp.MustCompile(`redis\.call\('(\w+)', ([^,)]+)`)
+	declared := regexp.MustCompile(`^(KEYS\[\d+\]|KEYS\[i\]|bestKey)$`)
+	for _, s := range scripts {
+		for _, m := range call.FindAllSubmatch(s[2], -1) {
+			if !declared.Match(m[2]) {
+				t.Errorf("%s: %s uses key %s that is not passed in KEYS", s[1], m[1], m[2])
+			}
+		}
+	}
+}
+
+func TestTaskQueueLeaseOrder(t *testing.T) {
+	q, _ := newTestQueue(t)
+	agentId, other := uuid.New(), uuid.New()
+	now := time.Now()
+
+	low := newQueuedTask(uuid.Nil, task.PRIORITY_LOW, now.Add(-3*time.Minute))
+	normal := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, now.Add(-2*time.Minute))
+	later := newQueuedTask(agentId, task.PRIORITY_NORMAL, now.Add(-time.Minute))
+	high := newQueuedTask(agentId, task.PRIORITY_HIGH, now)
+	foreign := newQueuedTask(other, task.PRIORITY_HIGH, now)
+	enqueue(t, q, low, normal, later, high, foreign)
+
+	leased := lease(t, q, agentId, 10, time.Minute)
+	want := []uuid.UUID{high.Id, normal.Id, later.Id, low.Id}
+	if got := leasedIds(leased); !slices.Equal(got, want) {
+		t.Errorf("leased %v, want %v", got, want)
+	}
+	for _, l := range leased {
+		if l.Attempts != 1 {
+			t.Errorf("task %s: attempts = %d, want 1", l.Id, l.Attempts)
+		}
+	}
+
+	if got := leasedIds(lease(t, q, other, 10, time.Minute)); !slices.Equal(got, []uuid.UUID{foreign.Id}) {
+		t.Errorf("other agent leased %v, want only its own task", got)
+	}
+}
+
+func TestTaskQueueLeaseTypes(t *testing.T) {
+	q, _ := newTestQueue(t)
+	agentId := uuid.New()
+
+	mc := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, time.Now())
+	fc := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, time.Now())
+	fc.Type = "factorio"
+	enqueue(t, q, mc, fc)
+
+	leased, err := q.Lease(agentId, []string{"factorio"}, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := leasedIds(leased); !slices.Equal(got, []uuid.UUID{fc.Id}) {
+		t.Errorf("leased %v, want only the factorio task", got)
+	}
+}
+
+func TestTaskQueueEnqueueIsIdempotent(t *testing.T) {
+	q, _ := newTestQueue(t)
+	agentId := uuid.New()
+
+	tk := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, time.Now())
+	enqueue(t, q, tk, tk)
+
+	if got := lease(t, q, agentId, 10, time.Minute); len(got) != 1 {
+		t.Fatalf("leased %d tasks, want 1", len(got))
+	}
+
+	// Повторная постановка арендованной задачи не выдаёт её второй раз
+	enqueue(t, q, tk)
+	if got := lease(t, q, agentId, 10, time.Minute); len(got) != 0 {
+		t.Errorf("leased task was leased again: %v", leasedIds(got))
+	}
+}
+
+func TestTaskQueueEnqueueMovesChangedTask(t *testing.T) {
+	q, _ := newTestQueue(t)
+	first, second := uuid.New(), uuid.New()
+	now := time.Now()
+
+	tk := newQueuedTask(first, task.PRIORITY_LOW, now.Add(-time.Minute))
+	other := newQueuedTask(second, task.PRIORITY_NORMAL, now)
+	enqueue(t, q, tk, other)
+
+	// Задачу переназначили на другого агента и подняли приоритет
+	tk.AgentId = second
+	tk.Priority = task.PRIORITY_HIGH
+	enqueue(t, q, tk)
+
+	if got := lease(t, q, first, 10, time.Minute); len(got) != 0 {
+		t.Errorf("task stayed in the old agent queue: %v", leasedIds(got))
+	}
+	want := []uuid.UUID{tk.Id, other.Id}
+	if got := leasedIds(lease(t, q, second, 10, time.Minute)); !slices.Equal(got, want) {
+		t.Errorf("leased %v, want %v", got, want)
+	}
+}
+
+func TestTaskQueueEnqueueChangedType(t *testing.T) {
+	q, _ := newTestQueue(t)
+	agentId := uuid.New()
+
+	tk := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, time.Now())
+	enqueue(t, q, tk)
+
+	tk.Type = "factorio"
+	enqueue(t, q, tk)
+
+	leased, err := q.Lease(agentId, []string{"minecraft"}, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leased) != 0 {
+		t.Errorf("task stayed in the old type queue")
+	}
+
+	leased, err = q.Lease(agentId, []string{"factorio"}, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := leasedIds(leased); !slices.Equal(got, []uuid.UUID{tk.Id}) {
+		t.Errorf("leased %v, want the retyped task", got)
+	}
+}
+
+func TestTaskQueueEnqueueChangedWhileLeased(t *testing.T) {
+	q, _ := newTestQueue(t)
+	first, second := uuid.New(), uuid.New()
+
+	tk := newQueuedTask(first, task.PRIORITY_NORMAL, time.Now())
+	enqueue(t, q, tk)
+	lease(t, q, first, 1, time.Minute)
+
+	tk.AgentId = second
+	enqueue(t, q, tk)
+
+	// После неудачи задача возвращается уже в очередь нового агента
+	res, err := q.Nack(tk.Id, first, 3)
+	if err != nil || res != REQUEUED {
+		t.Fatalf("Nack = %d, %v", res, err)
+	}
+	if got := lease(t, q, first, 10, time.Minute); len(got) != 0 {
+		t.Errorf("task returned to the old agent queue")
+	}
+	if got := lease(t, q, second, 10, time.Minute); len(got) != 1 || got[0].Attempts != 2 {
+		t.Errorf("leased %+v, want the task on its second attempt", got)
+	}
+}
+
+func TestTaskQueueExtendAndAck(t *testing.T) {
+	q, _ := newTestQueue(t)
+	agentId, other := uuid.New(), uuid.New()
+
+	tk := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, time.Now())
+	enqueue(t, q, tk)
+	lease(t, q, agentId, 1, time.Minute)
+
+	if ok, err := q.Extend(tk.Id, other, time.Minute); err != nil || ok {
+		t.Errorf("other agent extended the lease: %v, %v", ok, err)
+	}
+	if ok, err := q.Extend(tk.Id, agentId, time.Minute); err != nil || !ok {
+		t.Errorf("Extend = %v, %v", ok, err)
+	}
+	if ok, err := q.Ack(tk.Id, other); err != nil || ok {
+		t.Errorf("other agent acked the task: %v, %v", ok, err)
+	}
+	if ok, err := q.Ack(tk.Id, agentId); err != nil || !ok {
+		t.Errorf("Ack = %v, %v", ok, err)
+	}
+
+	// Подтверждённая задача больше не выдаётся и не может быть подтверждена снова
+	if ok, _ := q.Ack(tk.Id, agentId); ok {
+		t.Error("task was acked twice")
+	}
+	if got := lease(t, q, agentId, 10, time.Minute); len(got) != 0 {
+		t.Errorf("acked task was leased again")
+	}
+}
+
+func TestTaskQueueExpiredLeaseIsNotHeld(t *testing.T) {
+	q, _ := newTestQueue(t)
+	agentId := uuid.New()
+
+	tk := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, time.Now())
+	enqueue(t, q, tk)
+	lease(t, q, agentId, 1, -time.Second)
+
+	if ok, _ := q.Extend(tk.Id, agentId, time.Minute); ok {
+		t.Error("expired lease was extended")
+	}
+	if ok, _ := q.Ack(tk.Id, agentId); ok {
+		t.Error("expired lease was acked")
+	}
+	if res, _ := q.Nack(tk.Id, agentId, 3); res != 0 {
+		t.Errorf("expired lease was nacked: %d", res)
+	}
+}
+
+func TestTaskQueueNackDeadLetters(t *testing.T) {
+	q, _ := newTestQueue(t)
+	agentId := uuid.New()
+
+	tk := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, time.Now())
+	enqueue(t, q, tk)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		leased := lease(t, q, agentId, 1, time.Minute)
+		if len(leased) != 1 || leased[0].Attempts != attempt {
+			t.Fatalf("attempt %d: leased %+v", attempt, leased)
+		}
+
+		want := REQUEUED
+		if attempt == 2 {
+			want = DEAD_LETTERED
+		}
+		if res, err := q.Nack(tk.Id, agentId, 2); err != nil || res != want {
+			t.Fatalf("attempt %d: Nack = %d, %v, want %d", attempt, res, err, want)
+		}
+	}
+
+	dead, err := q.DeadLetters()
+	if err != nil || !slices.Equal(dead, []uuid.UUID{tk.Id}) {
+		t.Fatalf("dead letters = %v, %v", dead, err)
+	}
+	if got := lease(t, q, agentId, 1, time.Minute); len(got) != 0 {
+		t.Fatal("dead-lettered task was leased")
+	}
+
+	// Из dead-letter списка задача возвращается со сброшенным счётчиком попыток
+	if ok, err := q.Requeue(tk.Id); err != nil || !ok {
+		t.Fatalf("Requeue = %v, %v", ok, err)
+	}
+	if ok, _ := q.Requeue(tk.Id); ok {
+		t.Error("task was requeued twice")
+	}
+	if got := lease(t, q, agentId, 1, time.Minute); len(got) != 1 || got[0].Attempts != 1 {
+		t.Errorf("leased %+v after requeue, want first attempt", got)
+	}
+}
+
+func TestTaskQueueEnqueueDeadLetter(t *testing.T) {
+	q, _ := newTestQueue(t)
+	agentId := uuid.New()
+
+	tk := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, time.Now())
+	enqueue(t, q, tk)
+	lease(t, q, agentId, 1, time.Minute)
+	if res, _ := q.Nack(tk.Id, agentId, 1); res != DEAD_LETTERED {
+		t.Fatalf("Nack = %d, want DEAD_LETTERED", res)
+	}
+
+	// Задачу вернули в очередь через API и заодно переназначили
+	tk.AgentId = agentId
+	enqueue(t, q, tk)
+
+	if dead, _ := q.DeadLetters(); len(dead) != 0 {
+		t.Errorf("task stayed in dead letters: %v", dead)
+	}
+	if got := lease(t, q, agentId, 1, time.Minute); len(got) != 1 || got[0].Attempts != 1 {
+		t.Errorf("leased %+v, want first attempt", got)
+	}
+}
+
+func TestTaskQueueReap(t *testing.T) {
+	q, _ := newTestQueue(t)
+	agentId := uuid.New()
+
+	expiring := newQueuedTask(uuid.Nil, task.PRIORITY_HIGH, time.Now())
+	exhausted := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, time.Now())
+	active := newQueuedTask(uuid.Nil, task.PRIORITY_LOW, time.Now())
+	enqueue(t, q, expiring, exhausted, active)
+
+	// Вторая задача уже использовала все попытки
+	lease(t, q, agentId, 2, -time.Second)
+	lease(t, q, agentId, 1, time.Minute)
+	if err := q.rc.HSet(context.Background(), q.key("attempts"), exhausted.Id.String(), 3).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	reaped, err := q.Reap(3)
+	if err != nil {
+		t.Fatalf("Reap: %v", err)
+	}
+	want := map[uuid.UUID]int{expiring.Id: REQUEUED, exhausted.Id: DEAD_LETTERED}
+	if len(reaped) != len(want) || reaped[expiring.Id] != REQUEUED || reaped[exhausted.Id] != DEAD_LETTERED {
+		t.Errorf("reaped %v, want %v", reaped, want)
+	}
+
+	if got := leasedIds(lease(t, q, agentId, 10, time.Minute)); !slices.Equal(got, []uuid.UUID{expiring.Id}) {
+		t.Errorf("leased %v after reap, want the requeued task", got)
+	}
+	if dead, _ := q.DeadLetters(); !slices.Equal(dead, []uuid.UUID{exhausted.Id}) {
+		t.Errorf("dead letters = %v", dead)
+	}
+
+	// Активная аренда не снимается
+	if ok, _ := q.Extend(active.Id, agentId, time.Minute); !ok {
+		t.Error("active lease was reaped")
+	}
+}
+
+func TestTaskQueueRemove(t *testing.T) {
+	q, mr := newTestQueue(t)
+	agentId := uuid.New()
+
+	queued := newQueuedTask(uuid.Nil, task.PRIORITY_NORMAL, time.Now())
+	leased := newQueuedTask(uuid.Nil, task.PRIORITY_HIGH, time.Now())
+	enqueue(t, q, queued, leased)
+	lease(t, q, agentId, 1, time.Minute)
+
+	for _, tk := range []*task.Task{queued, leased, newQueuedTask(uuid.Nil, 0, time.Now())} {
+		if err := q.Remove(tk.Id); err != nil {
+			t.Fatalf("Remove: %v", err)
+		}
+	}
+
+	if got := lease(t, q, agentId, 10, time.Minute); len(got) != 0 {
+		t.Errorf("removed task was leased: %v", leasedIds(got))
+	}
+	if ok, _ := q.Ack(leased.Id, agentId); ok {
+		t.Error("lease of the removed task is still held")
+	}
+
+	// Кроме списка типов ничего не остаётся
+	if keys := mr.Keys(); !slices.Equal(keys, []string{"test:types"}) {
+		t.Errorf("keys left after remove: %v", keys)
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+const (
+	// Интервал, с которым агенты должны присылать heartbeat
+	HeartbeatInterval = 10 * time.Second
+
+	// Через сколько без heartbeat агент считается degraded / offline
+	DegradedAfter = 3 * HeartbeatInterval
+	OfflineAfter  = 6 * HeartbeatInterval
+)
+
+type agentStore interface {
+	GetAll() ([]*agent.Info, error)
+	UpdateFieldIf(s *repository.Schema[*agent.Info], id uuid.UUID, field string, value any, where ...repository.Condition) (bool, error)
+}
+
+// Периодически проверяет время последнего heartbeat агентов и переводит
+// замолчавших агентов в degraded, а затем в offline
+type AgentMonitor struct {
+	r             agentStore
+	interval      time.Duration
+	degradedAfter time.Duration
+	offlineAfter  time.Duration
+	events        eventPublisher
+}
+
+func NewAgentMonitor(r agentStore, events eventPublisher) *AgentMonitor {
+	return &AgentMonitor{
+		r:             r,
+		events:        events,
+		interval:      HeartbeatInterval,
+		degradedAfter: DegradedAfter,
+		offlineAfter:  OfflineAfter,
+	}
+}
+
+func (m *AgentMonitor) Run(ctx context.Context) {
+	t := time.NewTicker(m.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			if err := m.check(now); err != nil {
+				log.Printf("agent monitor: %v", err)
+			}
+		}
+	}
+}
+
+func (m *AgentMonitor) check(now time.Time) error {
+	agents, err := m.r.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, a := range agents {
+		status := a.StatusAt(now, m.degradedAfter, m.offlineAfter)
+		if status == a.Status {
+			continue
+		}
+
+		// Меняется только статус и только если за время проверки не пришёл heartbeat
+		// и статус никто не изменил, иначе свежие данные агента были бы затёрты
+		cutoff := now.Add(-m.degradedAfter)
+		if status == agent.STATUS_OFFLINE {
+			cutoff = now.Add(-m.offlineAfter)
+		}
+		ok, err := m.r.UpdateFieldIf(repository.AgentSchema, a.AgentId, "status", status,
+			repository.Lt("last_seen", cutoff), repository.In("status", a.Status))
+		if err != nil {
+			log.Printf("agent monitor: failed to update agent %s: %v", a.AgentId, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		log.Printf("agent %s: status %d -> %d", a.AgentId, a.Status, status)
+		if e := event.AgentStatus(a.AgentId, status); e != nil {
+			publishEvent(m.events, e)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+// Хранилище агентов в памяти. Понимает только условия, которые ставит монитор
+type memAgentStore struct {
+	m      sync.Mutex
+	agents map[uuid.UUID]agent.Info
+
+	// Вызывается после чтения списка, чтобы изменить агента между чтением и записью
+	afterGetAll func()
+}
+
+func newMemAgentStore(agents ...agent.Info) *memAgentStore {
+	s := &memAgentStore{agents: map[uuid.UUID]agent.Info{}}
+	for _, a := range agents {
+		s.agents[a.AgentId] = a
+	}
+	return s
+}
+
+func (s *memAgentStore) get(id uuid.UUID) agent.Info {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.agents[id]
+}
+
+func (s *memAgentStore) put(a agent.Info) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.agents[a.AgentId] = a
+}
+
+func (s *memAgentStore) GetAll() ([]*agent.Info, error) {
+	s.m.Lock()
+	var agents []*agent.Info
+	for _, a := range s.agents {
+		agents = append(agents, &a)
+	}
+	s.m.Unlock()
+
+	if s.afterGetAll != nil {
+		s.afterGetAll()
+	}
+	return agents, nil
+}
+
+func (s *memAgentStore) UpdateFieldIf(schema *repository.Schema[*agent.Info], id uuid.UUID, field string, value any, where ...repository.Condition) (bool, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	a, ok := s.agents[id]
+	if !ok {
+		return false, nil
+	}
+
+	for _, c := range where {
+		switch {
+		case c.Field == "last_seen" && c.Op == repository.OP_LT:
+			if !a.LastSeen.Before(c.Values[0].(time.Time)) {
+				return false, nil
+			}
+		case c.Field == "status" && c.Op == repository.OP_IN:
+			if a.Status != c.Values[0].(int16) {
+				return false, nil
+			}
+		default:
+			panic("unexpected condition on " + c.Field)
+		}
+	}
+
+	if field != "status" {
+		panic("unexpected field " + field)
+	}
+	a.Status = value.(int16)
+	s.agents[id] = a
+	return true, nil
+}
+
+func newTestMonitor(store agentStore, events eventPublisher) *AgentMonitor {
+	m := NewAgentMonitor(store, events)
+	m.degradedAfter = time.Minute
+	m.offlineAfter = 2 * time.Minute
+	return m
+}
+
+func TestAgentMonitorMarksSilentAgents(t *testing.T) {
+	now := time.Now()
+	fresh := agent.Info{AgentId: uuid.New(), Status: agent.STATUS_ONLINE, LastSeen: now.Add(-10 * time.Second)}
+	silent := agent.Info{AgentId: uuid.New(), Status: agent.STATUS_ONLINE, LastSeen: now.Add(-90 * time.Second)}
+	gone := agent.Info{AgentId: uuid.New(), Status: agent.STATUS_DEGRADED, LastSeen: now.Add(-3 * time.Minute)}
+
+	store := newMemAgentStore(fresh, silent, gone)
+	events := &fakeEvents{}
+	if err := newTestMonitor(store, events).check(now); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[uuid.UUID]int16{
+		fresh.AgentId:  agent.STATUS_ONLINE,
+		silent.AgentId: agent.STATUS_DEGRADED,
+		gone.AgentId:   agent.STATUS_OFFLINE,
+	}
+	for id, status := range want {
+		if got := store.get(id).Status; got != status {
+			t.Errorf("agent %s: status %d, want %d", id, got, status)
+		}
+	}
+
+	types := map[string]bool{}
+	for _, e := range events.published() {
+		types[e.Type] = true
+	}
+	if len(types) != 2 || !types[event.AGENT_DEGRADED] || !types[event.AGENT_OFFLINE] {
+		t.Errorf("published %v, want degraded and offline events", types)
+	}
+}
+
+// Heartbeat между чтением списка и записью статуса не затирается
+func TestAgentMonitorKeepsRacingHeartbeat(t *testing.T) {
+	now := time.Now()
+	a := agent.Info{AgentId: uuid.New(), Status: agent.STATUS_ONLINE, LastSeen: now.Add(-90 * time.Second)}
+
+	store := newMemAgentStore(a)
+	store.afterGetAll = func() {
+		hb := a
+		hb.LastSeen = now
+		hb.Config.Location = "eu"
+		store.put(hb)
+	}
+
+	events := &fakeEvents{}
+	if err := newTestMonitor(store, events).check(now); err != nil {
+		t.Fatal(err)
+	}
+
+	got := store.get(a.AgentId)
+	if got.Status != agent.STATUS_ONLINE || !got.LastSeen.Equal(now) || got.Config.Location != "eu" {
+		t.Errorf("heartbeat was overwritten: %+v", got)
+	}
+	if e := events.published(); len(e) != 0 {
+		t.Errorf("published %d events for an agent that is alive", len(e))
+	}
+}
+
+// Статус, изменённый между чтением и записью, не перезаписывается
+func TestAgentMonitorKeepsChangedStatus(t *testing.T) {
+	now := time.Now()
+	a := agent.Info{AgentId: uuid.New(), Status: agent.STATUS_ONLINE, LastSeen: now.Add(-90 * time.Second)}
+
+	store := newMemAgentStore(a)
+	store.afterGetAll = func() {
+		changed := a
+		changed.Status = agent.STATUS_OFFLINE
+		store.put(changed)
+	}
+
+	if err := newTestMonitor(store, &fakeEvents{}).check(now); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.get(a.AgentId).Status; got != agent.STATUS_OFFLINE {
+		t.Errorf("status %d, want the concurrently set offline", got)
+	}
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Время выполнения команды, если вызывающий не указал своё
+	DefaultCommandTimeout = 30 * time.Second
+
+	// Сколько сообщений потока может ждать медленного получателя
+	streamBuffer = 256
+)
+
+var (
+	ErrAgentNotConnected = errors.New("agent is not connected")
+	ErrSessionClosed     = errors.New("agent session closed")
+	ErrCommandTimeout    = errors.New("command timed out")
+	ErrStreamOverflow    = errors.New("stream consumer is too slow")
+)
+
+// Транспорт, по которому агент подключён к серверу: gRPC-поток или websocket
+type AgentConn interface {
+	Send(msg *api.ServerMessage) error
+	Recv() (*api.AgentMessage, error)
+}
+
+// Подключённые агенты. Через реестр сервер отправляет команды агентам,
+// ответы сопоставляются с командами по request id
+type AgentSessions struct {
+	m        sync.Mutex
+	sessions map[uuid.UUID]*AgentSession
+}
+
+func NewAgentSessions() *AgentSessions {
+	return &AgentSessions{sessions: make(map[uuid.UUID]*AgentSession)}
+}
+
+// Сессия одного подключения агента
+type AgentSession struct {
+	agentId uuid.UUID
+	conn    AgentConn
+
+	sendM sync.Mutex
+
+	m       sync.Mutex
+	pending map[string]chan *api.CommandResult
+	streams map[string]chan *api.AgentMessage
+	closed  chan struct{}
+	err     error
+}
+
+// Регистрирует подключение агента. Предыдущее подключение того же агента
+// закрывается, ожидающие его команды завершаются с ErrSessionClosed
+func (s *AgentSessions) Attach(agentId uuid.UUID, conn AgentConn) *AgentSession {
+	session := &AgentSession{
+		agentId: agentId,
+		conn:    conn,
+		pending: make(map[string]chan *api.CommandResult),
+		streams: make(map[string]chan *api.AgentMessage),
+		closed:  make(chan struct{}),
+	}
+
+	s.m.Lock()
+	old := s.sessions[agentId]
+	s.sessions[agentId] = session
+	s.m.Unlock()
+
+	if old != nil {
+		old.close(ErrSessionClosed)
+	}
+
+	log.Printf("agent %s attached to control channel", agentId)
+	return session
+}
+
+// Читает сообщения агента, пока подключение не оборвётся или не будет заменено новым
+func (s *AgentSessions) Serve(ctx context.Context, session *AgentSession) error {
+	defer s.detach(session)
+
+	msgs := make(chan *api.AgentMessage)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := session.conn.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case msgs <- msg:
+			case <-session.closed:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-session.closed:
+			return session.err
+		case err := <-errs:
+			return err
+		case msg := <-msgs:
+			session.dispatch(msg)
+		}
+	}
+}
+
+// Отправляет команду агенту и ждёт результата
+func (s *AgentSessions) Send(ctx context.Context, agentId uuid.UUID, cmd *api.Command, timeout time.Duration) (*api.CommandResult, error) {
+	s.m.Lock()
+	session := s.sessions[agentId]
+	s.m.Unlock()
+
+	if session == nil {
+		return nil, ErrAgentNotConnected
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+	cmd.Deadline = timestamppb.New(deadline)
+
+	return session.call(ctx, cmd)
+}
+
+// Запрашивает у агента вывод игрового сервера и передаёт порции в fn, пока агент
+// не завершит поток, не будет отменён ctx или fn не вернёт ошибку
+func (s *AgentSessions) Logs(ctx context.Context, agentId uuid.UUID, req *api.LogsRequest, fn func(*api.LogChunk) error) error {
+	s.m.Lock()
+	session := s.sessions[agentId]
+	s.m.Unlock()
+
+	if session == nil {
+		return ErrAgentNotConnected
+	}
+
+	return session.stream(ctx, req, fn)
+}
+
+// Подключён ли агент к каналу управления
+func (s *AgentSessions) IsConnected(agentId uuid.UUID) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	_, ok := s.sessions[agentId]
+	return ok
+}
+
+func (s *AgentSessions) detach(session *AgentSession) {
+	s.m.Lock()
+	if s.sessions[session.agentId] == session {
+		delete(s.sessions, session.agentId)
+		log.Printf("agent %s detached from control channel", session.agentId)
+	}
+	s.m.Unlock()
+
+	session.close(ErrSessionClosed)
+}
+
+func (s *AgentSession) call(ctx context.Context, cmd *api.Command) (*api.CommandResult, error) {
+	requestId := uuid.NewString()
+	res := make(chan *api.CommandResult, 1)
+
+	s.m.Lock()
+	if s.err != nil {
+		s.m.Unlock()
+		return nil, s.err
+	}
+	s.pending[requestId] = res
+	s.m.Unlock()
+
+	defer func() {
+		s.m.Lock()
+		delete(s.pending, requestId)
+		s.m.Unlock()
+	}()
+
+	err := s.send(&api.ServerMessage{
+		RequestId: requestId,
+		Body:      &api.ServerMessage_Command{Command: cmd},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case r := <-res:
+		return r, nil
+	case <-s.closed:
+		return nil, s.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrCommandTimeout
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (s *AgentSession) stream(ctx context.Context, req *api.LogsRequest, fn func(*api.LogChunk) error) error {
+	requestId := uuid.NewString()
+	msgs := make(chan *api.AgentMessage, streamBuffer)
+
+	s.m.Lock()
+	if s.err != nil {
+		s.m.Unlock()
+		return s.err
+	}
+	s.streams[requestId] = msgs
+	s.m.Unlock()
+
+	finished := false
+	defer func() {
+		s.m.Lock()
+		delete(s.streams, requestId)
+		s.m.Unlock()
+
+		// Агент продолжил бы слать вывод, которого уже никто не ждёт
+		if !finished {
+			s.send(&api.ServerMessage{
+				RequestId: requestId,
+				Body:      &api.ServerMessage_Cancel{Cancel: &api.CancelRequest{}},
+			})
+		}
+	}()
+
+	err := s.send(&api.ServerMessage{
+		RequestId: requestId,
+		Body:      &api.ServerMessage_Logs{Logs: req},
+	})
+	if err != nil {
+		finished = true
+		return err
+	}
+
+	for {
+		select {
+		case msg, ok := <-msgs:
+			if !ok {
+				return ErrStreamOverflow
+			}
+
+			switch body := msg.Body.(type) {
+			case *api.AgentMessage_Logs:
+				if err := fn(body.Logs); err != nil {
+					return err
+				}
+			case *api.AgentMessage_Result:
+				finished = true
+				if !body.Result.Ok {
+					return errors.New(body.Result.Error)
+				}
+				return nil
+			}
+		case <-s.closed:
+			finished = true
+			return s.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *AgentSession) send(msg *api.ServerMessage) error {
+	s.sendM.Lock()
+	defer s.sendM.Unlock()
+
+	return s.conn.Send(msg)
+}
+
+func (s *AgentSession) dispatch(msg *api.AgentMessage) {
+	switch body := msg.Body.(type) {
+	case *api.AgentMessage_Ack:
+		log.Printf("agent %s acknowledged command %s", s.agentId, msg.RequestId)
+	case *api.AgentMessage_Logs:
+		s.forward(msg)
+	case *api.AgentMessage_Result:
+		s.m.Lock()
+		res, ok := s.pending[msg.RequestId]
+		s.m.Unlock()
+
+		if !ok && s.forward(msg) {
+			return
+		}
+
+		if !ok {
+			// Команда уже завершилась по таймауту
+			log.Printf("agent %s: result for unknown command %s", s.agentId, msg.RequestId)
+			return
+		}
+		select {
+		case res <- body.Result:
+		default:
+		}
+	default:
+		log.Printf("agent %s: unexpected message %T", s.agentId, msg.Body)
+	}
+}
+
+// Передаёт сообщение потоку с тем же request id. Получатель, который не успевает
+// за агентом, отключается, чтобы не задерживать остальные сообщения сессии
+func (s *AgentSession) forward(msg *api.AgentMessage) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	msgs, ok := s.streams[msg.RequestId]
+	if !ok {
+		return false
+	}
+
+	select {
+	case msgs <- msg:
+	default:
+		delete(s.streams, msg.RequestId)
+		close(msgs)
+	}
+	return true
+}
+
+func (s *AgentSession) close(err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.err != nil {
+		return
+	}
+	s.err = err
+	close(s.closed)
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/model/history"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+type agentRepository interface {
+	Get(id uuid.UUID) (*agent.Info, error)
+	GetAll() ([]*agent.Info, error)
+	List(s *repository.Schema[*agent.Info], q repository.ListQuery) (*repository.Page[*agent.Info], error)
+	Add(agent *agent.Info) error
+	Update(id uuid.UUID, agent *agent.Info) error
+	Delete(id uuid.UUID) error
+	GetHistory() ([]history.Log[*agent.Info], error)
+}
+
+// Агенты с публикацией событий о добавлении, изменении и удалении. Агент
+// обновляется каждым heartbeat, поэтому об изменении публикуется, только если
+// изменилась конфигурация. О смене статуса публикуют те, кто статус меняет
+type Agents struct {
+	r      agentRepository
+	events eventPublisher
+}
+
+func NewAgents(r agentRepository, events eventPublisher) *Agents {
+	return &Agents{r: r, events: events}
+}
+
+func (a *Agents) Get(id uuid.UUID) (*agent.Info, error) {
+	return a.r.Get(id)
+}
+
+func (a *Agents) GetAll() ([]*agent.Info, error) {
+	return a.r.GetAll()
+}
+
+func (a *Agents) List(q repository.ListQuery) (*repository.Page[*agent.Info], error) {
+	return a.r.List(repository.AgentSchema, q)
+}
+
+func (a *Agents) GetHistory() ([]history.Log[*agent.Info], error) {
+	return a.r.GetHistory()
+}
+
+func (a *Agents) Add(info *agent.Info) error {
+	if err := a.r.Add(info); err != nil {
+		return err
+	}
+
+	a.publish(event.AGENT_CREATED, info.AgentId)
+	return nil
+}
+
+func (a *Agents) Update(id uuid.UUID, info *agent.Info) error {
+	prev, err := a.r.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if err := a.r.Update(id, info); err != nil {
+		return err
+	}
+
+	if !prev.Config.Equal(info.Config) {
+		a.publish(event.AGENT_UPDATED, id)
+	}
+	return nil
+}
+
+func (a *Agents) Delete(id uuid.UUID) error {
+	if err := a.r.Delete(id); err != nil {
+		return err
+	}
+
+	a.publish(event.AGENT_DELETED, id)
+	return nil
+}
+
+func (a *Agents) publish(eventType string, id uuid.UUID) {
+	e := event.New(eventType, "")
+	e.AgentId = id
+	publishEvent(a.events, e)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/model/history"
+	"github.com/vv-sam/otus-project/server/internal/model/metrics"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+// Остальные методы agentRepository. Агенты хранятся по значению,
+// поэтому изменение полученного агента не меняет хранилище
+func (s *memAgentStore) Get(id uuid.UUID) (*agent.Info, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	a, ok := s.agents[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &a, nil
+}
+
+func (s *memAgentStore) List(schema *repository.Schema[*agent.Info], q repository.ListQuery) (*repository.Page[*agent.Info], error) {
+	return &repository.Page[*agent.Info]{}, nil
+}
+
+func (s *memAgentStore) Add(a *agent.Info) error {
+	s.put(*a)
+	return nil
+}
+
+func (s *memAgentStore) Update(id uuid.UUID, a *agent.Info) error {
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+	s.put(*a)
+	return nil
+}
+
+func (s *memAgentStore) Delete(id uuid.UUID) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.agents, id)
+	return nil
+}
+
+func (s *memAgentStore) GetHistory() ([]history.Log[*agent.Info], error) {
+	return nil, nil
+}
+
+func TestAgentsUpdatePublishesConfigChanges(t *testing.T) {
+	a := agent.Info{AgentId: uuid.New(), Status: agent.STATUS_ONLINE, Config: agent.Config{Location: "eu"}}
+	events := &fakeEvents{}
+	agents := NewAgents(newMemAgentStore(a), events)
+
+	updates := []struct {
+		name    string
+		change  func(a *agent.Info)
+		updated bool
+	}{
+		{"heartbeat", func(a *agent.Info) {
+			a.Status = agent.STATUS_DEGRADED
+			a.LastSeen = time.Now()
+			a.Metrics = metrics.HostMetrics{CpuUsage: 50}
+			a.Servers = []metrics.ServerUsage{{}}
+		}, false},
+		{"empty labels", func(a *agent.Info) { a.Config.Labels = map[string]string{} }, false},
+		{"labels", func(a *agent.Info) { a.Config.Labels = map[string]string{"gpu": "yes"} }, true},
+		{"location", func(a *agent.Info) { a.Config.Location = "us" }, true},
+		{"capacity", func(a *agent.Info) { a.Config.Capacity.MaxServers = 3 }, true},
+	}
+
+	for _, u := range updates {
+		cur, err := agents.Get(a.AgentId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u.change(cur)
+
+		before := len(events.published())
+		if err := agents.Update(a.AgentId, cur); err != nil {
+			t.Fatal(err)
+		}
+
+		published := events.published()[before:]
+		updated := len(published) == 1 && published[0].Type == event.AGENT_UPDATED && published[0].AgentId == a.AgentId
+		if updated != u.updated || (!u.updated && len(published) != 0) {
+			t.Errorf("%s: published %v", u.name, published)
+		}
+	}
+}
+
+func TestAgentsUpdateUnknown(t *testing.T) {
+	events := &fakeEvents{}
+	err := NewAgents(newMemAgentStore(), events).Update(uuid.New(), &agent.Info{})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+	if len(events.published()) != 0 {
+		t.Errorf("published %v", events.published())
+	}
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Срок действия корневого сертификата, создаваемого при первом запуске
+	caValidity = 10 * 365 * 24 * time.Hour
+
+	// Срок действия сертификата gRPC-сервера
+	serverCertValidity = 365 * 24 * time.Hour
+)
+
+// Удостоверяющий центр сервера: подписывает клиентские сертификаты агентов
+// и сертификат gRPC-сервера. Ключ и сертификат хранятся в PEM-файлах
+type CertificateAuthority struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     *ecdsa.PrivateKey
+}
+
+// Загружает CA из файлов, а если их нет - создаёт новый и сохраняет
+func LoadOrCreateCA(certPath, keyPath string) (*CertificateAuthority, error) {
+	certPEM, certErr := os.ReadFile(certPath)
+	keyPEM, keyErr := os.ReadFile(keyPath)
+
+	switch {
+	case certErr == nil && keyErr == nil:
+		return parseCA(certPEM, keyPEM)
+	case errors.Is(certErr, os.ErrNotExist) && errors.Is(keyErr, os.ErrNotExist):
+	case certErr != nil:
+		return nil, fmt.Errorf("failed to read ca certificate: %w", certErr)
+	default:
+		return nil, fmt.Errorf("failed to read ca key: %w", keyErr)
+	}
+
+	ca, keyPEM, err := newCA()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write ca key: %w", err)
+	}
+	if err := os.WriteFile(certPath, ca.certPEM, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write ca certificate: %w", err)
+	}
+	return ca, nil
+}
+
+func newCA() (*CertificateAuthority, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate ca key: %w", err)
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serialNumber(uuid.New()),
+		Subject:               pkix.Name{CommonName: "otus-project agents CA"},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create ca certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	ca, err := parseCA(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ca, keyPEM, nil
+}
+
+func parseCA(certPEM, keyPEM []byte) (*CertificateAuthority, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, errors.New("ca certificate is not in PEM format")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ca certificate: %w", err)
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, errors.New("ca key is not in PEM format")
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ca key: %w", err)
+	}
+
+	return &CertificateAuthority{cert: cert, certPEM: certPEM, key: key}, nil
+}
+
+// Корневой сертификат в PEM, его агенты используют для проверки сервера
+func (ca *CertificateAuthority) CertPEM() []byte {
+	return ca.certPEM
+}
+
+func (ca *CertificateAuthority) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// Подписывает CSR агента. В сертификат попадает id агента (CN) и serial как серийный номер
+func (ca *CertificateAuthority) SignAgent(csr *x509.CertificateRequest, agentId, serial uuid.UUID, notAfter time.Time) ([]byte, error) {
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid csr signature: %w", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serialNumber(serial),
+		Subject:      pkix.Name{CommonName: agentId.String()},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     earliest(notAfter, ca.cert.NotAfter),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+// Выпускает сертификат gRPC-сервера для указанных имён и адресов
+func (ca *CertificateAuthority) ServerCertificate(hosts []string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serialNumber(uuid.New()),
+		Subject:      pkix.Name{CommonName: "otus-project server"},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     earliest(now.Add(serverCertValidity), ca.cert.NotAfter),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to create server certificate: %w", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der, ca.cert.Raw}, PrivateKey: key}, nil
+}
+
+// Настройки TLS для gRPC-сервера: клиентский сертификат не обязателен,
+// чтобы агенты без сертификата могли пройти регистрацию по join-токену
+func (ca *CertificateAuthority) ServerTLSConfig(hosts []string) (*tls.Config, error) {
+	cert, err := ca.ServerCertificate(hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		ClientCAs:    ca.Pool(),
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// Сертификат не может пережить CA, которым он подписан
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// Серийный номер сертификата из uuid
+func serialNumber(id uuid.UUID) *big.Int {
+	return new(big.Int).SetBytes(id[:])
+}
+
+// uuid из серийного номера сертификата, выпущенного этим CA
+func SerialToUUID(serial *big.Int) (uuid.UUID, error) {
+	b := serial.Bytes()
+	if len(b) > 16 {
+		return uuid.Nil, errors.New("serial number is too long")
+	}
+
+	var id uuid.UUID
+	copy(id[16-len(b):], b)
+	return id, nil
+}
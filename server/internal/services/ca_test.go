@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bytes"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CA создаётся при первом запуске и загружается из тех же файлов при следующих
+func TestLoadOrCreateCA(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+
+	created, err := LoadOrCreateCA(certPath, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(keyPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("ca key: %v, %v", info, err)
+	}
+
+	loaded, err := LoadOrCreateCA(certPath, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(created.CertPEM(), loaded.CertPEM()) || !created.key.Equal(loaded.key) {
+		t.Error("loaded CA differs from the created one")
+	}
+
+	// Сертификат без ключа - ошибка, а не новый CA
+	if err := os.Remove(keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOrCreateCA(certPath, keyPath); err == nil {
+		t.Error("CA without a key was accepted")
+	}
+}
+
+func TestCAServerCertificate(t *testing.T) {
+	ca, _, err := newCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := ca.ServerCertificate([]string{"localhost", "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, host := range []string{"localhost", "127.0.0.1"} {
+		_, err := leaf.Verify(x509.VerifyOptions{Roots: ca.Pool(), DNSName: host})
+		if err != nil {
+			t.Errorf("%s: %v", host, err)
+		}
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: ca.Pool(), DNSName: "example.com"}); err == nil {
+		t.Error("certificate is valid for a host it was not issued for")
+	}
+}
+
+// Сертификат агента не переживает CA
+func TestCASignAgentLimitsExpiry(t *testing.T) {
+	ca, _, err := newCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csr, err := x509.ParseCertificateRequest(mustDecodePEM(t, testCSR(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, err := ca.SignAgent(csr, uuid.New(), uuid.New(), ca.cert.NotAfter.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cert := parseTestCert(t, certPEM); !cert.NotAfter.Equal(ca.cert.NotAfter) {
+		t.Errorf("expires at %v, CA expires at %v", cert.NotAfter, ca.cert.NotAfter)
+	}
+}
+
+func TestSerialToUUID(t *testing.T) {
+	ids := []uuid.UUID{
+		uuid.New(),
+		uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		uuid.MustParse("0000ffff-0000-0000-0000-000000000000"),
+	}
+	for _, id := range ids {
+		if got, err := SerialToUUID(serialNumber(id)); err != nil || got != id {
+			t.Errorf("%s: got %s, %v", id, got, err)
+		}
+	}
+
+	long := serialNumber(uuid.New())
+	long.Lsh(long, 64)
+	if _, err := SerialToUUID(long); err == nil {
+		t.Error("serial longer than uuid was accepted")
+	}
+}
@@ -0,0 +1,416 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/model/history"
+	"github.com/vv-sam/otus-project/server/internal/model/placement"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"google.golang.org/protobuf/proto"
+)
+
+const updateResourcesTimeout = 10 * time.Second
+
+var (
+	ErrUnknownAgent   = errors.New("agent not found")
+	ErrPortConflict   = errors.New("port is already in use")
+	ErrPortOutOfRange = errors.New("port is out of the agent's port range")
+	ErrPortsExhausted = errors.New("no free ports left in the agent's port range")
+	ErrDuplicatePort  = errors.New("port is used twice in the configuration")
+
+	ErrExceedsCapacity = errors.New("resources exceed the agent's capacity")
+)
+
+type configurationStore interface {
+	Get(id uuid.UUID) (*configuration.Envelope, error)
+	GetAll() ([]*configuration.Envelope, error)
+	List(s *repository.Schema[*configuration.Envelope], q repository.ListQuery) (*repository.Page[*configuration.Envelope], error)
+	Add(configuration *configuration.Envelope) error
+	Update(id uuid.UUID, configuration *configuration.Envelope) error
+	Delete(id uuid.UUID) error
+	GetHistory() ([]history.Log[*configuration.Envelope], error)
+}
+
+type agentGetter interface {
+	Get(id uuid.UUID) (*agent.Info, error)
+}
+
+type placer interface {
+	Place(conf configuration.Configuration) (*placement.Decision, error)
+}
+
+type commandSender interface {
+	Send(ctx context.Context, agentId uuid.UUID, cmd *api.Command, timeout time.Duration) (*api.CommandResult, error)
+}
+
+type portRegistry interface {
+	Reserve(agentId, configurationId uuid.UUID, req repository.PortRequest) (*repository.PortReservation, error)
+	Release(agentId, configurationId uuid.UUID) error
+}
+
+// Конфигурации игровых серверов с учётом портов на агентах. Реализует интерфейс
+// репозитория конфигураций: при сохранении конфигурации, назначенной агенту,
+// её порты резервируются в диапазоне агента, пустые порты назначаются
+// автоматически, при удалении порты освобождаются. Конфигурацию без агента,
+// но с ограничениями размещения, размещает планировщик. Лимиты ресурсов
+// проверяются по capacity агента, изменённые лимиты сразу применяются к
+// запущенному серверу
+type Configurations struct {
+	m         sync.Mutex
+	r         configurationStore
+	agents    agentGetter
+	ports     portRegistry
+	scheduler placer
+	sessions  commandSender
+	events    eventPublisher
+}
+
+func NewConfigurations(r configurationStore, agents agentGetter, ports portRegistry, scheduler placer, sessions commandSender, events eventPublisher) *Configurations {
+	return &Configurations{r: r, agents: agents, ports: ports, scheduler: scheduler, sessions: sessions, events: events}
+}
+
+func (c *Configurations) Get(id uuid.UUID) (*configuration.Envelope, error) {
+	return c.r.Get(id)
+}
+
+func (c *Configurations) GetAll() ([]*configuration.Envelope, error) {
+	return c.r.GetAll()
+}
+
+func (c *Configurations) List(q repository.ListQuery) (*repository.Page[*configuration.Envelope], error) {
+	return c.r.List(repository.ConfigurationSchema, q)
+}
+
+func (c *Configurations) GetHistory() ([]history.Log[*configuration.Envelope], error) {
+	return c.r.GetHistory()
+}
+
+func (c *Configurations) Add(conf *configuration.Envelope) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if err := configuration.KeepSecrets(conf.Configuration, nil); err != nil {
+		return err
+	}
+
+	if err := c.place(conf); err != nil {
+		return err
+	}
+
+	agentId := conf.GetBase().AgentId
+	if err := c.reserve(agentId, conf); err != nil {
+		return err
+	}
+
+	if err := c.r.Add(conf); err != nil {
+		c.release(agentId, conf.GetId())
+		return err
+	}
+
+	c.publish(event.CONFIGURATION_CREATED, conf)
+	return nil
+}
+
+func (c *Configurations) Update(id uuid.UUID, conf *configuration.Envelope) error {
+	old, err := c.update(id, conf)
+	if err != nil {
+		return err
+	}
+	c.publish(event.CONFIGURATION_UPDATED, conf)
+
+	// Сервер на том же агенте получает новые лимиты без пересоздания
+	base := conf.GetBase()
+	if base.AgentId == old.GetBase().AgentId && !reflect.DeepEqual(base.Resources, old.GetBase().Resources) {
+		c.applyResources(base.AgentId, id, base.Resources)
+	}
+	return nil
+}
+
+func (c *Configurations) update(id uuid.UUID, conf *configuration.Envelope) (*configuration.Envelope, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	old, err := c.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := configuration.KeepSecrets(conf.Configuration, old.Configuration); err != nil {
+		return nil, err
+	}
+
+	oldAgent := old.GetBase().AgentId
+
+	// Размещённый сервер не переезжает при каждом обновлении
+	if conf.GetBase().AgentId == uuid.Nil && conf.GetBase().Placement != nil && oldAgent != uuid.Nil {
+		conf.GetBase().AgentId = oldAgent
+	}
+	if err := c.place(conf); err != nil {
+		return nil, err
+	}
+	newAgent := conf.GetBase().AgentId
+
+	// На том же агенте незаданные порты остаются прежними
+	if oldAgent == newAgent {
+		keepPorts(conf.Configuration, old.Configuration)
+	}
+
+	if err := c.reserve(newAgent, conf); err != nil {
+		return nil, err
+	}
+
+	if err := c.r.Update(id, conf); err != nil {
+		// Возвращаем резервирование к сохранённой конфигурации
+		if oldAgent != newAgent {
+			c.release(newAgent, id)
+		}
+		if err := c.reserve(oldAgent, old); err != nil {
+			log.Printf("configurations: failed to restore ports of %s: %v", id, err)
+		}
+		return nil, err
+	}
+
+	if oldAgent != newAgent {
+		c.release(oldAgent, id)
+	}
+	return old, nil
+}
+
+func (c *Configurations) Delete(id uuid.UUID) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	old, err := c.get(id)
+	if err != nil {
+		return err
+	}
+
+	if err := c.r.Delete(id); err != nil {
+		return err
+	}
+
+	c.release(old.GetBase().AgentId, id)
+	c.publish(event.CONFIGURATION_DELETED, old)
+	return nil
+}
+
+func (c *Configurations) publish(eventType string, conf *configuration.Envelope) {
+	e := event.New(eventType, "")
+	e.ConfigurationId = conf.GetId()
+	e.AgentId = conf.GetBase().AgentId
+	publishEvent(c.events, e)
+}
+
+// Выбирает агента для конфигурации с ограничениями размещения и без agent_id
+func (c *Configurations) place(conf *configuration.Envelope) error {
+	base := conf.GetBase()
+	if base.AgentId != uuid.Nil || base.Placement == nil {
+		return nil
+	}
+
+	decision, err := c.scheduler.Place(conf.Configuration)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("configurations: %s placed on %s", conf.GetId(), decision)
+	base.AgentId = decision.AgentId
+	return nil
+}
+
+// Оба репозитория сообщают об отсутствии по-разному
+func (c *Configurations) get(id uuid.UUID) (*configuration.Envelope, error) {
+	conf, err := c.r.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if conf == nil || conf.Configuration == nil {
+		return nil, repository.ErrNotFound
+	}
+	return conf, nil
+}
+
+// Резервирует порты конфигурации на агенте и записывает назначенные порты в неё
+func (c *Configurations) reserve(agentId uuid.UUID, conf *configuration.Envelope) error {
+	if agentId == uuid.Nil || conf.Configuration == nil {
+		return nil
+	}
+
+	a, err := c.agents.Get(agentId)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("failed to get agent: %w", err)
+	}
+	if a == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownAgent, agentId)
+	}
+
+	if err := c.checkCapacity(a, conf); err != nil {
+		return err
+	}
+
+	rng := a.Config.PortRange
+	slots := conf.Ports()
+
+	req := repository.PortRequest{Min: rng.Min, Max: rng.Max}
+	seen := make(map[uint16]string, len(slots))
+	var auto []configuration.PortSlot
+	for _, s := range slots {
+		port := *s.Port
+		if port == 0 {
+			// Без диапазона выбирать не из чего, сервер займёт порт игры по умолчанию
+			if rng.Max == 0 {
+				port = s.Default
+				*s.Port = port
+			} else {
+				auto = append(auto, s)
+				continue
+			}
+		} else if rng.Max != 0 && (port < rng.Min || port > rng.Max) {
+			return fmt.Errorf("%w: %s %d is not in %d-%d", ErrPortOutOfRange, s.Name, port, rng.Min, rng.Max)
+		}
+
+		if other, ok := seen[port]; ok {
+			return fmt.Errorf("%w: %s and %s are both %d", ErrDuplicatePort, other, s.Name, port)
+		}
+		seen[port] = s.Name
+		req.Ports = append(req.Ports, port)
+	}
+	req.Auto = len(auto)
+
+	res, err := c.ports.Reserve(agentId, conf.GetId(), req)
+	if err != nil {
+		return fmt.Errorf("failed to reserve ports: %w", err)
+	}
+
+	switch res.Result {
+	case repository.PORTS_CONFLICT:
+		return fmt.Errorf("%w: port %d on agent %s is used by configuration %s", ErrPortConflict, res.Port, agentId, res.Owner)
+	case repository.PORTS_EXHAUSTED:
+		return fmt.Errorf("%w: %d-%d on agent %s", ErrPortsExhausted, rng.Min, rng.Max, agentId)
+	}
+
+	for i, s := range auto {
+		*s.Port = res.Assigned[i]
+	}
+	return nil
+}
+
+// Проверяет, что сервер помещается на агента: число серверов не превышает
+// max_servers, а лимиты укладываются в capacity вместе с ресурсами, уже
+// обещанными остальным серверам на нём. CPU может перераспределяться
+// между серверами, поэтому квота одного сервера сравнивается только с числом ядер
+func (c *Configurations) checkCapacity(a *agent.Info, conf *configuration.Envelope) error {
+	capacity := a.Config.Capacity
+	memoryBytes, cpuCores, diskBytes := conf.GetBase().Reserved()
+
+	if capacity.CpuCores > 0 && cpuCores > capacity.CpuCores {
+		return fmt.Errorf("%w: %.2f cpu cores requested, agent %s has %.2f", ErrExceedsCapacity, cpuCores, a.AgentId, capacity.CpuCores)
+	}
+
+	checkMemory := capacity.MemoryBytes > 0 && memoryBytes > 0
+	checkDisk := capacity.DiskBytes > 0 && diskBytes > 0
+	if capacity.MaxServers == 0 && !checkMemory && !checkDisk {
+		return nil
+	}
+
+	confs, err := c.r.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get configurations: %w", err)
+	}
+
+	var servers uint32
+	var usedMemory, usedDisk uint64
+	for _, other := range confs {
+		if other.Configuration == nil || other.GetId() == conf.GetId() || other.GetBase().AgentId != a.AgentId {
+			continue
+		}
+		m, _, d := other.GetBase().Reserved()
+		servers++
+		usedMemory += m
+		usedDisk += d
+	}
+
+	if capacity.MaxServers > 0 && servers >= capacity.MaxServers {
+		return fmt.Errorf("%w: agent %s already runs %d of %d servers", ErrExceedsCapacity, a.AgentId, servers, capacity.MaxServers)
+	}
+	if checkMemory && usedMemory+memoryBytes > capacity.MemoryBytes {
+		return fmt.Errorf("%w: %d MiB memory requested, %d MiB of %d MiB on agent %s already reserved",
+			ErrExceedsCapacity, memoryBytes>>20, usedMemory>>20, capacity.MemoryBytes>>20, a.AgentId)
+	}
+	if checkDisk && usedDisk+diskBytes > capacity.DiskBytes {
+		return fmt.Errorf("%w: %d MiB disk requested, %d MiB of %d MiB on agent %s already reserved",
+			ErrExceedsCapacity, diskBytes>>20, usedDisk>>20, capacity.DiskBytes>>20, a.AgentId)
+	}
+	return nil
+}
+
+// Применяет лимиты к запущенному серверу. Если агент не подключён или сервер
+// ещё не развёрнут, лимиты применятся при следующем развёртывании
+func (c *Configurations) applyResources(agentId, configurationId uuid.UUID, r *configuration.Resources) {
+	if agentId == uuid.Nil {
+		return
+	}
+
+	res := &api.Resources{}
+	if r != nil {
+		res = &api.Resources{CpuCores: r.CpuCores, CpuShares: r.CpuShares, MemoryBytes: r.MemoryBytes, DiskBytes: r.DiskBytes}
+	}
+	payload, err := proto.Marshal(res)
+	if err != nil {
+		log.Printf("configurations: failed to marshal resources of %s: %v", configurationId, err)
+		return
+	}
+
+	out, err := c.sessions.Send(context.Background(), agentId, &api.Command{
+		Type:            api.CommandType_COMMAND_TYPE_UPDATE_RESOURCES,
+		ConfigurationId: configurationId.String(),
+		Payload:         payload,
+	}, updateResourcesTimeout)
+	switch {
+	case errors.Is(err, ErrAgentNotConnected):
+	case err != nil:
+		log.Printf("configurations: failed to update resources of %s: %v", configurationId, err)
+	case !out.Ok:
+		log.Printf("configurations: agent %s did not update resources of %s: %s", agentId, configurationId, out.Error)
+	default:
+		log.Printf("configurations: resources of %s updated", configurationId)
+	}
+}
+
+func (c *Configurations) release(agentId, configurationId uuid.UUID) {
+	if agentId == uuid.Nil {
+		return
+	}
+
+	if err := c.ports.Release(agentId, configurationId); err != nil {
+		log.Printf("configurations: failed to release ports of %s on agent %s: %v", configurationId, agentId, err)
+	}
+}
+
+// Переносит в conf порты из old для полей, которые в conf не заданы
+func keepPorts(conf, old configuration.Configuration) {
+	if conf == nil || old == nil {
+		return
+	}
+
+	prev := make(map[string]uint16)
+	for _, s := range old.Ports() {
+		prev[s.Name] = *s.Port
+	}
+	for _, s := range conf.Ports() {
+		if *s.Port == 0 {
+			*s.Port = prev[s.Name]
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+func testConf(agentId uuid.UUID, res *configuration.Resources) *configuration.Envelope {
+	c := &configuration.Minecraft{}
+	c.Id = uuid.New()
+	c.AgentId = agentId
+	c.Resources = res
+	c.Type = configuration.CONFIGURATION_TYPE_MINECRAFT
+	return &configuration.Envelope{Configuration: c}
+}
+
+func TestCheckCapacity(t *testing.T) {
+	a := &agent.Info{AgentId: uuid.New()}
+	other := uuid.New()
+
+	running := []*configuration.Envelope{
+		testConf(a.AgentId, &configuration.Resources{MemoryBytes: 2 << 30, DiskBytes: 10 << 30}),
+		testConf(a.AgentId, nil),
+		testConf(other, &configuration.Resources{MemoryBytes: 8 << 30}),
+	}
+
+	tests := []struct {
+		name     string
+		capacity agent.Capacity
+		conf     *configuration.Envelope
+		fail     bool
+	}{
+		{"no limits", agent.Capacity{}, testConf(a.AgentId, &configuration.Resources{MemoryBytes: 64 << 30}), false},
+		{"servers fit", agent.Capacity{MaxServers: 3}, testConf(a.AgentId, nil), false},
+		{"too many servers", agent.Capacity{MaxServers: 2}, testConf(a.AgentId, nil), true},
+		{"updated server is not counted twice", agent.Capacity{MaxServers: 2}, running[1], false},
+		{"too many cpu cores", agent.Capacity{CpuCores: 2}, testConf(a.AgentId, &configuration.Resources{CpuCores: 2.5}), true},
+		{"memory fits", agent.Capacity{MemoryBytes: 4 << 30}, testConf(a.AgentId, &configuration.Resources{MemoryBytes: 2 << 30}), false},
+		{"memory exceeded", agent.Capacity{MemoryBytes: 4 << 30}, testConf(a.AgentId, &configuration.Resources{MemoryBytes: 3 << 30}), true},
+		{"disk exceeded", agent.Capacity{DiskBytes: 16 << 30}, testConf(a.AgentId, &configuration.Resources{DiskBytes: 8 << 30}), true},
+		{"unlimited server", agent.Capacity{MemoryBytes: 1 << 30, DiskBytes: 1 << 30}, testConf(a.AgentId, nil), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repository.NewJsonRepository[*configuration.Envelope](t.TempDir(), "configurations")
+			for _, c := range running {
+				if err := store.Add(c); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			a.Config.Capacity = tt.capacity
+			c := NewConfigurations(store, nil, nil, nil, nil, nil)
+
+			err := c.checkCapacity(a, tt.conf)
+			if tt.fail && !errors.Is(err, ErrExceedsCapacity) {
+				t.Errorf("got %v, want ErrExceedsCapacity", err)
+			}
+			if !tt.fail && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrConsoleUnsupported   = errors.New("game does not support remote console")
+	ErrConsoleNotConfigured = errors.New("remote console is not configured, deploy the server first")
+	ErrCommandFailed        = errors.New("command failed")
+)
+
+// Удалённая консоль игровых серверов. Команда передаётся агенту, на котором
+// развёрнут сервер, и выполняется по RCON с хоста агента
+type Console struct {
+	sessions       *AgentSessions
+	configurations configurationGetter
+}
+
+func NewConsole(sessions *AgentSessions, configurations configurationGetter) *Console {
+	return &Console{sessions: sessions, configurations: configurations}
+}
+
+// Выполняет команду консоли и возвращает её вывод
+func (c *Console) Run(ctx context.Context, actor string, serverId uuid.UUID, command string) (string, error) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return "", errors.New("command is required")
+	}
+
+	conf, err := c.configurations.Get(serverId)
+	if err != nil {
+		return "", err
+	}
+	if conf == nil {
+		return "", repository.ErrNotFound
+	}
+
+	agentId := conf.GetBase().AgentId
+	if agentId == uuid.Nil {
+		return "", ErrServerNotAssigned
+	}
+
+	rcon, ok := conf.Configuration.(configuration.RconEnabled)
+	if !ok {
+		return "", ErrConsoleUnsupported
+	}
+
+	port, password := rcon.Rcon()
+	if password == "" {
+		return "", ErrConsoleNotConfigured
+	}
+
+	payload, err := proto.Marshal(&api.RconCommand{Port: uint32(port), Password: password, Command: command})
+	if err != nil {
+		return "", err
+	}
+
+	// Команды меняют состояние игры, поэтому каждая попадает в журнал
+	log.Printf("console: %s runs %q on server %s", actor, command, serverId)
+
+	res, err := c.sessions.Send(ctx, agentId, &api.Command{
+		Type:            api.CommandType_COMMAND_TYPE_CONSOLE,
+		ConfigurationId: serverId.String(),
+		Payload:         payload,
+	}, 0)
+	if err != nil {
+		return "", err
+	}
+
+	if !res.Ok {
+		return string(res.Output), fmt.Errorf("%w: %s", ErrCommandFailed, res.Error)
+	}
+	return string(res.Output), nil
+}
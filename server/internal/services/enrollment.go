@@ -0,0 +1,220 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+const (
+	// Срок действия join-токена, если администратор не указал свой
+	DefaultJoinTokenTTL = time.Hour
+
+	// Срок действия клиентского сертификата агента
+	DefaultAgentCertTTL = 365 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidJoinToken   = errors.New("join token is invalid, expired or already used")
+	ErrInvalidCSR         = errors.New("invalid certificate signing request")
+	ErrCertificateRevoked = errors.New("certificate is revoked or expired")
+	ErrUnknownCertificate = errors.New("certificate is not issued by this server")
+)
+
+type joinTokenStore interface {
+	Add(hash string, t *agent.JoinToken) error
+	Take(hash string) (*agent.JoinToken, error)
+}
+
+type certificateStore interface {
+	Get(id uuid.UUID) (*agent.Certificate, error)
+	GetAll() ([]*agent.Certificate, error)
+	Add(c *agent.Certificate) error
+	Update(id uuid.UUID, c *agent.Certificate) error
+}
+
+// Выдача агентам клиентских сертификатов. Администратор создаёт одноразовый
+// join-токен, агент обменивает его на сертификат, подписанный CA сервера,
+// и дальше входит по mTLS. Каждый сертификат привязан к одному id агента
+type Enrollment struct {
+	ca      *CertificateAuthority
+	tokens  joinTokenStore
+	certs   certificateStore
+	certTTL time.Duration
+}
+
+func NewEnrollment(ca *CertificateAuthority, tokens joinTokenStore, certs certificateStore, certTTL time.Duration) *Enrollment {
+	if certTTL <= 0 {
+		certTTL = DefaultAgentCertTTL
+	}
+
+	return &Enrollment{ca: ca, tokens: tokens, certs: certs, certTTL: certTTL}
+}
+
+// Создаёт join-токен. Токен возвращается только здесь, сервер хранит лишь его хеш
+func (e *Enrollment) CreateJoinToken(actor string, agentId uuid.UUID, ttl time.Duration) (string, *agent.JoinToken, error) {
+	if ttl <= 0 {
+		ttl = DefaultJoinTokenTTL
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	now := time.Now()
+	t := &agent.JoinToken{
+		CreatedBy: actor,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+		AgentId:   agentId,
+	}
+	if err := e.tokens.Add(hashToken(token), t); err != nil {
+		return "", nil, err
+	}
+
+	return token, t, nil
+}
+
+// Обменивает join-токен на сертификат для CSR агента в PEM.
+// Возвращает запись о выданном сертификате и сам сертификат в PEM
+func (e *Enrollment) Enroll(token string, csrPEM []byte) (*agent.Certificate, []byte, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, nil, ErrInvalidCSR
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+
+	// CSR проверяется до токена, чтобы опечатка агента не сожгла одноразовый токен
+	t, err := e.tokens.Take(hashToken(token))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, ErrInvalidJoinToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if !now.Before(t.ExpiresAt) {
+		return nil, nil, ErrInvalidJoinToken
+	}
+
+	agentId := t.AgentId
+	if agentId == uuid.Nil {
+		agentId = uuid.New()
+	}
+
+	c := &agent.Certificate{
+		Id:        uuid.New(),
+		AgentId:   agentId,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(e.certTTL),
+	}
+
+	certPEM, err := e.ca.SignAgent(csr, agentId, c.Id, c.ExpiresAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := e.certs.Add(c); err != nil {
+		return nil, nil, fmt.Errorf("failed to save certificate: %w", err)
+	}
+
+	log.Printf("enrollment: issued certificate %s for agent %s (token by %s)", c.Id, agentId, t.CreatedBy)
+	return c, certPEM, nil
+}
+
+// Корневой сертификат CA в PEM
+func (e *Enrollment) CACertificate() []byte {
+	return e.ca.CertPEM()
+}
+
+// Сертификаты, выданные агенту
+func (e *Enrollment) Certificates(agentId uuid.UUID) ([]*agent.Certificate, error) {
+	all, err := e.certs.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*agent.Certificate
+	for _, c := range all {
+		if c.AgentId == agentId {
+			certs = append(certs, c)
+		}
+	}
+	return certs, nil
+}
+
+// Отзывает сертификат агента. Отозванный сертификат перестаёт приниматься сразу
+func (e *Enrollment) Revoke(agentId, id uuid.UUID, actor string) error {
+	c, err := e.certs.Get(id)
+	if err != nil {
+		return err
+	}
+	if c == nil || c.AgentId != agentId {
+		return repository.ErrNotFound
+	}
+
+	if c.IsRevoked() {
+		return nil
+	}
+
+	c.RevokedAt = time.Now()
+	c.RevokedBy = actor
+	if err := e.certs.Update(id, c); err != nil {
+		return err
+	}
+
+	log.Printf("enrollment: certificate %s of agent %s revoked by %s", id, agentId, actor)
+	return nil
+}
+
+// Проверяет клиентский сертификат, цепочку которого уже проверил TLS,
+// и возвращает id агента, которому он выдан
+func (e *Enrollment) Verify(cert *x509.Certificate) (uuid.UUID, error) {
+	id, err := SerialToUUID(cert.SerialNumber)
+	if err != nil {
+		return uuid.Nil, ErrUnknownCertificate
+	}
+
+	c, err := e.certs.Get(id)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && c == nil) {
+		return uuid.Nil, ErrUnknownCertificate
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if c.AgentId.String() != cert.Subject.CommonName {
+		return uuid.Nil, ErrUnknownCertificate
+	}
+
+	if !c.IsValid(time.Now()) {
+		return uuid.Nil, ErrCertificateRevoked
+	}
+
+	return c.AgentId, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+// Join-токены в памяти. В отличие от Redis, не удаляет истёкшие токены сам
+type memJoinTokens struct {
+	m      sync.Mutex
+	tokens map[string]*agent.JoinToken
+}
+
+func (s *memJoinTokens) Add(hash string, t *agent.JoinToken) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.tokens[hash] = t
+	return nil
+}
+
+func (s *memJoinTokens) Take(hash string) (*agent.JoinToken, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	t, ok := s.tokens[hash]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	delete(s.tokens, hash)
+	return t, nil
+}
+
+type testEnrollment struct {
+	*Enrollment
+	ca     *CertificateAuthority
+	tokens *memJoinTokens
+	certs  *repository.JsonRepository[*agent.Certificate]
+}
+
+func newTestEnrollment(t *testing.T) testEnrollment {
+	t.Helper()
+
+	ca, _, err := newCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := &memJoinTokens{tokens: map[string]*agent.JoinToken{}}
+	certs := repository.NewJsonRepository[*agent.Certificate](t.TempDir(), "certificates")
+	return testEnrollment{NewEnrollment(ca, tokens, certs, 0), ca, tokens, certs}
+}
+
+func testCSR(t *testing.T) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "agent"}}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func parseTestCert(t *testing.T, certPEM []byte) *x509.Certificate {
+	t.Helper()
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		t.Fatalf("certificate is not in PEM format: %s", certPEM)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// Выдаёт агенту agentId сертификат по новому join-токену
+func enrollTestAgent(t *testing.T, e testEnrollment, agentId uuid.UUID) (*agent.Certificate, *x509.Certificate) {
+	t.Helper()
+
+	token, _, err := e.CreateJoinToken("admin", agentId, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, certPEM, err := e.Enroll(token, testCSR(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, parseTestCert(t, certPEM)
+}
+
+func TestEnrollIssuesCertificate(t *testing.T) {
+	e := newTestEnrollment(t)
+	agentId := uuid.New()
+
+	c, cert := enrollTestAgent(t, e, agentId)
+	if c.AgentId != agentId || cert.Subject.CommonName != agentId.String() {
+		t.Errorf("issued for %s with CN %q, want %s", c.AgentId, cert.Subject.CommonName, agentId)
+	}
+	if serial, _ := SerialToUUID(cert.SerialNumber); serial != c.Id {
+		t.Errorf("serial %s, want certificate id %s", serial, c.Id)
+	}
+
+	_, err := cert.Verify(x509.VerifyOptions{Roots: e.ca.Pool(), KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	if err != nil {
+		t.Errorf("certificate is not signed by the CA: %v", err)
+	}
+
+	if id, err := e.Verify(cert); err != nil || id != agentId {
+		t.Errorf("Verify: %s, %v", id, err)
+	}
+
+	certs, err := e.Certificates(agentId)
+	if err != nil || len(certs) != 1 || certs[0].Id != c.Id {
+		t.Errorf("Certificates: %v, %v", certs, err)
+	}
+}
+
+// Токен без агента выдаёт сертификат новому агенту
+func TestEnrollNewAgent(t *testing.T) {
+	e := newTestEnrollment(t)
+
+	c, cert := enrollTestAgent(t, e, uuid.Nil)
+	if c.AgentId == uuid.Nil || cert.Subject.CommonName != c.AgentId.String() {
+		t.Errorf("issued for %s with CN %q", c.AgentId, cert.Subject.CommonName)
+	}
+}
+
+func TestEnrollJoinTokenIsOneTime(t *testing.T) {
+	e := newTestEnrollment(t)
+
+	token, _, err := e.CreateJoinToken("admin", uuid.New(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Неверный CSR не расходует токен
+	if _, _, err := e.Enroll(token, []byte("not a csr")); !errors.Is(err, ErrInvalidCSR) {
+		t.Fatalf("invalid csr: %v", err)
+	}
+
+	if _, _, err := e.Enroll(token, testCSR(t)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := e.Enroll(token, testCSR(t)); !errors.Is(err, ErrInvalidJoinToken) {
+		t.Errorf("second use: %v, want ErrInvalidJoinToken", err)
+	}
+	if _, _, err := e.Enroll("unknown", testCSR(t)); !errors.Is(err, ErrInvalidJoinToken) {
+		t.Errorf("unknown token: %v, want ErrInvalidJoinToken", err)
+	}
+}
+
+func TestEnrollExpiredJoinToken(t *testing.T) {
+	e := newTestEnrollment(t)
+
+	token, jt, err := e.CreateJoinToken("admin", uuid.New(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Хранилище держит тот же токен, а Redis удалил бы истёкший сам
+	jt.ExpiresAt = time.Now().Add(-time.Second)
+
+	if _, _, err := e.Enroll(token, testCSR(t)); !errors.Is(err, ErrInvalidJoinToken) {
+		t.Errorf("got %v, want ErrInvalidJoinToken", err)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	e := newTestEnrollment(t)
+	agentId := uuid.New()
+
+	revoked, revokedCert := enrollTestAgent(t, e, agentId)
+	if err := e.Revoke(uuid.New(), revoked.Id, "admin"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("revoke certificate of another agent: %v", err)
+	}
+	if err := e.Revoke(agentId, revoked.Id, "admin"); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, expiredCert := enrollTestAgent(t, e, agentId)
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	if err := e.certs.Update(expired.Id, expired); err != nil {
+		t.Fatal(err)
+	}
+
+	// Сертификат с подписью того же CA, но не выданный через enrollment
+	foreign, err := x509.ParseCertificateRequest(mustDecodePEM(t, testCSR(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreignPEM, err := e.ca.SignAgent(foreign, agentId, uuid.New(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Запись о сертификате другого агента с тем же серийным номером
+	_, valid := enrollTestAgent(t, e, agentId)
+	stolen := *valid
+	stolen.Subject.CommonName = uuid.New().String()
+
+	cases := []struct {
+		name string
+		cert *x509.Certificate
+		err  error
+	}{
+		{"revoked", revokedCert, ErrCertificateRevoked},
+		{"expired", expiredCert, ErrCertificateRevoked},
+		{"unknown", parseTestCert(t, foreignPEM), ErrUnknownCertificate},
+		{"another agent", &stolen, ErrUnknownCertificate},
+	}
+	for _, tc := range cases {
+		if _, err := e.Verify(tc.cert); !errors.Is(err, tc.err) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
+
+func mustDecodePEM(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatalf("not in PEM format: %s", data)
+	}
+	return block.Bytes
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+)
+
+const (
+	// Как часто подписчику без новых событий отправляется keepalive
+	EventKeepAlive = 15 * time.Second
+
+	// Сколько событий подписчик может не успеть забрать, прежде чем его отключат
+	eventBufferSize = 256
+
+	// Сколько событий читается из журнала за раз
+	eventReadCount = 100
+
+	// Пауза после ошибки чтения журнала
+	eventRetryDelay = time.Second
+)
+
+var (
+	ErrInvalidEventSeq   = errors.New("invalid event id")
+	ErrUnknownEventType  = errors.New("unknown event type")
+	ErrConsumerRequired  = errors.New("consumer is required for a consumer group")
+	ErrSubscriberTooSlow = errors.New("subscriber is too slow, resume from the last received event")
+	ErrEventsExpired     = errors.New("events after this id are no longer kept")
+)
+
+type eventStream interface {
+	Publish(e *event.Event) error
+	Oldest(ctx context.Context) (string, error)
+	Last(ctx context.Context) (string, error)
+	Range(ctx context.Context, after string, count int64) ([]*event.Event, error)
+	Read(ctx context.Context, after string, count int64, block time.Duration) ([]*event.Event, error)
+	CreateGroup(ctx context.Context, group string) error
+	ReadGroup(ctx context.Context, group, consumer string, pending bool, count int64, block time.Duration) ([]*event.Event, error)
+	Ack(ctx context.Context, group string, seqs ...string) error
+}
+
+// Подписка на события
+type EventsQuery struct {
+	Types    []string // Типы или группы типов (agent, task...), пустой - все события
+	After    string   // Позиция последнего полученного события. Пустая - только новые события
+	Group    string   // Группа потребителей: события делятся между её потребителями, позицию помнит Redis
+	Consumer string   // Имя потребителя в группе
+}
+
+func (q EventsQuery) Validate() error {
+	for _, t := range q.Types {
+		if !event.IsKnown(t) {
+			return fmt.Errorf("%w: %s", ErrUnknownEventType, t)
+		}
+	}
+
+	if q.After != "" {
+		if _, _, err := parseEventSeq(q.After); err != nil {
+			return err
+		}
+	}
+
+	if q.Group != "" && q.Consumer == "" {
+		return ErrConsumerRequired
+	}
+
+	return nil
+}
+
+func (q EventsQuery) matches(e *event.Event) bool {
+	// Событие вытеснено из журнала раньше, чем группа его подтвердила
+	if e.Type == "" {
+		return false
+	}
+
+	return len(q.Types) == 0 || slices.ContainsFunc(q.Types, func(t string) bool {
+		return event.Matches(t, e.Type)
+	})
+}
+
+// Шина событий предметной области. События пишутся в журнал Redis, один
+// читатель на процесс раздаёт новые события подписчикам, а пропущенные
+// подписчик дочитывает из журнала сам
+type Events struct {
+	s           eventStream
+	m           sync.Mutex
+	subscribers map[*subscriber]struct{}
+	keepAlive   time.Duration
+}
+
+type subscriber struct {
+	ch chan *event.Event
+}
+
+func NewEvents(s eventStream) *Events {
+	return &Events{
+		s:           s,
+		subscribers: make(map[*subscriber]struct{}),
+		keepAlive:   EventKeepAlive,
+	}
+}
+
+func (b *Events) Publish(e *event.Event) error {
+	return b.s.Publish(e)
+}
+
+// Позиция последнего опубликованного события: подписка после неё
+// получит все изменения, сделанные после вызова
+func (b *Events) Revision(ctx context.Context) (string, error) {
+	return b.s.Last(ctx)
+}
+
+// Проверяет, что события после позиции ещё хранятся в журнале. Если журнал
+// уже вытеснял события, позиция перед самым старым тоже считается устаревшей:
+// по журналу нельзя понять, не было ли между ними вытесненных событий
+func (b *Events) CheckKept(ctx context.Context, after string) error {
+	if _, _, err := parseEventSeq(after); err != nil {
+		return err
+	}
+
+	oldest, err := b.s.Oldest(ctx)
+	if err != nil {
+		return err
+	}
+	if oldest != "" && seqAfter(oldest, after) {
+		return fmt.Errorf("%w: %s", ErrEventsExpired, after)
+	}
+	return nil
+}
+
+// Читает журнал и раздаёт новые события подписчикам
+func (b *Events) Run(ctx context.Context) {
+	last := ""
+	for ctx.Err() == nil {
+		var err error
+		if last == "" {
+			last, err = b.s.Last(ctx)
+		}
+
+		var events []*event.Event
+		if err == nil {
+			events, err = b.s.Read(ctx, last, eventReadCount, b.keepAlive)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("events: failed to read events: %v", err)
+				wait(ctx, eventRetryDelay)
+			}
+			continue
+		}
+
+		for _, e := range events {
+			last = e.Seq
+			b.broadcast(e)
+		}
+	}
+}
+
+// Передаёт события в fn, пока не отменён ctx или fn не вернёт ошибку. Если
+// событий долго нет, fn вызывается с nil, чтобы соединение не простаивало.
+// В группе потребителей событие подтверждается, когда fn его приняла,
+// неподтверждённые события потребитель получит при следующей подписке
+func (b *Events) Subscribe(ctx context.Context, q EventsQuery, fn func(*event.Event) error) error {
+	if err := q.Validate(); err != nil {
+		return err
+	}
+
+	if q.Group != "" {
+		return b.subscribeGroup(ctx, q, fn)
+	}
+
+	// Подписчик регистрируется до чтения истории, чтобы не пропустить события между ними
+	sub := b.subscribe()
+	defer b.unsubscribe(sub)
+
+	// Без вытесненных событий история была бы неполной, а подписчик бы этого не заметил
+	if q.After != "" {
+		if err := b.CheckKept(ctx, q.After); err != nil {
+			return err
+		}
+	}
+
+	last := q.After
+	for last != "" {
+		events, err := b.s.Range(ctx, last, eventReadCount)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			last = e.Seq
+			if err := deliver(q, e, fn); err != nil {
+				return err
+			}
+		}
+		if len(events) < eventReadCount {
+			break
+		}
+	}
+
+	t := time.NewTicker(b.keepAlive)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if err := fn(nil); err != nil {
+				return err
+			}
+		case e, ok := <-sub.ch:
+			if !ok {
+				return ErrSubscriberTooSlow
+			}
+
+			// Событие уже отправлено из истории
+			if last != "" && !seqAfter(e.Seq, last) {
+				continue
+			}
+			last = e.Seq
+
+			if err := deliver(q, e, fn); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (b *Events) subscribeGroup(ctx context.Context, q EventsQuery, fn func(*event.Event) error) error {
+	if err := b.s.CreateGroup(ctx, q.Group); err != nil {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	// Сначала события, которые потребитель получил, но не подтвердил
+	pending := true
+	for {
+		events, err := b.s.ReadGroup(ctx, q.Group, q.Consumer, pending, eventReadCount, b.keepAlive)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return err
+		}
+
+		if pending && len(events) < eventReadCount {
+			pending = false
+		}
+
+		if len(events) == 0 && !pending {
+			if err := fn(nil); err != nil {
+				return err
+			}
+		}
+
+		for _, e := range events {
+			if err := deliver(q, e, fn); err != nil {
+				return err
+			}
+			if err := b.s.Ack(ctx, q.Group, e.Seq); err != nil {
+				return fmt.Errorf("failed to ack event %s: %w", e.Seq, err)
+			}
+		}
+	}
+}
+
+func (b *Events) subscribe() *subscriber {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	sub := &subscriber{ch: make(chan *event.Event, eventBufferSize)}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (b *Events) unsubscribe(sub *subscriber) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	delete(b.subscribers, sub)
+}
+
+// Отстающий подписчик отключается: закрытый канал сообщает ему об этом,
+// продолжить он может с последнего полученного события
+func (b *Events) broadcast(e *event.Event) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	for sub := range b.subscribers {
+		select {
+		case sub.ch <- e:
+		default:
+			delete(b.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+func deliver(q EventsQuery, e *event.Event, fn func(*event.Event) error) error {
+	if !q.matches(e) {
+		return nil
+	}
+	return fn(e)
+}
+
+// Позиция в журнале Redis: "<миллисекунды>-<номер>", номер можно опустить
+func parseEventSeq(seq string) (ms uint64, n uint64, err error) {
+	msPart, nPart, hasN := strings.Cut(seq, "-")
+
+	ms, err = strconv.ParseUint(msPart, 10, 64)
+	if err == nil && hasN {
+		n, err = strconv.ParseUint(nPart, 10, 64)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidEventSeq, seq)
+	}
+	return ms, n, nil
+}
+
+func seqAfter(a, b string) bool {
+	aMs, aN, _ := parseEventSeq(a)
+	bMs, bN, _ := parseEventSeq(b)
+	return aMs > bMs || (aMs == bMs && aN > bN)
+}
+
+func wait(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
+
+// Ошибка публикации не должна отменять уже сохранённое изменение
+func publishEvent(events eventPublisher, e *event.Event) {
+	if err := events.Publish(e); err != nil {
+		log.Printf("events: failed to publish %s: %v", e.Type, err)
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+)
+
+// Журнал, из которого вытеснены все события до oldest
+type memEventStream struct {
+	events []*event.Event
+	oldest string
+}
+
+func (s *memEventStream) Publish(e *event.Event) error {
+	s.events = append(s.events, e)
+	return nil
+}
+
+func (s *memEventStream) Oldest(ctx context.Context) (string, error) {
+	return s.oldest, nil
+}
+
+func (s *memEventStream) Last(ctx context.Context) (string, error) {
+	if len(s.events) == 0 {
+		return "0-0", nil
+	}
+	return s.events[len(s.events)-1].Seq, nil
+}
+
+func (s *memEventStream) Range(ctx context.Context, after string, count int64) ([]*event.Event, error) {
+	var res []*event.Event
+	for _, e := range s.events {
+		if seqAfter(e.Seq, after) && int64(len(res)) < count {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+func (s *memEventStream) Read(ctx context.Context, after string, count int64, block time.Duration) ([]*event.Event, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *memEventStream) CreateGroup(ctx context.Context, group string) error {
+	return errors.New("not implemented")
+}
+
+func (s *memEventStream) ReadGroup(ctx context.Context, group, consumer string, pending bool, count int64, block time.Duration) ([]*event.Event, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *memEventStream) Ack(ctx context.Context, group string, seqs ...string) error {
+	return errors.New("not implemented")
+}
+
+func TestSubscribeAfterExpiredEvents(t *testing.T) {
+	s := &memEventStream{oldest: "5-0"}
+	for _, seq := range []string{"5-0", "6-0", "7-0"} {
+		s.Publish(&event.Event{Seq: seq, Type: event.AGENT_ONLINE})
+	}
+
+	tests := []struct {
+		after string
+		err   error
+		seqs  []string
+	}{
+		{after: "3-0", err: ErrEventsExpired},
+		// Между 4-0 и 5-0 могли быть вытесненные события
+		{after: "4-0", err: ErrEventsExpired},
+		{after: "5-0", err: context.Canceled, seqs: []string{"6-0", "7-0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.after, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var seqs []string
+			err := NewEvents(s).Subscribe(ctx, EventsQuery{After: tt.after}, func(e *event.Event) error {
+				seqs = append(seqs, e.Seq)
+				if len(seqs) == len(tt.seqs) {
+					cancel()
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got %v, want %v", err, tt.err)
+			}
+			if !slices.Equal(seqs, tt.seqs) {
+				t.Errorf("got events %v, want %v", seqs, tt.seqs)
+			}
+		})
+	}
+}
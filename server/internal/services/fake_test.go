@@ -0,0 +1,165 @@
+package services
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/model/history"
+	"github.com/vv-sam/otus-project/server/internal/model/task"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+// Хранилище задач в памяти. Возвращает копии, как настоящий репозиторий
+type memTaskStore struct {
+	m     sync.Mutex
+	tasks map[uuid.UUID]task.Task
+}
+
+func newMemTaskStore(tasks ...*task.Task) *memTaskStore {
+	s := &memTaskStore{tasks: map[uuid.UUID]task.Task{}}
+	for _, t := range tasks {
+		s.tasks[t.Id] = *t
+	}
+	return s
+}
+
+func (s *memTaskStore) Get(id uuid.UUID) (*task.Task, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	t, ok := s.tasks[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	t.Transitions = slices.Clone(t.Transitions)
+	return &t, nil
+}
+
+func (s *memTaskStore) GetAll() ([]*task.Task, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var tasks []*task.Task
+	for _, t := range s.tasks {
+		t.Transitions = slices.Clone(t.Transitions)
+		tasks = append(tasks, &t)
+	}
+	return tasks, nil
+}
+
+func (s *memTaskStore) List(schema *repository.Schema[*task.Task], q repository.ListQuery) (*repository.Page[*task.Task], error) {
+	tasks, _ := s.GetAll()
+	return &repository.Page[*task.Task]{Items: tasks}, nil
+}
+
+func (s *memTaskStore) Add(t *task.Task) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.tasks[t.Id] = *t
+	return nil
+}
+
+func (s *memTaskStore) Update(id uuid.UUID, t *task.Task) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, ok := s.tasks[id]; !ok {
+		return repository.ErrNotFound
+	}
+	s.tasks[id] = *t
+	return nil
+}
+
+func (s *memTaskStore) Delete(id uuid.UUID) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.tasks, id)
+	return nil
+}
+
+func (s *memTaskStore) GetHistory() ([]history.Log[*task.Task], error) {
+	return nil, nil
+}
+
+// Брокер, который помнит только текущие аренды
+type fakeBroker struct {
+	m      sync.Mutex
+	leases map[uuid.UUID]uuid.UUID
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{leases: map[uuid.UUID]uuid.UUID{}}
+}
+
+func (b *fakeBroker) lease(id, agentId uuid.UUID) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.leases[id] = agentId
+}
+
+func (b *fakeBroker) release(id, agentId uuid.UUID) bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.leases[id] != agentId {
+		return false
+	}
+	delete(b.leases, id)
+	return true
+}
+
+func (b *fakeBroker) Enqueue(t *task.Task) error { return nil }
+
+func (b *fakeBroker) Lease(agentId uuid.UUID, types []string, max int, timeout time.Duration) ([]repository.QueueLease, error) {
+	return nil, nil
+}
+
+func (b *fakeBroker) Extend(id, agentId uuid.UUID, timeout time.Duration) (bool, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	return b.leases[id] == agentId, nil
+}
+
+func (b *fakeBroker) Ack(id, agentId uuid.UUID) (bool, error) {
+	return b.release(id, agentId), nil
+}
+
+func (b *fakeBroker) Nack(id, agentId uuid.UUID, maxAttempts int) (int, error) {
+	if !b.release(id, agentId) {
+		return 0, nil
+	}
+	return repository.DEAD_LETTERED, nil
+}
+
+func (b *fakeBroker) Reap(maxAttempts int) (map[uuid.UUID]int, error) { return nil, nil }
+func (b *fakeBroker) DeadLetters() ([]uuid.UUID, error)               { return nil, nil }
+func (b *fakeBroker) Requeue(id uuid.UUID) (bool, error)              { return false, nil }
+func (b *fakeBroker) Remove(id uuid.UUID) error                       { return nil }
+
+// Запоминает опубликованные события
+type fakeEvents struct {
+	m      sync.Mutex
+	events []*event.Event
+}
+
+func (e *fakeEvents) Publish(ev *event.Event) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.events = append(e.events, ev)
+	return nil
+}
+
+func (e *fakeEvents) published() []*event.Event {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return slices.Clone(e.events)
+}
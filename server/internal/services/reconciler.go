@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/server"
+	"github.com/vv-sam/otus-project/server/internal/model/task"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// Интервал согласования серверов
+	ReconcileInterval = 15 * time.Second
+
+	// Через сколько после неудачной задачи создавать новую
+	ReconcileRetryDelay = time.Minute
+)
+
+type taskCreator interface {
+	Get(id uuid.UUID) (*task.Task, error)
+	Add(task *task.Task) error
+}
+
+// Приводит фактическое состояние серверов к желаемому. Фактическое состояние
+// берётся из последнего heartbeat агента, расхождение устраняется задачей
+// для агента. Пока задача не завершилась и агент не прислал отчёт после
+// её завершения, новая задача для сервера не создаётся, поэтому повторные
+// проходы, перезапуск сервера API и перезапуск агента не порождают лишних задач
+type Reconciler struct {
+	servers        *Servers
+	configurations configurationGetter
+	agents         agentGetter
+	tasks          taskCreator
+	interval       time.Duration
+	retryDelay     time.Duration
+}
+
+func NewReconciler(servers *Servers, configurations configurationGetter, agents agentGetter, tasks taskCreator) *Reconciler {
+	return &Reconciler{
+		servers:        servers,
+		configurations: configurations,
+		agents:         agents,
+		tasks:          tasks,
+		interval:       ReconcileInterval,
+		retryDelay:     ReconcileRetryDelay,
+	}
+}
+
+func (r *Reconciler) Run(ctx context.Context) {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		if err := r.reconcile(); err != nil {
+			log.Printf("reconciler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (r *Reconciler) reconcile() error {
+	servers, err := r.servers.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get servers: %w", err)
+	}
+
+	for _, s := range servers {
+		if err := r.reconcileServer(s.Id); err != nil {
+			log.Printf("reconciler: server %s: %v", s.Id, err)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileServer(id uuid.UUID) error {
+	remove := false
+	err := r.servers.update(id, func(s *server.Server) (bool, error) {
+		before := *s
+		var err error
+		remove, err = r.step(s, time.Now())
+		if err != nil {
+			s.Error = err.Error()
+		}
+		if s.ObservedState != before.ObservedState || s.ObservedRevision != before.ObservedRevision {
+			log.Printf("server %s: %s -> %s (desired %s)", s.Id, before.ObservedState, s.ObservedState, s.DesiredState)
+		}
+		return *s != before, nil
+	})
+	if err != nil || !remove {
+		return err
+	}
+
+	log.Printf("server %s: deleted", id)
+	return r.servers.remove(id)
+}
+
+// Один шаг согласования: обновляет фактическое состояние и при расхождении
+// создаёт задачу. Возвращает true, если удалённый сервер можно забыть
+func (r *Reconciler) step(s *server.Server, now time.Time) (bool, error) {
+	conf, err := r.configuration(s.Id)
+	if err != nil {
+		return false, err
+	}
+
+	desired := s.DesiredState
+	if conf == nil {
+		// Конфигурацию удалили вместе с описанием сервера, остаётся убрать контейнер
+		desired = server.DESIRED_DELETED
+	}
+
+	// Конфигурацию перенесли на другой агент: контейнер на старом удаляется,
+	// после этого сервер разворачивается на новом
+	moving := conf != nil && conf.GetBase().AgentId != s.AgentId
+	if moving && s.AgentId == uuid.Nil {
+		s.AgentId = conf.GetBase().AgentId
+		moving = false
+	}
+
+	if s.AgentId == uuid.Nil {
+		s.ObservedState = server.STATE_UNKNOWN
+		return desired == server.DESIRED_DELETED, errors.New("configuration is not assigned to an agent")
+	}
+
+	a, err := r.observe(s, now)
+	if err != nil {
+		return false, err
+	}
+
+	if s.DesiredRevision, err = desiredRevision(conf); err != nil {
+		return false, err
+	}
+
+	if a == nil {
+		// Агент не на связи, ждём его отчёта. Если агент удалён, удалять нечего
+		if s.ObservedState != server.STATE_ABSENT {
+			return false, nil
+		}
+		if moving {
+			r.move(s, conf.GetBase().AgentId)
+			return false, nil
+		}
+		return desired == server.DESIRED_DELETED, nil
+	}
+
+	// Ждём завершения задачи и отчёта агента, отправленного после него
+	if s.TaskId != uuid.Nil {
+		t, err := r.tasks.Get(s.TaskId)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return false, fmt.Errorf("failed to get task: %w", err)
+		}
+		if t != nil {
+			if !task.IsFinal(t.Status) {
+				return false, nil
+			}
+			if t.Status != task.STATUS_OK && now.Before(t.FinishedAt.Add(r.retryDelay)) {
+				s.Error = fmt.Sprintf("task %s %s: %s", t.Action, task.StatusName(t.Status), t.Error)
+				return false, nil
+			}
+			if !a.LastSeen.After(t.FinishedAt) {
+				return false, nil
+			}
+			if t.Status == task.STATUS_OK {
+				s.Error = ""
+			}
+		}
+	}
+
+	if moving {
+		if s.ObservedState != server.STATE_ABSENT {
+			return false, r.createTask(s, task.ACTION_DELETE, nil, now)
+		}
+		r.move(s, conf.GetBase().AgentId)
+		return false, nil
+	}
+
+	switch desired {
+	case server.DESIRED_DELETED:
+		if s.ObservedState == server.STATE_ABSENT {
+			return true, nil
+		}
+		return false, r.createTask(s, task.ACTION_DELETE, nil, now)
+	case server.DESIRED_RUNNING:
+		switch {
+		case s.ObservedState == server.STATE_ABSENT || s.ObservedRevision != s.DesiredRevision:
+			return false, r.deploy(s, conf, now)
+		case s.ObservedState == server.STATE_STOPPED:
+			return false, r.createTask(s, task.ACTION_START, nil, now)
+		}
+	case server.DESIRED_STOPPED:
+		if s.ObservedState == server.STATE_RUNNING {
+			return false, r.createTask(s, task.ACTION_STOP, nil, now)
+		}
+	}
+
+	if s.IsConverged() {
+		s.Error = ""
+	}
+	return false, nil
+}
+
+// Переносит сервер на новый агент. Состояние на нём станет известно
+// на следующем проходе
+func (r *Reconciler) move(s *server.Server, agentId uuid.UUID) {
+	log.Printf("server %s: moved from agent %s to %s", s.Id, s.AgentId, agentId)
+	s.AgentId = agentId
+	s.ObservedState = server.STATE_UNKNOWN
+	s.ObservedRevision = ""
+	s.TaskId = uuid.Nil
+}
+
+// Обновляет фактическое состояние по последнему heartbeat агента. Возвращает
+// агента, если его отчёту можно доверять
+func (r *Reconciler) observe(s *server.Server, now time.Time) (*agent.Info, error) {
+	a, err := r.agents.Get(s.AgentId)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+	if a == nil {
+		s.ObservedState = server.STATE_ABSENT
+		s.ObservedRevision = ""
+		return nil, nil
+	}
+
+	if a.StatusAt(now, DegradedAfter, OfflineAfter) != agent.STATUS_ONLINE {
+		s.ObservedState = server.STATE_UNKNOWN
+		return nil, nil
+	}
+
+	s.ObservedAt = a.LastSeen
+	s.ObservedState = server.STATE_ABSENT
+	s.ObservedRevision = ""
+	for _, u := range a.Servers {
+		if u.ServerId != s.Id {
+			continue
+		}
+
+		s.ObservedState = server.STATE_STOPPED
+		if u.Running {
+			s.ObservedState = server.STATE_RUNNING
+		}
+		s.ObservedRevision = u.Revision
+	}
+	return a, nil
+}
+
+func (r *Reconciler) deploy(s *server.Server, conf *configuration.Envelope, now time.Time) error {
+	dep, err := deploymentToProto(conf)
+	if err != nil {
+		return err
+	}
+	dep.Start = true
+
+	payload, err := protojson.Marshal(dep)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deployment: %w", err)
+	}
+	return r.createTask(s, task.ACTION_DEPLOY, payload, now)
+}
+
+func (r *Reconciler) createTask(s *server.Server, action string, payload []byte, now time.Time) error {
+	t := &task.Task{
+		Id:              uuid.New(),
+		Status:          task.STATUS_QUEUED,
+		Type:            s.Type,
+		Priority:        task.PRIORITY_NORMAL,
+		Action:          action,
+		AgentId:         s.AgentId,
+		ConfigurationId: s.Id,
+		Payload:         payload,
+		CreatedAt:       now,
+	}
+	if err := r.tasks.Add(t); err != nil {
+		return fmt.Errorf("failed to create %s task: %w", action, err)
+	}
+
+	log.Printf("server %s: %s on agent %s (task %s)", s.Id, action, s.AgentId, t.Id)
+	s.TaskId = t.Id
+	return nil
+}
+
+func (r *Reconciler) configuration(id uuid.UUID) (*configuration.Envelope, error) {
+	conf, err := r.configurations.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configuration: %w", err)
+	}
+	if conf == nil || conf.Configuration == nil {
+		return nil, nil
+	}
+	return conf, nil
+}
+
+func desiredRevision(conf *configuration.Envelope) (string, error) {
+	if conf == nil {
+		return "", nil
+	}
+
+	dep, err := conf.Deployment()
+	if err != nil {
+		return "", fmt.Errorf("failed to render deployment: %w", err)
+	}
+	return dep.Revision()
+}
+
+// Описание развёртывания для агента. Лимиты ресурсов передаются вместе
+// с ним, но в ревизию не входят
+func deploymentToProto(conf *configuration.Envelope) (*api.Deployment, error) {
+	dep, err := conf.Deployment()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render deployment: %w", err)
+	}
+
+	rev, err := dep.Revision()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &api.Deployment{
+		Revision: rev,
+		Image:    dep.Image,
+		Env:      dep.Env,
+		Files:    dep.Files,
+		DataPath: dep.DataPath,
+	}
+	for _, p := range dep.Ports {
+		res.Ports = append(res.Ports, &api.DeploymentPort{Port: uint32(p.Port), Protocol: p.Protocol})
+	}
+	if r := conf.GetBase().Resources; r != nil {
+		res.Resources = &api.Resources{CpuCores: r.CpuCores, CpuShares: r.CpuShares, MemoryBytes: r.MemoryBytes, DiskBytes: r.DiskBytes}
+	}
+	return res, nil
+}
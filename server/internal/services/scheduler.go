@@ -0,0 +1,205 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/placement"
+)
+
+var ErrNoSuitableAgent = errors.New("no suitable agent")
+
+type agentLister interface {
+	GetAll() ([]*agent.Info, error)
+}
+
+// Ресурсы, уже обещанные серверам на агенте
+type reserved struct {
+	servers     uint32
+	memoryBytes uint64
+	cpuCores    float64
+	diskBytes   uint64
+}
+
+// Подходящий агент и его загрузка
+type candidate struct {
+	agent *agent.Info
+	res   reserved
+	load  float64
+}
+
+// Выбирает агента для конфигурации без agent_id. Агент должен быть online,
+// соответствовать ограничениям размещения и иметь свободные ресурсы с учётом
+// последних метрик и ресурсов, запрошенных уже размещёнными серверами.
+// Из подходящих выбирается наименее загруженный
+type Scheduler struct {
+	agents         agentLister
+	configurations configurationLister
+}
+
+func NewScheduler(agents agentLister, configurations configurationLister) *Scheduler {
+	return &Scheduler{agents: agents, configurations: configurations}
+}
+
+// Выбирает агента. Если никто не подошёл, возвращает решение с причинами
+// отказа каждого агента и ErrNoSuitableAgent
+func (s *Scheduler) Place(conf configuration.Configuration) (*placement.Decision, error) {
+	agents, err := s.agents.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agents: %w", err)
+	}
+
+	confs, err := s.configurations.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configurations: %w", err)
+	}
+
+	// Сам сервер при переразмещении не должен занимать ресурсы
+	res := make(map[uuid.UUID]reserved)
+	for _, c := range confs {
+		if c.Configuration == nil || c.GetId() == conf.GetId() {
+			continue
+		}
+		base := c.GetBase()
+		if base.AgentId == uuid.Nil {
+			continue
+		}
+
+		r := res[base.AgentId]
+		memoryBytes, cpuCores, diskBytes := base.Reserved()
+		r.servers++
+		r.memoryBytes += memoryBytes
+		r.cpuCores += cpuCores
+		r.diskBytes += diskBytes
+		res[base.AgentId] = r
+	}
+
+	want := conf.GetBase().Placement
+	if want == nil {
+		want = &configuration.Placement{}
+	}
+
+	// Лимиты ресурсов сервера занимают ресурсы агента так же, как минимумы размещения
+	var need reserved
+	need.memoryBytes, need.cpuCores, need.diskBytes = conf.GetBase().Reserved()
+
+	slices.SortFunc(agents, func(a, b *agent.Info) int {
+		return strings.Compare(a.AgentId.String(), b.AgentId.String())
+	})
+
+	decision := &placement.Decision{}
+	var best *candidate
+	suitable := 0
+	for _, a := range agents {
+		r := res[a.AgentId]
+		if reasons := reject(a, r, want, need); len(reasons) > 0 {
+			decision.Rejected = append(decision.Rejected, placement.Rejection{AgentId: a.AgentId, Reasons: reasons})
+			continue
+		}
+
+		suitable++
+		c := &candidate{agent: a, res: r, load: load(a)}
+		if best == nil || c.load < best.load || (c.load == best.load && c.res.servers < best.res.servers) {
+			best = c
+		}
+	}
+
+	if best == nil {
+		return decision, fmt.Errorf("%w: %s", ErrNoSuitableAgent, decision)
+	}
+
+	m := best.agent.Metrics
+	decision.AgentId = best.agent.AgentId
+	decision.Reason = fmt.Sprintf("least loaded of %d suitable agents: cpu %.0f%%, %d MiB of %d MiB memory available, %d servers",
+		suitable, m.CpuUsage, m.RamAvailable>>20, m.RamTotal>>20, best.res.servers)
+	return decision, nil
+}
+
+// Причины, по которым агент не подходит. Пустой список - агент подходит
+func reject(a *agent.Info, r reserved, want *configuration.Placement, need reserved) []string {
+	var reasons []string
+
+	if !a.IsAvailable() {
+		reasons = append(reasons, fmt.Sprintf("agent is %s", agent.StatusName(a.Status)))
+	}
+
+	cfg := a.Config
+	if want.Location != "" && cfg.Location != want.Location {
+		reasons = append(reasons, fmt.Sprintf("location is %q, want %q", cfg.Location, want.Location))
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(want.Labels)) {
+		v, ok := cfg.Labels[k]
+		switch {
+		case !ok:
+			reasons = append(reasons, fmt.Sprintf("label %s is missing", k))
+		case v != want.Labels[k]:
+			reasons = append(reasons, fmt.Sprintf("label %s is %q, want %q", k, v, want.Labels[k]))
+		}
+	}
+
+	if limit := cfg.Capacity.MaxServers; limit > 0 && r.servers >= limit {
+		reasons = append(reasons, fmt.Sprintf("already runs %d of %d servers", r.servers, limit))
+	}
+
+	if free := freeMemory(a, r); need.memoryBytes > free {
+		reasons = append(reasons, fmt.Sprintf("%d MiB memory free, want %d MiB", free>>20, need.memoryBytes>>20))
+	}
+
+	if free := freeCpu(a, r); need.cpuCores > free {
+		reasons = append(reasons, fmt.Sprintf("%.2f cpu cores free, want %.2f", free, need.cpuCores))
+	}
+
+	if capacity := cfg.Capacity.DiskBytes; capacity > 0 && r.diskBytes+need.diskBytes > capacity {
+		reasons = append(reasons, fmt.Sprintf("%d MiB of %d MiB disk reserved, want %d MiB", r.diskBytes>>20, capacity>>20, need.diskBytes>>20))
+	}
+
+	return reasons
+}
+
+// Свободная память: меньшее из доступного на хосте по метрикам
+// и не обещанного другим серверам в пределах capacity
+func freeMemory(a *agent.Info, r reserved) uint64 {
+	free := uint64(math.MaxUint64)
+	if a.Metrics.RamTotal > 0 {
+		free = a.Metrics.RamAvailable
+	}
+
+	if capacity := a.Config.Capacity.MemoryBytes; capacity > 0 {
+		left := uint64(0)
+		if capacity > r.memoryBytes {
+			left = capacity - r.memoryBytes
+		}
+		free = min(free, left)
+	}
+	return free
+}
+
+// Свободные ядра. Число ядер хоста сервер знает только из capacity,
+// поэтому без него CPU не ограничивается
+func freeCpu(a *agent.Info, r reserved) float64 {
+	capacity := a.Config.Capacity.CpuCores
+	if capacity == 0 {
+		return math.Inf(1)
+	}
+
+	idle := capacity * (100 - float64(a.Metrics.CpuUsage)) / 100
+	return max(0, min(capacity-r.cpuCores, idle))
+}
+
+// Загрузка хоста от 0 до 2: доля занятого CPU плюс доля занятой памяти
+func load(a *agent.Info) float64 {
+	m := a.Metrics
+	l := float64(m.CpuUsage) / 100
+	if m.RamTotal > 0 {
+		l += 1 - float64(m.RamAvailable)/float64(m.RamTotal)
+	}
+	return l
+}
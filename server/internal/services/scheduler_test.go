@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/agent"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/metrics"
+	"github.com/vv-sam/otus-project/server/internal/model/placement"
+)
+
+type confList []*configuration.Envelope
+
+func (l confList) GetAll() ([]*configuration.Envelope, error) {
+	return l, nil
+}
+
+// Online агент с загрузкой CPU cpu% и свободной половиной из 8 GiB памяти
+func testAgent(cpu float32, cfg agent.Config) agent.Info {
+	return agent.Info{
+		AgentId: uuid.New(),
+		Status:  agent.STATUS_ONLINE,
+		Metrics: metrics.HostMetrics{CpuUsage: cpu, RamTotal: 8 << 30, RamAvailable: 4 << 30},
+		Config:  cfg,
+	}
+}
+
+func placed(p *configuration.Placement, res *configuration.Resources) *configuration.Envelope {
+	c := testConf(uuid.Nil, res)
+	c.GetBase().Placement = p
+	return c
+}
+
+func rejection(d *placement.Decision, id uuid.UUID) []string {
+	for _, r := range d.Rejected {
+		if r.AgentId == id {
+			return r.Reasons
+		}
+	}
+	return nil
+}
+
+func TestSchedulerPicksLeastLoaded(t *testing.T) {
+	busy := testAgent(80, agent.Config{Location: "eu"})
+	idle := testAgent(10, agent.Config{Location: "eu"})
+	offline := testAgent(0, agent.Config{Location: "eu"})
+	offline.Status = agent.STATUS_OFFLINE
+	elsewhere := testAgent(0, agent.Config{Location: "us"})
+
+	s := NewScheduler(newMemAgentStore(busy, idle, offline, elsewhere), confList{})
+	d, err := s.Place(placed(&configuration.Placement{Location: "eu"}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.AgentId != idle.AgentId {
+		t.Errorf("placed on %s, want the idle agent %s", d.AgentId, idle.AgentId)
+	}
+	if len(d.Rejected) != 2 {
+		t.Errorf("rejected %v, want offline and elsewhere", d.Rejected)
+	}
+	if r := rejection(d, offline.AgentId); !slices.Equal(r, []string{"agent is offline"}) {
+		t.Errorf("offline agent rejected with %q", r)
+	}
+	if r := rejection(d, elsewhere.AgentId); !slices.Equal(r, []string{`location is "us", want "eu"`}) {
+		t.Errorf("agent in another location rejected with %q", r)
+	}
+}
+
+// При равной загрузке выбирается агент с меньшим числом серверов
+func TestSchedulerPrefersFewerServers(t *testing.T) {
+	crowded := testAgent(50, agent.Config{})
+	empty := testAgent(50, agent.Config{})
+
+	confs := confList{testConf(crowded.AgentId, nil)}
+	d, err := NewScheduler(newMemAgentStore(crowded, empty), confs).Place(placed(nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.AgentId != empty.AgentId {
+		t.Errorf("placed on %s, want the agent without servers", d.AgentId)
+	}
+}
+
+func TestSchedulerRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		agent  agent.Info
+		confs  func(a agent.Info) confList
+		conf   *configuration.Envelope
+		reason string
+	}{
+		{
+			name:   "missing label",
+			agent:  testAgent(0, agent.Config{}),
+			conf:   placed(&configuration.Placement{Labels: map[string]string{"gpu": "yes"}}, nil),
+			reason: "label gpu is missing",
+		},
+		{
+			name:   "label value",
+			agent:  testAgent(0, agent.Config{Labels: map[string]string{"gpu": "no"}}),
+			conf:   placed(&configuration.Placement{Labels: map[string]string{"gpu": "yes"}}, nil),
+			reason: `label gpu is "no", want "yes"`,
+		},
+		{
+			name:  "max servers",
+			agent: testAgent(0, agent.Config{Capacity: agent.Capacity{MaxServers: 1}}),
+			confs: func(a agent.Info) confList {
+				return confList{testConf(a.AgentId, nil)}
+			},
+			conf:   placed(nil, nil),
+			reason: "already runs 1 of 1 servers",
+		},
+		{
+			name:   "host memory",
+			agent:  testAgent(0, agent.Config{}),
+			conf:   placed(&configuration.Placement{MinMemoryBytes: 6 << 30}, nil),
+			reason: "4096 MiB memory free, want 6144 MiB",
+		},
+		{
+			name:  "reserved memory",
+			agent: testAgent(0, agent.Config{Capacity: agent.Capacity{MemoryBytes: 4 << 30}}),
+			confs: func(a agent.Info) confList {
+				return confList{testConf(a.AgentId, &configuration.Resources{MemoryBytes: 3 << 30})}
+			},
+			conf:   placed(nil, &configuration.Resources{MemoryBytes: 2 << 30}),
+			reason: "1024 MiB memory free, want 2048 MiB",
+		},
+		{
+			name:   "busy cpu",
+			agent:  testAgent(75, agent.Config{Capacity: agent.Capacity{CpuCores: 4}}),
+			conf:   placed(&configuration.Placement{MinCpuCores: 1.5}, nil),
+			reason: "1.00 cpu cores free, want 1.50",
+		},
+		{
+			name:  "disk",
+			agent: testAgent(0, agent.Config{Capacity: agent.Capacity{DiskBytes: 10 << 30}}),
+			confs: func(a agent.Info) confList {
+				return confList{testConf(a.AgentId, &configuration.Resources{DiskBytes: 8 << 30})}
+			},
+			conf:   placed(nil, &configuration.Resources{DiskBytes: 4 << 30}),
+			reason: "8192 MiB of 10240 MiB disk reserved, want 4096 MiB",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			confs := confList{}
+			if tt.confs != nil {
+				confs = tt.confs(tt.agent)
+			}
+
+			d, err := NewScheduler(newMemAgentStore(tt.agent), confs).Place(tt.conf)
+			if !errors.Is(err, ErrNoSuitableAgent) {
+				t.Fatalf("got %v, want ErrNoSuitableAgent", err)
+			}
+			if r := rejection(d, tt.agent.AgentId); !slices.Equal(r, []string{tt.reason}) {
+				t.Errorf("rejected with %q, want %q", r, tt.reason)
+			}
+		})
+	}
+}
+
+// Переразмещаемый сервер не занимает место сам у себя
+func TestSchedulerIgnoresPlacedConfiguration(t *testing.T) {
+	a := testAgent(0, agent.Config{Capacity: agent.Capacity{MaxServers: 1}})
+	conf := testConf(a.AgentId, nil)
+
+	d, err := NewScheduler(newMemAgentStore(a), confList{conf}).Place(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.AgentId != a.AgentId {
+		t.Errorf("placed on %s, want %s", d.AgentId, a.AgentId)
+	}
+}
+
+func TestSchedulerNoAgents(t *testing.T) {
+	d, err := NewScheduler(newMemAgentStore(), confList{}).Place(placed(nil, nil))
+	if !errors.Is(err, ErrNoSuitableAgent) {
+		t.Fatalf("got %v, want ErrNoSuitableAgent", err)
+	}
+	if d.String() != "no agents" {
+		t.Errorf("decision %q", d)
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/health"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Интервал проверки игровых серверов
+	ProbeInterval = 30 * time.Second
+
+	// Сколько проверок подряд должно провалиться, чтобы сервер стал unhealthy
+	ProbeFailureThreshold = 2
+
+	probeTimeout = 10 * time.Second
+)
+
+type configurationLister interface {
+	GetAll() ([]*configuration.Envelope, error)
+}
+
+// Периодически проверяет развёрнутые игровые серверы так, как их видит игрок:
+// агент подключается к игровому порту со своего хоста. Состояние хранится
+// в памяти и после перезапуска восстанавливается первой же проверкой
+type ServerHealth struct {
+	sessions       *AgentSessions
+	configurations configurationLister
+	interval       time.Duration
+	threshold      uint32
+
+	m      sync.RWMutex
+	health map[uuid.UUID]*health.Health
+}
+
+func NewServerHealth(sessions *AgentSessions, configurations configurationLister) *ServerHealth {
+	return &ServerHealth{
+		sessions:       sessions,
+		configurations: configurations,
+		interval:       ProbeInterval,
+		threshold:      ProbeFailureThreshold,
+		health:         make(map[uuid.UUID]*health.Health),
+	}
+}
+
+func (s *ServerHealth) Run(ctx context.Context) {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+
+	for {
+		if err := s.check(ctx); err != nil {
+			log.Printf("server health: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Состояние сервера. Сервер, который ещё не проверялся, возвращается
+// со статусом unknown
+func (s *ServerHealth) Get(serverId uuid.UUID) (*health.Health, error) {
+	s.m.RLock()
+	h, ok := s.health[serverId]
+	s.m.RUnlock()
+	if ok {
+		c := *h
+		return &c, nil
+	}
+
+	// Отличаем непроверенный сервер от несуществующего
+	confs, err := s.configurations.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, conf := range confs {
+		if conf.GetId() == serverId {
+			return &health.Health{ServerId: serverId}, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// Состояние всех проверенных серверов
+func (s *ServerHealth) GetAll() []*health.Health {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	res := make([]*health.Health, 0, len(s.health))
+	for _, h := range s.health {
+		c := *h
+		res = append(res, &c)
+	}
+	return res
+}
+
+// Проверяет все серверы, назначенные агентам, параллельно
+func (s *ServerHealth) check(ctx context.Context) error {
+	confs, err := s.configurations.GetAll()
+	if err != nil {
+		return err
+	}
+
+	assigned := make(map[uuid.UUID]struct{}, len(confs))
+	var wg sync.WaitGroup
+	for _, conf := range confs {
+		if conf.Configuration == nil || conf.GetBase().AgentId == uuid.Nil {
+			continue
+		}
+		assigned[conf.GetId()] = struct{}{}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.checkServer(ctx, conf.Configuration)
+		}()
+	}
+	wg.Wait()
+
+	// Удалённые и снятые с агентов серверы больше не отслеживаются
+	s.m.Lock()
+	defer s.m.Unlock()
+	for id := range s.health {
+		if _, ok := assigned[id]; !ok {
+			delete(s.health, id)
+		}
+	}
+	return nil
+}
+
+func (s *ServerHealth) checkServer(ctx context.Context, conf configuration.Configuration) {
+	now := time.Now()
+	agentId := conf.GetBase().AgentId
+
+	if !s.sessions.IsConnected(agentId) {
+		s.update(conf.GetId(), func(h *health.Health) {
+			h.Unknown(now, ErrAgentNotConnected)
+		})
+		return
+	}
+
+	probe, err := s.probe(ctx, agentId, conf)
+	if ctx.Err() != nil {
+		return
+	}
+	s.update(conf.GetId(), func(h *health.Health) {
+		h.Record(now, probe, err, s.threshold)
+	})
+}
+
+func (s *ServerHealth) update(serverId uuid.UUID, fn func(h *health.Health)) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	h, ok := s.health[serverId]
+	if !ok {
+		h = &health.Health{ServerId: serverId}
+		s.health[serverId] = h
+	}
+
+	before := h.Status
+	fn(h)
+	if h.Status != before {
+		log.Printf("server %s: health %d -> %d (%s)", serverId, before, h.Status, h.Error)
+	}
+}
+
+// Просит агента проверить сервер с его хоста
+func (s *ServerHealth) probe(ctx context.Context, agentId uuid.UUID, conf configuration.Configuration) (*health.Probe, error) {
+	req := &api.ProbeRequest{Game: conf.GetBase().Type, Port: uint32(conf.GamePort())}
+	if rcon, ok := conf.(configuration.RconEnabled); ok {
+		port, password := rcon.Rcon()
+		req.RconPort = uint32(port)
+		req.RconPassword = password
+	}
+
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.sessions.Send(ctx, agentId, &api.Command{
+		Type:            api.CommandType_COMMAND_TYPE_PROBE,
+		ConfigurationId: conf.GetId().String(),
+		Payload:         payload,
+	}, probeTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Ok {
+		return nil, errors.New(res.Error)
+	}
+
+	var out api.ProbeResult
+	if err := proto.Unmarshal(res.Output, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse probe result: %w", err)
+	}
+
+	return &health.Probe{
+		LatencyMs:     out.LatencyMs,
+		Version:       out.Version,
+		Motd:          out.Motd,
+		PlayersOnline: out.PlayersOnline,
+		PlayersMax:    out.PlayersMax,
+		Players:       out.Players,
+	}, nil
+}
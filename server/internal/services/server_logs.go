@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/vv-sam/otus-project/proto/grpc/pkg"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var ErrServerNotAssigned = errors.New("server is not assigned to an agent")
+
+type configurationGetter interface {
+	Get(id uuid.UUID) (*configuration.Envelope, error)
+}
+
+// Выборка вывода игрового сервера
+type LogsQuery struct {
+	Since  time.Time // Строки не старше
+	Tail   uint32    // Сколько последних строк вернуть, 0 - все
+	Follow bool      // Продолжать поток новыми строками
+}
+
+// Вывод игровых серверов. Сервер определяется конфигурацией, вывод
+// запрашивается у агента, на котором она развёрнута
+type ServerLogs struct {
+	sessions       *AgentSessions
+	configurations configurationGetter
+}
+
+func NewServerLogs(sessions *AgentSessions, configurations configurationGetter) *ServerLogs {
+	return &ServerLogs{sessions: sessions, configurations: configurations}
+}
+
+// Передаёт вывод сервера порциями в fn. С q.Follow поток продолжается,
+// пока сервер работает и не отменён ctx
+func (l *ServerLogs) Stream(ctx context.Context, serverId uuid.UUID, q LogsQuery, fn func(*api.LogChunk) error) error {
+	c, err := l.configurations.Get(serverId)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return repository.ErrNotFound
+	}
+
+	agentId := c.GetBase().AgentId
+	if agentId == uuid.Nil {
+		return ErrServerNotAssigned
+	}
+
+	req := &api.LogsRequest{
+		ConfigurationId: serverId.String(),
+		Tail:            q.Tail,
+		Follow:          q.Follow,
+	}
+	if !q.Since.IsZero() {
+		req.Since = timestamppb.New(q.Since)
+	}
+
+	return l.sessions.Logs(ctx, agentId, req, fn)
+}
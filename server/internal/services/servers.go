@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/configuration"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/model/history"
+	"github.com/vv-sam/otus-project/server/internal/model/server"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+var (
+	ErrUnknownConfiguration = errors.New("configuration not found")
+	ErrServerExists         = errors.New("server already exists")
+	ErrServerDeleting       = errors.New("server is being deleted")
+)
+
+type serverStore interface {
+	Get(id uuid.UUID) (*server.Server, error)
+	GetAll() ([]*server.Server, error)
+	Add(server *server.Server) error
+	Update(id uuid.UUID, server *server.Server) error
+	Delete(id uuid.UUID) error
+	GetHistory() ([]history.Log[*server.Server], error)
+}
+
+type configurationUpdater interface {
+	Get(id uuid.UUID) (*configuration.Envelope, error)
+	Update(id uuid.UUID, configuration *configuration.Envelope) error
+}
+
+// Игровые серверы. Через API меняется только желаемое состояние,
+// фактическое состояние и задачи ведёт Reconciler. Изменения идут под
+// общей блокировкой, чтобы цикл согласования не затёр изменение пользователя
+type Servers struct {
+	m              sync.Mutex
+	r              serverStore
+	configurations configurationUpdater
+	events         eventPublisher
+}
+
+func NewServers(r serverStore, configurations configurationUpdater, events eventPublisher) *Servers {
+	return &Servers{r: r, configurations: configurations, events: events}
+}
+
+func (s *Servers) Get(id uuid.UUID) (*server.Server, error) {
+	return s.r.Get(id)
+}
+
+func (s *Servers) GetAll() ([]*server.Server, error) {
+	return s.r.GetAll()
+}
+
+func (s *Servers) GetHistory() ([]history.Log[*server.Server], error) {
+	return s.r.GetHistory()
+}
+
+// Создаёт сервер для конфигурации. Агент и тип берутся из конфигурации
+func (s *Servers) Add(srv *server.Server) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	existing, err := s.get(srv.Id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: %s", ErrServerExists, srv.Id)
+	}
+
+	conf, err := s.configurations.Get(srv.Id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("failed to get configuration: %w", err)
+	}
+	if conf == nil || conf.Configuration == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownConfiguration, srv.Id)
+	}
+
+	// Конфигурации, сохранённые до генерации паролей RCON, получают пароль сейчас,
+	// иначе он менялся бы при каждом развёртывании
+	if configuration.MissingSecrets(conf.Configuration) {
+		if err := s.configurations.Update(conf.GetId(), conf); err != nil {
+			return fmt.Errorf("failed to save rcon password: %w", err)
+		}
+	}
+
+	now := time.Now()
+	srv.AgentId = conf.GetBase().AgentId
+	srv.Type = conf.GetBase().Type
+	srv.ObservedState = server.STATE_UNKNOWN
+	srv.ObservedRevision = ""
+	srv.DesiredRevision = ""
+	srv.ObservedAt = time.Time{}
+	srv.TaskId = uuid.Nil
+	srv.Error = ""
+	srv.CreatedAt = now
+	srv.UpdatedAt = now
+
+	if err := s.r.Add(srv); err != nil {
+		return err
+	}
+
+	s.publish(event.SERVER_CREATED, srv, "", srv.DesiredState)
+	return nil
+}
+
+// Меняет желаемое состояние сервера. Остальные поля ведёт цикл согласования
+func (s *Servers) Update(id uuid.UUID, srv *server.Server) error {
+	return s.update(id, func(cur *server.Server) (bool, error) {
+		if cur.DesiredState == server.DESIRED_DELETED && srv.DesiredState != server.DESIRED_DELETED {
+			return false, fmt.Errorf("%w: %s", ErrServerDeleting, id)
+		}
+
+		cur.DesiredState = srv.DesiredState
+		return true, nil
+	})
+}
+
+// Переводит сервер в состояние deleted. Запись удаляется циклом
+// согласования, когда контейнер будет удалён с агента
+func (s *Servers) Delete(id uuid.UUID) error {
+	return s.update(id, func(cur *server.Server) (bool, error) {
+		cur.DesiredState = server.DESIRED_DELETED
+		return true, nil
+	})
+}
+
+// Изменяет сервер под блокировкой. fn возвращает, нужно ли сохранять изменения
+func (s *Servers) update(id uuid.UUID, fn func(cur *server.Server) (bool, error)) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	cur, err := s.get(id)
+	if err != nil {
+		return err
+	}
+
+	before := *cur
+	changed, err := fn(cur)
+	if err != nil || !changed {
+		return err
+	}
+
+	cur.UpdatedAt = time.Now()
+	if err := s.r.Update(id, cur); err != nil {
+		return err
+	}
+
+	if cur.DesiredState != before.DesiredState {
+		s.publish(event.SERVER_UPDATED, cur, before.DesiredState, cur.DesiredState)
+	}
+	if cur.ObservedState != before.ObservedState {
+		s.publish(event.SERVER_STATE_CHANGED, cur, before.ObservedState, cur.ObservedState)
+	}
+	return nil
+}
+
+// Удаляет запись сервера, если он всё ещё удаляется
+func (s *Servers) remove(id uuid.UUID) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	cur, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	if cur.DesiredState != server.DESIRED_DELETED {
+		return nil
+	}
+
+	if err := s.r.Delete(id); err != nil {
+		return err
+	}
+
+	s.publish(event.SERVER_DELETED, cur, "", "")
+	return nil
+}
+
+func (s *Servers) publish(eventType string, srv *server.Server, from, to string) {
+	e := event.New(eventType, srv.Error)
+	e.ServerId = srv.Id
+	e.ConfigurationId = srv.Id
+	e.AgentId = srv.AgentId
+	e.From = from
+	e.To = to
+	publishEvent(s.events, e)
+}
+
+// Оба репозитория сообщают об отсутствии по-разному
+func (s *Servers) get(id uuid.UUID) (*server.Server, error) {
+	srv, err := s.r.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if srv == nil {
+		return nil, repository.ErrNotFound
+	}
+	return srv, nil
+}
@@ -0,0 +1,458 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/model/history"
+	"github.com/vv-sam/otus-project/server/internal/model/task"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+const (
+	// Время аренды задачи, если агент не указал своё
+	DefaultVisibilityTimeout = 5 * time.Minute
+
+	// Сколько раз задача выдаётся агентам, прежде чем попасть в dead-letter список
+	DefaultMaxAttempts = 3
+
+	// Как часто возвращать в очередь задачи с истёкшей арендой
+	leaseCheckInterval = 10 * time.Second
+
+	// Кто меняет статус задачи, когда это делает сама очередь
+	systemActor = "system"
+)
+
+var (
+	ErrLeaseNotHeld     = errors.New("task is not leased by this agent")
+	ErrNotInDeadLetters = errors.New("task is not in dead letters")
+)
+
+type taskStore interface {
+	Get(id uuid.UUID) (*task.Task, error)
+	GetAll() ([]*task.Task, error)
+	List(s *repository.Schema[*task.Task], q repository.ListQuery) (*repository.Page[*task.Task], error)
+	Add(task *task.Task) error
+	Update(id uuid.UUID, task *task.Task) error
+	Delete(id uuid.UUID) error
+	GetHistory() ([]history.Log[*task.Task], error)
+}
+
+type taskBroker interface {
+	Enqueue(t *task.Task) error
+	Lease(agentId uuid.UUID, types []string, max int, timeout time.Duration) ([]repository.QueueLease, error)
+	Extend(id, agentId uuid.UUID, timeout time.Duration) (bool, error)
+	Ack(id, agentId uuid.UUID) (bool, error)
+	Nack(id, agentId uuid.UUID, maxAttempts int) (int, error)
+	Reap(maxAttempts int) (map[uuid.UUID]int, error)
+	DeadLetters() ([]uuid.UUID, error)
+	Requeue(id uuid.UUID) (bool, error)
+	Remove(id uuid.UUID) error
+}
+
+// Очередь задач: сами задачи хранятся в репозитории, а порядок выдачи, аренды
+// и dead-letter список - в брокере. Реализует интерфейс репозитория задач,
+// чтобы задачи, созданные или изменённые через API, сразу попадали в очередь
+type TaskQueue struct {
+	m           sync.Mutex
+	r           taskStore
+	b           taskBroker
+	maxAttempts int
+	events      eventPublisher
+}
+
+func NewTaskQueue(r taskStore, b taskBroker, maxAttempts int, events eventPublisher) *TaskQueue {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	return &TaskQueue{r: r, b: b, maxAttempts: maxAttempts, events: events}
+}
+
+func (q *TaskQueue) Get(id uuid.UUID) (*task.Task, error) {
+	return q.r.Get(id)
+}
+
+func (q *TaskQueue) GetAll() ([]*task.Task, error) {
+	return q.r.GetAll()
+}
+
+func (q *TaskQueue) List(query repository.ListQuery) (*repository.Page[*task.Task], error) {
+	return q.r.List(repository.TaskSchema, query)
+}
+
+func (q *TaskQueue) GetHistory() ([]history.Log[*task.Task], error) {
+	return q.r.GetHistory()
+}
+
+func (q *TaskQueue) Add(t *task.Task) error {
+	if err := q.r.Add(t); err != nil {
+		return err
+	}
+	publishEvent(q.events, taskEvent(event.TASK_CREATED, t))
+
+	return q.sync(t)
+}
+
+func (q *TaskQueue) Update(id uuid.UUID, t *task.Task) error {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	return q.update(id, t)
+}
+
+// Изменяет задачу из API: переносит служебные поля сохранённой версии и
+// проверяет переход статуса под той же блокировкой, что и Report, чтобы
+// изменение от агента между чтением и записью не потерялось
+func (q *TaskQueue) Put(id uuid.UUID, t *task.Task, actor string) error {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	current, err := q.r.Get(id)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return repository.ErrNotFound
+	}
+
+	if err := t.MergeFrom(current, actor, time.Now()); err != nil {
+		return err
+	}
+
+	return q.update(id, t)
+}
+
+func (q *TaskQueue) update(id uuid.UUID, t *task.Task) error {
+	// Переходы из API уже в истории задачи, новыми считаются те, которых нет в сохранённой
+	published := 0
+	if cur, err := q.r.Get(id); err == nil && cur != nil {
+		published = len(cur.Transitions)
+	}
+
+	if err := q.r.Update(id, t); err != nil {
+		return err
+	}
+	if len(t.Transitions) > published {
+		q.publishTransitions(t, published)
+	} else {
+		publishEvent(q.events, taskEvent(event.TASK_UPDATED, t))
+	}
+
+	return q.sync(t)
+}
+
+func (q *TaskQueue) Delete(id uuid.UUID) error {
+	if err := q.r.Delete(id); err != nil {
+		return err
+	}
+	publishEvent(q.events, taskEvent(event.TASK_DELETED, &task.Task{Id: id}))
+
+	return q.b.Remove(id)
+}
+
+// Выдаёт агенту до max задач указанных типов (любых, если types пуст)
+func (q *TaskQueue) Lease(agentId uuid.UUID, types []string, max int, timeout time.Duration) ([]*task.Task, error) {
+	if timeout <= 0 {
+		timeout = DefaultVisibilityTimeout
+	}
+
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	leases, err := q.b.Lease(agentId, types, max, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var leased []*task.Task
+	for _, l := range leases {
+		t, err := q.r.Get(l.Id)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return leased, err
+		}
+
+		if t == nil {
+			// Задачу удалили в обход очереди
+			if err := q.b.Remove(l.Id); err != nil {
+				log.Printf("task queue: failed to remove task %s: %v", l.Id, err)
+			}
+			continue
+		}
+
+		t.LeasedBy = agentId
+		t.LeaseExpiresAt = now.Add(timeout)
+		t.Attempts = l.Attempts
+		if err := q.r.Update(t.Id, t); err != nil {
+			return leased, err
+		}
+		leased = append(leased, t)
+	}
+
+	return leased, nil
+}
+
+// Обновляет статус арендованной задачи. Статус in progress продлевает аренду,
+// завершающие статусы снимают её и сохраняют результат и ошибку выполнения.
+// Неудачная задача возвращается в очередь, пока не исчерпаны попытки
+func (q *TaskQueue) Report(id, agentId uuid.UUID, status int16, result json.RawMessage, taskErr string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultVisibilityTimeout
+	}
+
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	t, err := q.r.Get(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if t == nil || t.LeasedBy != agentId || !t.IsLeased(now) {
+		return ErrLeaseNotHeld
+	}
+	published := len(t.Transitions)
+
+	// Переход проверяется до изменения брокера, в репозиторий задача попадёт только после него
+	if err := t.Transition(status, "agent:"+agentId.String(), now); err != nil {
+		return err
+	}
+
+	requeue := false
+	switch status {
+	case task.STATUS_IN_PROGRESS:
+		ok, err := q.b.Extend(id, agentId, timeout)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrLeaseNotHeld
+		}
+	case task.STATUS_OK:
+		ok, err := q.b.Ack(id, agentId)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrLeaseNotHeld
+		}
+	default:
+		res, err := q.b.Nack(id, agentId, q.maxAttempts)
+		if err != nil {
+			return err
+		}
+		switch res {
+		case repository.REQUEUED:
+			requeue = true
+		case repository.DEAD_LETTERED:
+			log.Printf("task %s: attempts exhausted, moved to dead letters", id)
+		default:
+			return ErrLeaseNotHeld
+		}
+	}
+
+	if status == task.STATUS_IN_PROGRESS {
+		t.LeaseExpiresAt = now.Add(timeout)
+	} else {
+		t.LeasedBy = uuid.Nil
+		t.LeaseExpiresAt = time.Time{}
+		t.Result = result
+		t.Error = taskErr
+	}
+
+	if requeue {
+		if err := t.Transition(task.STATUS_QUEUED, systemActor, now); err != nil {
+			return err
+		}
+	}
+
+	if err := q.r.Update(id, t); err != nil {
+		return err
+	}
+
+	q.publishTransitions(t, published)
+	return nil
+}
+
+// Задачи, исчерпавшие попытки
+func (q *TaskQueue) DeadLetters() ([]*task.Task, error) {
+	ids, err := q.b.DeadLetters()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*task.Task, 0, len(ids))
+	for _, id := range ids {
+		t, err := q.r.Get(id)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		if t != nil {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+// Возвращает задачу из dead-letter списка в очередь со сброшенным счётчиком попыток
+func (q *TaskQueue) RequeueDeadLetter(id uuid.UUID, actor string) error {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	t, err := q.r.Get(id)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return repository.ErrNotFound
+	}
+
+	published := len(t.Transitions)
+	if err := t.Transition(task.STATUS_QUEUED, actor, time.Now()); err != nil {
+		return err
+	}
+
+	ok, err := q.b.Requeue(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotInDeadLetters
+	}
+
+	t.Attempts = 0
+	t.Error = ""
+
+	if err := q.r.Update(id, t); err != nil {
+		return err
+	}
+
+	q.publishTransitions(t, published)
+	return nil
+}
+
+func (q *TaskQueue) Run(ctx context.Context) {
+	if err := q.restore(); err != nil {
+		log.Printf("task queue: failed to restore queue: %v", err)
+	}
+
+	t := time.NewTicker(leaseCheckInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			if err := q.releaseExpired(now); err != nil {
+				log.Printf("task queue: %v", err)
+			}
+		}
+	}
+}
+
+// Ставит в очередь задачи, которые ждут выполнения, но отсутствуют в брокере,
+// например после очистки Redis
+func (q *TaskQueue) restore() error {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	tasks, err := q.r.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		if t.Status != task.STATUS_QUEUED {
+			continue
+		}
+		if err := q.b.Enqueue(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *TaskQueue) releaseExpired(now time.Time) error {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	reaped, err := q.b.Reap(q.maxAttempts)
+	if err != nil {
+		return err
+	}
+
+	for id, res := range reaped {
+		t, err := q.r.Get(id)
+		if err != nil || t == nil {
+			log.Printf("task queue: failed to get task %s: %v", id, err)
+			continue
+		}
+
+		to := int16(task.STATUS_QUEUED)
+		if res == repository.DEAD_LETTERED {
+			log.Printf("task %s: lease of agent %s expired, attempts exhausted", t.Id, t.LeasedBy)
+			to = task.STATUS_TIMED_OUT
+		} else {
+			log.Printf("task %s: lease of agent %s expired, returning to queue", t.Id, t.LeasedBy)
+		}
+
+		published := len(t.Transitions)
+		if err := t.Transition(to, systemActor, now); err != nil {
+			log.Printf("task queue: failed to release task %s: %v", t.Id, err)
+			continue
+		}
+		t.LeasedBy = uuid.Nil
+		t.LeaseExpiresAt = time.Time{}
+		if err := q.r.Update(t.Id, t); err != nil {
+			log.Printf("task queue: failed to release task %s: %v", t.Id, err)
+			continue
+		}
+		q.publishTransitions(t, published)
+	}
+
+	return nil
+}
+
+// Приводит состояние брокера в соответствие со статусом задачи.
+// Неудачные задачи остаются в dead-letter списке до явного возврата в очередь
+func (q *TaskQueue) sync(t *task.Task) error {
+	switch t.Status {
+	case task.STATUS_QUEUED:
+		return q.b.Enqueue(t)
+	case task.STATUS_OK, task.STATUS_CANCELLED, task.STATUS_DELETED:
+		return q.b.Remove(t.Id)
+	default:
+		return nil
+	}
+}
+
+// Публикует переходы из истории задачи, начиная с from
+func (q *TaskQueue) publishTransitions(t *task.Task, from int) {
+	for _, tr := range t.Transitions[min(from, len(t.Transitions)):] {
+		e := taskEvent(event.TASK_STATUS_CHANGED, t)
+		e.Time = tr.At
+		e.From = task.StatusName(tr.From)
+		e.To = task.StatusName(tr.To)
+		if tr.To == task.STATUS_FAILED {
+			e.Message = t.Error
+		}
+		publishEvent(q.events, e)
+	}
+}
+
+func taskEvent(eventType string, t *task.Task) *event.Event {
+	e := event.New(eventType, "")
+	e.TaskId = t.Id
+	e.AgentId = t.AgentId
+	e.ConfigurationId = t.ConfigurationId
+	return e
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/task"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+// Задача, арендованная агентом agentId
+func leasedTask(b *fakeBroker, agentId uuid.UUID) *task.Task {
+	t := &task.Task{
+		Id:             uuid.New(),
+		Status:         task.STATUS_IN_PROGRESS,
+		Type:           "minecraft",
+		Action:         task.ACTION_START,
+		LeasedBy:       agentId,
+		LeaseExpiresAt: time.Now().Add(time.Minute),
+		Attempts:       1,
+		CreatedAt:      time.Now().Add(-time.Minute),
+		Transitions: []task.Transition{
+			{From: task.STATUS_QUEUED, To: task.STATUS_IN_PROGRESS, Actor: systemActor},
+		},
+	}
+	b.lease(t.Id, agentId)
+	return t
+}
+
+func TestTaskQueuePut(t *testing.T) {
+	agentId := uuid.New()
+	b := newFakeBroker()
+	cur := leasedTask(b, agentId)
+	store := newMemTaskStore(cur)
+	q := NewTaskQueue(store, b, 0, &fakeEvents{})
+
+	update := &task.Task{Id: cur.Id, Status: task.STATUS_CANCELLED, Type: "minecraft", Action: task.ACTION_STOP, Priority: task.PRIORITY_HIGH}
+	if err := q.Put(cur.Id, update, "admin"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	saved, _ := store.Get(cur.Id)
+	if saved.Status != task.STATUS_CANCELLED || saved.Action != task.ACTION_STOP || saved.Priority != task.PRIORITY_HIGH {
+		t.Errorf("update was not applied: %+v", saved)
+	}
+	if saved.LeasedBy != agentId || saved.Attempts != 1 || !saved.CreatedAt.Equal(cur.CreatedAt) {
+		t.Errorf("service fields were not kept: %+v", saved)
+	}
+	if n := len(saved.Transitions); n != 2 || saved.Transitions[1].Actor != "admin" {
+		t.Errorf("unexpected transitions: %+v", saved.Transitions)
+	}
+}
+
+func TestTaskQueuePutNotFound(t *testing.T) {
+	q := NewTaskQueue(newMemTaskStore(), newFakeBroker(), 0, &fakeEvents{})
+
+	err := q.Put(uuid.New(), &task.Task{}, "admin")
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Изменение из API, прочитанное до отчёта агента, не затирает его результат
+func TestTaskQueuePutAfterReport(t *testing.T) {
+	agentId := uuid.New()
+	b := newFakeBroker()
+	cur := leasedTask(b, agentId)
+	store := newMemTaskStore(cur)
+	q := NewTaskQueue(store, b, 0, &fakeEvents{})
+
+	result := json.RawMessage(`{"port":25565}`)
+	if err := q.Report(cur.Id, agentId, task.STATUS_OK, result, "", 0); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+
+	// Клиент видел задачу в работе и хочет её отменить
+	stale := *cur
+	stale.Status = task.STATUS_CANCELLED
+	stale.Result = nil
+	err := q.Put(cur.Id, &stale, "admin")
+	if !errors.Is(err, task.ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+
+	saved, _ := store.Get(cur.Id)
+	if saved.Status != task.STATUS_OK || string(saved.Result) != string(result) {
+		t.Errorf("agent report was lost: status %d, result %s", saved.Status, saved.Result)
+	}
+}
+
+func TestTaskQueuePutConcurrentWithReport(t *testing.T) {
+	for range 50 {
+		agentId := uuid.New()
+		b := newFakeBroker()
+		cur := leasedTask(b, agentId)
+		store := newMemTaskStore(cur)
+		q := NewTaskQueue(store, b, 0, &fakeEvents{})
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		var reportErr, putErr error
+		go func() {
+			defer wg.Done()
+			reportErr = q.Report(cur.Id, agentId, task.STATUS_OK, json.RawMessage(`{}`), "", 0)
+		}()
+		go func() {
+			defer wg.Done()
+			putErr = q.Put(cur.Id, &task.Task{Id: cur.Id, Status: task.STATUS_IN_PROGRESS, Priority: task.PRIORITY_LOW}, "admin")
+		}()
+		wg.Wait()
+
+		// После отчёта агента перевод в работу уже недопустим
+		if reportErr != nil || (putErr != nil && !errors.Is(putErr, task.ErrInvalidTransition)) {
+			t.Fatalf("report: %v, put: %v", reportErr, putErr)
+		}
+
+		saved, _ := store.Get(cur.Id)
+		if saved.Status != task.STATUS_OK || saved.Result == nil {
+			t.Fatalf("agent report was overwritten: status %d, result %s", saved.Status, saved.Result)
+		}
+		if putErr == nil && saved.Priority != task.PRIORITY_LOW {
+			t.Fatalf("api update was lost: %+v", saved)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vv-sam/otus-project/server/internal/model/event"
+	"github.com/vv-sam/otus-project/server/internal/model/metrics"
+	"github.com/vv-sam/otus-project/server/internal/repository"
+)
+
+// С какой доли квоты диск считается заполненным. Запись в слой контейнера
+// упирается в квоту раньше, чем размер достигает её точно
+const DiskFullRatio = 0.98
+
+type eventPublisher interface {
+	Publish(e *event.Event) error
+}
+
+// Сравнивает потребление ресурсов серверами между heartbeat агента
+// и поднимает события, когда сервер упирается в лимит
+type UsageMonitor struct {
+	configurations configurationGetter
+	events         eventPublisher
+}
+
+func NewUsageMonitor(configurations configurationGetter, events eventPublisher) *UsageMonitor {
+	return &UsageMonitor{configurations: configurations, events: events}
+}
+
+// Обрабатывает новый отчёт агента. prev - предыдущий отчёт
+func (m *UsageMonitor) Observe(agentId uuid.UUID, prev, cur []metrics.ServerUsage) {
+	before := make(map[uuid.UUID]metrics.ServerUsage, len(prev))
+	for _, u := range prev {
+		before[u.ServerId] = u
+	}
+
+	now := time.Now()
+	for _, u := range cur {
+		p, known := before[u.ServerId]
+
+		// После перезапуска сервера API прошлый отчёт неизвестен, старые OOM не повторяем
+		if !u.OOMKilledAt.IsZero() && u.OOMKilledAt.After(p.OOMKilledAt) && (known || now.Sub(u.OOMKilledAt) < OfflineAfter) {
+			m.publish(agentId, u.ServerId, event.SERVER_OOM_KILLED,
+				fmt.Sprintf("server was killed at %s after exceeding its memory limit of %d bytes", u.OOMKilledAt.Format(time.RFC3339), u.MemoryLimit))
+		}
+
+		limit, err := m.diskLimit(u.ServerId)
+		if err != nil {
+			log.Printf("usage monitor: server %s: %v", u.ServerId, err)
+			continue
+		}
+		if limit == 0 {
+			continue
+		}
+
+		full := func(usage uint64) bool { return float64(usage) >= float64(limit)*DiskFullRatio }
+		if full(u.DiskUsage) && (!known || !full(p.DiskUsage)) {
+			m.publish(agentId, u.ServerId, event.SERVER_DISK_FULL,
+				fmt.Sprintf("server uses %d of %d bytes of its disk quota", u.DiskUsage, limit))
+		}
+	}
+}
+
+func (m *UsageMonitor) diskLimit(serverId uuid.UUID) (uint64, error) {
+	conf, err := m.configurations.Get(serverId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if conf == nil || conf.Configuration == nil || conf.GetBase().Resources == nil {
+		return 0, nil
+	}
+	return conf.GetBase().Resources.DiskBytes, nil
+}
+
+func (m *UsageMonitor) publish(agentId, serverId uuid.UUID, eventType, message string) {
+	e := event.New(eventType, message)
+	e.AgentId = agentId
+	e.ServerId = serverId
+
+	log.Printf("server %s on agent %s: %s: %s", serverId, agentId, eventType, message)
+	if err := m.events.Publish(e); err != nil {
+		log.Printf("usage monitor: failed to publish %s: %v", eventType, err)
+	}
+}